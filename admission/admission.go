@@ -0,0 +1,254 @@
+// Package admission validates OpenTelemetryCollector custom resources so that operators can
+// reject invalid collector configurations from a Kubernetes validating admission webhook.
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// maxRequestBodySize limits the size of AdmissionReview requests read by the HTTP handler
+const maxRequestBodySize = 10 << 20
+
+// Response is the admission-style outcome of validating a collector configuration
+type Response struct {
+	Allowed bool     `json:"allowed"`
+	Message string   `json:"message,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// Validator validates OpenTelemetryCollector custom resources and raw collector configurations
+type Validator struct {
+	schemaManager  *collectorschema.SchemaManager
+	defaultVersion string
+	// options apply to every validation, including the AdmissionReview requests of the webhook
+	options []collectorschema.ValidationOption
+}
+
+// NewValidator creates a new validator. The default version is used when the collector version
+// cannot be derived from the custom resource; if empty, the latest embedded version is used.
+// The options apply to every validation, e.g. collectorschema.WithDistribution to reject components
+// that are not part of the deployed distribution.
+func NewValidator(schemaManager *collectorschema.SchemaManager, defaultVersion string, opts ...collectorschema.ValidationOption) *Validator {
+	return &Validator{
+		schemaManager:  schemaManager,
+		defaultVersion: defaultVersion,
+		options:        opts,
+	}
+}
+
+// openTelemetryCollector contains the parts of the OpenTelemetryCollector custom resource needed for validation
+type openTelemetryCollector struct {
+	Kind string `yaml:"kind"`
+	Spec struct {
+		Image string `yaml:"image"`
+		// Config is a map in v1beta1 and a YAML string in v1alpha1
+		Config interface{} `yaml:"config"`
+	} `yaml:"spec"`
+}
+
+// ValidateConfig validates a raw collector configuration for the given version.
+// An empty version resolves to the validator's default version. The options are applied after
+// the options of the validator.
+func (v *Validator) ValidateConfig(config []byte, version string, opts ...collectorschema.ValidationOption) Response {
+	if version == "" {
+		resolved, err := v.resolveDefaultVersion()
+		if err != nil {
+			return denied(err.Error())
+		}
+		version = resolved
+	}
+
+	options := append(append([]collectorschema.ValidationOption{}, v.options...), opts...)
+	result, err := v.schemaManager.ValidateCollectorConfig(config, version, options...)
+	if err != nil {
+		return denied(err.Error())
+	}
+
+	if result.Valid() {
+		return Response{
+			Allowed: true,
+			Message: fmt.Sprintf("collector configuration is valid for version %s", version),
+		}
+	}
+
+	response := Response{
+		Allowed: false,
+		Message: fmt.Sprintf("collector configuration is invalid for version %s", version),
+	}
+	for _, validationError := range result.Errors {
+		response.Errors = append(response.Errors, validationError.String())
+	}
+	return response
+}
+
+// ValidateCustomResource validates the config of an OpenTelemetryCollector custom resource in YAML or JSON.
+// The collector version is derived from the spec.image tag when it matches an embedded schema version,
+// the options are applied like in ValidateConfig.
+func (v *Validator) ValidateCustomResource(resource []byte, opts ...collectorschema.ValidationOption) Response {
+	var collector openTelemetryCollector
+	if err := yaml.Unmarshal(resource, &collector); err != nil {
+		return denied(fmt.Sprintf("failed to parse custom resource: %v", err))
+	}
+
+	if collector.Kind != "" && collector.Kind != "OpenTelemetryCollector" {
+		return denied(fmt.Sprintf("unexpected kind %q, expected OpenTelemetryCollector", collector.Kind))
+	}
+
+	var config []byte
+	switch c := collector.Spec.Config.(type) {
+	case nil:
+		return denied("spec.config is missing")
+	case string:
+		config = []byte(c)
+	default:
+		encoded, err := yaml.Marshal(c)
+		if err != nil {
+			return denied(fmt.Sprintf("failed to encode spec.config: %v", err))
+		}
+		config = encoded
+	}
+
+	return v.ValidateConfig(config, v.versionFromImage(collector.Spec.Image), opts...)
+}
+
+// ServeHTTP handles admission.k8s.io/v1 AdmissionReview requests for OpenTelemetryCollector resources
+func (v *Validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review, err := v.Review(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(review)
+}
+
+// admissionReview is the subset of admission.k8s.io/v1 AdmissionReview used by the webhook
+type admissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *admissionRequest  `json:"request,omitempty"`
+	Response   *admissionResponse `json:"response,omitempty"`
+}
+
+type admissionRequest struct {
+	UID       string          `json:"uid"`
+	Operation string          `json:"operation"`
+	Object    json.RawMessage `json:"object"`
+}
+
+type admissionResponse struct {
+	UID     string           `json:"uid"`
+	Allowed bool             `json:"allowed"`
+	Status  *admissionStatus `json:"status,omitempty"`
+}
+
+type admissionStatus struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+// Review validates the object of an AdmissionReview request and returns the encoded AdmissionReview response
+func (v *Validator) Review(request []byte) ([]byte, error) {
+	var review admissionReview
+	if err := json.Unmarshal(request, &review); err != nil {
+		return nil, fmt.Errorf("failed to parse AdmissionReview: %w", err)
+	}
+	if review.Request == nil {
+		return nil, fmt.Errorf("AdmissionReview has no request")
+	}
+
+	// Deletes carry no object and are always allowed
+	response := Response{Allowed: true}
+	if review.Request.Operation != "DELETE" && len(review.Request.Object) > 0 {
+		response = v.ValidateCustomResource(review.Request.Object)
+	}
+
+	reviewResponse := &admissionResponse{
+		UID:     review.Request.UID,
+		Allowed: response.Allowed,
+	}
+	if !response.Allowed {
+		reviewResponse.Status = &admissionStatus{
+			Code:    http.StatusForbidden,
+			Message: response.String(),
+		}
+	}
+
+	return json.Marshal(admissionReview{
+		APIVersion: "admission.k8s.io/v1",
+		Kind:       "AdmissionReview",
+		Response:   reviewResponse,
+	})
+}
+
+// String returns the message followed by all errors, suitable for admission status messages
+func (r Response) String() string {
+	if len(r.Errors) == 0 {
+		return r.Message
+	}
+	return fmt.Sprintf("%s: %s", r.Message, strings.Join(r.Errors, "; "))
+}
+
+// versionFromImage extracts a known collector version from an image reference, e.g.
+// "otel/opentelemetry-collector-contrib:0.138.0", falling back to an empty version
+func (v *Validator) versionFromImage(image string) string {
+	if image == "" {
+		return ""
+	}
+
+	// Strip the digest and registry port so only the tag remains after the last colon
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	colon := strings.LastIndex(image, ":")
+	if colon < 0 || colon < slash {
+		return ""
+	}
+	tag := strings.TrimPrefix(image[colon+1:], "v")
+
+	versions, err := v.schemaManager.GetAllVersions()
+	if err != nil {
+		return ""
+	}
+	for _, version := range versions {
+		if version == tag {
+			return version
+		}
+	}
+	return ""
+}
+
+// resolveDefaultVersion returns the configured default version or the latest embedded version
+func (v *Validator) resolveDefaultVersion() (string, error) {
+	if v.defaultVersion != "" {
+		return v.defaultVersion, nil
+	}
+	return v.schemaManager.GetLatestVersion()
+}
+
+// denied creates a response rejecting the request with a message
+func denied(message string) Response {
+	return Response{
+		Allowed: false,
+		Message: message,
+	}
+}
@@ -0,0 +1,205 @@
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+const validConfig = `
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`
+
+const invalidConfig = `
+receivers:
+  otlp:
+processors:
+  batch:
+    send_batch_size: "many"
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlphttp]
+`
+
+func TestValidator_ValidateConfig(t *testing.T) {
+	validator := NewValidator(collectorschema.NewSchemaManager(), "0.138.0")
+
+	response := validator.ValidateConfig([]byte(validConfig), "")
+	assert.True(t, response.Allowed, "Expected valid config to be allowed: %s", response)
+	assert.Contains(t, response.Message, "0.138.0")
+
+	response = validator.ValidateConfig([]byte(invalidConfig), "0.138.0")
+	assert.False(t, response.Allowed)
+	assert.Len(t, response.Errors, 2)
+	assert.Contains(t, response.String(), "processors.batch.send_batch_size")
+	assert.Contains(t, response.String(), `references "otlphttp"`)
+
+	response = validator.ValidateConfig([]byte("receivers: [unclosed"), "0.138.0")
+	assert.False(t, response.Allowed)
+	assert.Contains(t, response.Message, "failed to parse collector configuration")
+}
+
+func TestValidator_ValidationOptions(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+	core, err := manager.GetDistribution(collectorschema.DistributionCore, "0.138.0")
+	require.NoError(t, err)
+	config := []byte("receivers:\n  sqlquery:\nexporters:\n  debug:\nservice:\n  pipelines:\n    metrics:\n      receivers: [sqlquery]\n      exporters: [debug]\n")
+
+	validator := NewValidator(manager, "0.138.0")
+	response := validator.ValidateConfig(config, "")
+	assert.True(t, response.Allowed, "Expected config to be allowed without a distribution: %s", response)
+	response = validator.ValidateConfig(config, "", collectorschema.WithDistribution(core))
+	assert.False(t, response.Allowed)
+	assert.Equal(t, []string{`receivers.sqlquery: receiver "sqlquery" is not part of the core distribution`}, response.Errors)
+
+	resource := []byte("kind: OpenTelemetryCollector\nspec:\n  config:\n    receivers:\n      otlp:\n      sqlquery:\n")
+	response = validator.ValidateCustomResource(resource, collectorschema.WithDistribution(core), collectorschema.Strict())
+	assert.False(t, response.Allowed)
+	assert.Contains(t, response.String(), `receiver "sqlquery" is not part of the core distribution`)
+
+	// Options of the validator apply to the AdmissionReview requests of the webhook
+	request := `{"request": {"uid": "1", "operation": "CREATE", "object": {"kind": "OpenTelemetryCollector", "spec": {"config": {"receivers": {"sqlquery": null}}}}}}`
+	reviewResponse, err := NewValidator(manager, "0.138.0", collectorschema.WithDistribution(core)).Review([]byte(request))
+	require.NoError(t, err)
+	var review admissionReview
+	require.NoError(t, json.Unmarshal(reviewResponse, &review))
+	assert.False(t, review.Response.Allowed)
+	assert.Contains(t, review.Response.Status.Message, `receiver "sqlquery" is not part of the core distribution`)
+}
+
+func TestValidator_ValidateCustomResource(t *testing.T) {
+	validator := NewValidator(collectorschema.NewSchemaManager(), "")
+
+	v1beta1 := []byte(`
+apiVersion: opentelemetry.io/v1beta1
+kind: OpenTelemetryCollector
+metadata:
+  name: simplest
+spec:
+  image: ghcr.io/open-telemetry/opentelemetry-collector-releases/opentelemetry-collector-contrib:0.138.0
+  config:
+    receivers:
+      otlp:
+    exporters:
+      debug:
+    service:
+      pipelines:
+        traces:
+          receivers: [otlp]
+          exporters: [debug]
+`)
+	response := validator.ValidateCustomResource(v1beta1)
+	assert.True(t, response.Allowed, "Expected valid v1beta1 resource to be allowed: %s", response)
+	assert.Contains(t, response.Message, "0.138.0", "Version should be derived from the image tag")
+
+	v1alpha1, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "opentelemetry.io/v1alpha1",
+		"kind":       "OpenTelemetryCollector",
+		"spec": map[string]interface{}{
+			"config": invalidConfig,
+		},
+	})
+	require.NoError(t, err)
+	response = validator.ValidateCustomResource(v1alpha1)
+	assert.False(t, response.Allowed)
+	assert.NotEmpty(t, response.Errors)
+
+	response = validator.ValidateCustomResource([]byte("kind: Deployment\nspec: {}"))
+	assert.False(t, response.Allowed)
+	assert.Contains(t, response.Message, "unexpected kind")
+
+	response = validator.ValidateCustomResource([]byte("kind: OpenTelemetryCollector\nspec: {}"))
+	assert.False(t, response.Allowed)
+	assert.Equal(t, "spec.config is missing", response.Message)
+}
+
+func TestValidator_VersionFromImage(t *testing.T) {
+	validator := NewValidator(collectorschema.NewSchemaManager(), "")
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"otel/opentelemetry-collector-contrib:0.138.0", "0.138.0"},
+		{"otel/opentelemetry-collector-contrib:v0.137.0", "0.137.0"},
+		{"localhost:5000/otelcol:0.136.0@sha256:abcdef", "0.136.0"},
+		{"localhost:5000/otelcol", ""},
+		{"otel/opentelemetry-collector-contrib:latest", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, validator.versionFromImage(tt.image), "image %q", tt.image)
+	}
+}
+
+func TestValidator_ServeHTTP(t *testing.T) {
+	validator := NewValidator(collectorschema.NewSchemaManager(), "0.138.0")
+
+	resource := map[string]interface{}{
+		"kind": "OpenTelemetryCollector",
+		"spec": map[string]interface{}{"config": invalidConfig},
+	}
+	review := map[string]interface{}{
+		"apiVersion": "admission.k8s.io/v1",
+		"kind":       "AdmissionReview",
+		"request": map[string]interface{}{
+			"uid":       "705ab4f5-6393-11e8-b7cc-42010a800002",
+			"operation": "CREATE",
+			"object":    resource,
+		},
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	validator.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response admissionReview
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.NotNil(t, response.Response)
+	assert.Equal(t, "705ab4f5-6393-11e8-b7cc-42010a800002", response.Response.UID)
+	assert.False(t, response.Response.Allowed)
+	require.NotNil(t, response.Response.Status)
+	assert.Contains(t, response.Response.Status.Message, "collector configuration is invalid")
+
+	recorder = httptest.NewRecorder()
+	validator.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	validator.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/validate", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestValidator_Review_Delete(t *testing.T) {
+	validator := NewValidator(collectorschema.NewSchemaManager(), "0.138.0")
+
+	reviewResponse, err := validator.Review([]byte(`{"request": {"uid": "1", "operation": "DELETE"}}`))
+	require.NoError(t, err)
+
+	var response admissionReview
+	require.NoError(t, json.Unmarshal(reviewResponse, &response))
+	assert.True(t, response.Response.Allowed)
+}
@@ -0,0 +1,262 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Checks of the security audit, see Audit
+const (
+	AuditCheckInsecureTLS          = "insecure-tls"
+	AuditCheckPlaintextEndpoint    = "plaintext-endpoint"
+	AuditCheckPublicEndpoint       = "public-endpoint"
+	AuditCheckMissingAuthenticator = "missing-authenticator"
+	AuditCheckInlineCredential     = "inline-credential"
+)
+
+// AuditFinding is a security relevant setting found in a collector configuration
+type AuditFinding struct {
+	CheckID  string       `json:"checkId"`
+	Severity LintSeverity `json:"severity"`
+	// Path is the dot separated location of the setting, e.g. "receivers.otlp.protocols.grpc.endpoint"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// Remediation describes how to secure the setting
+	Remediation string `json:"remediation"`
+}
+
+// String returns the finding in "severity: path: message (check)" form
+func (f AuditFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Path, f.Message, f.CheckID)
+}
+
+// AuditResult holds the findings of auditing a collector configuration
+type AuditResult struct {
+	Findings []AuditFinding `json:"findings"`
+}
+
+// HasSeverity returns true if at least one finding has the given severity
+func (r *AuditResult) HasSeverity(severity LintSeverity) bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialKeys are the configuration keys whose values are secrets, compared without case, dashes and underscores
+var credentialKeys = []string{
+	"password", "passwd", "secret", "clientsecret", "secretkey", "token", "accesstoken", "bearertoken", "apikey",
+	"apitoken", "accesskey", "authorization", "privatekey", "sharedkey", "connectionstring",
+}
+
+// Audit reports security relevant settings of a collector configuration (YAML or JSON): disabled TLS and
+// certificate verification, server endpoints bound to all interfaces, receivers accepting data without an
+// authenticator and credentials (writeOnly fields or fields named like credentials) written inline instead of
+// read from the env or file providers. Components
+// are audited whether or not they are used in a pipeline. Findings are sorted by path.
+func (sm *SchemaManager) Audit(config []byte, version string) (*AuditResult, error) {
+	return sm.AuditContext(context.Background(), config, version)
+}
+
+// AuditContext is Audit with a context, auditing stops with the error of the context when it is canceled
+func (sm *SchemaManager) AuditContext(ctx context.Context, config []byte, version string) (*AuditResult, error) {
+	configMap, err := parseCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditResult{}
+	for _, cs := range componentSections {
+		section, _ := configMap[cs.section].(map[string]interface{})
+		for _, id := range sortedKeys(section) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// Unknown components are audited without a schema, only the authenticator check needs one
+			var schema, definitions map[string]interface{}
+			if componentID, err := ParseComponentID(id); err == nil {
+				if componentSchema, err := sm.getComponentSchema(ctx, cs.componentType, componentID.Component, version); err == nil {
+					schema = componentSchema.Schema
+					definitions, _ = schema["$defs"].(map[string]interface{})
+				}
+			}
+			auditValue(cs.componentType, schema, definitions, cs.section+"."+id, section[id], result)
+		}
+	}
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		return result.Findings[i].Path < result.Findings[j].Path
+	})
+	return result, nil
+}
+
+// auditValue audits a configuration value and its children, schema is the schema of the value or nil
+func auditValue(componentType ComponentType, schema map[string]interface{}, definitions map[string]interface{}, path string, value interface{}, result *AuditResult) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, definitionReferencePrefix)].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		auditBlock(componentType, schema, path, v, result)
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			auditValue(componentType, propertySchema, definitions, path+"."+key, v[key], result)
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			auditValue(componentType, items, definitions, fmt.Sprintf("%s[%d]", path, i), item, result)
+		}
+	}
+}
+
+// auditBlock audits the settings of a configuration block
+func auditBlock(componentType ComponentType, schema map[string]interface{}, path string, block map[string]interface{}, result *AuditResult) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, key := range sortedKeys(block) {
+		value, isString := block[key].(string)
+		propertySchema, _ := properties[key].(map[string]interface{})
+		writeOnly, _ := propertySchema["writeOnly"].(bool)
+		if isString && (writeOnly || isCredentialKey(key)) && !usesConfigProvider(value) {
+			result.Findings = append(result.Findings, AuditFinding{
+				CheckID:     AuditCheckInlineCredential,
+				Severity:    LintSeverityError,
+				Path:        path + "." + key,
+				Message:     fmt.Sprintf("%q is a credential written inline in the configuration", key),
+				Remediation: fmt.Sprintf("read the value from the environment or a file, e.g. %s: ${env:%s}", key, strings.ToUpper(key)),
+			})
+		}
+	}
+
+	// Headers declared as a map are checked as keys, e.g. in the otlphttp exporter headers are lists of name and
+	// value pairs
+	if headers, ok := block["headers"].([]interface{}); ok {
+		for i, item := range headers {
+			header, _ := item.(map[string]interface{})
+			name, _ := header["name"].(string)
+			if value, ok := header["value"].(string); ok && isCredentialKey(name) && !usesConfigProvider(value) {
+				result.Findings = append(result.Findings, AuditFinding{
+					CheckID:     AuditCheckInlineCredential,
+					Severity:    LintSeverityError,
+					Path:        fmt.Sprintf("%s.headers[%d].value", path, i),
+					Message:     fmt.Sprintf("header %q holds a credential written inline in the configuration", name),
+					Remediation: fmt.Sprintf("read the header value from the environment or a file, e.g. ${env:%s}", strings.ToUpper(strings.ReplaceAll(name, "-", "_"))),
+				})
+			}
+		}
+	}
+
+	if tls, ok := block["tls"].(map[string]interface{}); ok {
+		if insecure, _ := tls["insecure"].(bool); insecure {
+			result.Findings = append(result.Findings, AuditFinding{
+				CheckID:     AuditCheckInsecureTLS,
+				Severity:    LintSeverityWarning,
+				Path:        path + ".tls.insecure",
+				Message:     "TLS is disabled, data and credentials are sent in plaintext",
+				Remediation: "remove tls.insecure and configure the CA of the server with tls.ca_file",
+			})
+		}
+		if skipVerify, _ := tls["insecure_skip_verify"].(bool); skipVerify {
+			result.Findings = append(result.Findings, AuditFinding{
+				CheckID:     AuditCheckInsecureTLS,
+				Severity:    LintSeverityError,
+				Path:        path + ".tls.insecure_skip_verify",
+				Message:     "the certificate of the server is not verified, connections can be intercepted",
+				Remediation: "remove tls.insecure_skip_verify and configure the CA of the server with tls.ca_file",
+			})
+		}
+	}
+
+	endpoint, ok := block["endpoint"].(string)
+	if !ok || endpoint == "" || usesConfigProvider(endpoint) {
+		return
+	}
+	if componentType == ComponentTypeExporter {
+		if endpointURL, err := url.Parse(endpoint); err == nil && endpointURL.Scheme == "http" && !isLoopbackHost(endpointURL.Hostname()) {
+			result.Findings = append(result.Findings, AuditFinding{
+				CheckID:     AuditCheckPlaintextEndpoint,
+				Severity:    LintSeverityWarning,
+				Path:        path + ".endpoint",
+				Message:     fmt.Sprintf("%q sends data to a remote host without TLS", endpoint),
+				Remediation: "use an https endpoint",
+			})
+		}
+	}
+	// Clients never connect to all interfaces, these endpoints are listen addresses, e.g. of the prometheus exporter
+	if !isPublicEndpoint(endpoint) {
+		return
+	}
+	result.Findings = append(result.Findings, AuditFinding{
+		CheckID:     AuditCheckPublicEndpoint,
+		Severity:    LintSeverityWarning,
+		Path:        path + ".endpoint",
+		Message:     fmt.Sprintf("%q listens on all network interfaces", endpoint),
+		Remediation: "bind the endpoint to localhost or the address of a single interface, e.g. ${env:MY_POD_IP}:4317",
+	})
+
+	// Server blocks are expected to configure an authenticator unless their schema has no auth setting
+	_, hasProperties := schema["properties"].(map[string]interface{})
+	if _, supportsAuth := properties["auth"]; componentType == ComponentTypeReceiver && (supportsAuth || !hasProperties) {
+		if auth, _ := block["auth"].(map[string]interface{}); auth["authenticator"] == nil {
+			result.Findings = append(result.Findings, AuditFinding{
+				CheckID:     AuditCheckMissingAuthenticator,
+				Severity:    LintSeverityWarning,
+				Path:        path,
+				Message:     "the receiver accepts data from any network client without authentication",
+				Remediation: "configure an authenticator extension with auth.authenticator, e.g. bearertokenauth or oidc",
+			})
+		}
+	}
+}
+
+// isCredentialKey returns whether a configuration key or header name holds a secret, custom headers are checked
+// without their x- prefix, e.g. X-Api-Key
+func isCredentialKey(key string) bool {
+	key = strings.ToLower(key)
+	key = strings.TrimPrefix(key, "x-")
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(key)
+	return contains(credentialKeys, normalized)
+}
+
+// usesConfigProvider returns whether a value is read from a config provider or an environment variable, e.g.
+// ${env:TOKEN} or ${file:/secrets/token}
+func usesConfigProvider(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// isPublicEndpoint returns whether a listen address binds all network interfaces, e.g. 0.0.0.0:4317, [::]:4317 or :4317
+func isPublicEndpoint(endpoint string) bool {
+	if parsed, err := url.Parse(endpoint); err == nil && parsed.Host != "" {
+		endpoint = parsed.Host
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = endpoint
+	}
+	return host == "" || host == "0.0.0.0" || host == "::"
+}
+
+// isLoopbackHost returns whether a host is the local machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
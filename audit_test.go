@@ -0,0 +1,119 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditConfig has a finding of every audit check
+var auditConfig = []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: localhost:4318
+  otlp/authenticated:
+    protocols:
+      grpc:
+        endpoint: "[::]:4317"
+        auth:
+          authenticator: bearertokenauth
+  zipkin:
+    endpoint: ${env:MY_POD_IP}:9411
+exporters:
+  otlp:
+    endpoint: backend:4317
+    tls:
+      insecure: true
+  otlphttp:
+    endpoint: http://backend:4318
+    headers:
+      Authorization: Bearer abc
+      X-Scope-OrgID: tenant
+    tls:
+      insecure_skip_verify: true
+  otlphttp/local:
+    endpoint: http://localhost:4318
+    headers:
+      Authorization: ${env:TOKEN}
+  prometheus:
+    endpoint: :8889
+extensions:
+  basicauth/client:
+    client_auth:
+      username: collector
+      password: hunter2
+  bearertokenauth:
+    token: ${file:/secrets/token}
+`)
+
+func auditFindingStrings(result *AuditResult) []string {
+	var findings []string
+	for _, finding := range result.Findings {
+		findings = append(findings, finding.String())
+	}
+	return findings
+}
+
+func TestSchemaManager_Audit(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.Audit(auditConfig, "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`warning: exporters.otlp.tls.insecure: TLS is disabled, data and credentials are sent in plaintext (insecure-tls)`,
+		`warning: exporters.otlphttp.endpoint: "http://backend:4318" sends data to a remote host without TLS (plaintext-endpoint)`,
+		`error: exporters.otlphttp.headers.Authorization: "Authorization" is a credential written inline in the configuration (inline-credential)`,
+		`error: exporters.otlphttp.tls.insecure_skip_verify: the certificate of the server is not verified, connections can be intercepted (insecure-tls)`,
+		`warning: exporters.prometheus.endpoint: ":8889" listens on all network interfaces (public-endpoint)`,
+		`error: extensions.basicauth/client.client_auth.password: "password" is a credential written inline in the configuration (inline-credential)`,
+		`warning: receivers.otlp.protocols.grpc: the receiver accepts data from any network client without authentication (missing-authenticator)`,
+		`warning: receivers.otlp.protocols.grpc.endpoint: "0.0.0.0:4317" listens on all network interfaces (public-endpoint)`,
+		`warning: receivers.otlp/authenticated.protocols.grpc.endpoint: "[::]:4317" listens on all network interfaces (public-endpoint)`,
+	}, auditFindingStrings(result))
+	assert.True(t, result.HasSeverity(LintSeverityError))
+	for _, finding := range result.Findings {
+		assert.NotEmpty(t, finding.Remediation, finding.Path)
+	}
+}
+
+func TestSchemaManager_Audit_HeaderList(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.Audit([]byte(`
+exporters:
+  otlphttp:
+    endpoint: https://backend:4318
+    headers:
+      - name: x-api-key
+        value: abc
+      - name: x-tenant
+        value: tenant
+`), "0.139.0")
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, AuditFinding{
+		CheckID:     AuditCheckInlineCredential,
+		Severity:    LintSeverityError,
+		Path:        "exporters.otlphttp.headers[0].value",
+		Message:     `header "x-api-key" holds a credential written inline in the configuration`,
+		Remediation: "read the header value from the environment or a file, e.g. ${env:X_API_KEY}",
+	}, result.Findings[0])
+}
+
+func TestSchemaManager_Audit_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.Audit([]byte("receivers: ["), "0.139.0")
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = manager.AuditContext(ctx, auditConfig, "0.139.0")
+	require.ErrorIs(t, err, context.Canceled)
+}
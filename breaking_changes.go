@@ -0,0 +1,345 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaChangeKind is the kind of a breaking change between the schemas of two versions
+type SchemaChangeKind string
+
+// Kinds of breaking changes
+const (
+	SchemaChangeRemovedComponent SchemaChangeKind = "removed-component"
+	SchemaChangeRemovedField     SchemaChangeKind = "removed-field"
+	SchemaChangeRenamedField     SchemaChangeKind = "renamed-field"
+	SchemaChangeNarrowedType     SchemaChangeKind = "narrowed-type"
+	SchemaChangeRequiredField    SchemaChangeKind = "required-field"
+)
+
+// SchemaChange is a change of a component schema that can break configurations written for the old version
+type SchemaChange struct {
+	ComponentType ComponentType    `json:"componentType"`
+	Component     string           `json:"component"`
+	Kind          SchemaChangeKind `json:"kind"`
+	// Path is the dotted path of the field in the old schema, e.g. protocols.grpc.endpoint. It is empty for
+	// removed components.
+	Path string `json:"path,omitempty"`
+	// NewPath is the path of a renamed field in the new schema
+	NewPath string `json:"newPath,omitempty"`
+	Message string `json:"message"`
+}
+
+// BreakingChanges holds the breaking changes between the schemas of two versions, see GetBreakingChanges
+type BreakingChanges struct {
+	OldVersion string         `json:"oldVersion"`
+	NewVersion string         `json:"newVersion"`
+	Changes    []SchemaChange `json:"changes"`
+}
+
+// Markdown returns the changes as a CHANGES.md style document with a section per component
+func (c *BreakingChanges) Markdown() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# Breaking changes from %s to %s\n", c.OldVersion, c.NewVersion)
+	if len(c.Changes) == 0 {
+		out.WriteString("\nNo breaking changes.\n")
+		return out.String()
+	}
+
+	var component string
+	for _, change := range c.Changes {
+		if id := fmt.Sprintf("%s/%s", change.ComponentType, change.Component); id != component {
+			component = id
+			fmt.Fprintf(&out, "\n## %s\n\n", component)
+		}
+		fmt.Fprintf(&out, "- %s\n", change.Message)
+	}
+	return out.String()
+}
+
+// GetBreakingChanges compares the component schemas of two versions and returns the changes that can break
+// configurations written for the old version: removed components, removed or renamed fields, narrowed types and enums
+// and fields that became required. A removed field is reported as renamed when a field with the same description
+// was added next to it. Changes are sorted by component type, component and path.
+func (sm *SchemaManager) GetBreakingChanges(oldVersion string, newVersion string) (*BreakingChanges, error) {
+	oldComponents, err := sm.ListAvailableComponents(oldVersion)
+	if err != nil {
+		return nil, err
+	}
+	newComponents, err := sm.ListAvailableComponents(newVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &BreakingChanges{OldVersion: oldVersion, NewVersion: newVersion}
+	for componentType, names := range oldComponents {
+		for _, name := range names {
+			if !contains(newComponents[componentType], name) {
+				changes.Changes = append(changes.Changes, SchemaChange{
+					ComponentType: componentType,
+					Component:     name,
+					Kind:          SchemaChangeRemovedComponent,
+					Message:       fmt.Sprintf("Removed %s `%s`", componentType, name),
+				})
+				continue
+			}
+
+			oldSchema, err := sm.GetComponentSchema(componentType, name, oldVersion)
+			if err != nil {
+				return nil, err
+			}
+			newSchema, err := sm.GetComponentSchema(componentType, name, newVersion)
+			if err != nil {
+				return nil, err
+			}
+			for _, change := range compareSchemaFields(indexSchemaFields(oldSchema.Schema), indexSchemaFields(newSchema.Schema)) {
+				change.ComponentType = componentType
+				change.Component = name
+				changes.Changes = append(changes.Changes, change)
+			}
+		}
+	}
+
+	sort.SliceStable(changes.Changes, func(i, j int) bool {
+		a, b := changes.Changes[i], changes.Changes[j]
+		if a.ComponentType != b.ComponentType {
+			return a.ComponentType < b.ComponentType
+		}
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Path < b.Path
+	})
+	return changes, nil
+}
+
+// schemaFieldIndex holds the fields of a component schema by dotted path
+type schemaFieldIndex struct {
+	definitions map[string]interface{}
+	fields      map[string]map[string]interface{}
+	required    map[string]bool
+}
+
+// indexSchemaFields collects the fields of a schema and whether they are required, fields of union alternatives
+// are indexed once
+func indexSchemaFields(schema map[string]interface{}) *schemaFieldIndex {
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	index := &schemaFieldIndex{
+		definitions: definitions,
+		fields:      make(map[string]map[string]interface{}),
+		required:    make(map[string]bool),
+	}
+	index.addRequired("", schema)
+	searchSchemaFields(schema, definitions, "", nil, func(path string, field map[string]interface{}) {
+		if _, found := index.fields[path]; found {
+			return
+		}
+		index.fields[path] = field
+		index.addRequired(path, field)
+	})
+	return index
+}
+
+// addRequired records the required properties of the object at path
+func (index *schemaFieldIndex) addRequired(path string, schema map[string]interface{}) {
+	schema = index.resolve(schema)
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if path == "" {
+			index.required[fmt.Sprint(name)] = true
+		} else {
+			index.required[path+"."+fmt.Sprint(name)] = true
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		index.addRequired(path+"[]", items)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok && path != "" {
+		index.addRequired(path+".*", additional)
+	}
+}
+
+// resolve returns the definition a schema references or the schema itself
+func (index *schemaFieldIndex) resolve(schema map[string]interface{}) map[string]interface{} {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := index.definitions[strings.TrimPrefix(ref, definitionReferencePrefix)].(map[string]interface{}); ok {
+			return definition
+		}
+	}
+	return schema
+}
+
+// description returns the description of a field, or of the definition it references
+func (index *schemaFieldIndex) description(field map[string]interface{}) string {
+	if description, ok := field["description"].(string); ok {
+		return description
+	}
+	description, _ := index.resolve(field)["description"].(string)
+	return description
+}
+
+// compareSchemaFields returns the breaking changes between the fields of two schemas
+func compareSchemaFields(oldIndex, newIndex *schemaFieldIndex) []SchemaChange {
+	var changes []SchemaChange
+	var added []string
+	for path := range newIndex.fields {
+		if _, found := oldIndex.fields[path]; !found {
+			added = append(added, path)
+		}
+	}
+	sort.Strings(added)
+
+	for _, path := range sortedFieldPaths(oldIndex.fields) {
+		oldField := oldIndex.fields[path]
+		newField, found := newIndex.fields[path]
+		if !found {
+			// Fields of removed parents are covered by the change of the parent
+			if parent := parentFieldPath(path); parent != "" {
+				if _, parentFound := newIndex.fields[parent]; !parentFound {
+					continue
+				}
+			}
+			if renamed := findRenamedField(path, oldIndex.description(oldField), added, newIndex); renamed != "" {
+				changes = append(changes, SchemaChange{
+					Kind:    SchemaChangeRenamedField,
+					Path:    path,
+					NewPath: renamed,
+					Message: fmt.Sprintf("Renamed field `%s` to `%s`", path, renamed),
+				})
+				continue
+			}
+			changes = append(changes, SchemaChange{
+				Kind:    SchemaChangeRemovedField,
+				Path:    path,
+				Message: fmt.Sprintf("Removed field `%s`", path),
+			})
+			continue
+		}
+
+		oldTypes := strings.Split(schemaTypeName(oldField, oldIndex.definitions), "|")
+		newTypes := strings.Split(schemaTypeName(newField, newIndex.definitions), "|")
+		if removed := removedSchemaTypes(oldTypes, newTypes); len(removed) > 0 {
+			changes = append(changes, SchemaChange{
+				Kind:    SchemaChangeNarrowedType,
+				Path:    path,
+				Message: fmt.Sprintf("Changed the type of `%s` from %s to %s", path, strings.Join(oldTypes, "|"), strings.Join(newTypes, "|")),
+			})
+		}
+		if removed := removedEnumValues(oldIndex.resolve(oldField), newIndex.resolve(newField)); len(removed) > 0 {
+			changes = append(changes, SchemaChange{
+				Kind:    SchemaChangeNarrowedType,
+				Path:    path,
+				Message: fmt.Sprintf("Removed the values %s of `%s`", strings.Join(removed, ", "), path),
+			})
+		}
+	}
+
+	for _, path := range sortedRequiredPaths(newIndex.required) {
+		if oldIndex.required[path] {
+			continue
+		}
+		// Required fields of new optional blocks only apply to configurations using the block
+		if parent := parentFieldPath(path); parent != "" {
+			if _, parentFound := oldIndex.fields[parent]; !parentFound {
+				continue
+			}
+		}
+		changes = append(changes, SchemaChange{
+			Kind:    SchemaChangeRequiredField,
+			Path:    path,
+			Message: fmt.Sprintf("Field `%s` is required", path),
+		})
+	}
+	return changes
+}
+
+// findRenamedField returns the added field next to a removed field with the same non-empty description
+func findRenamedField(path string, description string, added []string, newIndex *schemaFieldIndex) string {
+	if description == "" {
+		return ""
+	}
+	for _, candidate := range added {
+		if parentFieldPath(candidate) == parentFieldPath(path) && newIndex.description(newIndex.fields[candidate]) == description {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// removedSchemaTypes returns the types of a field that are not accepted anymore, integers are accepted by numbers.
+// Fields without a type, e.g. unions of definitions, are not compared.
+func removedSchemaTypes(oldTypes, newTypes []string) []string {
+	if contains(oldTypes, "") || contains(newTypes, "") {
+		return nil
+	}
+	var removed []string
+	for _, oldType := range oldTypes {
+		if contains(newTypes, oldType) || (oldType == "integer" && contains(newTypes, "number")) {
+			continue
+		}
+		removed = append(removed, oldType)
+	}
+	return removed
+}
+
+// removedEnumValues returns the enum values of the old schema that the new schema does not accept
+func removedEnumValues(oldSchema, newSchema map[string]interface{}) []string {
+	oldValues, _ := oldSchema["enum"].([]interface{})
+	newValues, ok := newSchema["enum"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var removed []string
+	for _, oldValue := range oldValues {
+		found := false
+		for _, newValue := range newValues {
+			if reflect.DeepEqual(oldValue, newValue) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, fmt.Sprintf("`%v`", oldValue))
+		}
+	}
+	return removed
+}
+
+// parentFieldPath returns the path of the field an object field belongs to, or "" for top-level fields. Fields of
+// list items and map values belong to the list or map, e.g. headers for headers.*.value.
+func parentFieldPath(path string) string {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return ""
+	}
+	parent := path[:i]
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(parent, "[]"), ".*")
+		if trimmed == parent {
+			return parent
+		}
+		parent = trimmed
+	}
+}
+
+// sortedFieldPaths returns the paths of indexed fields in sorted order
+func sortedFieldPaths(fields map[string]map[string]interface{}) []string {
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// sortedRequiredPaths returns the paths of required fields in sorted order
+func sortedRequiredPaths(required map[string]bool) []string {
+	paths := make([]string, 0, len(required))
+	for path := range required {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,81 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_GetBreakingChanges(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "1.0.0", []byte(`{
+		"type": "object",
+		"properties": {
+			"endpoint": {"type": "string", "description": "Address of the backend"},
+			"timeout": {"type": ["string", "integer"]},
+			"compression": {"type": "string", "enum": ["gzip", "zstd", "none"]},
+			"retries": {"type": "integer"},
+			"tls": {"$ref": "#/$defs/tls"},
+			"legacy": {"type": "object", "properties": {"mode": {"type": "string"}}},
+			"headers": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}}
+		},
+		"$defs": {"tls": {"type": "object", "properties": {"insecure": {"type": "boolean"}, "ca_file": {"type": "string"}}}}
+	}`)))
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "legacy", "1.0.0", []byte(`{"type": "object"}`)))
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "2.0.0", []byte(`{
+		"type": "object",
+		"required": ["url"],
+		"properties": {
+			"url": {"type": "string", "description": "Address of the backend"},
+			"timeout": {"type": "string"},
+			"compression": {"type": "string", "enum": ["gzip", "none", "snappy"]},
+			"retries": {"type": "number"},
+			"tls": {"$ref": "#/$defs/tls"},
+			"queue": {"type": "object", "required": ["size"], "properties": {"size": {"type": "integer"}}},
+			"headers": {"type": "array", "items": {"type": "object", "required": ["name", "value"], "properties": {"name": {"type": "string"}, "value": {"type": "string"}}}}
+		},
+		"$defs": {"tls": {"type": "object", "properties": {"insecure": {"type": "boolean"}}}}
+	}`)))
+
+	changes, err := manager.GetBreakingChanges("1.0.0", "2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaChange{
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeNarrowedType, Path: "compression", Message: "Removed the values `zstd` of `compression`"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRenamedField, Path: "endpoint", NewPath: "url", Message: "Renamed field `endpoint` to `url`"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRequiredField, Path: "headers[].name", Message: "Field `headers[].name` is required"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRequiredField, Path: "headers[].value", Message: "Field `headers[].value` is required"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRemovedField, Path: "legacy", Message: "Removed field `legacy`"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeNarrowedType, Path: "timeout", Message: "Changed the type of `timeout` from string|integer to string"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRemovedField, Path: "tls.ca_file", Message: "Removed field `tls.ca_file`"},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Kind: SchemaChangeRequiredField, Path: "url", Message: "Field `url` is required"},
+		{ComponentType: ComponentTypeReceiver, Component: "legacy", Kind: SchemaChangeRemovedComponent, Message: "Removed receiver `legacy`"},
+	}, changes.Changes)
+
+	assert.Equal(t, "# Breaking changes from 1.0.0 to 2.0.0\n\n"+
+		"## exporter/inhouse\n\n"+
+		"- Removed the values `zstd` of `compression`\n"+
+		"- Renamed field `endpoint` to `url`\n"+
+		"- Field `headers[].name` is required\n"+
+		"- Field `headers[].value` is required\n"+
+		"- Removed field `legacy`\n"+
+		"- Changed the type of `timeout` from string|integer to string\n"+
+		"- Removed field `tls.ca_file`\n"+
+		"- Field `url` is required\n"+
+		"\n## receiver/legacy\n\n"+
+		"- Removed receiver `legacy`\n", changes.Markdown())
+}
+
+func TestSchemaManager_GetBreakingChanges_EmbeddedVersions(t *testing.T) {
+	manager := NewSchemaManager()
+
+	changes, err := manager.GetBreakingChanges("0.139.0", "0.139.0")
+	require.NoError(t, err)
+	assert.Empty(t, changes.Changes)
+	assert.Equal(t, "# Breaking changes from 0.139.0 to 0.139.0\n\nNo breaking changes.\n", changes.Markdown())
+
+	_, err = manager.GetBreakingChanges("0.0.1", "0.139.0")
+	require.Error(t, err)
+	_, err = manager.GetBreakingChanges("0.139.0", "0.0.1")
+	require.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	"go.opentelemetry.io/collector/component"
+	"golang.org/x/mod/module"
+	"gopkg.in/yaml.v3"
+)
+
+// exampleConfigFile is the file of a component module with example configurations, most contrib components load
+// it in their config tests
+const exampleConfigFile = "testdata/config.yaml"
+
+// moduleCacheDir returns the module cache directory, testdata directories are not vendored
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	output, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// exampleConfigPath returns the path of the example configuration file of a module ("path version") in the module cache
+func exampleConfigPath(modCacheDir string, modulePath string) (string, error) {
+	parts := strings.Fields(modulePath)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid module path: %s", modulePath)
+	}
+	escapedPath, err := module.EscapePath(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %s: %w", parts[0], err)
+	}
+	escapedVersion, err := module.EscapeVersion(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid module version %s: %w", parts[1], err)
+	}
+	return filepath.Join(modCacheDir, escapedPath+"@"+escapedVersion, exampleConfigFile), nil
+}
+
+// exampleConfig is the configuration of a component in an example configuration file
+type exampleConfig struct {
+	// ID is the component ID of the configuration, e.g. otlp/withauth
+	ID     string
+	Config map[string]interface{}
+}
+
+// loadExampleConfigs returns the configurations of a component in an example configuration file ordered by
+// component ID. Files are either collector configurations with a section per category (e.g. receivers) or
+// hold the components by ID at the top level. Components without settings are skipped.
+func loadExampleConfigs(path string, componentCategory string, componentType component.Type) ([]map[string]interface{}, error) {
+	examples, err := loadExampleConfigsByID(path, componentCategory, componentType)
+	if err != nil {
+		return nil, err
+	}
+	var configs []map[string]interface{}
+	for _, example := range examples {
+		configs = append(configs, example.Config)
+	}
+	return configs, nil
+}
+
+// loadExampleConfigsByID returns the configurations of a component in an example configuration file with their
+// component IDs, see loadExampleConfigs
+func loadExampleConfigsByID(path string, componentCategory string, componentType component.Type) ([]exampleConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if section, ok := document[componentCategory+"s"].(map[string]interface{}); ok {
+		document = section
+	}
+
+	var ids []string
+	for id := range document {
+		if id == componentType.String() || strings.HasPrefix(id, componentType.String()+"/") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var examples []exampleConfig
+	for _, id := range ids {
+		if config, ok := jsonConfig(document[id]); ok {
+			examples = append(examples, exampleConfig{ID: id, Config: config})
+		}
+	}
+	return examples, nil
+}
+
+// jsonConfig converts a configuration decoded from YAML into JSON values, ok is false for empty configurations
+// and configurations that cannot be represented in JSON, e.g. maps with non-string keys
+func jsonConfig(value interface{}) (map[string]interface{}, bool) {
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+
+	// Numbers keep their literal form, e.g. large integers
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var config map[string]interface{}
+	if err := decoder.Decode(&config); err != nil || len(config) == 0 {
+		return nil, false
+	}
+	return config, true
+}
+
+// addExamples records the example configurations of a component in its schema. Modules without example
+// configurations, e.g. components declared in this module, are skipped.
+func (sg *SchemaGenerator) addExamples(schema *schemagen.Schema, componentCategory string, componentType component.Type, modulePath string) {
+	if sg.modCacheDir == "" || modulePath == "" {
+		return
+	}
+	path, err := exampleConfigPath(sg.modCacheDir, modulePath)
+	if err != nil {
+		sg.logger.Warn("Failed to locate examples", "component", ComponentID{Category: componentCategory, Type: componentType}, "error", err)
+		return
+	}
+	configs, err := loadExampleConfigs(path, componentCategory, componentType)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			sg.logger.Warn("Failed to load examples", "component", ComponentID{Category: componentCategory, Type: componentType}, "error", err)
+		}
+		return
+	}
+	for _, config := range configs {
+		schemagen.AddExamples(schema, config)
+	}
+}
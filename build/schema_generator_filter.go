@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// categories are the component categories of a distribution
+var categories = []string{"extension", "receiver", "processor", "exporter", "connector"}
+
+// ComponentFilter selects the components whose schemas are generated. Patterns are a category, e.g. receiver,
+// or a component ID, e.g. receiver/otlp. Schemas of other components are left untouched in the output directory.
+type ComponentFilter struct {
+	// Only restricts the generation to the matching components, an empty list selects all components
+	Only []string
+	// Exclude skips the matching components, e.g. heavy components while iterating on the generator
+	Exclude []string
+}
+
+// ParseComponentFilter parses comma separated lists of patterns, e.g. "receiver/otlp,exporter/kafka"
+func ParseComponentFilter(only string, exclude string) (ComponentFilter, error) {
+	var filter ComponentFilter
+	var err error
+	if filter.Only, err = parsePatterns(only); err != nil {
+		return ComponentFilter{}, err
+	}
+	if filter.Exclude, err = parsePatterns(exclude); err != nil {
+		return ComponentFilter{}, err
+	}
+	return filter, nil
+}
+
+// parsePatterns parses a comma separated list of categories and component IDs
+func parsePatterns(list string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(list, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		category, componentType, hasType := strings.Cut(pattern, "/")
+		if !contains(categories, category) || (hasType && componentType == "") {
+			return nil, fmt.Errorf("invalid component pattern %q, expected a category (%s) or <category>/<type>", pattern, strings.Join(categories, ", "))
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Includes returns whether the schema of a component is generated
+func (f ComponentFilter) Includes(id ComponentID) bool {
+	if len(f.Only) > 0 && !matchesAny(f.Only, id) {
+		return false
+	}
+	return !matchesAny(f.Exclude, id)
+}
+
+// matchesAny returns whether a component matches any of the patterns
+func matchesAny(patterns []string, id ComponentID) bool {
+	for _, pattern := range patterns {
+		if pattern == id.Category || pattern == id.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns whether a list contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
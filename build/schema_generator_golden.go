@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/otelcol"
+)
+
+// goldenDir holds the golden schemas of goldenComponents, see TestGoldenSchemas
+const goldenDir = "testdata/golden"
+
+// goldenComponents are the components whose generated schemas are compared with golden files: nested protocol
+// configurations (otlp receiver), durations and limits (batch processor) and squashed and shared definitions of a
+// large contrib configuration (kafka exporter). Their versions are pinned by go.mod.
+var goldenComponents = []ComponentID{
+	{Category: "receiver", Type: component.MustNewType("otlp")},
+	{Category: "processor", Type: component.MustNewType("batch")},
+	{Category: "exporter", Type: component.MustNewType("kafka")},
+}
+
+// goldenFilePath returns the path of the golden schema of a component, e.g. testdata/golden/receiver_otlp.json
+func goldenFilePath(id ComponentID) string {
+	return filepath.Join(goldenDir, fmt.Sprintf("%s_%s.json", id.Category, id.Type))
+}
+
+// goldenSchema generates the schema of a component as recorded in its golden file: the output of the generator with
+// the override of the component applied. Metadata and examples are not recorded, they change with the modules.
+func (sg *SchemaGenerator) goldenSchema(factories *otelcol.Factories, id ComponentID) ([]byte, error) {
+	factory := componentFactory(factories, id)
+	if factory == nil {
+		return nil, fmt.Errorf("component %s is not part of the distribution", id)
+	}
+	defaultConfig := factory.CreateDefaultConfig()
+	if defaultConfig == nil {
+		return nil, errNoConfig
+	}
+
+	schema, err := sg.generateJSONSchema(defaultConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	if schema, err = sg.applyOverrides(schema, id.Category, id.Type); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// componentFactory returns the factory of a component of a distribution, or nil
+func componentFactory(factories *otelcol.Factories, id ComponentID) component.Factory {
+	var factory component.Factory
+	var ok bool
+	switch id.Category {
+	case "extension":
+		factory, ok = factories.Extensions[id.Type]
+	case "receiver":
+		factory, ok = factories.Receivers[id.Type]
+	case "processor":
+		factory, ok = factories.Processors[id.Type]
+	case "exporter":
+		factory, ok = factories.Exporters[id.Type]
+	case "connector":
+		factory, ok = factories.Connectors[id.Type]
+	}
+	if !ok {
+		return nil
+	}
+	return factory
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// lockFileName is the file of an output directory that records what its schemas were generated from, it has no
+// .json extension so it is neither embedded nor counted as a schema
+const lockFileName = "schemas.lock"
+
+// schemagenPackage is the package of the generator, schemas are regenerated when its sources change
+const schemagenPackage = "github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+
+// schemaLock records the generator and the modules ("path version") of the components of the last generation
+type schemaLock struct {
+	// Generator is the fingerprint of the generator sources
+	Generator string `json:"generator"`
+	// Components maps component IDs (e.g. receiver/otlp) to their modules
+	Components map[string]string `json:"components"`
+}
+
+// readSchemaLock reads a lock file, a missing file is an empty lock
+func readSchemaLock(path string) (*schemaLock, error) {
+	lock := &schemaLock{Components: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if lock.Components == nil {
+		lock.Components = make(map[string]string)
+	}
+	return lock, nil
+}
+
+// write writes the lock file, components are sorted so regenerations produce minimal diffs
+func (l *schemaLock) write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// update records the module of a component whose schema was generated and forgets components without a schema.
+// Components without a module version are declared in the build module and are always regenerated.
+func (l *schemaLock) update(report *GenerationReport, modules map[ComponentID]string) {
+	for _, id := range report.Succeeded {
+		if module := modules[id]; len(strings.Fields(module)) == 2 {
+			l.Components[id.String()] = module
+		} else {
+			delete(l.Components, id.String())
+		}
+	}
+	for _, failures := range [][]ComponentFailure{report.Skipped, report.Failed, report.Invalid} {
+		for _, failure := range failures {
+			delete(l.Components, failure.Component.String())
+		}
+	}
+}
+
+// generatorSources matches the sources of the build tool that shape the generated schemas, e.g. the metadata,
+// examples, operators and overrides of the components
+const generatorSources = "schema_generator*.go"
+
+// generatorFingerprint returns a hash of the Go sources of the generator, the schemagen package and the generator
+// files of the build tool, or an empty string if they cannot be located, e.g. without a go command. The lock of an
+// empty fingerprint never matches.
+func generatorFingerprint() string {
+	output, err := exec.Command("go", "list", "-f", "{{.Dir}}", schemagenPackage).Output()
+	if err != nil {
+		return ""
+	}
+	buildDir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.New()
+	for _, pattern := range []string{filepath.Join(strings.TrimSpace(string(output)), "*.go"), filepath.Join(buildDir, generatorSources)} {
+		if !hashSources(hash, pattern) {
+			return ""
+		}
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashSources writes the Go sources matching a pattern without tests to a hash, it returns false if no source
+// matches or a source cannot be read
+func hashSources(hash io.Writer, pattern string) bool {
+	files, err := filepath.Glob(pattern)
+	if err != nil || len(files) == 0 {
+		return false
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(hash, "%s/%s %d\n", filepath.Base(filepath.Dir(file)), filepath.Base(file), len(data))
+		hash.Write(data)
+	}
+	return true
+}
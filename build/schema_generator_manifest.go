@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// manifestFileName is the provenance manifest of an output directory, it is embedded with the schemas and read by
+// GetVersionManifest
+const manifestFileName = "manifest.json"
+
+// versionManifest is the provenance of the schemas of an output directory, see VersionManifest of the library
+type versionManifest struct {
+	Version     string            `json:"version"`
+	Generator   string            `json:"generator,omitempty"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Modules     map[string]string `json:"modules"`
+	Components  map[string]int    `json:"components"`
+}
+
+// writeVersionManifest writes the provenance manifest of the output directory. The version is the name of the
+// directory, e.g. 0.139.0, and components are counted from the schema files so schemas kept by incremental or
+// filtered generations are included.
+func (sg *SchemaGenerator) writeVersionManifest(generatedAt time.Time) error {
+	manifest := versionManifest{
+		Version:     filepath.Base(sg.outputDir),
+		Generator:   sg.lock.Generator,
+		GeneratedAt: generatedAt.UTC().Truncate(time.Second),
+		Modules:     make(map[string]string),
+		Components:  make(map[string]int),
+	}
+	for _, module := range sg.modules {
+		if parts := strings.Fields(module); len(parts) == 2 {
+			manifest.Modules[parts[0]] = parts[1]
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(sg.outputDir, "*_*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list schema files: %w", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		category, _, _ := strings.Cut(filepath.Base(file), "_")
+		manifest.Components[category]++
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(sg.outputDir, manifestFileName), append(data, '\n'), 0644)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/otelcol"
+)
+
+// metadataKeyword is the keyword of the generated schemas that holds the component metadata
+const metadataKeyword = "x-otel"
+
+// signals are the signals of collector pipelines in the order they are listed in the metadata
+var signals = []string{"traces", "metrics", "logs", "profiles"}
+
+// repositories maps module path prefixes to the GitHub repositories they are released from
+var repositories = map[string]string{
+	"github.com/open-telemetry/opentelemetry-collector-contrib/": "https://github.com/open-telemetry/opentelemetry-collector-contrib",
+	"go.opentelemetry.io/collector/":                             "https://github.com/open-telemetry/opentelemetry-collector",
+}
+
+// componentMetadata is the metadata of a component recorded in its schema
+type componentMetadata struct {
+	Module        string            `json:"module,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Signals       []string          `json:"signals,omitempty"`
+	Stability     map[string]string `json:"stability,omitempty"`
+	Documentation string            `json:"documentation,omitempty"`
+}
+
+// moduleIndex returns the modules ("path version") of all components of a distribution
+func moduleIndex(factories *otelcol.Factories) map[ComponentID]string {
+	modules := make(map[ComponentID]string)
+	for category, categoryModules := range map[string]map[component.Type]string{
+		"extension": factories.ExtensionModules,
+		"receiver":  factories.ReceiverModules,
+		"processor": factories.ProcessorModules,
+		"exporter":  factories.ExporterModules,
+		"connector": factories.ConnectorModules,
+	} {
+		for componentType, module := range categoryModules {
+			modules[ComponentID{Category: category, Type: componentType}] = module
+		}
+	}
+	return modules
+}
+
+// newComponentMetadata returns the metadata of a component from its factory and module ("path version")
+func newComponentMetadata(componentCategory string, factory component.Factory, module string) componentMetadata {
+	metadata := componentMetadata{Stability: make(map[string]string)}
+	if parts := strings.Fields(module); len(parts) == 2 {
+		metadata.Module, metadata.Version = parts[0], parts[1]
+		metadata.Documentation = documentationURL(metadata.Module, metadata.Version)
+	}
+
+	// Stability keys follow the metadata.yaml of collector components: the signal, from_to_to for connectors
+	// and extension for extensions. Unsupported signals have an undefined stability.
+	if componentCategory == "extension" {
+		addStability(metadata.Stability, "extension", factory, "Stability")
+		return metadata
+	}
+	for _, signal := range signals {
+		if componentCategory != "connector" {
+			if addStability(metadata.Stability, signal, factory, title(signal)+"Stability") {
+				metadata.Signals = append(metadata.Signals, signal)
+			}
+			continue
+		}
+
+		supported := false
+		for _, other := range signals {
+			supported = addStability(metadata.Stability, signal+"_to_"+other, factory, title(signal)+"To"+title(other)+"Stability") || supported
+			supported = addStability(metadata.Stability, other+"_to_"+signal, factory, title(other)+"To"+title(signal)+"Stability") || supported
+		}
+		if supported {
+			metadata.Signals = append(metadata.Signals, signal)
+		}
+	}
+	return metadata
+}
+
+// addStability adds the stability level returned by a factory method, it returns false if the factory does not
+// have the method or the stability is undefined
+func addStability(stability map[string]string, key string, factory component.Factory, methodName string) bool {
+	method := reflect.ValueOf(factory).MethodByName(methodName)
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return false
+	}
+	level, ok := method.Call(nil)[0].Interface().(component.StabilityLevel)
+	if !ok || level == component.StabilityLevelUndefined {
+		return false
+	}
+	stability[key] = strings.ToLower(level.String())
+	return true
+}
+
+// documentationURL returns the URL of the sources of a module at its version, or an empty string for modules
+// that are not released from a known repository
+func documentationURL(module string, version string) string {
+	for prefix, repository := range repositories {
+		if path, found := strings.CutPrefix(module, prefix); found {
+			return fmt.Sprintf("%s/tree/%s/%s", repository, version, path)
+		}
+	}
+	return ""
+}
+
+// title returns a signal name with an upper case first letter, e.g. Traces
+func title(signal string) string {
+	return strings.ToUpper(signal[:1]) + signal[1:]
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+)
+
+// operatorTypes are the types of the stanza operators that process entries in the operators of log receivers, e.g.
+// filelog, in the order they are listed in the generated oneOf. Types that are not registered by the operator packages
+// linked into the distribution are skipped.
+var operatorTypes = []string{
+	// Parsers
+	"container", "csv_parser", "json_array_parser", "json_parser", "key_value_parser", "regex_parser",
+	"scope_name_parser", "severity_parser", "syslog_parser", "time_parser", "trace_parser", "uri_parser",
+	// Transformers
+	"add", "assign_keys", "copy", "filter", "flatten", "move", "noop", "recombine", "regex_replace", "remove",
+	"retain", "router", "unquote",
+	// Outputs
+	"drop_output", "file_output", "stdout",
+}
+
+// operatorsOption renders the stanza operator.Config, which decodes the builder registered for its "type", as a oneOf
+// of the configurations of the registered operators discriminated by their type
+func operatorsOption() schemagen.Option {
+	var variants []schemagen.Implementation
+	for _, operatorType := range operatorTypes {
+		newBuilder, ok := operator.Lookup(operatorType)
+		if !ok {
+			continue
+		}
+		variants = append(variants, schemagen.Implementation{Name: operatorType, Type: reflect.TypeOf(newBuilder())})
+	}
+
+	configType := reflect.TypeOf(operator.Config{})
+	return schemagen.WithTypeMapping(schemagen.TypeMapping{
+		PkgPath:  configType.PkgPath(),
+		TypeName: configType.Name(),
+		Mapper:   schemagen.Variants("type", variants...),
+	})
+}
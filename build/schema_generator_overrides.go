@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	"go.opentelemetry.io/collector/component"
+)
+
+// SetOverridesDir sets the directory of the hand-maintained override files that are applied to the generated
+// schemas, e.g. to add enums, unions or descriptions reflection cannot derive. See applyOverrides.
+func (sg *SchemaGenerator) SetOverridesDir(dir string) {
+	sg.overridesDir = dir
+}
+
+// overridePath returns the path of the override file of a component, it is named like the schema file
+func (sg *SchemaGenerator) overridePath(componentCategory string, componentType component.Type) string {
+	return filepath.Join(sg.overridesDir, fmt.Sprintf("%s_%s.json", componentCategory, componentType))
+}
+
+// hasOverride returns whether a component has an override file
+func (sg *SchemaGenerator) hasOverride(componentCategory string, componentType component.Type) bool {
+	if sg.overridesDir == "" {
+		return false
+	}
+	_, err := os.Stat(sg.overridePath(componentCategory, componentType))
+	return err == nil
+}
+
+// applyOverrides applies the override file of a component to its generated schema. A JSON object is applied as
+// a JSON Merge Patch (RFC 7396), a JSON array as a JSON Patch (RFC 6902). Schemas of components without an
+// override file are returned unchanged.
+func (sg *SchemaGenerator) applyOverrides(schema *schemagen.Schema, componentCategory string, componentType component.Type) (*schemagen.Schema, error) {
+	if sg.overridesDir == "" {
+		return schema, nil
+	}
+	path := sg.overridePath(componentCategory, componentType)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return schema, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read override: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		schema, err = schemagen.ApplyJSONPatch(schema, data)
+	} else {
+		schema, err = schemagen.ApplyMergePatch(schema, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply override %s: %w", filepath.Base(path), err)
+	}
+	sg.logger.Debug("Applied override", "component", ComponentID{Category: componentCategory, Type: componentType}, "file", filepath.Base(path))
+	return schema, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// ComponentID identifies a component of the distribution, e.g. receiver otlp
+type ComponentID struct {
+	Category string
+	Type     component.Type
+}
+
+// String returns the component as category/type, e.g. receiver/otlp
+func (c ComponentID) String() string {
+	return fmt.Sprintf("%s/%s", c.Category, c.Type)
+}
+
+// ComponentFailure is a component that was skipped or failed and the reason
+type ComponentFailure struct {
+	Component ComponentID
+	Err       error
+}
+
+// GenerationReport is the outcome of generating the schemas of all components
+type GenerationReport struct {
+	// Succeeded components have a valid schema
+	Succeeded []ComponentID
+	// Unchanged components kept the schema of an earlier generation, see SchemaGenerator.SetIncremental
+	Unchanged []ComponentID
+	// Skipped components have no configuration
+	Skipped []ComponentFailure
+	// Failed components have no schema
+	Failed []ComponentFailure
+	// Invalid components have a schema that is not a valid JSON schema
+	Invalid []ComponentFailure
+	// Profiles are the generated components that implement the experimental profiles signal, e.g. the
+	// factories of the xreceiver and xexporter packages
+	Profiles []ComponentID
+}
+
+// record records the outcome of generating the schema of a component
+func (r *GenerationReport) record(category string, componentType component.Type, err error) {
+	id := ComponentID{Category: category, Type: componentType}
+	switch {
+	case err == nil:
+		r.Succeeded = append(r.Succeeded, id)
+	case errors.Is(err, errNoConfig):
+		r.Skipped = append(r.Skipped, ComponentFailure{Component: id, Err: err})
+	default:
+		r.Failed = append(r.Failed, ComponentFailure{Component: id, Err: err})
+	}
+}
+
+// invalidate moves a succeeded component whose schema is not valid to the invalid components
+func (r *GenerationReport) invalidate(id ComponentID, err error) {
+	for i, succeeded := range r.Succeeded {
+		if succeeded == id {
+			r.Succeeded = append(r.Succeeded[:i], r.Succeeded[i+1:]...)
+			break
+		}
+	}
+	r.Invalid = append(r.Invalid, ComponentFailure{Component: id, Err: err})
+}
+
+// Err returns the failed and invalid components as one error, or nil if all schemas were generated
+func (r *GenerationReport) Err() error {
+	var errs []error
+	for _, failure := range append(append([]ComponentFailure(nil), r.Failed...), r.Invalid...) {
+		errs = append(errs, fmt.Errorf("%s: %w", failure.Component, failure.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Print writes the number of generated schemas and the skipped, failed and invalid components
+func (r *GenerationReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "Generated %d schemas: %d skipped, %d failed, %d invalid\n",
+		len(r.Succeeded), len(r.Skipped), len(r.Failed), len(r.Invalid))
+	if len(r.Unchanged) > 0 {
+		fmt.Fprintf(w, "Kept %d unchanged schemas\n", len(r.Unchanged))
+	}
+	if len(r.Profiles) > 0 {
+		fmt.Fprintf(w, "%d components support the profiles signal\n", len(r.Profiles))
+	}
+
+	for _, section := range []struct {
+		title    string
+		failures []ComponentFailure
+	}{
+		{"Skipped", r.Skipped},
+		{"Failed", r.Failed},
+		{"Invalid", r.Invalid},
+	} {
+		if len(section.failures) == 0 {
+			continue
+		}
+		sort.Slice(section.failures, func(i, j int) bool {
+			return section.failures[i].Component.String() < section.failures[j].Component.String()
+		})
+		fmt.Fprintf(w, "%s:\n", section.title)
+		for _, failure := range section.failures {
+			fmt.Fprintf(w, "  %s: %v\n", failure.Component, failure.Err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+)
+
+// knownRoundTripFailuresFile lists the example configurations that are expected to be rejected, e.g. invalid
+// configurations of the component tests
+const knownRoundTripFailuresFile = "roundtrip_known_failures.txt"
+
+// RoundTripFailure is an example configuration of a component test that the generated schema of the component
+// rejects, a false negative of the generator unless the example is invalid on purpose
+type RoundTripFailure struct {
+	Component ComponentID
+	// ID is the component ID of the configuration in the example file, e.g. otlp/withauth
+	ID  string
+	Err error
+}
+
+// Key returns the key of the failure in the known failures file, the category and the component ID of the
+// configuration, e.g. receiver/otlp/withauth
+func (f RoundTripFailure) Key() string {
+	return f.Component.Category + "/" + f.ID
+}
+
+// RoundTripReport is the outcome of validating the example configurations of the component tests against the
+// generated schemas
+type RoundTripReport struct {
+	// Validated is the number of example configurations the schemas accept
+	Validated int
+	// Components is the number of components with example configurations
+	Components int
+	// Failures are the rejected configurations that are not known failures
+	Failures []RoundTripFailure
+	// Known are the rejected configurations listed in the known failures file
+	Known []RoundTripFailure
+	// Fixed are known failures whose configuration the schema accepts now, they should be removed from the file
+	Fixed []string
+}
+
+// RoundTripSchemas validates the example configurations of the component tests (testdata/config.yaml of the
+// component modules in the module cache, converted to JSON) against the schemas generated in schemaDir. A rejected
+// configuration is a false negative of the generator, e.g. a field that is missing from the schema or a wrong type.
+// Known failures are keyed like RoundTripFailure.Key. Components without a schema or examples are skipped.
+func RoundTripSchemas(schemaDir string, modules map[ComponentID]string, modCacheDir string, known map[string]bool) (*RoundTripReport, error) {
+	ids := make([]ComponentID, 0, len(modules))
+	for id := range modules {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	report := &RoundTripReport{}
+	rejected := make(map[string]bool)
+	for _, id := range ids {
+		if modules[id] == "" {
+			continue
+		}
+		schema, err := os.ReadFile(filepath.Join(schemaDir, fmt.Sprintf("%s_%s.json", id.Category, id.Type)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema of %s: %w", id, err)
+		}
+
+		path, err := exampleConfigPath(modCacheDir, modules[id])
+		if err != nil {
+			return nil, err
+		}
+		examples, err := loadExampleConfigsByID(path, id.Category, id.Type)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(examples) > 0 {
+			report.Components++
+		}
+
+		for _, example := range examples {
+			if err := schemagen.ValidateInstance(schema, example.Config); err != nil {
+				failure := RoundTripFailure{Component: id, ID: example.ID, Err: err}
+				rejected[failure.Key()] = true
+				if known[failure.Key()] {
+					report.Known = append(report.Known, failure)
+				} else {
+					report.Failures = append(report.Failures, failure)
+				}
+				continue
+			}
+			report.Validated++
+		}
+	}
+
+	for key := range known {
+		if !rejected[key] {
+			report.Fixed = append(report.Fixed, key)
+		}
+	}
+	sort.Strings(report.Fixed)
+	return report, nil
+}
+
+// readKnownRoundTripFailures reads a known failures file, one key per line (see RoundTripFailure.Key), # starts a
+// comment. A missing file lists no failures.
+func readKnownRoundTripFailures(path string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return known, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			known[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return known, nil
+}
+
+// Err returns the failures as one error, or nil if the schemas accept every example that is not a known failure
+func (r *RoundTripReport) Err() error {
+	var errs []error
+	for _, failure := range r.Failures {
+		errs = append(errs, fmt.Errorf("%s: %w", failure.Key(), failure.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Print writes the number of validated examples, the failures with the locations the schemas reject and the known
+// failures that are fixed
+func (r *RoundTripReport) Print(w io.Writer) {
+	fmt.Fprintf(w, "Validated %d examples of %d components: %d false negatives, %d known failures\n",
+		r.Validated, r.Components, len(r.Failures), len(r.Known))
+	if len(r.Failures) > 0 {
+		fmt.Fprintln(w, "False negatives:")
+		for _, failure := range r.Failures {
+			fmt.Fprintf(w, "  %s (%s):\n", failure.Key(), failure.Component)
+			for _, line := range strings.Split(failure.Err.Error(), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+	if len(r.Fixed) > 0 {
+		fmt.Fprintf(w, "Known failures that pass now, remove them from %s:\n", knownRoundTripFailuresFile)
+		for _, key := range r.Fixed {
+			fmt.Fprintf(w, "  %s\n", key)
+		}
+	}
+}
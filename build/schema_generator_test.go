@@ -1,16 +1,37 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/xreceiver"
 )
 
+// The progress of TestGenerateAllSchemas is logged at info level, e.g. go test -run TestGenerateAllSchemas -quiet
+var (
+	quiet   = flag.Bool("quiet", false, "only log warnings of the schema generation")
+	verbose = flag.Bool("verbose", false, "log every generated schema and copied README")
+)
+
+// updateGolden rewrites the golden schemas of TestGoldenSchemas, e.g. go test -run TestGoldenSchemas -update-golden
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden schemas in testdata/golden")
+
 // TestGenerateAllSchemas tests the schema generator by generating JSON schemas for all components
 func TestGenerateAllSchemas(t *testing.T) {
 	// Get output directory from environment variable, fallback to default
@@ -19,13 +40,51 @@ func TestGenerateAllSchemas(t *testing.T) {
 		schemaOutputDir = "test-schemas"
 	}
 
+	// Strict schemas reject unknown keys with additionalProperties:false
+	strict := os.Getenv("SCHEMA_STRICT") == "true"
+
+	// Components without a schema fail the generation instead of only being reported
+	failOnError := os.Getenv("SCHEMA_FAIL_ON_ERROR") == "true"
+
+	// Field comments are cached next to the output so regenerating schemas does not re-parse unchanged module sources
+	commentCacheDir := os.Getenv("SCHEMA_COMMENT_CACHE_DIR")
+	if commentCacheDir == "" {
+		commentCacheDir = filepath.Join(filepath.Dir(schemaOutputDir), ".cache")
+	}
+
+	// Restrict the generation to some components, e.g. SCHEMA_ONLY=receiver/otlp,exporter SCHEMA_EXCLUDE=exporter/kafka
+	filter, err := ParseComponentFilter(os.Getenv("SCHEMA_ONLY"), os.Getenv("SCHEMA_EXCLUDE"))
+	if err != nil {
+		t.Fatalf("Invalid component filter: %v", err)
+	}
+
+	// Only components whose module changed since the generation recorded in schemas.lock are regenerated
+	incremental := os.Getenv("SCHEMA_INCREMENTAL") == "true"
+
+	// Tools that only support draft-07 use the copies written to a parallel directory, e.g. ../schemas-draft-07/0.139.0
+	draft07OutputDir := os.Getenv("SCHEMA_DRAFT07_OUTPUT_DIR")
+
+	// Hand-maintained overrides correct known gaps of the generated schemas, e.g. ../overrides/receiver_otlp.json
+	overridesDir := os.Getenv("SCHEMA_OVERRIDES_DIR")
+	if overridesDir == "" {
+		overridesDir = filepath.Join("..", "overrides")
+	}
+
 	// Create schema generator
-	generator := NewSchemaGenerator(schemaOutputDir)
+	generator := NewSchemaGenerator(schemaOutputDir, schemagen.WithStrict(strict), schemagen.WithCommentCacheDir(commentCacheDir))
+	generator.SetFailOnError(failOnError)
+	generator.SetFilter(filter)
+	generator.SetIncremental(incremental)
+	generator.SetDraft07OutputDir(draft07OutputDir)
+	generator.SetOverridesDir(overridesDir)
+	generator.SetLogger(NewLogger(os.Stdout, *quiet, *verbose))
 
 	// Generate all schemas
-	if err := generator.GenerateAllSchemas(); err != nil {
+	report, err := generator.GenerateAllSchemas()
+	if err != nil {
 		t.Fatalf("Failed to generate schemas: %v", err)
 	}
+	t.Logf("Generated %d schemas, kept %d, skipped %d and failed %d components", len(report.Succeeded), len(report.Unchanged), len(report.Skipped), len(report.Failed))
 
 	// Verify that schemas were created
 	if err := verifyGeneratedSchemas(t, schemaOutputDir); err != nil {
@@ -161,15 +220,15 @@ func TestSchemaFormat(t *testing.T) {
 		}
 
 		// Verify required schema fields
-		if schema["$schema"] == nil {
+		if schema.Schema == "" {
 			t.Error("Schema missing $schema field")
 		}
 
-		if schema["type"] != "object" {
+		if !schema.Type.Is("object") {
 			t.Error("Schema type should be 'object'")
 		}
 
-		if schema["properties"] == nil {
+		if schema.Properties == nil {
 			t.Error("Schema missing properties field")
 		}
 
@@ -179,6 +238,559 @@ func TestSchemaFormat(t *testing.T) {
 	}
 }
 
+// TestGenerationReport tests that skipped, failed and invalid components are listed in the report
+func TestGenerationReport(t *testing.T) {
+	kafka := ComponentID{Category: "receiver", Type: component.MustNewType("kafka")}
+
+	report := &GenerationReport{}
+	report.record("receiver", component.MustNewType("otlp"), nil)
+	report.record(kafka.Category, kafka.Type, nil)
+	report.record("exporter", component.MustNewType("nop"), fmt.Errorf("failed: %w", errNoConfig))
+	report.record("processor", component.MustNewType("transform"), errors.New("unsupported type"))
+	report.invalidate(kafka, errors.New("invalid schema"))
+
+	if len(report.Succeeded) != 1 || report.Succeeded[0].String() != "receiver/otlp" {
+		t.Errorf("Unexpected succeeded components: %v", report.Succeeded)
+	}
+	if err := report.Err(); err == nil || err.Error() != "processor/transform: unsupported type\nreceiver/kafka: invalid schema" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	var output bytes.Buffer
+	report.Print(&output)
+
+	expected := strings.Join([]string{
+		"Generated 1 schemas: 1 skipped, 1 failed, 1 invalid",
+		"Skipped:",
+		"  exporter/nop: failed: factory returned nil config",
+		"Failed:",
+		"  processor/transform: unsupported type",
+		"Invalid:",
+		"  receiver/kafka: invalid schema",
+		"",
+	}, "\n")
+	if output.String() != expected {
+		t.Errorf("Unexpected report:\n%s", output.String())
+	}
+
+	output.Reset()
+	(&GenerationReport{Succeeded: report.Succeeded, Profiles: report.Succeeded}).Print(&output)
+	if !strings.Contains(output.String(), "1 components support the profiles signal\n") {
+		t.Errorf("Expected the profiles components in the report:\n%s", output.String())
+	}
+
+	if err := (&GenerationReport{Succeeded: report.Succeeded}).Err(); err != nil {
+		t.Errorf("Expected no error without failed components, got %v", err)
+	}
+}
+
+// TestComponentMetadata tests the signals, stability and module recorded for a component
+func TestComponentMetadata(t *testing.T) {
+	metadata := newComponentMetadata("receiver", NewFactory(), "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/testreceiver v0.139.0")
+
+	expected := componentMetadata{
+		Module:        "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/testreceiver",
+		Version:       "v0.139.0",
+		Signals:       []string{"traces", "metrics", "logs"},
+		Stability:     map[string]string{"traces": "development", "metrics": "development", "logs": "development"},
+		Documentation: "https://github.com/open-telemetry/opentelemetry-collector-contrib/tree/v0.139.0/receiver/testreceiver",
+	}
+	if !reflect.DeepEqual(expected, metadata) {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+
+	if url := documentationURL("go.opentelemetry.io/collector/receiver/otlpreceiver", "v0.139.0"); url != "https://github.com/open-telemetry/opentelemetry-collector/tree/v0.139.0/receiver/otlpreceiver" {
+		t.Errorf("Unexpected documentation URL: %s", url)
+	}
+	if url := documentationURL("example.com/inhouse/receiver", "v1.0.0"); url != "" {
+		t.Errorf("Expected no documentation URL for unknown modules, got %s", url)
+	}
+}
+
+// TestComponentMetadataProfiles tests that the profiles signal of experimental factories is recorded
+func TestComponentMetadataProfiles(t *testing.T) {
+	factory := xreceiver.NewFactory(
+		TestComponentType,
+		CreateDefaultConfig,
+		xreceiver.WithTraces(createTracesReceiver, component.StabilityLevelBeta),
+		xreceiver.WithProfiles(nil, component.StabilityLevelDevelopment),
+	)
+	metadata := newComponentMetadata("receiver", factory, "")
+
+	if expected := []string{"traces", "profiles"}; !reflect.DeepEqual(expected, metadata.Signals) {
+		t.Errorf("Unexpected signals: %v", metadata.Signals)
+	}
+	if expected := map[string]string{"traces": "beta", "profiles": "development"}; !reflect.DeepEqual(expected, metadata.Stability) {
+		t.Errorf("Unexpected stability: %v", metadata.Stability)
+	}
+}
+
+// TestOperatorsOption tests that stanza operators are rendered as a union discriminated by their type
+func TestOperatorsOption(t *testing.T) {
+	schema, err := schemagen.GenerateSchema(struct {
+		Operators []operator.Config `mapstructure:"operators"`
+	}{}, schemagen.WithComments(false), operatorsOption())
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	items := schema["properties"].(map[string]interface{})["operators"].(map[string]interface{})["items"].(map[string]interface{})
+	options, _ := items["oneOf"].([]interface{})
+	types := map[string]bool{}
+	for _, option := range options {
+		discriminator := option.(map[string]interface{})["properties"].(map[string]interface{})["type"].(map[string]interface{})
+		types[fmt.Sprint(discriminator["const"])] = true
+	}
+	for _, operatorType := range []string{"regex_parser", "json_parser", "move", "router"} {
+		if !types[operatorType] {
+			t.Errorf("Expected operator %s in %v", operatorType, types)
+		}
+	}
+}
+
+func TestLoadExampleConfigs(t *testing.T) {
+	configs, err := loadExampleConfigs(filepath.Join("testdata", "example_config.yaml"), "receiver", component.MustNewType("testreceiver"))
+	if err != nil {
+		t.Fatalf("Failed to load examples: %v", err)
+	}
+
+	// Components without settings are skipped
+	expected := []map[string]interface{}{{
+		"database":            map[string]interface{}{"host": "db.example.com", "port": json.Number("5433")},
+		"collection_interval": "30s",
+	}}
+	if !reflect.DeepEqual(expected, configs) {
+		t.Errorf("Unexpected examples: %+v", configs)
+	}
+
+	path, err := exampleConfigPath("/go/pkg/mod", "github.com/IBM/sarama v1.46.0")
+	if err != nil {
+		t.Fatalf("Failed to locate examples: %v", err)
+	}
+	if path != filepath.Join("/go/pkg/mod", "github.com/!i!b!m/sarama@v1.46.0", "testdata", "config.yaml") {
+		t.Errorf("Unexpected path: %s", path)
+	}
+}
+
+// TestRoundTripSchemas validates the example configurations of the component tests against the schemas generated
+// in SCHEMA_OUTPUT_DIR and fails on false negatives, it gates publishing a new version, see make roundtrip-schemas
+func TestRoundTripSchemas(t *testing.T) {
+	schemaOutputDir := os.Getenv("SCHEMA_OUTPUT_DIR")
+	if schemaOutputDir == "" {
+		t.Skip("SCHEMA_OUTPUT_DIR is not set")
+	}
+	knownFailuresPath := os.Getenv("SCHEMA_ROUNDTRIP_KNOWN_FAILURES")
+	if knownFailuresPath == "" {
+		knownFailuresPath = knownRoundTripFailuresFile
+	}
+
+	factories, err := components()
+	if err != nil {
+		t.Fatalf("Failed to get component factories: %v", err)
+	}
+	modCacheDir := moduleCacheDir()
+	if modCacheDir == "" {
+		t.Fatal("Failed to locate the module cache")
+	}
+	known, err := readKnownRoundTripFailures(knownFailuresPath)
+	if err != nil {
+		t.Fatalf("Failed to read known failures: %v", err)
+	}
+
+	report, err := RoundTripSchemas(schemaOutputDir, moduleIndex(&factories), modCacheDir, known)
+	if err != nil {
+		t.Fatalf("Failed to validate examples: %v", err)
+	}
+	report.Print(os.Stdout)
+	if len(report.Failures) > 0 {
+		t.Fatalf("%d examples are rejected by their schema", len(report.Failures))
+	}
+}
+
+func TestRoundTripReport(t *testing.T) {
+	schemaDir := t.TempDir()
+	schema := `{"type":"object","properties":{"database":{"type":"object","properties":{"port":{"type":"integer","maximum":5000}}}}}`
+	if err := os.WriteFile(filepath.Join(schemaDir, "receiver_testreceiver.json"), []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	// The example file is read from the module directory in the module cache
+	modCacheDir := t.TempDir()
+	exampleDir := filepath.Join(modCacheDir, "example.com", "testreceiver@v1.0.0", "testdata")
+	if err := os.MkdirAll(exampleDir, 0755); err != nil {
+		t.Fatalf("Failed to create module directory: %v", err)
+	}
+	example, err := os.ReadFile(filepath.Join("testdata", "example_config.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read examples: %v", err)
+	}
+	example = append(example, []byte("  testreceiver/valid:\n    database:\n      port: 4317\n")...)
+	if err := os.WriteFile(filepath.Join(exampleDir, "config.yaml"), example, 0644); err != nil {
+		t.Fatalf("Failed to write examples: %v", err)
+	}
+
+	testReceiver := ComponentID{Category: "receiver", Type: component.MustNewType("testreceiver")}
+	modules := map[ComponentID]string{
+		testReceiver: "example.com/testreceiver v1.0.0",
+		{Category: "exporter", Type: component.MustNewType("debug")}: "example.com/debugexporter v1.0.0",
+	}
+
+	report, err := RoundTripSchemas(schemaDir, modules, modCacheDir, map[string]bool{})
+	if err != nil {
+		t.Fatalf("Failed to validate examples: %v", err)
+	}
+	if report.Validated != 1 || report.Components != 1 || len(report.Failures) != 1 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if report.Failures[0].Key() != "receiver/testreceiver/custom" || !strings.Contains(report.Failures[0].Err.Error(), "/database/port") {
+		t.Errorf("Unexpected failure: %s: %v", report.Failures[0].Key(), report.Failures[0].Err)
+	}
+	if report.Err() == nil {
+		t.Error("Expected an error for the false negative")
+	}
+
+	// Known failures are reported separately, known failures that pass are reported as fixed
+	report, err = RoundTripSchemas(schemaDir, modules, modCacheDir, map[string]bool{"receiver/testreceiver/custom": true, "receiver/testreceiver/valid": true})
+	if err != nil {
+		t.Fatalf("Failed to validate examples: %v", err)
+	}
+	if len(report.Failures) != 0 || len(report.Known) != 1 || !reflect.DeepEqual([]string{"receiver/testreceiver/valid"}, report.Fixed) {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	var output bytes.Buffer
+	report.Print(&output)
+	if !strings.HasPrefix(output.String(), "Validated 1 examples of 1 components: 0 false negatives, 1 known failures\n") {
+		t.Errorf("Unexpected output: %s", output.String())
+	}
+}
+
+func TestReadKnownRoundTripFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), knownRoundTripFailuresFile)
+	if err := os.WriteFile(path, []byte("# header\nreceiver/otlp/invalid # invalid on purpose\n\nexporter/kafka\n"), 0644); err != nil {
+		t.Fatalf("Failed to write known failures: %v", err)
+	}
+	known, err := readKnownRoundTripFailures(path)
+	if err != nil {
+		t.Fatalf("Failed to read known failures: %v", err)
+	}
+	if !reflect.DeepEqual(map[string]bool{"receiver/otlp/invalid": true, "exporter/kafka": true}, known) {
+		t.Errorf("Unexpected known failures: %v", known)
+	}
+
+	known, err = readKnownRoundTripFailures(filepath.Join(t.TempDir(), "missing.txt"))
+	if err != nil || len(known) != 0 {
+		t.Errorf("Expected no known failures for a missing file: %v %v", known, err)
+	}
+}
+
+// TestGoldenSchemas regenerates the schemas of a pinned set of components and compares them with the golden files in
+// testdata/golden, changes of the generator that change the shape of the schemas fail it. Review the differences and
+// rewrite the golden files with -update-golden if they are intended.
+func TestGoldenSchemas(t *testing.T) {
+	factories, err := components()
+	if err != nil {
+		t.Fatalf("Failed to get component factories: %v", err)
+	}
+	generator := NewSchemaGenerator(t.TempDir())
+	generator.SetOverridesDir(filepath.Join("..", "overrides"))
+
+	for _, id := range goldenComponents {
+		t.Run(id.String(), func(t *testing.T) {
+			actual, err := generator.goldenSchema(&factories, id)
+			if err != nil {
+				t.Fatalf("Failed to generate schema: %v", err)
+			}
+
+			path := goldenFilePath(id)
+			if *updateGolden {
+				if err := os.MkdirAll(goldenDir, 0755); err != nil {
+					t.Fatalf("Failed to create %s: %v", goldenDir, err)
+				}
+				if err := os.WriteFile(path, actual, 0644); err != nil {
+					t.Fatalf("Failed to write golden schema: %v", err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read golden schema, write it with go test -run TestGoldenSchemas -update-golden: %v", err)
+			}
+			differences, err := schemagen.DiffSchemas(expected, actual)
+			if err != nil {
+				t.Fatalf("Failed to compare schemas: %v", err)
+			}
+			if len(differences) > 0 {
+				lines := make([]string, 0, len(differences))
+				for _, difference := range differences {
+					lines = append(lines, "  "+difference.String())
+				}
+				t.Errorf("Generated schema differs from %s, run go test -run TestGoldenSchemas -update-golden if the changes are intended:\n%s", path, strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+func TestComponentFilter(t *testing.T) {
+	filter, err := ParseComponentFilter("receiver/otlp, exporter", "exporter/kafka")
+	if err != nil {
+		t.Fatalf("Failed to parse filter: %v", err)
+	}
+
+	tests := map[ComponentID]bool{
+		{Category: "receiver", Type: component.MustNewType("otlp")}:     true,
+		{Category: "receiver", Type: component.MustNewType("kafka")}:    false,
+		{Category: "exporter", Type: component.MustNewType("debug")}:    true,
+		{Category: "exporter", Type: component.MustNewType("kafka")}:    false,
+		{Category: "processor", Type: component.MustNewType("batch")}:   false,
+		{Category: "extension", Type: component.MustNewType("pprof")}:   false,
+		{Category: "connector", Type: component.MustNewType("forward")}: false,
+	}
+	for id, expected := range tests {
+		if filter.Includes(id) != expected {
+			t.Errorf("Expected Includes(%s) to be %v", id, expected)
+		}
+	}
+
+	// An empty filter selects all components
+	if !(ComponentFilter{}).Includes(ComponentID{Category: "processor", Type: component.MustNewType("batch")}) {
+		t.Error("Expected an empty filter to include all components")
+	}
+
+	for _, invalid := range []string{"receivers", "receiver/", "otlp"} {
+		if _, err := ParseComponentFilter(invalid, ""); err == nil {
+			t.Errorf("Expected an error for pattern %q", invalid)
+		}
+	}
+}
+
+func TestWriteVersionManifest(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "0.139.0")
+	if err := os.Mkdir(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	for _, name := range []string{"receiver_otlp.json", "receiver_kafka.json", "exporter_debug.json", lockFileName} {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	generator := NewSchemaGenerator(outputDir)
+	generator.lock = &schemaLock{Generator: "sha256:1"}
+	generator.modules = map[ComponentID]string{
+		{Category: "receiver", Type: component.MustNewType("otlp")}:         "go.opentelemetry.io/collector/receiver/otlpreceiver v0.139.0",
+		{Category: "receiver", Type: component.MustNewType("testreceiver")}: "",
+	}
+	generatedAt := time.Date(2025, 11, 4, 10, 30, 0, 0, time.UTC)
+	if err := generator.writeVersionManifest(generatedAt); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, manifestFileName))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest versionManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	expected := versionManifest{
+		Version:     "0.139.0",
+		Generator:   "sha256:1",
+		GeneratedAt: generatedAt,
+		Modules:     map[string]string{"go.opentelemetry.io/collector/receiver/otlpreceiver": "v0.139.0"},
+		Components:  map[string]int{"receiver": 2, "exporter": 1},
+	}
+	if !reflect.DeepEqual(expected, manifest) {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	overridesDir := t.TempDir()
+	overrides := map[string]string{
+		"receiver_otlp.json":  `{"properties": {"mode": {"enum": ["push", "pull"]}, "legacy": null}}`,
+		"exporter_debug.json": `[{"op": "replace", "path": "/properties/mode/description", "value": "Delivery mode"}]`,
+		"exporter_kafka.json": `[{"op": "remove", "path": "/properties/unknown"}]`,
+	}
+	for name, override := range overrides {
+		if err := os.WriteFile(filepath.Join(overridesDir, name), []byte(override), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	generator := NewSchemaGenerator(t.TempDir())
+	generator.SetOverridesDir(overridesDir)
+	generate := func() *schemagen.Schema {
+		properties := schemagen.NewProperties()
+		properties.Set("mode", &schemagen.Schema{Type: schemagen.Types{"string"}, Description: "Mode"})
+		properties.Set("legacy", &schemagen.Schema{Type: schemagen.Types{"boolean"}})
+		return &schemagen.Schema{Type: schemagen.Types{"object"}, Properties: properties}
+	}
+
+	tests := []struct {
+		category string
+		typ      string
+		expected string
+	}{
+		{category: "receiver", typ: "otlp", expected: `{"type":"object","properties":{"mode":{"type":"string","description":"Mode","enum":["push","pull"]}}}`},
+		{category: "exporter", typ: "debug", expected: `{"type":"object","properties":{"mode":{"type":"string","description":"Delivery mode"},"legacy":{"type":"boolean"}}}`},
+		{category: "processor", typ: "batch", expected: `{"type":"object","properties":{"mode":{"type":"string","description":"Mode"},"legacy":{"type":"boolean"}}}`},
+	}
+	for _, test := range tests {
+		schema, err := generator.applyOverrides(generate(), test.category, component.MustNewType(test.typ))
+		if err != nil {
+			t.Fatalf("Failed to apply override of %s/%s: %v", test.category, test.typ, err)
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("Failed to marshal schema: %v", err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("Unexpected schema of %s/%s: %s", test.category, test.typ, data)
+		}
+	}
+
+	if _, err := generator.applyOverrides(generate(), "exporter", component.MustNewType("kafka")); err == nil || !strings.Contains(err.Error(), "exporter_kafka.json") {
+		t.Errorf("Expected an error naming the failing override, got %v", err)
+	}
+	if !generator.hasOverride("receiver", component.MustNewType("otlp")) || generator.hasOverride("processor", component.MustNewType("batch")) {
+		t.Error("Components with an override file should be detected")
+	}
+}
+
+func TestSchemaLock(t *testing.T) {
+	otlp := ComponentID{Category: "receiver", Type: component.MustNewType("otlp")}
+	kafka := ComponentID{Category: "receiver", Type: component.MustNewType("kafka")}
+	test := ComponentID{Category: "receiver", Type: component.MustNewType("testreceiver")}
+	modules := map[ComponentID]string{
+		otlp:  "go.opentelemetry.io/collector/receiver/otlpreceiver v0.139.0",
+		kafka: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver v0.139.0",
+	}
+
+	outputDir := t.TempDir()
+	path := filepath.Join(outputDir, lockFileName)
+	lock, err := readSchemaLock(path)
+	if err != nil {
+		t.Fatalf("Failed to read missing lock: %v", err)
+	}
+	lock.Generator = "sha256:1"
+	lock.Components[kafka.String()] = modules[kafka]
+
+	// Components without a module version and failed components are not recorded
+	lock.update(&GenerationReport{
+		Succeeded: []ComponentID{otlp, test},
+		Failed:    []ComponentFailure{{Component: kafka, Err: errors.New("unsupported type")}},
+	}, modules)
+	if err := lock.write(path); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+	lock, err = readSchemaLock(path)
+	if err != nil {
+		t.Fatalf("Failed to read lock: %v", err)
+	}
+	expected := &schemaLock{Generator: "sha256:1", Components: map[string]string{otlp.String(): modules[otlp]}}
+	if !reflect.DeepEqual(expected, lock) {
+		t.Errorf("Unexpected lock: %+v", lock)
+	}
+
+	// Schemas are kept if the module did not change and the schema file exists
+	generator := NewSchemaGenerator(outputDir)
+	generator.SetIncremental(true)
+	generator.lock = lock
+	generator.modules = modules
+	generator.report = &GenerationReport{}
+	if generator.keepUnchanged(otlp.Category, otlp.Type) {
+		t.Error("Expected a missing schema file to be regenerated")
+	}
+	if err := os.WriteFile(generator.schemaFilePath(otlp.Category, otlp.Type), []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if !generator.keepUnchanged(otlp.Category, otlp.Type) {
+		t.Error("Expected the schema of an unchanged module to be kept")
+	}
+	generator.modules = map[ComponentID]string{otlp: "go.opentelemetry.io/collector/receiver/otlpreceiver v0.140.0"}
+	if generator.keepUnchanged(otlp.Category, otlp.Type) {
+		t.Error("Expected the schema of an updated module to be regenerated")
+	}
+	if !reflect.DeepEqual([]ComponentID{otlp}, generator.report.Unchanged) {
+		t.Errorf("Unexpected unchanged components: %v", generator.report.Unchanged)
+	}
+}
+
+// TestHashSources tests that the fingerprint of the generator changes with its sources but not with its tests
+func TestHashSources(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	fingerprint := func() string {
+		hash := sha256.New()
+		if !hashSources(hash, filepath.Join(dir, generatorSources)) {
+			t.Fatal("Expected the sources to be hashed")
+		}
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+
+	write("schema_generator.go", "package main")
+	write("schema_generator_metadata.go", "package main")
+	write("main.go", "package main")
+	before := fingerprint()
+
+	write("schema_generator_test.go", "package main")
+	write("main.go", "package main // changed")
+	if fingerprint() != before {
+		t.Error("Expected tests and other files not to change the fingerprint")
+	}
+	write("schema_generator_metadata.go", "package main // changed")
+	if fingerprint() == before {
+		t.Error("Expected a changed generator file to change the fingerprint")
+	}
+
+	if hashSources(sha256.New(), filepath.Join(t.TempDir(), generatorSources)) {
+		t.Error("Expected a pattern without sources to fail")
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		quiet    bool
+		verbose  bool
+		expected []string
+	}{
+		{name: "default", expected: []string{"INFO", "WARN"}},
+		{name: "quiet", quiet: true, expected: []string{"WARN"}},
+		{name: "verbose", verbose: true, expected: []string{"DEBUG", "INFO", "WARN"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := NewLogger(&out, tt.quiet, tt.verbose)
+			logger.Debug("Generated schema", "component", ComponentID{Category: "receiver", Type: component.MustNewType("otlp")})
+			logger.Info("Generating schemas")
+			logger.Warn("Failed to copy README")
+
+			var levels []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				for _, field := range strings.Fields(line) {
+					if level, ok := strings.CutPrefix(field, "level="); ok {
+						levels = append(levels, level)
+					}
+				}
+			}
+			if !reflect.DeepEqual(tt.expected, levels) {
+				t.Errorf("Expected levels %v, got %v", tt.expected, levels)
+			}
+			if tt.verbose && !strings.Contains(out.String(), "component=receiver/otlp") {
+				t.Errorf("Expected the component ID to be logged: %s", out.String())
+			}
+		})
+	}
+}
+
 // BenchmarkSchemaGeneration benchmarks the schema generation process
 func BenchmarkSchemaGeneration(b *testing.B) {
 	// Create schema generator
@@ -187,7 +799,7 @@ func BenchmarkSchemaGeneration(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if err := generator.GenerateAllSchemas(); err != nil {
+		if _, err := generator.GenerateAllSchemas(); err != nil {
 			b.Fatalf("Failed to generate schemas: %v", err)
 		}
 	}
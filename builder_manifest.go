@@ -0,0 +1,176 @@
+package collectorconfigschema
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// embeddedManifests contains the contrib builder manifests the schemas were generated from
+//
+//go:embed manifest-*.yaml
+var embeddedManifests embed.FS
+
+// BuilderManifest is an OpenTelemetry Collector Builder (ocb) manifest
+type BuilderManifest struct {
+	Dist       BuilderDist     `yaml:"dist"`
+	Extensions []BuilderModule `yaml:"extensions,omitempty"`
+	Exporters  []BuilderModule `yaml:"exporters,omitempty"`
+	Processors []BuilderModule `yaml:"processors,omitempty"`
+	Receivers  []BuilderModule `yaml:"receivers,omitempty"`
+	Connectors []BuilderModule `yaml:"connectors,omitempty"`
+	Providers  []BuilderModule `yaml:"providers,omitempty"`
+	Replaces   []string        `yaml:"replaces,omitempty"`
+}
+
+// BuilderDist describes the distribution produced by the builder
+type BuilderDist struct {
+	Module      string `yaml:"module,omitempty"`
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version,omitempty"`
+	OutputPath  string `yaml:"output_path,omitempty"`
+}
+
+// BuilderModule is a Go module entry of a builder manifest, e.g. "go.opentelemetry.io/collector/receiver/otlpreceiver v0.138.0"
+type BuilderModule struct {
+	GoMod string `yaml:"gomod"`
+}
+
+// modulesByType returns the component modules of the manifest by component type
+func (m *BuilderManifest) modulesByType() map[ComponentType][]BuilderModule {
+	return map[ComponentType][]BuilderModule{
+		ComponentTypeReceiver:  m.Receivers,
+		ComponentTypeProcessor: m.Processors,
+		ComponentTypeExporter:  m.Exporters,
+		ComponentTypeExtension: m.Extensions,
+		ComponentTypeConnector: m.Connectors,
+	}
+}
+
+// GenerateBuilderManifest creates a builder manifest containing exactly the component modules
+// declared in a collector configuration. The configuration must be valid for the version.
+// Module paths and versions are taken from the contrib manifest the schemas were generated from,
+// empty dist fields default to an "otelcol-custom" distribution of the given version.
+func (sm *SchemaManager) GenerateBuilderManifest(config []byte, version string, dist BuilderDist) ([]byte, error) {
+	result, err := sm.ValidateCollectorConfig(config, version)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid() {
+		var messages []string
+		for _, validationError := range result.Errors {
+			messages = append(messages, validationError.String())
+		}
+		return nil, fmt.Errorf("collector configuration is invalid: %s", strings.Join(messages, "; "))
+	}
+
+	configMap, err := parseCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	contribManifest, err := loadContribManifest(version)
+	if err != nil {
+		return nil, err
+	}
+	contribModules := contribManifest.modulesByType()
+
+	if dist.Name == "" {
+		dist.Name = "otelcol-custom"
+	}
+	if dist.Description == "" {
+		dist.Description = "Custom OpenTelemetry Collector distribution"
+	}
+	if dist.Version == "" {
+		dist.Version = version
+	}
+	if dist.OutputPath == "" {
+		dist.OutputPath = "./" + dist.Name
+	}
+
+	manifest := &BuilderManifest{
+		Dist:     dist,
+		Replaces: contribManifest.Replaces,
+	}
+
+	modules := make(map[ComponentType][]BuilderModule)
+	for _, cs := range componentSections {
+		section, ok := configMap[cs.section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var names []string
+		for _, id := range sortedKeys(section) {
+			componentID, err := ParseComponentID(id)
+			if err != nil {
+				return nil, err
+			}
+			if !contains(names, componentID.Component) {
+				names = append(names, componentID.Component)
+			}
+		}
+		sort.Strings(names)
+
+		for _, componentName := range names {
+			module := findComponentModule(cs.componentType, componentName, contribModules[cs.componentType])
+			if module == "" {
+				return nil, fmt.Errorf("no module found for %s %s in the %s contrib manifest", cs.componentType, componentName, version)
+			}
+			modules[cs.componentType] = append(modules[cs.componentType], BuilderModule{GoMod: module})
+		}
+	}
+
+	manifest.Receivers = modules[ComponentTypeReceiver]
+	manifest.Processors = modules[ComponentTypeProcessor]
+	manifest.Exporters = modules[ComponentTypeExporter]
+	manifest.Extensions = modules[ComponentTypeExtension]
+	manifest.Connectors = modules[ComponentTypeConnector]
+
+	// Core confmap providers are needed to load the configuration at all
+	for _, provider := range contribManifest.Providers {
+		if strings.HasPrefix(provider.GoMod, "go.opentelemetry.io/collector/") {
+			manifest.Providers = append(manifest.Providers, provider)
+		}
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode builder manifest: %w", err)
+	}
+
+	return data, nil
+}
+
+// loadContribManifest loads the embedded contrib builder manifest of a version
+func loadContribManifest(version string) (*BuilderManifest, error) {
+	data, err := embeddedManifests.ReadFile(fmt.Sprintf("manifest-%s.yaml", version))
+	if err != nil {
+		return nil, fmt.Errorf("builder manifest not found for version %s", version)
+	}
+
+	var manifest BuilderManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse builder manifest for version %s: %w", version, err)
+	}
+
+	return &manifest, nil
+}
+
+// findComponentModule returns the gomod entry (module path and version) of a component
+func findComponentModule(componentType ComponentType, componentName string, modules []BuilderModule) string {
+	for _, module := range modules {
+		fields := strings.Fields(module.GoMod)
+		if len(fields) == 0 {
+			continue
+		}
+		if componentNameForModule(componentType, fields[0], []string{componentName}) == componentName {
+			return module.GoMod
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaManager_GenerateBuilderManifest(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+  otlp/internal:
+  k8s_cluster:
+processors:
+  batch:
+exporters:
+  debug:
+extensions:
+  health_check:
+connectors:
+  forward:
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp, otlp/internal]
+      processors: [batch]
+      exporters: [forward]
+    traces/2:
+      receivers: [forward]
+      exporters: [debug]
+    metrics:
+      receivers: [k8s_cluster]
+      exporters: [debug]
+`)
+
+	data, err := manager.GenerateBuilderManifest(config, "0.138.0", BuilderDist{Name: "otelcol-test"})
+	require.NoError(t, err)
+
+	var manifest BuilderManifest
+	require.NoError(t, yaml.Unmarshal(data, &manifest))
+
+	assert.Equal(t, BuilderDist{
+		Name:        "otelcol-test",
+		Description: "Custom OpenTelemetry Collector distribution",
+		Version:     "0.138.0",
+		OutputPath:  "./otelcol-test",
+	}, manifest.Dist)
+	assert.Equal(t, []BuilderModule{
+		{GoMod: "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver v0.138.0"},
+		{GoMod: "go.opentelemetry.io/collector/receiver/otlpreceiver v0.138.0"},
+	}, manifest.Receivers)
+	assert.Equal(t, []BuilderModule{{GoMod: "go.opentelemetry.io/collector/processor/batchprocessor v0.138.0"}}, manifest.Processors)
+	assert.Equal(t, []BuilderModule{{GoMod: "go.opentelemetry.io/collector/exporter/debugexporter v0.138.0"}}, manifest.Exporters)
+	assert.Equal(t, []BuilderModule{{GoMod: "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension v0.138.0"}}, manifest.Extensions)
+	assert.Equal(t, []BuilderModule{{GoMod: "go.opentelemetry.io/collector/connector/forwardconnector v0.138.0"}}, manifest.Connectors)
+
+	assert.NotEmpty(t, manifest.Providers)
+	for _, provider := range manifest.Providers {
+		assert.Contains(t, provider.GoMod, "go.opentelemetry.io/collector/confmap/provider/")
+	}
+	assert.NotEmpty(t, manifest.Replaces)
+}
+
+func TestSchemaManager_GenerateBuilderManifest_InvalidConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`)
+
+	_, err := manager.GenerateBuilderManifest(config, "0.138.0", BuilderDist{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "collector configuration is invalid")
+	assert.Contains(t, err.Error(), `references "debug"`)
+}
+
+func TestSchemaManager_GenerateBuilderManifest_UnknownVersion(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.GenerateBuilderManifest([]byte("receivers: {}"), "0.100.0", BuilderDist{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "builder manifest not found for version 0.100.0")
+}
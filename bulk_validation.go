@@ -0,0 +1,148 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ConfigInput is a collector configuration validated by ValidateMany
+type ConfigInput struct {
+	// Name identifies the configuration in its result, e.g. a file path or the ID of an agent
+	Name   string
+	Config []byte
+}
+
+// BulkValidationStats are the aggregate statistics of ValidateMany
+type BulkValidationStats struct {
+	// Total is the number of validated configurations
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	// Failed is the number of configurations that could not be parsed
+	Failed int `json:"failed"`
+	// Errors is the number of validation errors of all configurations
+	Errors int `json:"errors"`
+	// ErrorsByCode counts the validation errors with a code, e.g. ErrorCodeUnknownComponent
+	ErrorsByCode map[string]int `json:"errorsByCode,omitempty"`
+	// Duration is the time the validation took, it is serialized in milliseconds as durationMs
+	Duration time.Duration `json:"-"`
+}
+
+// MarshalJSON serializes the statistics with the duration in milliseconds
+func (s BulkValidationStats) MarshalJSON() ([]byte, error) {
+	type stats BulkValidationStats
+	return json.Marshal(struct {
+		stats
+		DurationMs float64 `json:"durationMs"`
+	}{stats(s), float64(s.Duration) / float64(time.Millisecond)})
+}
+
+// add records the result of a configuration
+func (s *BulkValidationStats) add(result ConfigFileResult) {
+	s.Total++
+	switch {
+	case result.Err != nil:
+		s.Failed++
+	case result.Valid():
+		s.Valid++
+	default:
+		s.Invalid++
+	}
+	if result.Result == nil {
+		return
+	}
+	s.Errors += len(result.Result.Errors)
+	for _, validationError := range result.Result.Errors {
+		if validationError.Code == "" {
+			continue
+		}
+		if s.ErrorsByCode == nil {
+			s.ErrorsByCode = make(map[string]int)
+		}
+		s.ErrorsByCode[validationError.Code]++
+	}
+}
+
+// WithParallelism sets the number of configurations ValidateMany validates concurrently, it defaults to GOMAXPROCS
+func WithParallelism(parallelism int) ValidationOption {
+	return func(options *validationOptions) {
+		options.parallelism = parallelism
+	}
+}
+
+// ValidateMany validates a stream of collector configurations against the schemas of a version concurrently, e.g.
+// the configurations of a fleet of agents. Compiled schemas are shared by all validations. Results are passed to
+// onResult in the order the validations complete, the Path of a result is the Name of its input. onResult is
+// never called concurrently. Configurations that are not validated because the context was canceled are not
+// reported, the error of the context is returned with the statistics of the reported configurations. ValidateMany
+// returns on cancellation also while configs blocks waiting for the next configuration, the iteration is stopped
+// when configs yields again.
+func (sm *SchemaManager) ValidateMany(ctx context.Context, configs iter.Seq[ConfigInput], version string, onResult func(ConfigFileResult), opts ...ValidationOption) (*BulkValidationStats, error) {
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	parallelism := options.parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	start := time.Now()
+	inputs := make(chan ConfigInput)
+	results := make(chan ConfigFileResult)
+
+	var workers sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for input := range inputs {
+				result := sm.validateConfigData(ctx, input.Name, input.Config, version, opts...)
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(inputs)
+		for input := range configs {
+			select {
+			case inputs <- input:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	stats := &BulkValidationStats{}
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				stats.Duration = time.Since(start)
+				return stats, ctx.Err()
+			}
+			// Results validated before a cancellation are not reported after it
+			if ctx.Err() != nil {
+				continue
+			}
+			stats.add(result)
+			onResult(result)
+		case <-ctx.Done():
+			stats.Duration = time.Since(start)
+			return stats, ctx.Err()
+		}
+	}
+}
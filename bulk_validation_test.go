@@ -0,0 +1,108 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// agentConfigs returns n configurations, every third one declares an unknown receiver and every fifth one is not
+// a map
+func agentConfigs(n int) iter.Seq[ConfigInput] {
+	return func(yield func(ConfigInput) bool) {
+		for i := 0; i < n; i++ {
+			config := "receivers:\n  otlp:\n"
+			switch {
+			case i%5 == 0:
+				config = "- otlp"
+			case i%3 == 0:
+				config = "receivers:\n  unknown:\n"
+			}
+			if !yield(ConfigInput{Name: fmt.Sprintf("agent-%d", i), Config: []byte(config)}) {
+				return
+			}
+		}
+	}
+}
+
+func TestSchemaManager_ValidateMany(t *testing.T) {
+	manager := NewSchemaManager()
+
+	var names []string
+	stats, err := manager.ValidateMany(context.Background(), agentConfigs(30), "0.139.0", func(result ConfigFileResult) {
+		names = append(names, result.Path)
+	}, WithParallelism(4))
+	require.NoError(t, err)
+
+	assert.Equal(t, 30, stats.Total)
+	assert.Equal(t, 6, stats.Failed)
+	assert.Equal(t, 8, stats.Invalid)
+	assert.Equal(t, 16, stats.Valid)
+	assert.Equal(t, 8, stats.Errors)
+	assert.Equal(t, map[string]int{ErrorCodeUnknownComponent: 8}, stats.ErrorsByCode)
+	assert.Positive(t, stats.Duration)
+
+	data, err := json.Marshal(BulkValidationStats{Total: 1, Valid: 1, Duration: 1500 * time.Microsecond})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total": 1, "valid": 1, "invalid": 0, "failed": 0, "errors": 0, "durationMs": 1.5}`, string(data))
+
+	slices.Sort(names)
+	assert.Len(t, slices.Compact(names), 30)
+}
+
+func TestSchemaManager_ValidateMany_Canceled(t *testing.T) {
+	manager := NewSchemaManager()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reported := 0
+	stats, err := manager.ValidateMany(ctx, agentConfigs(1000), "0.139.0", func(ConfigFileResult) {
+		reported++
+		if reported == 10 {
+			cancel()
+		}
+	}, WithParallelism(2))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, reported, stats.Total)
+	assert.Less(t, stats.Total, 1000)
+}
+
+func TestSchemaManager_ValidateMany_CanceledWhileWaiting(t *testing.T) {
+	manager := NewSchemaManager()
+
+	// The configurations of a stream that stalls after the first one, e.g. agents that connect later
+	release := make(chan struct{})
+	defer close(release)
+	stalled := func(yield func(ConfigInput) bool) {
+		if !yield(ConfigInput{Name: "agent-0", Config: []byte("receivers:\n  otlp:\n")}) {
+			return
+		}
+		<-release
+		yield(ConfigInput{Name: "agent-1", Config: []byte("receivers:\n  otlp:\n")})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var stats *BulkValidationStats
+	var err error
+	go func() {
+		defer close(done)
+		stats, err = manager.ValidateMany(ctx, stalled, "0.139.0", func(ConfigFileResult) {
+			cancel()
+		}, WithParallelism(2))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ValidateMany did not return after the context was canceled")
+	}
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, stats.Total)
+}
@@ -0,0 +1,157 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// schemaIDBase is the base of the $id of bundled schemas, e.g. <base>0.139.0/receiver_otlp.json
+const schemaIDBase = "https://github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/"
+
+// bundleFileName is the file name of the bundle of a version, it is the last segment of the bundle $id
+const bundleFileName = "bundle.json"
+
+// schemaID returns the $id of a bundled schema named "<type>_<name>" or of the bundle itself
+func schemaID(version string, fileName string) string {
+	return schemaIDBase + version + "/" + fileName
+}
+
+// GetSchemaBundle returns all component schemas of a version in a single schema, e.g. for web editors that
+// cannot load hundreds of files. The component schemas are embedded under $defs (named "<type>_<name>") with
+// a stable $id, so their own $defs and references are kept as they are. The bundle validates a full collector
+// configuration like GetCollectorConfigSchema.
+func (sm *SchemaManager) GetSchemaBundle(version string) (map[string]interface{}, error) {
+	return sm.GetSchemaBundleContext(context.Background(), version)
+}
+
+// GetSchemaBundleContext is GetSchemaBundle with a context, the bundle is not built and the error of the context
+// is returned when it is canceled
+func (sm *SchemaManager) GetSchemaBundleContext(ctx context.Context, version string) (map[string]interface{}, error) {
+	properties, definitions, err := sm.bundleContent(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := bundleHeader(version, properties)
+	bundle["$defs"] = definitions
+	return bundle, nil
+}
+
+// bundleContent returns the properties of the collector configuration and the component schemas of the bundle of
+// a version, keyed by definition name
+func (sm *SchemaManager) bundleContent(ctx context.Context, version string) (map[string]interface{}, map[string]interface{}, error) {
+	definitions := make(map[string]interface{})
+	properties, err := sm.collectorConfigProperties(ctx, version, func(definitionName string, schema map[string]interface{}) string {
+		fileName := definitionName + ".json"
+		bundled := make(map[string]interface{}, len(schema)+1)
+		for key, value := range schema {
+			bundled[key] = value
+		}
+		delete(bundled, "$schema")
+		bundled["$id"] = schemaID(version, fileName)
+		definitions[definitionName] = bundled
+
+		// References are resolved against the $id of the bundle, i.e. to the $id of the component schema
+		return fileName
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return properties, definitions, nil
+}
+
+// bundleHeader returns the keywords of the bundle of a version except its $defs
+func bundleHeader(version string, properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  schemaID(version, bundleFileName),
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// RegisterSchemaBundle registers the component schemas of a bundle created by GetSchemaBundle for the version
+// of the bundle, e.g. a bundle of a version that is not embedded
+func (sm *SchemaManager) RegisterSchemaBundle(data []byte) error {
+	_, componentSchemas, err := parseSchemaBundle(data)
+	if err != nil {
+		return err
+	}
+
+	for _, schema := range componentSchemas {
+		if err := sm.RegisterComponentSchema(schema); err != nil {
+			return fmt.Errorf("failed to register %s_%s from schema bundle: %w", schema.Type, schema.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// parseSchemaBundle returns the version and the component schemas of a bundle created by GetSchemaBundle, sorted
+// by definition name
+func parseSchemaBundle(data []byte) (string, []*ComponentSchema, error) {
+	var bundle map[string]interface{}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return "", nil, fmt.Errorf("failed to parse schema bundle: %w", err)
+	}
+
+	id, _ := bundle["$id"].(string)
+	version := strings.TrimSuffix(strings.TrimPrefix(id, schemaIDBase), "/"+bundleFileName)
+	if version == "" || version == id || strings.Contains(version, "/") {
+		return "", nil, fmt.Errorf("invalid schema bundle $id %q, expected %s", id, schemaID("<version>", bundleFileName))
+	}
+
+	definitions, _ := bundle["$defs"].(map[string]interface{})
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var componentSchemas []*ComponentSchema
+	for _, name := range names {
+		bundled, ok := definitions[name].(map[string]interface{})
+		if !ok || bundled["$id"] != schemaID(version, name+".json") {
+			continue
+		}
+		parts := strings.SplitN(name, "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		schema := make(map[string]interface{}, len(bundled))
+		for key, value := range bundled {
+			schema[key] = value
+		}
+		delete(schema, "$id")
+		schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+		componentSchemas = append(componentSchemas, &ComponentSchema{
+			Name:    parts[1],
+			Type:    ComponentType(parts[0]),
+			Version: version,
+			Schema:  schema,
+		})
+	}
+
+	return version, componentSchemas, nil
+}
+
+// RegisterSchemaBundleFile registers the component schemas of a bundle file, the file is verified with the
+// checksums.json in its directory, see RequireVerifiedSchemas
+func (sm *SchemaManager) RegisterSchemaBundleFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read schema bundle: %w", err)
+	}
+	if err := sm.verification.verifyFile(path, data); err != nil {
+		return fmt.Errorf("failed to verify schema bundle: %w", err)
+	}
+
+	return sm.RegisterSchemaBundle(data)
+}
@@ -0,0 +1,97 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_GetSchemaBundle(t *testing.T) {
+	manager := NewSchemaManager()
+
+	bundle, err := manager.GetSchemaBundle("0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, schemaIDBase+"0.139.0/bundle.json", bundle["$id"])
+
+	definitions := bundle["$defs"].(map[string]interface{})
+	otlp := definitions["receiver_otlp"].(map[string]interface{})
+	assert.Equal(t, schemaIDBase+"0.139.0/receiver_otlp.json", otlp["$id"])
+	assert.NotContains(t, otlp, "$schema")
+
+	receivers := bundle["properties"].(map[string]interface{})["receivers"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"anyOf": []interface{}{
+		map[string]interface{}{"type": "null"},
+		map[string]interface{}{"$ref": "receiver_otlp.json"},
+	}}, receivers["patternProperties"].(map[string]interface{})[`^otlp(/[^\s/]+)?$`])
+
+	// References to the bundled schemas and their own definitions resolve
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	require.NoError(t, schemagen.VerifySchema(data))
+
+	result := validateAgainstConfigSchema(t, bundle, "receivers:\n  otlp/internal:\n    protocols:\n      grpc:\n        endpoint: 0.0.0.0:4317\n")
+	assert.True(t, result.Valid(), "Config should be valid: %v", result.Errors())
+	result = validateAgainstConfigSchema(t, bundle, "processors:\n  batch:\n    send_batch_size: large\n")
+	assert.False(t, result.Valid())
+}
+
+func TestSchemaManager_RegisterSchemaBundle(t *testing.T) {
+	bundle, err := NewSchemaManager().GetSchemaBundle("0.139.0")
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	// A bundle of a version that is not embedded
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterSchemaBundle(bytes.ReplaceAll(data, []byte("/0.139.0/"), []byte("/0.199.0/"))))
+
+	components, err := manager.ListAvailableComponents("0.199.0")
+	require.NoError(t, err)
+	embedded, err := manager.ListAvailableComponents("0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, embedded, components)
+
+	registered, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.199.0")
+	require.NoError(t, err)
+	original, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, original.Schema, registered.Schema, "Bundled schemas are registered as they were embedded")
+	assert.Equal(t, original.Metadata, registered.Metadata)
+
+	_, err = manager.ListAvailableComponents("0.198.0")
+	assert.Error(t, err)
+
+	assert.ErrorContains(t, manager.RegisterSchemaBundle([]byte(`{"$id":"https://example.com/bundle.json"}`)), "invalid schema bundle $id")
+	assert.ErrorContains(t, manager.RegisterSchemaBundle([]byte(`{`)), "failed to parse schema bundle")
+}
+
+func TestCommittedBundles(t *testing.T) {
+	manager := NewSchemaManager()
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+
+	for _, version := range versions {
+		t.Run(version, func(t *testing.T) {
+			dir := filepath.Join("schemas", version)
+			committed, err := os.ReadFile(filepath.Join(dir, "bundle.json"))
+			require.NoError(t, err)
+			var bundle bytes.Buffer
+			require.NoError(t, manager.WriteBundle(&bundle, version))
+			assert.True(t, bytes.Equal(bundle.Bytes(), committed), "%s/bundle.json is outdated, run make bundles", dir)
+
+			data, err := os.ReadFile(filepath.Join(dir, "checksums.json"))
+			require.NoError(t, err)
+			checksums, err := ParseSchemaManifest(data)
+			require.NoError(t, err)
+			expected, err := CreateSchemaManifest(version, dir)
+			require.NoError(t, err)
+			assert.Equal(t, expected, checksums, "%s/checksums.json is outdated, run make bundles", dir)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package collectorconfigschema
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultCacheSize is the number of parsed component schemas a SchemaManager keeps by default, about the
+// components of four collector versions
+const DefaultCacheSize = 1000
+
+// ManagerOption configures a SchemaManager
+type ManagerOption func(*managerOptions)
+
+// managerOptions holds the settings applied by ManagerOption
+type managerOptions struct {
+	cacheSize      int
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	registry       *remoteRegistry
+	verification   schemaVerification
+}
+
+// WithCacheSize limits the number of parsed component schemas kept in the cache, the least recently used schema
+// is evicted when the limit is reached. A size of zero or less disables the limit.
+func WithCacheSize(size int) ManagerOption {
+	return func(options *managerOptions) {
+		options.cacheSize = size
+	}
+}
+
+// CacheStats are the counters of a schema cache of a SchemaManager
+type CacheStats struct {
+	// Hits is the number of schemas served from the cache
+	Hits uint64 `json:"hits"`
+	// Misses is the number of schemas that were parsed
+	Misses uint64 `json:"misses"`
+	// Evictions is the number of schemas evicted because the cache was full
+	Evictions uint64 `json:"evictions"`
+	// Size is the number of cached schemas
+	Size int `json:"size"`
+	// Capacity is the maximum number of cached schemas, zero if the cache is unbounded
+	Capacity int `json:"capacity"`
+}
+
+// CacheStats returns the hit, miss and eviction counters and the size of the component schema cache
+func (sm *SchemaManager) CacheStats() CacheStats {
+	return sm.cache.stats()
+}
+
+// CompiledCacheStats returns the counters of the cache of compiled validators, see ValidateComponentJSON
+func (sm *SchemaManager) CompiledCacheStats() CacheStats {
+	return sm.compiled.stats()
+}
+
+// InvalidateCache removes the cached schema and compiled validators of a component, they are parsed and compiled
+// again when they are requested
+func (sm *SchemaManager) InvalidateCache(componentType ComponentType, componentName string, version string) {
+	key := schemaCacheKey(componentType, componentName, version)
+	sm.cache.remove(func(cached string) bool { return cached == key })
+	sm.compiled.remove(func(cached string) bool {
+		return cached == compiledCacheKey(componentType, componentName, version, false) ||
+			cached == compiledCacheKey(componentType, componentName, version, true)
+	})
+}
+
+// ClearCache removes all cached schemas and compiled validators, the counters are kept
+func (sm *SchemaManager) ClearCache() {
+	sm.cache.clear()
+	sm.compiled.clear()
+}
+
+// schemaCacheKey returns the cache key of a component schema
+func schemaCacheKey(componentType ComponentType, componentName string, version string) string {
+	return fmt.Sprintf("%s_%s_%s", componentType, componentName, version)
+}
+
+// compiledCacheKey returns the cache key of a compiled validator, component names cannot contain slashes
+func compiledCacheKey(componentType ComponentType, componentName string, version string, strict bool) string {
+	return fmt.Sprintf("%s/%s/%s/%t", componentType, componentName, version, strict)
+}
+
+// compileSchema compiles a schema into a validator, strict schemas reject undeclared properties
+func compileSchema(schema map[string]interface{}, strict bool) (*gojsonschema.Schema, error) {
+	if strict {
+		schema = closeObjectSchemas(schema)
+	}
+	return gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+}
+
+// validateJSON validates JSON data against a component schema, compiled validators are cached by component,
+// version and strictness
+func (sm *SchemaManager) validateJSON(componentSchema *ComponentSchema, strict bool, jsonData []byte) (*gojsonschema.Result, error) {
+	key := compiledCacheKey(componentSchema.Type, componentSchema.Name, componentSchema.Version, strict)
+	compiled, ok := sm.compiled.get(key)
+	if !ok {
+		var err error
+		if compiled, err = compileSchema(componentSchema.Schema, strict); err != nil {
+			return nil, fmt.Errorf("failed to compile schema for %s %s: %w", componentSchema.Type, componentSchema.Name, err)
+		}
+		sm.compiled.add(key, compiled)
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewBytesLoader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("validation failed for %s %s: %w", componentSchema.Type, componentSchema.Name, err)
+	}
+	return result, nil
+}
+
+// lruCache is a least recently used cache, it is safe for concurrent use
+type lruCache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	// order holds the entries from the most to the least recently used
+	order     *list.List
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// lruEntry is an entry of the order list
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+// newLRUCache creates a cache of at most capacity values, a capacity of zero or less is unbounded
+func newLRUCache[V any](capacity int) *lruCache[V] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &lruCache[V]{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// get returns a cached value and marks it as most recently used
+func (c *lruCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		var zero V
+		return zero, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[V]).value, true
+}
+
+// add caches a value and evicts the least recently used values above the capacity
+func (c *lruCache[V]) add(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry[V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+		c.evictions++
+	}
+}
+
+// remove removes the cached values whose key matches
+func (c *lruCache[V]) remove(match func(key string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if match(key) {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// clear removes all cached values
+func (c *lruCache[V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// stats returns the counters and the size of the cache
+func (c *lruCache[V]) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.order.Len(),
+		Capacity:  c.capacity,
+	}
+}
@@ -0,0 +1,118 @@
+package collectorconfigschema
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestSchemaManager_Cache(t *testing.T) {
+	manager := NewSchemaManager(WithCacheSize(2))
+
+	otlp, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	cached, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Same(t, otlp, cached)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Size: 1, Capacity: 2}, manager.CacheStats())
+
+	// The least recently used schema is evicted
+	_, err = manager.GetComponentSchema(ComponentTypeProcessor, "batch", "0.139.0")
+	require.NoError(t, err)
+	_, err = manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "debug", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 3, Evictions: 1, Size: 2, Capacity: 2}, manager.CacheStats())
+	_, err = manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), manager.CacheStats().Hits, "otlp was used more recently than batch")
+
+	manager.InvalidateCache(ComponentTypeReceiver, "otlp", "0.139.0")
+	reloaded, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.NotSame(t, otlp, reloaded)
+	assert.Equal(t, otlp.Schema, reloaded.Schema)
+
+	manager.ClearCache()
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 4, Evictions: 1, Capacity: 2}, manager.CacheStats())
+}
+
+func TestSchemaManager_Cache_Unbounded(t *testing.T) {
+	manager := NewSchemaManager(WithCacheSize(0))
+
+	components, err := manager.ListAvailableComponents("0.139.0")
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for _, name := range components[ComponentTypeReceiver] {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := manager.GetComponentSchema(ComponentTypeReceiver, name, "0.139.0")
+			assert.NoError(t, err)
+		}(name)
+	}
+	wg.Wait()
+
+	stats := manager.CacheStats()
+	assert.Equal(t, len(components[ComponentTypeReceiver]), stats.Size)
+	assert.Zero(t, stats.Evictions)
+	assert.Zero(t, stats.Capacity)
+}
+
+func TestSchemaManager_CompiledCache(t *testing.T) {
+	manager := NewSchemaManager()
+
+	for i := 0; i < 3; i++ {
+		result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "batch", "0.139.0", []byte(`{"send_batch_size": "large"}`))
+		require.NoError(t, err)
+		assert.False(t, result.Valid())
+	}
+	result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "batch", "0.139.0", []byte(`{"unknown": true}`), Strict())
+	require.NoError(t, err)
+	assert.False(t, result.Valid(), "Strict validators are compiled separately")
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Size: 2, Capacity: DefaultCacheSize}, manager.CompiledCacheStats())
+
+	// Registering a schema drops the validators compiled from the schema it replaces
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeProcessor, "batch", "", []byte(`{"type": "object"}`)))
+	assert.Zero(t, manager.CompiledCacheStats().Size)
+	result, err = manager.ValidateComponentJSON(ComponentTypeProcessor, "batch", "0.139.0", []byte(`{"send_batch_size": "large"}`))
+	require.NoError(t, err)
+	assert.True(t, result.Valid())
+
+	manager.InvalidateCache(ComponentTypeProcessor, "batch", "0.139.0")
+	assert.Zero(t, manager.CompiledCacheStats().Size)
+}
+
+// BenchmarkValidateComponentJSON compares validations with the cached validator to compiling the schema for
+// every validation
+func BenchmarkValidateComponentJSON(b *testing.B) {
+	config := []byte(`{"protocols": {"grpc": {"endpoint": "0.0.0.0:4317"}, "http": {"endpoint": "0.0.0.0:4318"}}}`)
+
+	b.Run("cached", func(b *testing.B) {
+		manager := NewSchemaManager()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		manager := NewSchemaManager()
+		schema, err := manager.GetComponentSchema(ComponentTypeReceiver, "otlp", "0.139.0")
+		require.NoError(b, err)
+		for i := 0; i < b.N; i++ {
+			compiled, err := compileSchema(schema.Schema, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := compiled.Validate(gojsonschema.NewBytesLoader(config)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
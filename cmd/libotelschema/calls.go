@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// response is the JSON returned by the exported functions, Error is set instead of Result if the call failed, e.g.
+// {"version": "0.139.0", "result": {"errors": []}} or {"error": "unknown version 0.0.1"}
+type response struct {
+	// Version is the collector version the call used, e.g. the latest embedded version for an empty version
+	Version string      `json:"version,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// schemaManager is shared by all calls so parsed and compiled schemas are cached between them
+var schemaManager = sync.OnceValue(func() *collectorschema.SchemaManager {
+	return collectorschema.NewSchemaManager()
+})
+
+// validateCollectorConfig returns the validation errors of a collector configuration
+func validateCollectorConfig(config []byte, version string) []byte {
+	return call(version, func(version string) (interface{}, error) {
+		result, err := schemaManager().ValidateCollectorConfig(config, version)
+		if err == nil && result.Errors == nil {
+			// Bindings get a list for valid configurations
+			result.Errors = []collectorschema.ConfigValidationError{}
+		}
+		return result, err
+	})
+}
+
+// componentSchema returns the JSON schema of a component
+func componentSchema(componentType string, componentName string, version string) []byte {
+	return call(version, func(version string) (interface{}, error) {
+		data, err := schemaManager().GetComponentSchemaJSON(collectorschema.ComponentType(componentType), componentName, version)
+		return json.RawMessage(data), err
+	})
+}
+
+// listComponents returns the component names of a version by type
+func listComponents(version string) []byte {
+	return call(version, func(version string) (interface{}, error) {
+		return schemaManager().ListAvailableComponents(version)
+	})
+}
+
+// versions returns the embedded versions
+func versions() []byte {
+	all, err := schemaManager().GetAllVersions()
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return encode(response{Result: all})
+}
+
+// call resolves an empty version to the latest embedded version and encodes the result of a function
+func call(version string, fn func(version string) (interface{}, error)) []byte {
+	if version == "" {
+		latest, err := schemaManager().GetLatestVersion()
+		if err != nil {
+			return encode(response{Error: err.Error()})
+		}
+		version = latest
+	}
+
+	result, err := fn(version)
+	if err != nil {
+		return encode(response{Version: version, Error: err.Error()})
+	}
+	return encode(response{Version: version, Result: result})
+}
+
+// encode encodes a response, results are always encodable
+func encode(r response) []byte {
+	data, err := json.Marshal(r)
+	if err != nil {
+		data, _ = json.Marshal(response{Version: r.Version, Error: err.Error()})
+	}
+	return data
+}
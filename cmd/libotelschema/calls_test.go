@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCollectorConfig(t *testing.T) {
+	latest, err := schemaManager().GetLatestVersion()
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"version": "`+latest+`", "result": {"errors": []}}`, string(validateCollectorConfig([]byte("receivers:\n  otlp:\n"), "")))
+
+	response := validateCollectorConfig([]byte("receivers:\n  unknown:\n"), "0.139.0")
+	assert.JSONEq(t, `{"version": "0.139.0", "result": {"errors": [{"path": "receivers.unknown", "message": "unknown receiver type \"unknown\"", "code": "unknown-component", "position": {"line": 2, "column": 3}}]}}`, string(response))
+
+	var failed map[string]interface{}
+	require.NoError(t, json.Unmarshal(validateCollectorConfig([]byte("receivers: ["), "0.139.0"), &failed))
+	assert.NotEmpty(t, failed["error"])
+	assert.NotContains(t, failed, "result")
+}
+
+func TestComponentSchema(t *testing.T) {
+	var response struct {
+		Version string                 `json:"version"`
+		Result  map[string]interface{} `json:"result"`
+		Error   string                 `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(componentSchema("receiver", "otlp", "0.139.0"), &response))
+	assert.Equal(t, "0.139.0", response.Version)
+	assert.Empty(t, response.Error)
+	assert.Contains(t, response.Result, "properties")
+
+	response.Result = nil
+	require.NoError(t, json.Unmarshal(componentSchema("receiver", "doesnotexist", "0.139.0"), &response))
+	assert.Nil(t, response.Result)
+	assert.Contains(t, response.Error, "doesnotexist")
+}
+
+func TestListComponentsAndVersions(t *testing.T) {
+	var components struct {
+		Result map[string][]string `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(listComponents("0.139.0"), &components))
+	assert.Contains(t, components.Result["receiver"], "otlp")
+	assert.Contains(t, components.Result["processor"], "batch")
+
+	var all struct {
+		Result []string `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(versions(), &all))
+	assert.Contains(t, all.Result, "0.139.0")
+}
@@ -0,0 +1,57 @@
+// Program libotelschema exports the validator to C, e.g. for Python (ctypes or cffi) and Rust tooling that calls the
+// library in process instead of running a service. Build it with
+//
+//	go build -buildmode=c-shared -o libotelschema.so ./cmd/libotelschema
+//
+// which also writes the C header libotelschema.h. Every function returns a JSON response allocated with malloc, see
+// response, that the caller must release with otelschema_free. Arguments are NUL terminated UTF-8 strings, an empty
+// version selects the latest embedded version. The functions are safe to call from several threads.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// otelschema_validate_collector_config validates a collector configuration in YAML or JSON, the result holds the
+// errors of ValidateCollectorConfig
+//
+//export otelschema_validate_collector_config
+func otelschema_validate_collector_config(config *C.char, version *C.char) *C.char {
+	return C.CString(string(validateCollectorConfig([]byte(C.GoString(config)), C.GoString(version))))
+}
+
+// otelschema_get_component_schema returns the JSON schema of a component, e.g. "receiver" and "otlp"
+//
+//export otelschema_get_component_schema
+func otelschema_get_component_schema(componentType *C.char, componentName *C.char, version *C.char) *C.char {
+	return C.CString(string(componentSchema(C.GoString(componentType), C.GoString(componentName), C.GoString(version))))
+}
+
+// otelschema_list_components returns the names of the components of a version by type
+//
+//export otelschema_list_components
+func otelschema_list_components(version *C.char) *C.char {
+	return C.CString(string(listComponents(C.GoString(version))))
+}
+
+// otelschema_versions returns the embedded versions, the latest version is last
+//
+//export otelschema_versions
+func otelschema_versions() *C.char {
+	return C.CString(string(versions()))
+}
+
+// otelschema_free releases a response returned by the library
+//
+//export otelschema_free
+func otelschema_free(response *C.char) {
+	C.free(unsafe.Pointer(response))
+}
+
+// main is required by -buildmode=c-shared, it is not called
+func main() {}
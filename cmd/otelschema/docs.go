@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// docsWidth is the width descriptions are wrapped at
+const docsWidth = 100
+
+// defaultPager pages the docs in a terminal if $PAGER is not set
+const defaultPager = "less -R"
+
+// ANSI escape sequences of the colorized docs
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorDim     = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+)
+
+// runDocs implements "otelschema docs exporter otlp --field sending_queue" and
+// "otelschema docs 'receivers.otlp.**.endpoint'"
+func runDocs(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	field := flags.String("field", "", "Path of the field to document, e.g. sending_queue or headers.* (defaults to all fields)")
+	depth := flags.Int("depth", 0, "Levels of nested fields to render, 0 renders all levels")
+	color := flags.String("color", "auto", "Colorize the output: auto, always or never")
+	output := addOutputFlags(flags, true, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if *color != "auto" && *color != "always" && *color != "never" {
+		return fmt.Errorf("unknown color mode %q, expected auto, always or never", *color)
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+	// Paths of a collector configuration are given with wildcards, e.g. receivers.otlp.*.endpoint
+	explainPath := len(positional) == 1 && strings.Contains(positional[0], ".")
+	if explainPath && *field != "" {
+		return fmt.Errorf("--field is not supported with a configuration path")
+	}
+	// Components are given as "exporter otlp" or "exporter/otlp"
+	if len(positional) == 1 && !explainPath {
+		positional = strings.SplitN(positional[0], "/", 2)
+	}
+	if !explainPath && (len(positional) != 2 || positional[0] == "" || positional[1] == "") {
+		return fmt.Errorf("expected a component type and name, e.g. exporter otlp, or a configuration path, e.g. receivers.otlp.*.endpoint")
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	// Docs written to a file are neither colorized nor paged
+	terminal := output.file == "" && isTerminal(stdout)
+	renderer := docsRenderer{
+		color: *color == "always" || *color == "auto" && terminal && os.Getenv("NO_COLOR") == "",
+		depth: *depth,
+	}
+	var page bytes.Buffer
+	if explainPath {
+		matches, err := schemaManager.ExplainPath(resolvedVersion, positional[0])
+		if err != nil {
+			return err
+		}
+		if output.json() {
+			if matches == nil {
+				matches = []collectorschema.ExplainedField{}
+			}
+			return output.writeJSON(stdout, matches)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no fields match %s in version %s", positional[0], resolvedVersion)
+		}
+		for i, match := range matches {
+			if i > 0 {
+				fmt.Fprintln(&page)
+			}
+			renderer.render(&page, fmt.Sprintf("%s %s", match.Path, resolvedVersion), match.Doc)
+		}
+	} else {
+		componentType, componentName := collectorschema.ComponentType(positional[0]), positional[1]
+		doc, err := schemaManager.Explain(componentType, componentName, resolvedVersion, *field)
+		if err != nil {
+			return err
+		}
+		if output.json() {
+			return output.writeJSON(stdout, doc)
+		}
+		renderer.render(&page, fmt.Sprintf("%s/%s %s", componentType, componentName, resolvedVersion), doc)
+	}
+
+	if terminal {
+		return writePaged(stdout, page.Bytes())
+	}
+	return output.write(stdout, page.Bytes())
+}
+
+// docsRenderer renders the documentation of the fields of a component as a tree
+type docsRenderer struct {
+	color bool
+	// depth is the number of levels of nested fields to render, 0 renders all levels
+	depth int
+}
+
+// render writes a title, the field documented by doc and the tree of its nested fields
+func (r docsRenderer) render(w io.Writer, title string, doc *collectorschema.FieldDoc) {
+	fmt.Fprintln(w, r.paint(colorBold, title))
+	if doc.Path != "" {
+		fmt.Fprintln(w, r.describe(doc))
+	}
+	r.writeDescription(w, "", doc.Description)
+	r.renderFields(w, "", doc.Fields, 1)
+}
+
+// renderFields writes the tree of fields below prefix, the lines of the tree are drawn with box characters
+func (r docsRenderer) renderFields(w io.Writer, prefix string, fields []*collectorschema.FieldDoc, level int) {
+	for i, field := range fields {
+		branch, indent := "├── ", "│   "
+		if i == len(fields)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintln(w, prefix+r.paint(colorDim, branch)+r.describe(field))
+
+		nested := field.Fields
+		if r.depth > 0 && level >= r.depth {
+			nested = nil
+		}
+		descriptionPrefix := prefix + r.paint(colorDim, indent)
+		if len(nested) > 0 {
+			descriptionPrefix += r.paint(colorDim, "│ ")
+		} else {
+			descriptionPrefix += "  "
+		}
+		r.writeDescription(w, descriptionPrefix, field.Description)
+		r.renderFields(w, prefix+r.paint(colorDim, indent), nested, level+1)
+	}
+}
+
+// describe returns the name, type, default and allowed values of a field on one line, e.g.
+// sizer <string> = "requests" (one of: "requests", "items", "bytes") [required]
+func (r docsRenderer) describe(field *collectorschema.FieldDoc) string {
+	line := r.paint(colorBold, field.Name)
+	if field.Type != "" {
+		line += " " + r.paint(colorGreen, "<"+field.Type+">")
+	}
+	if field.Default != nil {
+		line += " = " + r.paint(colorYellow, docsValue(field.Default))
+	}
+	if len(field.Enum) > 0 {
+		values := make([]string, 0, len(field.Enum))
+		for _, value := range field.Enum {
+			values = append(values, docsValue(value))
+		}
+		line += " " + r.paint(colorCyan, "(one of: "+strings.Join(values, ", ")+")")
+	}
+	if field.Required {
+		line += " " + r.paint(colorRed, "[required]")
+	}
+	if field.Deprecated {
+		line += " " + r.paint(colorMagenta, "[deprecated]")
+	}
+	return line
+}
+
+// writeDescription writes a description wrapped at docsWidth, every line starts with prefix
+func (r docsRenderer) writeDescription(w io.Writer, prefix string, description string) {
+	for _, line := range wrapText(description, docsWidth-visibleWidth(prefix)) {
+		fmt.Fprintln(w, prefix+r.paint(colorDim, line))
+	}
+}
+
+// paint colors text if the renderer is colorized
+func (r docsRenderer) paint(color string, text string) string {
+	if !r.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// docsValue returns the JSON of a default or allowed value
+func docsValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// wrapText splits text into lines of at most width characters, words longer than width get a line of their own
+func wrapText(text string, width int) []string {
+	if width < 20 {
+		width = 20
+	}
+	var lines []string
+	var line strings.Builder
+	length := 0
+	for _, word := range strings.Fields(text) {
+		wordLength := len([]rune(word))
+		if length > 0 && length+1+wordLength > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			length = 0
+		}
+		if length > 0 {
+			line.WriteByte(' ')
+			length++
+		}
+		line.WriteString(word)
+		length += wordLength
+	}
+	if length > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// visibleWidth returns the number of characters of text without ANSI escape sequences
+func visibleWidth(text string) int {
+	width, escape := 0, false
+	for _, c := range text {
+		switch {
+		case c == '\x1b':
+			escape = true
+		case escape:
+			escape = c != 'm'
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// isTerminal returns whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writePaged writes a page to a terminal through the pager of $PAGER, less -R by default. The page is written
+// directly if the pager is not installed.
+func writePaged(stdout io.Writer, page []byte) error {
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = defaultPager
+	}
+	command := strings.Fields(pager)
+	if len(command) == 0 {
+		_, err := stdout.Write(page)
+		return err
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		_, err := stdout.Write(page)
+		return err
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(page)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,947 @@
+// Program otelschema works with OpenTelemetry collector configurations using the embedded component schemas.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/protoschema"
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemahttp"
+)
+
+// command is a CLI subcommand
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdout io.Writer) error
+}
+
+// commands lists all subcommands in the order they are printed in the usage
+var commands = []command{
+	{"manifest", "Generate an OpenTelemetry Collector Builder manifest for a config file", runManifest},
+	{"scaffold", "Generate a validated config from pipelines like traces='otlp -> batch -> otlphttp' or a recipe file", runScaffold},
+	{"templates", "List the validated reference configs of a version or write one as a starting point", runTemplates},
+	{"validate", "Validate config files against the component schemas", runValidate},
+	{"bundle", "Write all component schemas of a version into a single schema file", runBundle},
+	{"catalog", "Write a JSON Schema Store catalog and the schema bundles of all versions", runCatalog},
+	{"proto", "Generate protocol buffer messages mirroring component schemas", runProto},
+	{"search", "Find component fields by name or description", runSearch},
+	{"docs", "Render the fields of a component with types, defaults and descriptions", runDocs},
+	{"changes", "Report the breaking changes of the component schemas between two versions", runChanges},
+	{"watch", "Validate a config file again whenever it changes", runWatch},
+	{"check", "Report validation, lint, deprecation and audit diagnostics of a config file", runCheck},
+	{"merge", "Merge a base config file with overlays like repeated --config flags", runMerge},
+	{"form", "Write the schema, uiSchema and defaults of a component for form renderers", runForm},
+	{"checksums", "Write the SHA-256 manifest of the schema files of a directory for signing", runChecksums},
+	{"serve", "Serve the component schemas and bundles over HTTP", runServe},
+	{"output-schema", "Write the JSON Schema of the results of all commands with --output json", runOutputSchema},
+}
+
+// watchContext returns the context of the watch and serve commands, it is canceled on interrupt
+var watchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches the arguments to a subcommand
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout)
+		}
+	}
+
+	printUsage(stderr)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// printUsage prints the list of available subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: otelschema <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.description)
+	}
+}
+
+// runManifest implements "otelschema manifest config.yaml"
+func runManifest(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("manifest", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	name := flags.String("name", "", "Name of the distribution (defaults to otelcol-custom)")
+	module := flags.String("module", "", "Go module of the generated distribution")
+	outputPath := flags.String("output-path", "", "Output path of the builder (defaults to ./<name>)")
+	output := addOutputFlags(flags, true, "yaml", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected exactly one config file, got %d", len(positional))
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	config, err := os.ReadFile(positional[0])
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := schemaManager.GenerateBuilderManifest(config, resolvedVersion, collectorschema.BuilderDist{
+		Module:     *module,
+		Name:       *name,
+		OutputPath: *outputPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	return output.writeYAML(stdout, manifest)
+}
+
+// runScaffold implements "otelschema scaffold recipe.yaml" and "otelschema scaffold traces='otlp -> batch -> otlphttp'"
+func runScaffold(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	extensions := flags.String("extensions", "", "Comma separated extensions to enable, e.g. health_check,pprof")
+	output := addOutputFlags(flags, true, "yaml", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("expected a recipe file or pipelines, e.g. traces='otlp -> batch -> otlphttp'")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	recipe := &collectorschema.PipelineRecipe{Pipelines: make(map[string]collectorschema.RecipePipeline)}
+	for _, arg := range positional {
+		pipelineID, pipeline, ok := strings.Cut(arg, "=")
+		if !ok {
+			if len(positional) != 1 {
+				return fmt.Errorf("expected a single recipe file or pipelines, got %q", arg)
+			}
+			data, err := os.ReadFile(arg)
+			if err != nil {
+				return fmt.Errorf("failed to read recipe file: %w", err)
+			}
+			if recipe, err = collectorschema.ParsePipelineRecipe(data); err != nil {
+				return err
+			}
+			continue
+		}
+		parsed, err := collectorschema.ParseRecipePipeline(pipeline)
+		if err != nil {
+			return err
+		}
+		recipe.Pipelines[pipelineID] = parsed
+	}
+	if *extensions != "" {
+		recipe.Extensions = append(recipe.Extensions, strings.Split(*extensions, ",")...)
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	config, err := schemaManager.GeneratePipeline(recipe, resolvedVersion)
+	if err != nil {
+		return err
+	}
+
+	return output.writeYAML(stdout, config)
+}
+
+// runTemplates implements "otelschema templates" and "otelschema templates gateway-traces"
+func runTemplates(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("templates", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	// The template list is text, a single template is its YAML config
+	output := addOutputFlags(flags, true, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("expected at most one template name, got %d", len(positional))
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 1 {
+		template, err := schemaManager.GetTemplate(resolvedVersion, positional[0])
+		if err != nil {
+			return err
+		}
+		return output.writeYAML(stdout, template)
+	}
+
+	templates, err := schemaManager.ListTemplates(resolvedVersion)
+	if err != nil {
+		return err
+	}
+	if output.json() {
+		return output.writeJSON(stdout, templates)
+	}
+	var list bytes.Buffer
+	for _, template := range templates {
+		fmt.Fprintf(&list, "%s: %s\n", template.Name, template.Description)
+	}
+	return output.write(stdout, list.Bytes())
+}
+
+// runValidate implements "otelschema validate config.yaml [config.yaml...]"
+func runValidate(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	output := addOutputFlags(flags, true, "text", "json", "junit")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("expected at least one config file")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	results := schemaManager.ValidateConfigFiles(positional, resolvedVersion)
+
+	var report bytes.Buffer
+	switch output.format {
+	case "junit":
+		err = collectorschema.WriteJUnitReport(&report, results)
+		if err == nil {
+			err = output.write(stdout, report.Bytes())
+		}
+	case "json":
+		fileResults := make([]fileResult, 0, len(results))
+		for _, result := range results {
+			fileResults = append(fileResults, newFileResult(result))
+		}
+		err = output.writeJSON(stdout, fileResults)
+	default:
+		writeTextReport(&report, results)
+		err = output.write(stdout, report.Bytes())
+	}
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, result := range results {
+		if !result.Valid() {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d config files are invalid", invalid, len(results))
+	}
+	return nil
+}
+
+// runBundle implements "otelschema bundle --version 0.139.0"
+func runBundle(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	// The bundle is a JSON document, json is the only format
+	output := addOutputFlags(flags, true, "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	if output.file != "" {
+		return writeBundle(schemaManager, resolvedVersion, output.file)
+	}
+	return schemaManager.WriteBundle(stdout, resolvedVersion)
+}
+
+// runCatalog implements "otelschema catalog --base-url https://example.com/schemas"
+func runCatalog(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	baseURL := flags.String("base-url", "", "URL the output directory is published at")
+	outputDir := flags.String("output-dir", "schemas", "Directory to write catalog.json, otel-collector.json and <version>/bundle.json to")
+	// The catalog is written to --output-dir, the output is the summary of the written files
+	output := addOutputFlags(flags, false, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+	if *baseURL == "" {
+		return fmt.Errorf("--base-url is required")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	catalog, err := schemaManager.GetSchemaCatalog(*baseURL)
+	if err != nil {
+		return err
+	}
+	latestVersion, err := schemaManager.GetLatestVersion()
+	if err != nil {
+		return err
+	}
+
+	// The catalog references the bundle of every version and the bundle of the latest version as the collector schema
+	for version := range catalog.Schemas[0].Versions {
+		if err := os.MkdirAll(filepath.Join(*outputDir, version), 0755); err != nil {
+			return err
+		}
+		if err := writeBundle(schemaManager, version, filepath.Join(*outputDir, version, "bundle.json")); err != nil {
+			return err
+		}
+	}
+	if err := writeBundle(schemaManager, latestVersion, filepath.Join(*outputDir, "otel-collector.json")); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(*outputDir, "catalog.json"), data, 0644); err != nil {
+		return err
+	}
+
+	if output.json() {
+		versions := make([]string, 0, len(catalog.Schemas[0].Versions))
+		for version := range catalog.Schemas[0].Versions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		return writeJSON(stdout, catalogSummary{OutputDir: *outputDir, Versions: versions, LatestVersion: latestVersion})
+	}
+	fmt.Fprintf(stdout, "Wrote catalog with %d versions to %s\n", len(catalog.Schemas[0].Versions), *outputDir)
+	return nil
+}
+
+// runProto implements "otelschema proto receiver/otlp exporter/kafka"
+func runProto(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("proto", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	packageName := flags.String("package", "", "Proto package of the messages (defaults to otelcol.config.v<version>)")
+	output := addOutputFlags(flags, true, "proto", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("expected at least one component, e.g. receiver/otlp")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	selection := make(map[collectorschema.ComponentType][]string)
+	for _, component := range positional {
+		componentType, componentName, found := strings.Cut(component, "/")
+		if !found || componentName == "" {
+			return fmt.Errorf("invalid component %q, expected <type>/<name>, e.g. receiver/otlp", component)
+		}
+		selection[collectorschema.ComponentType(componentType)] = append(selection[collectorschema.ComponentType(componentType)], componentName)
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	proto, err := protoschema.NewGenerator(*packageName).Generate(schemaManager, resolvedVersion, selection)
+	if err != nil {
+		return err
+	}
+
+	if output.json() {
+		return output.writeJSON(stdout, protoResult{Version: resolvedVersion, Components: positional, Proto: string(proto)})
+	}
+	return output.write(stdout, proto)
+}
+
+// runMerge implements "otelschema merge base.yaml overlay.yaml [overlay.yaml...]"
+func runMerge(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	validate := flags.Bool("validate", false, "Validate the merged config and report errors at the file that set the failing value")
+	output := addOutputFlags(flags, true, "yaml", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("expected at least one config file")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	layers := make([]collectorschema.ConfigLayer, 0, len(positional))
+	configs := make([][]byte, 0, len(positional))
+	for _, path := range positional {
+		config, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		layers = append(layers, collectorschema.ConfigLayer{Name: path, Config: config})
+		configs = append(configs, config)
+	}
+
+	var validationErrors []collectorschema.ConfigValidationError
+	if *validate {
+		schemaManager := collectorschema.NewSchemaManager()
+		resolvedVersion, err := resolveVersion(schemaManager, *version)
+		if err != nil {
+			return err
+		}
+		result, err := schemaManager.ValidateLayeredConfig(layers, resolvedVersion)
+		if err != nil {
+			return err
+		}
+		validationErrors = result.Errors
+	}
+
+	merged, err := collectorschema.MergeConfigs(configs[0], configs[1:]...)
+	if err != nil {
+		return err
+	}
+
+	if output.json() {
+		config, err := decodeYAML(merged)
+		if err != nil {
+			return err
+		}
+		if err := output.writeJSON(stdout, mergeResult{Config: config, Errors: validationErrors}); err != nil {
+			return err
+		}
+	} else if len(validationErrors) > 0 {
+		// The merged config of an invalid merge is not written, the errors point to the files to fix
+		var report bytes.Buffer
+		for _, validationError := range validationErrors {
+			if validationError.Position != nil {
+				fmt.Fprintf(&report, "%s:%s: %s\n", validationError.Layer, validationError.Position, validationError)
+			} else {
+				fmt.Fprintf(&report, "%s: %s\n", validationError.Layer, validationError)
+			}
+		}
+		if _, err := stdout.Write(report.Bytes()); err != nil {
+			return err
+		}
+	} else if err := output.write(stdout, merged); err != nil {
+		return err
+	}
+
+	if len(validationErrors) > 0 {
+		return fmt.Errorf("merged config is invalid")
+	}
+	return nil
+}
+
+// runForm implements "otelschema form receiver/otlp"
+func runForm(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("form", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	output := addOutputFlags(flags, true, "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected one component, e.g. receiver/otlp")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+	componentType, componentName, found := strings.Cut(positional[0], "/")
+	if !found || componentName == "" {
+		return fmt.Errorf("invalid component %q, expected <type>/<name>, e.g. receiver/otlp", positional[0])
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	model, err := schemaManager.GetFormModel(collectorschema.ComponentType(componentType), componentName, resolvedVersion)
+	if err != nil {
+		return err
+	}
+	return output.writeJSON(stdout, model)
+}
+
+// runChecksums implements "otelschema checksums schemas/0.139.0"
+func runChecksums(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("checksums", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version recorded in the manifest (defaults to the directory name)")
+	output := addOutputFlags(flags, true, "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected one schema directory")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+	if *version == "" {
+		*version = filepath.Base(positional[0])
+	}
+
+	manifest, err := collectorschema.CreateSchemaManifest(*version, positional[0])
+	if err != nil {
+		return err
+	}
+	return output.writeJSON(stdout, manifest)
+}
+
+// runSearch implements "otelschema search tls"
+func runSearch(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	output := addOutputFlags(flags, true, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected one search query, e.g. sampling_percentage")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	matches, err := schemaManager.SearchFields(resolvedVersion, positional[0])
+	if err != nil {
+		return err
+	}
+	if output.json() {
+		if matches == nil {
+			matches = []collectorschema.FieldMatch{}
+		}
+		return output.writeJSON(stdout, matches)
+	}
+	var report bytes.Buffer
+	for _, match := range matches {
+		fmt.Fprintf(&report, "%s/%s %s (%s)", match.ComponentType, match.Component, match.Path, match.Type)
+		if match.Description != "" {
+			fmt.Fprintf(&report, ": %s", match.Description)
+		}
+		fmt.Fprintln(&report)
+	}
+	return output.write(stdout, report.Bytes())
+}
+
+// runChanges implements "otelschema changes --from 0.137.0 --to 0.138.0"
+func runChanges(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("changes", flag.ContinueOnError)
+	from := flags.String("from", "", "Collector version to compare from")
+	to := flags.String("to", "", "Collector version to compare to (defaults to the latest embedded version)")
+	output := addOutputFlags(flags, true, "markdown", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+	if *from == "" {
+		return fmt.Errorf("--from is required")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *to)
+	if err != nil {
+		return err
+	}
+
+	changes, err := schemaManager.GetBreakingChanges(*from, resolvedVersion)
+	if err != nil {
+		return err
+	}
+
+	if output.json() {
+		if changes.Changes == nil {
+			changes.Changes = []collectorschema.SchemaChange{}
+		}
+		return output.writeJSON(stdout, changes)
+	}
+	return output.write(stdout, []byte(changes.Markdown()))
+}
+
+// fileResult is the validation result of a config file written by the validate and watch commands with --output json
+type fileResult struct {
+	Path   string                                  `json:"path"`
+	Valid  bool                                    `json:"valid"`
+	Errors []collectorschema.ConfigValidationError `json:"errors,omitempty"`
+	Error  string                                  `json:"error,omitempty"`
+}
+
+// newFileResult returns the JSON result of a validated config file
+func newFileResult(result collectorschema.ConfigFileResult) fileResult {
+	report := fileResult{Path: result.Path, Valid: result.Valid()}
+	if result.Err != nil {
+		report.Error = result.Err.Error()
+	} else {
+		report.Errors = result.Result.Errors
+	}
+	return report
+}
+
+// catalogSummary is the result of the catalog command with --output json
+type catalogSummary struct {
+	OutputDir     string   `json:"outputDir"`
+	Versions      []string `json:"versions"`
+	LatestVersion string   `json:"latestVersion"`
+}
+
+// serveEvent is written by the serve command with --output json once it accepts requests
+type serveEvent struct {
+	// Address is the address the schemas are served on, e.g. with the port chosen for --listen localhost:0
+	Address string `json:"address"`
+}
+
+// protoResult is the result of the proto command with --output json
+type protoResult struct {
+	Version string `json:"version"`
+	// Components are the requested components, e.g. receiver/otlp
+	Components []string `json:"components"`
+	Proto      string   `json:"proto"`
+}
+
+// mergeResult is the result of the merge command with --output json
+type mergeResult struct {
+	Config interface{} `json:"config"`
+	// Errors are the validation errors of the merged config with --validate
+	Errors []collectorschema.ConfigValidationError `json:"errors,omitempty"`
+}
+
+// runWatch implements "otelschema watch config.yaml"
+func runWatch(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	interval := flags.Duration("interval", collectorschema.DefaultWatchInterval, "Interval to check the file for changes at")
+	// JSON results are written one per line as the file changes
+	output := addOutputFlags(flags, false, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected one config file")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := watchContext()
+	defer cancel()
+
+	encoder := json.NewEncoder(stdout)
+	err = schemaManager.WatchConfigFile(ctx, positional[0], resolvedVersion, *interval, func(result collectorschema.ConfigFileResult) {
+		if output.json() {
+			_ = encoder.Encode(newFileResult(result))
+			return
+		}
+		fmt.Fprintf(stdout, "--- %s\n", time.Now().Format(time.TimeOnly))
+		writeTextReport(stdout, []collectorschema.ConfigFileResult{result})
+	})
+	// Watching ends when the command is interrupted
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+// runServe implements "otelschema serve --listen localhost:8080"
+func runServe(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := flags.String("listen", "localhost:8080", "Address to serve the schemas on")
+	output := addOutputFlags(flags, false, "text", "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           schemahttp.NewHandler(collectorschema.NewSchemaManager()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := watchContext()
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	if output.json() {
+		if err := writeJSON(stdout, serveEvent{Address: listener.Addr().String()}); err != nil {
+			_ = listener.Close()
+			return err
+		}
+	} else {
+		fmt.Fprintf(stdout, "Serving schemas on http://%s/<version>/bundle.json\n", listener.Addr())
+	}
+	// Serving ends when the command is interrupted
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// writeBundle writes the schema bundle of a version to a file
+func writeBundle(schemaManager *collectorschema.SchemaManager, version string, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := schemaManager.WriteBundle(file, version); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runCheck implements "otelschema check config.yaml"
+func runCheck(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	version := flags.String("version", "", "Collector version of the schemas (defaults to the latest embedded version)")
+	output := addOutputFlags(flags, true, "text", "json")
+	minSeverity := flags.String("min-severity", "info", "Least severe diagnostics to report: info, warning or error")
+	suppress := flags.String("suppress", "", "Comma separated diagnostic codes to skip, e.g. OTELCFG013,OTELCFG032")
+	profile := flags.String("profile", "", "Deployment profile of the config, e.g. production")
+	strict := flags.Bool("strict", false, "Report configuration keys that are not defined in the component schemas")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected one config file")
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+	severity := collectorschema.LintSeverity(*minSeverity)
+	if severity != collectorschema.LintSeverityInfo && severity != collectorschema.LintSeverityWarning && severity != collectorschema.LintSeverityError {
+		return fmt.Errorf("unknown severity %q, expected info, warning or error", *minSeverity)
+	}
+
+	config, err := os.ReadFile(positional[0])
+	if err != nil {
+		return err
+	}
+
+	schemaManager := collectorschema.NewSchemaManager()
+	resolvedVersion, err := resolveVersion(schemaManager, *version)
+	if err != nil {
+		return err
+	}
+
+	opts := []collectorschema.DiagnosticOption{
+		collectorschema.WithMinSeverity(severity),
+		collectorschema.WithLintOptions(collectorschema.WithLintProfile(*profile)),
+	}
+	if *strict {
+		opts = append(opts, collectorschema.WithValidationOptions(collectorschema.Strict()))
+	}
+	if *suppress != "" {
+		opts = append(opts, collectorschema.SuppressDiagnostics(strings.Split(*suppress, ",")...))
+	}
+	diagnostics, err := schemaManager.Diagnose(config, resolvedVersion, opts...)
+	if err != nil {
+		return err
+	}
+
+	if output.json() {
+		err = output.writeJSON(stdout, diagnostics)
+	} else {
+		var report bytes.Buffer
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Position != nil {
+				fmt.Fprintf(&report, "%s:%s: %s\n", positional[0], diagnostic.Position, diagnostic)
+			} else {
+				fmt.Fprintf(&report, "%s: %s\n", positional[0], diagnostic)
+			}
+		}
+		err = output.write(stdout, report.Bytes())
+	}
+	if err != nil {
+		return err
+	}
+
+	errors := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == collectorschema.LintSeverityError {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("%d diagnostics with severity error", errors)
+	}
+	return nil
+}
+
+// writeTextReport writes the validation errors of every file, one per line
+func writeTextReport(w io.Writer, results []collectorschema.ConfigFileResult) {
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			fmt.Fprintf(w, "%s: %v\n", result.Path, result.Err)
+		case result.Valid():
+			fmt.Fprintf(w, "%s: valid\n", result.Path)
+		default:
+			for _, validationError := range result.Result.Errors {
+				// file:line:column is understood by editors and terminals as a link to the location
+				if validationError.Position != nil {
+					fmt.Fprintf(w, "%s:%s: %s\n", result.Path, validationError.Position, validationError)
+				} else {
+					fmt.Fprintf(w, "%s: %s\n", result.Path, validationError)
+				}
+			}
+		}
+	}
+}
+
+// writeJSON writes the indented JSON of a command result followed by a newline
+func writeJSON(w io.Writer, value interface{}) error {
+	data, err := marshalResult(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// parseFlags parses flags that may be interspersed with positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveVersion returns the requested version or the latest embedded version if none was requested
+func resolveVersion(schemaManager *collectorschema.SchemaManager, version string) (string, error) {
+	if version != "" {
+		return version, nil
+	}
+	return schemaManager.GetLatestVersion()
+}
@@ -0,0 +1,575 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`
+
+// writeConfig writes a config file into a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// assertOutputSchema asserts that the JSON result of a command is valid against its definition in the output schema
+func assertOutputSchema(t *testing.T, command string, data []byte) {
+	t.Helper()
+	schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(outputSchema))
+	require.NoError(t, err)
+	result, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("urn:otelschema:output", schema))
+	compiled, err := compiler.Compile("urn:otelschema:output#/$defs/" + command)
+	require.NoError(t, err)
+	assert.NoError(t, compiled.Validate(result))
+}
+
+func TestRun_OutputSchema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"output-schema"}, &stdout, &stderr))
+	assert.Equal(t, outputSchema, stdout.Bytes())
+
+	var schema struct {
+		Defs map[string]interface{} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &schema))
+	for _, cmd := range commands {
+		assert.Contains(t, schema.Defs, cmd.name, "Every command has a JSON result")
+	}
+	assertOutputSchema(t, "output-schema", stdout.Bytes())
+
+	err := run([]string{"output-schema", "--output", "text"}, &stdout, &stderr)
+	assert.EqualError(t, err, `unknown format "text", expected json`)
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	require.NoError(t, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: otelschema")
+	assert.Contains(t, stderr.String(), "manifest")
+
+	err := run([]string{"unknown"}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown command "unknown"`)
+}
+
+func TestRun_Manifest(t *testing.T) {
+	configPath := writeConfig(t, testConfig)
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"manifest", configPath, "--version", "0.138.0", "--name", "otelcol-test"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	manifest := stdout.String()
+	assert.Contains(t, manifest, "name: otelcol-test")
+	assert.Contains(t, manifest, "go.opentelemetry.io/collector/receiver/otlpreceiver v0.138.0")
+	assert.Contains(t, manifest, "go.opentelemetry.io/collector/exporter/debugexporter v0.138.0")
+}
+
+func TestRun_Manifest_OutputFile(t *testing.T) {
+	configPath := writeConfig(t, testConfig)
+	outputPath := filepath.Join(t.TempDir(), "builder-config.yaml")
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"manifest", "--version=0.138.0", "--output-file", outputPath, configPath}, &stdout, &stderr)
+	require.NoError(t, err)
+	assert.Empty(t, stdout.String())
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "otlpreceiver")
+
+	err = run([]string{"manifest", "--version=0.138.0", "--name", "otelcol-test", "--output", "json", configPath}, &stdout, &stderr)
+	require.NoError(t, err)
+	assertOutputSchema(t, "manifest", stdout.Bytes())
+	var manifest map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifest))
+	assert.Equal(t, "otelcol-test", manifest["dist"].(map[string]interface{})["name"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"gomod": "go.opentelemetry.io/collector/receiver/otlpreceiver v0.138.0"}}, manifest["receivers"])
+
+	err = run([]string{"manifest", "--output", "xml", configPath}, &stdout, &stderr)
+	assert.EqualError(t, err, `unknown format "xml", expected yaml or json`)
+}
+
+func TestRun_Scaffold(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"scaffold", "traces=otlp -> batch -> debug", "--extensions", "health_check", "--version", "0.139.0"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "receivers:\n  otlp: {}\n")
+	assert.Contains(t, stdout.String(), "  extensions:\n    - health_check\n")
+
+	recipePath := writeConfig(t, "pipelines:\n  metrics: otlp -> prometheus\n")
+	outputPath := filepath.Join(t.TempDir(), "config.yaml")
+	stdout.Reset()
+	require.NoError(t, run([]string{"scaffold", recipePath, "--version", "0.139.0", "--output-file", outputPath}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "prometheus: {}")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"scaffold", "traces=otlp -> debug", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "scaffold", stdout.Bytes())
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &config))
+	assert.Equal(t, map[string]interface{}{"otlp": map[string]interface{}{}}, config["receivers"])
+
+	err = run([]string{"scaffold", "logs=otlp -> prometheus", "--version", "0.139.0"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, `exporter "prometheus" does not support the logs signal`)
+	err = run([]string{"scaffold", "traces=otlp"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected receivers -> processors -> exporters")
+	err = run([]string{"scaffold"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected a recipe file or pipelines")
+}
+
+func TestRun_Templates(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"templates", "--version", "0.139.0"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "gateway-traces: Receives traces from agents over OTLP")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"templates", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "templates", stdout.Bytes())
+	var templates []collectorschema.ConfigTemplate
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &templates))
+	assert.Len(t, templates, 3)
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"templates", "prometheus-scrape", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "templates", stdout.Bytes())
+	assert.Contains(t, stdout.String(), `"prometheusremotewrite": {`)
+
+	outputPath := filepath.Join(t.TempDir(), "config.yaml")
+	stdout.Reset()
+	require.NoError(t, run([]string{"templates", "prometheus-scrape", "--version", "0.139.0", "--output-file", outputPath}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "prometheusremotewrite:")
+
+	err = run([]string{"templates", "doesnotexist", "--version", "0.139.0"}, &stdout, &stderr)
+	assert.EqualError(t, err, "template doesnotexist not found for version 0.139.0")
+}
+
+func TestRun_Manifest_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run([]string{"manifest"}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected exactly one config file")
+
+	err = run([]string{"manifest", filepath.Join(t.TempDir(), "missing.yaml")}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+
+	invalidPath := writeConfig(t, "receivers:\n  doesnotexist:\n")
+	err = run([]string{"manifest", "--version", "0.138.0", invalidPath}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "collector configuration is invalid")
+}
+
+func TestRun_Validate(t *testing.T) {
+	configPath := writeConfig(t, testConfig)
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"validate", "--version", "0.138.0", configPath}, &stdout, &stderr))
+	assert.Equal(t, configPath+": valid\n", stdout.String())
+
+	invalidPath := writeConfig(t, "receivers:\n  doesnotexist:\n")
+	stdout.Reset()
+	err := run([]string{"validate", "--version", "0.138.0", configPath, invalidPath}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Equal(t, "1 of 2 config files are invalid", err.Error())
+	assert.Contains(t, stdout.String(), invalidPath+":2:3: receivers.doesnotexist: ")
+}
+
+func TestRun_Validate_JUnit(t *testing.T) {
+	configPath := writeConfig(t, testConfig)
+	outputPath := filepath.Join(t.TempDir(), "report.xml")
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"validate", "--version", "0.138.0", "--output", "junit", "--output-file", outputPath, configPath}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<testsuites name="otelschema" tests="3" failures="0" errors="0">`)
+	assert.Contains(t, string(data), `<testcase name="receivers.otlp" classname="`+configPath+`"></testcase>`)
+
+	err = run([]string{"validate", "--output", "xml", configPath}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown format "xml"`)
+
+	err = run([]string{"validate"}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected at least one config file")
+}
+
+func TestRun_Validate_JSON(t *testing.T) {
+	configPath := writeConfig(t, testConfig)
+	invalidPath := writeConfig(t, "receivers:\n  doesnotexist:\n")
+	missingPath := filepath.Join(t.TempDir(), "missing.yaml")
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"validate", "--version", "0.138.0", "--output", "json", configPath, invalidPath, missingPath}, &stdout, &stderr)
+	assert.EqualError(t, err, "2 of 3 config files are invalid")
+
+	assertOutputSchema(t, "validate", stdout.Bytes())
+	var results []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	require.Len(t, results, 3)
+	assert.Equal(t, map[string]interface{}{"path": configPath, "valid": true}, results[0])
+	assert.JSONEq(t, `{"path": "`+invalidPath+`", "valid": false, "errors": [{"path": "receivers.doesnotexist", "message": "unknown receiver type \"doesnotexist\"", "code": "unknown-component", "position": {"line": 2, "column": 3}}]}`, mustMarshal(t, results[1]))
+	assert.Equal(t, false, results[2]["valid"])
+	assert.Contains(t, results[2]["error"], "missing.yaml")
+}
+
+// mustMarshal returns the JSON of a value
+func mustMarshal(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRun_Bundle(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "bundle.json")
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"bundle", "--version", "0.139.0", "--output-file", outputPath}, &stdout, &stderr))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	manager := collectorschema.NewSchemaManager()
+	require.NoError(t, manager.RegisterSchemaBundle(data))
+
+	require.NoError(t, run([]string{"bundle", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "bundle", stdout.Bytes())
+	assert.JSONEq(t, string(data), stdout.String())
+
+	err = run([]string{"bundle", "extra"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "unexpected arguments")
+}
+
+func TestRun_Checksums(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "0.139.0")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	bundle := []byte(`{"$defs": {}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bundle.json"), bundle, 0644))
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"checksums", dir}, &stdout, &stderr))
+	assertOutputSchema(t, "checksums", stdout.Bytes())
+	manifest, err := collectorschema.ParseSchemaManifest(stdout.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "0.139.0", manifest.Version)
+	assert.NoError(t, manifest.Verify("bundle.json", bundle))
+
+	err = run([]string{"checksums"}, &stdout, &stderr)
+	assert.EqualError(t, err, "expected one schema directory")
+}
+
+func TestRun_Catalog(t *testing.T) {
+	outputDir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"catalog", "--base-url", "https://example.com/schemas/", "--output-dir", outputDir}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "Wrote catalog")
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "catalog.json"))
+	require.NoError(t, err)
+	var catalog collectorschema.SchemaCatalog
+	require.NoError(t, json.Unmarshal(data, &catalog))
+	require.Len(t, catalog.Schemas, 1)
+	assert.Equal(t, "https://example.com/schemas/otel-collector.json", catalog.Schemas[0].URL)
+	assert.Equal(t, "https://example.com/schemas/0.139.0/bundle.json", catalog.Schemas[0].Versions["0.139.0"])
+
+	assert.FileExists(t, filepath.Join(outputDir, "otel-collector.json"))
+	assert.FileExists(t, filepath.Join(outputDir, "0.135.0", "bundle.json"))
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"catalog", "--base-url", "https://example.com/schemas/", "--output-dir", outputDir, "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "catalog", stdout.Bytes())
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &summary))
+	assert.Equal(t, outputDir, summary["outputDir"])
+	assert.Contains(t, summary["versions"], "0.135.0")
+	assert.Equal(t, "0.139.0", summary["latestVersion"])
+
+	err = run([]string{"catalog"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "--base-url is required")
+}
+
+func TestRun_Proto(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"proto", "--version", "0.139.0", "--package", "example.config", "receiver/otlp", "processor/batch"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "package example.config;")
+	assert.Contains(t, stdout.String(), "message ReceiverOtlpConfig {")
+	assert.Contains(t, stdout.String(), "message ProcessorBatchConfig {")
+
+	proto := stdout.String()
+	stdout.Reset()
+	require.NoError(t, run([]string{"proto", "--version", "0.139.0", "--package", "example.config", "receiver/otlp", "processor/batch", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "proto", stdout.Bytes())
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.Equal(t, map[string]interface{}{"version": "0.139.0", "components": []interface{}{"receiver/otlp", "processor/batch"}, "proto": proto}, result)
+
+	err := run([]string{"proto", "otlp"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected <type>/<name>")
+	err = run([]string{"proto"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected at least one component")
+}
+
+func TestRun_Merge(t *testing.T) {
+	basePath := writeConfig(t, testConfig)
+	overlayPath := writeConfig(t, "processors:\n  batch:\n    send_batch_size: many\n")
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"merge", basePath, overlayPath}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "send_batch_size: many\n")
+
+	stdout.Reset()
+	err := run([]string{"merge", "--validate", "--version", "0.139.0", basePath, overlayPath}, &stdout, &stderr)
+	assert.EqualError(t, err, "merged config is invalid")
+	assert.Equal(t, overlayPath+":3:5: processors.batch.send_batch_size: Invalid type. Expected: integer, given: string\n", stdout.String())
+
+	stdout.Reset()
+	err = run([]string{"merge", "--validate", "--version", "0.139.0", "--output", "json", basePath, overlayPath}, &stdout, &stderr)
+	assert.EqualError(t, err, "merged config is invalid")
+	assertOutputSchema(t, "merge", stdout.Bytes())
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.Equal(t, map[string]interface{}{"batch": map[string]interface{}{"send_batch_size": "many"}}, result["config"].(map[string]interface{})["processors"])
+	assert.JSONEq(t, `[{"path": "processors.batch.send_batch_size", "message": "Invalid type. Expected: integer, given: string", "code": "invalid-type", "position": {"line": 3, "column": 5}, "layer": "`+overlayPath+`"}]`, mustMarshal(t, result["errors"]))
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"merge", "--output", "json", basePath}, &stdout, &stderr))
+	assertOutputSchema(t, "merge", stdout.Bytes())
+	assert.NotContains(t, stdout.String(), `"errors"`)
+
+	err = run([]string{"merge"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected at least one config file")
+}
+
+func TestRun_Form(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"form", "--version", "0.139.0", "processor/transform"}, &stdout, &stderr))
+	assertOutputSchema(t, "form", stdout.Bytes())
+	var model map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &model))
+	assert.Contains(t, model, "schema")
+	assert.Contains(t, model["uiSchema"], "trace_statements")
+	assert.NotContains(t, model, "formData")
+
+	err := run([]string{"form", "transform"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected <type>/<name>")
+	err = run([]string{"form"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected one component")
+}
+
+func TestRun_Search(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"search", "--version", "0.139.0", "sampling_percentage"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "processor/probabilistic_sampler sampling_percentage (number)")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"search", "--version", "0.139.0", "--output", "json", "sampling_percentage"}, &stdout, &stderr))
+	assertOutputSchema(t, "search", stdout.Bytes())
+	var matches []collectorschema.FieldMatch
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &matches))
+	var components []string
+	for _, match := range matches {
+		assert.Equal(t, "sampling_percentage", match.Path[strings.LastIndex(match.Path, ".")+1:])
+		components = append(components, string(match.ComponentType)+"/"+match.Component)
+	}
+	assert.Contains(t, components, "processor/probabilistic_sampler")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"search", "--version", "0.139.0", "--output", "json", "doesnotexist"}, &stdout, &stderr))
+	assert.Equal(t, "[]\n", stdout.String())
+
+	err := run([]string{"search", "--output", "xml", "tls"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+
+	err = run([]string{"search"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected one search query")
+}
+
+func TestRun_Docs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"docs", "exporter", "otlp", "--version", "0.139.0", "--field", "sending_queue"}, &stdout, &stderr))
+	assert.True(t, strings.HasPrefix(stdout.String(), "exporter/otlp 0.139.0\nsending_queue <object>\n├── batch <object>\n"), stdout.String())
+	assert.Contains(t, stdout.String(), "│   └── sizer <string> (one of: \"requests\", \"items\", \"bytes\")\n│         Sizer determines")
+	assert.NotContains(t, stdout.String(), "\x1b[", "Output that is not a terminal is not colorized")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"docs", "exporter/otlp", "--version", "0.139.0", "--field", "sending_queue", "--depth", "1", "--color", "always"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "\x1b[1mbatch\x1b[0m \x1b[32m<object>\x1b[0m")
+	assert.NotContains(t, stdout.String(), "flush_timeout", "Nested fields below the depth are not rendered")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"docs", "processor", "tail_sampling", "--version", "0.139.0", "--field", "policies[].type", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "docs", stdout.Bytes())
+	var doc collectorschema.FieldDoc
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &doc))
+	assert.Equal(t, "policies[].type", doc.Path)
+	assert.True(t, doc.Required)
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"docs", "exporters.otlp*.sending_queue.sizer", "--version", "0.139.0"}, &stdout, &stderr))
+	assert.True(t, strings.HasPrefix(stdout.String(), "exporters.otlp.sending_queue.sizer 0.139.0\nsizer <string>"), stdout.String())
+	assert.Contains(t, stdout.String(), "\n\nexporters.otlphttp.sending_queue.sizer 0.139.0\n")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"docs", "receivers.otlp.**.endpoint", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "docs", stdout.Bytes())
+	var matches []collectorschema.ExplainedField
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &matches))
+	require.Len(t, matches, 2)
+	assert.Equal(t, "receivers.otlp.protocols.grpc.endpoint", matches[0].Path)
+	assert.Equal(t, "string", matches[0].Doc.Type)
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"docs", "receivers.otlp.doesnotexist", "--version", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assert.JSONEq(t, `[]`, stdout.String())
+
+	err := run([]string{"docs", "receivers.otlp.doesnotexist", "--version", "0.139.0"}, &stdout, &stderr)
+	assert.EqualError(t, err, "no fields match receivers.otlp.doesnotexist in version 0.139.0")
+	err = run([]string{"docs", "receivers.otlp.protocols.grpc", "--field", "tls"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "--field is not supported")
+	err = run([]string{"docs", "exporter", "otlp", "--field", "doesnotexist"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, `has no field "doesnotexist"`)
+	err = run([]string{"docs", "otlp"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected a component type and name")
+	err = run([]string{"docs", "exporter", "otlp", "--color", "sometimes"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, `unknown color mode "sometimes"`)
+}
+
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, []string{"The quick brown fox jumps", "over the lazy dog"}, wrapText("The quick  brown fox jumps over the lazy dog", 25))
+	assert.Nil(t, wrapText("", 25))
+	assert.Equal(t, 4, visibleWidth("\x1b[2m│ \x1b[0m  "))
+}
+
+func TestRun_Changes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"changes", "--from", "0.137.0", "--to", "0.139.0"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "# Breaking changes from 0.137.0 to 0.139.0")
+	assert.Contains(t, stdout.String(), "## exporter/datadog\n")
+	assert.NotContains(t, stdout.String(), "## exporter/otlp\n", "Headers still accept the map form")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"changes", "--from", "0.139.0", "--to", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assert.JSONEq(t, `{"oldVersion": "0.139.0", "newVersion": "0.139.0", "changes": []}`, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"changes", "--from", "0.137.0", "--to", "0.139.0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "changes", stdout.Bytes())
+
+	err := run([]string{"changes"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "--from is required")
+	err = run([]string{"changes", "--from", "0.137.0", "--output", "html"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "unknown format")
+}
+
+func TestRun_Check(t *testing.T) {
+	configPath := writeConfig(t, "receivers:\n  zipkin:\n    endpoint: localhost:9411\n    unknown: true\nexporters:\n  debug:\nservice:\n  pipelines:\n    traces:\n      receivers: [zipkin]\n      exporters: [debug]\n")
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"check", configPath, "--version", "0.139.0", "--profile", "production", "--strict"}, &stdout, &stderr)
+	assert.EqualError(t, err, "1 diagnostics with severity error")
+	assert.Contains(t, stdout.String(), configPath+`:2:3: error: receivers.zipkin: unknown field "unknown" (OTELCFG003)`)
+	assert.Contains(t, stdout.String(), configPath+`:6:3: warning: exporters.debug: `)
+
+	stdout.Reset()
+	err = run([]string{"check", configPath, "--version", "0.139.0", "--profile", "production", "--strict", "--min-severity", "warning", "--suppress", "OTELCFG003,OTELCFG013", "--output", "json"}, &stdout, &stderr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, stdout.String())
+
+	stdout.Reset()
+	err = run([]string{"check", configPath, "--version", "0.139.0", "--profile", "production", "--strict", "--output", "json"}, &stdout, &stderr)
+	assert.EqualError(t, err, "1 diagnostics with severity error")
+	assertOutputSchema(t, "check", stdout.Bytes())
+
+	err = run([]string{"check"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected one config file")
+	err = run([]string{"check", configPath, "--min-severity", "fatal"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "unknown severity")
+}
+
+func TestRun_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("receivers:\n  unknown:\n"), 0644))
+
+	defaultWatchContext := watchContext
+	t.Cleanup(func() { watchContext = defaultWatchContext })
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 200*time.Millisecond)
+	}
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"watch", path, "--version", "0.139.0", "--interval", "10ms", "--output", "json"}, &stdout, &stderr))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assertOutputSchema(t, "watch", []byte(lines[0]))
+	assert.JSONEq(t, `{"path": "`+path+`", "valid": false, "errors": [{"path": "receivers.unknown", "message": "unknown receiver type \"unknown\"", "code": "unknown-component", "position": {"line": 2, "column": 3}}]}`, lines[0])
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"watch", path, "--version", "0.139.0"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), path+`:2:3: receivers.unknown: unknown receiver type "unknown"`)
+
+	err := run([]string{"watch"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "expected one config file")
+}
+
+func TestRun_Serve(t *testing.T) {
+	defaultWatchContext := watchContext
+	t.Cleanup(func() { watchContext = defaultWatchContext })
+	watchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 100*time.Millisecond)
+	}
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"serve", "--listen", "127.0.0.1:0"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "Serving schemas on http://127.0.0.1:")
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"serve", "--listen", "127.0.0.1:0", "--output", "json"}, &stdout, &stderr))
+	assertOutputSchema(t, "serve", stdout.Bytes())
+	var event map[string]string
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &event))
+	assert.Regexp(t, `^127\.0\.0\.1:[1-9][0-9]*$`, event["address"])
+
+	err := run([]string{"serve", "--listen", "256.0.0.1:0"}, &stdout, &stderr)
+	assert.Error(t, err)
+	err = run([]string{"serve", "extra"}, &stdout, &stderr)
+	assert.ErrorContains(t, err, "unexpected arguments")
+}
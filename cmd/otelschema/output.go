@@ -0,0 +1,152 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputSchema is the JSON Schema of the results commands write with --output json, the result of every command is
+// defined by #/$defs/<command>
+//
+//go:embed output.schema.json
+var outputSchema []byte
+
+// outputFlags are the --output and --output-file flags shared by all commands
+type outputFlags struct {
+	// formats are the output formats of the command, the first one is the default
+	formats []string
+	format  string
+	// file is the file to write the result to, it is empty for stdout
+	file string
+}
+
+// addOutputFlags registers --output with the formats of a command, the first format is the default. Commands that
+// write a single result also accept --output-file.
+func addOutputFlags(flags *flag.FlagSet, file bool, formats ...string) *outputFlags {
+	output := &outputFlags{formats: formats}
+	flags.StringVar(&output.format, "output", formats[0], "Output format: "+formatList(formats))
+	if file {
+		flags.StringVar(&output.file, "output-file", "", "File to write the result to (defaults to stdout)")
+	}
+	return output
+}
+
+// validate checks that the requested output format is supported by the command
+func (o *outputFlags) validate() error {
+	for _, format := range o.formats {
+		if o.format == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q, expected %s", o.format, formatList(o.formats))
+}
+
+// json returns whether the result is written as JSON
+func (o *outputFlags) json() bool {
+	return o.format == "json"
+}
+
+// write writes the result to --output-file or stdout
+func (o *outputFlags) write(stdout io.Writer, data []byte) error {
+	if o.file != "" {
+		return os.WriteFile(o.file, data, 0644)
+	}
+	_, err := stdout.Write(data)
+	return err
+}
+
+// writeJSON writes the indented JSON of the result to --output-file or stdout
+func (o *outputFlags) writeJSON(stdout io.Writer, value interface{}) error {
+	data, err := marshalResult(value)
+	if err != nil {
+		return err
+	}
+	return o.write(stdout, data)
+}
+
+// writeYAML writes a YAML result to --output-file or stdout, it is converted to JSON with --output json
+func (o *outputFlags) writeYAML(stdout io.Writer, data []byte) error {
+	if !o.json() {
+		return o.write(stdout, data)
+	}
+	value, err := decodeYAML(data)
+	if err != nil {
+		return err
+	}
+	return o.writeJSON(stdout, value)
+}
+
+// formatList returns the formats in "a, b or c" form
+func formatList(formats []string) string {
+	if len(formats) == 1 {
+		return formats[0]
+	}
+	return strings.Join(formats[:len(formats)-1], ", ") + " or " + formats[len(formats)-1]
+}
+
+// decodeYAML decodes a YAML document into a JSON compatible value, keys that are not strings are formatted
+func decodeYAML(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+	return jsonValue(value), nil
+}
+
+// jsonValue converts maps with keys that are not strings produced by the YAML decoder into JSON compatible maps
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// marshalResult returns the indented JSON of a command result followed by a newline
+func marshalResult(value interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// runOutputSchema implements "otelschema output-schema"
+func runOutputSchema(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("output-schema", flag.ContinueOnError)
+	output := addOutputFlags(flags, true, "json")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+	if err := output.validate(); err != nil {
+		return err
+	}
+	return output.write(stdout, outputSchema)
+}
@@ -0,0 +1,649 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Top-level sections of a collector configuration
+const (
+	sectionReceivers  = "receivers"
+	sectionProcessors = "processors"
+	sectionExporters  = "exporters"
+	sectionExtensions = "extensions"
+	sectionConnectors = "connectors"
+	sectionService    = "service"
+)
+
+// componentSections maps configuration sections to the component type they declare
+var componentSections = []struct {
+	section       string
+	componentType ComponentType
+}{
+	{sectionReceivers, ComponentTypeReceiver},
+	{sectionProcessors, ComponentTypeProcessor},
+	{sectionExporters, ComponentTypeExporter},
+	{sectionExtensions, ComponentTypeExtension},
+	{sectionConnectors, ComponentTypeConnector},
+}
+
+// pipelineSignals lists the signals that can be used as pipeline types
+var pipelineSignals = []string{"traces", "metrics", "logs", "profiles"}
+
+// ConfigValidationError describes a single problem found in a collector configuration
+type ConfigValidationError struct {
+	// Path is the dot separated location of the problem, e.g. "receivers.otlp.protocols"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// Code identifies the kind of problem for dedicated checks, e.g. ErrorCodeUnknownProviderScheme
+	Code string `json:"code,omitempty"`
+	// Position is the location of Path in the configuration, or of its closest parent if Path is missing, e.g. a
+	// required field. It is nil for problems without a location in the configuration.
+	Position *Position `json:"position,omitempty"`
+	// Layer is the name of the configuration layer that set the failing value, see ValidateLayeredConfig
+	Layer string `json:"layer,omitempty"`
+}
+
+// String returns the error in "path: message" form
+func (e ConfigValidationError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Codes of the errors reported for components and fields that are not defined in the schemas of a version
+const (
+	ErrorCodeUnknownComponent = "unknown-component"
+	ErrorCodeUnknownField     = "unknown-field"
+	ErrorCodeInvalidType      = "invalid-type"
+	// ErrorCodeUnsupportedSignal is reported for components in pipelines of a signal they do not implement
+	ErrorCodeUnsupportedSignal = "unsupported-signal"
+)
+
+// ConfigValidationResult holds the outcome of validating a collector configuration
+type ConfigValidationResult struct {
+	Errors []ConfigValidationError `json:"errors"`
+}
+
+// Valid returns true if no validation errors were found
+func (r *ConfigValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// addError records a validation error at the given path
+func (r *ConfigValidationResult) addError(path string, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, ConfigValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// addErrorWithCode records a validation error with a code at the given path
+func (r *ConfigValidationResult) addErrorWithCode(code string, path string, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, ConfigValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+		Code:    code,
+	})
+}
+
+// ValidationOption configures how a collector configuration is validated
+type ValidationOption func(*validationOptions)
+
+// validationOptions holds the settings applied by ValidationOption
+type validationOptions struct {
+	distribution *Distribution
+	strict       bool
+	ottl         bool
+	patterns     bool
+	// configProviders are the schemes of config providers accepted in addition to configProviderSchemes
+	configProviders []string
+	// parallelism is the number of configurations validated concurrently by ValidateMany
+	parallelism int
+	// fragment skips the checks of references between sections, see ValidateFragment
+	fragment bool
+}
+
+// WithDistribution rejects components that are not part of the given distribution
+func WithDistribution(distribution *Distribution) ValidationOption {
+	return func(options *validationOptions) {
+		options.distribution = distribution
+	}
+}
+
+// Strict rejects configuration keys that are not defined in the component schemas, e.g. misspelled fields.
+// Objects that accept arbitrary keys (maps) are not affected.
+func Strict() ValidationOption {
+	return func(options *validationOptions) {
+		options.strict = true
+	}
+}
+
+// ValidateOTTL parses the OTTL statements and conditions of the transform and filter processors and reports
+// syntax errors with their position. Paths, functions and enums are not resolved.
+func ValidateOTTL() ValidationOption {
+	return func(options *validationOptions) {
+		options.ottl = true
+	}
+}
+
+// ValidatePatterns compiles regular expressions and globs, e.g. of the attributes, filter and resource detection
+// processors and the filelog receiver, and reports invalid patterns with the compile error. Values of filter match
+// properties are compiled when their match_type is "regexp".
+func ValidatePatterns() ValidationOption {
+	return func(options *validationOptions) {
+		options.patterns = true
+	}
+}
+
+// ValidateCollectorConfig validates a full collector configuration (YAML or JSON) against the schemas of a version.
+// Every declared component is validated against its schema and the service section is checked for
+// references to undeclared components and, in profiles pipelines, components that do not implement profiles.
+// Extensions referenced from component configurations, e.g. authenticators and storage, must be declared, enabled
+// in the service and of the expected kind. References to config providers, e.g. ${env:API_KEY}, must use a known
+// provider scheme, a non-empty selector and balanced braces. An error is returned only if the configuration cannot
+// be parsed.
+func (sm *SchemaManager) ValidateCollectorConfig(config []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	return sm.ValidateCollectorConfigContext(context.Background(), config, version, opts...)
+}
+
+// ValidateCollectorConfigContext is ValidateCollectorConfig with a context, the validation stops with the error
+// of the context when it is canceled
+func (sm *SchemaManager) ValidateCollectorConfigContext(ctx context.Context, config []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	ctx, end := sm.telemetry.startCollectorConfigValidation(ctx, version)
+	result, err := sm.validateCollectorConfig(ctx, config, version, opts...)
+	end(result, err)
+	return result, err
+}
+
+// validateCollectorConfig validates a full collector configuration, see ValidateCollectorConfig
+func (sm *SchemaManager) validateCollectorConfig(ctx context.Context, config []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+	}
+	configMap, err := decodeCollectorConfig(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sm.validateCollectorConfigMap(ctx, configMap, version, options)
+	if err != nil {
+		return nil, err
+	}
+	result.addPositions(yamlPositions(&document))
+	return result, nil
+}
+
+// validateCollectorConfigMap validates a parsed collector configuration, see ValidateCollectorConfig
+func (sm *SchemaManager) validateCollectorConfigMap(ctx context.Context, configMap map[string]interface{}, version string, options *validationOptions) (*ConfigValidationResult, error) {
+	result := &ConfigValidationResult{}
+	for _, key := range sortedKeys(configMap) {
+		if !isKnownSection(key) {
+			result.addError(key, "unknown configuration section")
+		}
+	}
+
+	declared := make(map[string]map[string]bool)
+	var references []componentReference
+	for _, cs := range componentSections {
+		declared[cs.section] = make(map[string]bool)
+
+		value, exists := configMap[cs.section]
+		if !exists || value == nil {
+			continue
+		}
+
+		section, ok := value.(map[string]interface{})
+		if !ok {
+			result.addError(cs.section, "expected a map of components, got %s", describeValue(value))
+			continue
+		}
+
+		for _, id := range sortedKeys(section) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			declared[cs.section][id] = true
+			references = append(references, sm.validateComponentConfig(ctx, cs.componentType, cs.section, id, section[id], version, options, result)...)
+		}
+	}
+
+	// References of fragments point to components outside of the fragment
+	if options.fragment {
+		declared = nil
+	}
+	if service, exists := configMap[sectionService]; exists && service != nil {
+		validateService(service, declared, result)
+		if declared != nil {
+			sm.validateProfilesPipelines(ctx, service, declared, version, result)
+			validateRoutingPipelines(configMap[sectionConnectors], service, result)
+		}
+	}
+
+	if !options.fragment {
+		service, _ := configMap[sectionService].(map[string]interface{})
+		enabled, _ := service["extensions"].([]interface{})
+		validateComponentReferences(references, declared, enabled, result)
+	}
+	validateProviderReferences("", configMap, append(configProviderSchemes, options.configProviders...), result)
+
+	return result, nil
+}
+
+// validateComponentConfig validates the configuration of a single declared component and returns the
+// components referenced from it
+func (sm *SchemaManager) validateComponentConfig(ctx context.Context, componentType ComponentType, section string, id string, body interface{}, version string, options *validationOptions, result *ConfigValidationResult) []componentReference {
+	path := section + "." + id
+	componentID, err := ParseComponentID(id)
+	if err != nil {
+		result.addError(path, "%v", err)
+		return nil
+	}
+	componentName := componentID.Component
+
+	errorCount := len(result.Errors)
+	ctx, end := sm.telemetry.startValidation(ctx, componentType, componentName, version)
+	defer func() { end(len(result.Errors)-errorCount, nil) }()
+
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		result.addError(path, "failed to encode configuration: %v", err)
+		return nil
+	}
+
+	componentSchema, err := sm.getComponentSchema(ctx, componentType, componentName, version)
+	if err != nil {
+		result.addErrorWithCode(ErrorCodeUnknownComponent, path, "unknown %s type %q", componentType, componentName)
+		return nil
+	}
+
+	if options.distribution != nil && !options.distribution.Has(componentType, componentName) {
+		result.addError(path, "%s %q is not part of the %s distribution", componentType, componentName, options.distribution.Name)
+		return nil
+	}
+
+	validation, err := sm.validateJSON(componentSchema, options.strict, jsonData)
+	if err != nil {
+		result.addError(path, "%v", err)
+		return nil
+	}
+	embeddedErrors, err := sm.validateEmbeddedConfigs(ctx, componentSchema, body, version, options.strict)
+	if err != nil {
+		result.addError(path, "%v", err)
+		return nil
+	}
+	addSemanticErrors(validation, embeddedErrors)
+	addSemanticErrors(validation, validateComponentRules(componentType, componentName, body))
+	if options.ottl {
+		addSemanticErrors(validation, validateOTTL(componentType, componentName, body))
+	}
+	if options.patterns {
+		validation = withoutRegexFormatErrors(validation)
+		addSemanticErrors(validation, validatePatterns(componentSchema.Schema, body))
+	}
+
+	for _, validationError := range validation.Errors() {
+		fieldPath := path
+		if field := validationError.Field(); field != "" && field != "(root)" {
+			fieldPath = path + "." + field
+		}
+		switch validationError.Type() {
+		case "additional_property_not_allowed":
+			result.addErrorWithCode(ErrorCodeUnknownField, fieldPath, "unknown field %q", validationError.Details()["property"])
+		case "invalid_type":
+			result.addErrorWithCode(ErrorCodeInvalidType, fieldPath, "%s", validationError.Description())
+		default:
+			result.addError(fieldPath, "%s", validationError.Description())
+		}
+	}
+
+	return collectComponentReferences(componentSchema.Schema, path, body)
+}
+
+// closeObjectSchemas returns a copy of a schema where every object with declared properties
+// rejects additional properties. Objects that already define additionalProperties are left as they are.
+func closeObjectSchemas(schema map[string]interface{}) map[string]interface{} {
+	closed := make(map[string]interface{}, len(schema)+1)
+	for key, value := range schema {
+		switch key {
+		case "properties", "patternProperties", "$defs", "definitions":
+			if schemas, ok := value.(map[string]interface{}); ok {
+				closedSchemas := make(map[string]interface{}, len(schemas))
+				for name, subschema := range schemas {
+					closedSchemas[name] = closeSubschema(subschema)
+				}
+				value = closedSchemas
+			}
+		case "items", "additionalProperties", "not", "if", "then", "else", "contains":
+			value = closeSubschema(value)
+		case "allOf", "anyOf", "oneOf", "prefixItems":
+			if subschemas, ok := value.([]interface{}); ok {
+				closedSubschemas := make([]interface{}, len(subschemas))
+				for i, subschema := range subschemas {
+					closedSubschemas[i] = closeSubschema(subschema)
+				}
+				value = closedSubschemas
+			}
+		}
+		closed[key] = value
+	}
+
+	if _, hasProperties := schema["properties"]; hasProperties {
+		if _, exists := schema["additionalProperties"]; !exists {
+			closed["additionalProperties"] = false
+		}
+	}
+
+	return closed
+}
+
+// closeSubschema applies closeObjectSchemas to a subschema, boolean schemas are returned unchanged
+func closeSubschema(value interface{}) interface{} {
+	if schema, ok := value.(map[string]interface{}); ok {
+		return closeObjectSchemas(schema)
+	}
+	return value
+}
+
+// validateService checks pipelines and extensions referenced from the service section
+func validateService(value interface{}, declared map[string]map[string]bool, result *ConfigValidationResult) {
+	service, ok := value.(map[string]interface{})
+	if !ok {
+		result.addError(sectionService, "expected a map, got %s", describeValue(value))
+		return
+	}
+
+	if extensions, exists := service["extensions"]; exists && extensions != nil {
+		validateReferences(sectionService+".extensions", extensions, []string{sectionExtensions}, declared, result)
+	}
+
+	pipelinesValue, exists := service["pipelines"]
+	if !exists || pipelinesValue == nil {
+		return
+	}
+
+	pipelines, ok := pipelinesValue.(map[string]interface{})
+	if !ok {
+		result.addError(sectionService+".pipelines", "expected a map of pipelines, got %s", describeValue(pipelinesValue))
+		return
+	}
+
+	for _, pipelineID := range sortedKeys(pipelines) {
+		path := sectionService + ".pipelines." + pipelineID
+
+		if id, err := ParseComponentID(pipelineID); err != nil {
+			result.addError(path, "%v", err)
+		} else if !contains(pipelineSignals, id.Component) {
+			result.addError(path, "unknown pipeline signal %q, expected one of %s", id.Component, strings.Join(pipelineSignals, ", "))
+		}
+
+		pipeline, ok := pipelines[pipelineID].(map[string]interface{})
+		if !ok {
+			result.addError(path, "expected a map, got %s", describeValue(pipelines[pipelineID]))
+			continue
+		}
+
+		// Connectors act as exporters of one pipeline and receivers of another
+		validateReferences(path+".receivers", pipeline["receivers"], []string{sectionReceivers, sectionConnectors}, declared, result)
+		validateReferences(path+".processors", pipeline["processors"], []string{sectionProcessors}, declared, result)
+		validateReferences(path+".exporters", pipeline["exporters"], []string{sectionExporters, sectionConnectors}, declared, result)
+
+		for _, required := range []string{"receivers", "exporters"} {
+			if list, ok := pipeline[required].([]interface{}); !ok || len(list) == 0 {
+				result.addError(path, "pipeline must have at least one %s", strings.TrimSuffix(required, "s"))
+			}
+		}
+	}
+}
+
+// pipelineComponentSections maps the component lists of a pipeline to the sections their IDs are declared in
+var pipelineComponentSections = []struct {
+	list     string
+	sections []string
+}{
+	{"receivers", []string{sectionReceivers, sectionConnectors}},
+	{"processors", []string{sectionProcessors}},
+	{"exporters", []string{sectionExporters, sectionConnectors}},
+}
+
+// validateProfilesPipelines checks that the components of profiles pipelines implement the experimental profiles
+// signal, which only few components support. Components whose schema records no signals are not checked.
+func (sm *SchemaManager) validateProfilesPipelines(ctx context.Context, value interface{}, declared map[string]map[string]bool, version string, result *ConfigValidationResult) {
+	service, _ := value.(map[string]interface{})
+	pipelines, _ := service["pipelines"].(map[string]interface{})
+	for _, pipelineID := range sortedKeys(pipelines) {
+		id, err := ParseComponentID(pipelineID)
+		if err != nil || id.Component != "profiles" {
+			continue
+		}
+		pipeline, _ := pipelines[pipelineID].(map[string]interface{})
+
+		for _, pcs := range pipelineComponentSections {
+			list, _ := pipeline[pcs.list].([]interface{})
+			for i, item := range list {
+				componentID, _ := item.(string)
+				section := declaringSection(declared, pcs.sections, componentID)
+				if section == "" {
+					continue
+				}
+				metadata := sm.componentMetadata(ctx, section, componentID, version)
+				if metadata == nil || len(metadata.Signals) == 0 || supportsPipelineSignal(metadata, id.Component, section, pcs.list) {
+					continue
+				}
+				path := fmt.Sprintf("%s.pipelines.%s.%s[%d]", sectionService, pipelineID, pcs.list, i)
+				if section == sectionConnectors && pcs.list == "receivers" {
+					result.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "connector %q does not emit the %s signal", componentID, id.Component)
+				} else if section == sectionConnectors {
+					result.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "connector %q does not consume the %s signal", componentID, id.Component)
+				} else {
+					result.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "%s %q does not support the %s signal", strings.TrimSuffix(section, "s"), componentID, id.Component)
+				}
+			}
+		}
+	}
+}
+
+// declaringSection returns the first of the sections that declares a component ID, or an empty string
+func declaringSection(declared map[string]map[string]bool, sections []string, componentID string) string {
+	for _, section := range sections {
+		if declared[section][componentID] {
+			return section
+		}
+	}
+	return ""
+}
+
+// componentMetadata returns the metadata of a component declared in a configuration section, or nil if the
+// component is unknown or its schema has no metadata
+func (sm *SchemaManager) componentMetadata(ctx context.Context, section string, id string, version string) *ComponentMetadata {
+	componentID, err := ParseComponentID(id)
+	if err != nil {
+		return nil
+	}
+	for _, cs := range componentSections {
+		if cs.section != section {
+			continue
+		}
+		componentSchema, err := sm.getComponentSchema(ctx, cs.componentType, componentID.Component, version)
+		if err != nil {
+			return nil
+		}
+		return componentSchema.Metadata
+	}
+	return nil
+}
+
+// supportsPipelineSignal returns true if a component supports the signal of a pipeline. Connectors are checked
+// in the direction they are used: as exporter of the pipeline they consume the signal, as receiver they emit it.
+func supportsPipelineSignal(metadata *ComponentMetadata, signal string, section string, list string) bool {
+	if section != sectionConnectors {
+		return contains(metadata.Signals, signal)
+	}
+	for key := range metadata.Stability {
+		from, to, found := strings.Cut(key, "_to_")
+		if found && (list == "exporters" && from == signal || list == "receivers" && to == signal) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateReferences checks that every ID in a list is declared in one of the given sections, only the IDs are
+// checked if declared is nil
+func validateReferences(path string, value interface{}, sections []string, declared map[string]map[string]bool, result *ConfigValidationResult) {
+	if value == nil {
+		return
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		result.addError(path, "expected a list of component IDs, got %s", describeValue(value))
+		return
+	}
+
+	for i, item := range list {
+		id, ok := item.(string)
+		if !ok {
+			result.addError(fmt.Sprintf("%s[%d]", path, i), "expected a component ID string, got %s", describeValue(item))
+			continue
+		}
+		if declared == nil {
+			continue
+		}
+
+		found := false
+		for _, section := range sections {
+			if declared[section][id] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result.addError(fmt.Sprintf("%s[%d]", path, i), "references %q which is not declared in %s", id, strings.Join(sections, " or "))
+		}
+	}
+}
+
+// parseCollectorConfig parses a YAML or JSON collector configuration into a JSON compatible map
+func parseCollectorConfig(config []byte) (map[string]interface{}, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+	}
+
+	return decodeCollectorConfig(&document)
+}
+
+// decodeCollectorConfig decodes a parsed YAML document into a JSON compatible map
+func decodeCollectorConfig(document *yaml.Node) (map[string]interface{}, error) {
+	var raw interface{}
+	if document.Kind != 0 {
+		if err := document.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+		}
+	}
+
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	configMap, ok := normalizeYAMLValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("collector configuration must be a map, got %s", describeValue(raw))
+	}
+
+	return configMap, nil
+}
+
+// normalizeYAMLValue converts maps with non-string keys produced by the YAML decoder into JSON compatible maps
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAMLValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalizeYAMLValue(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isKnownSection checks if the key is a valid top-level configuration section
+func isKnownSection(key string) bool {
+	if key == sectionService {
+		return true
+	}
+	for _, cs := range componentSections {
+		if cs.section == key {
+			return true
+		}
+	}
+	return false
+}
+
+// describeValue returns a short description of a decoded value's type for error messages
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order for deterministic output
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contains checks if a string slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package collectorconfigschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ValidateCollectorConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+  otlp/internal:
+processors:
+  batch:
+    timeout: 1s
+exporters:
+  debug:
+    sampling_initial: 2
+extensions:
+  zpages:
+service:
+  extensions: [zpages]
+  pipelines:
+    traces:
+      receivers: [otlp, otlp/internal]
+      processors: [batch]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	for _, validationError := range result.Errors {
+		t.Errorf("Validation error: %s", validationError)
+	}
+	assert.True(t, result.Valid(), "Expected valid configuration to pass validation")
+}
+
+func TestSchemaManager_ValidateCollectorConfig_JSON(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`{
+		"receivers": {"otlp": {}},
+		"exporters": {"debug": {}},
+		"service": {"pipelines": {"logs": {"receivers": ["otlp"], "exporters": ["debug"]}}}
+	}`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected valid JSON configuration to pass validation: %v", result.Errors)
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Invalid(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+  doesnotexist:
+processors:
+  batch:
+    send_batch_size: "not a number"
+exporters:
+  debug:
+unknown_section: {}
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch, memory_limiter]
+      exporters: [debug]
+    spans:
+      receivers: [otlp]
+      exporters: [debug]
+    metrics:
+      receivers: [otlp]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	require.False(t, result.Valid())
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	joined := strings.Join(messages, "\n")
+	t.Logf("Validation errors:\n%s", joined)
+
+	assert.Contains(t, joined, "unknown_section: unknown configuration section")
+	assert.Contains(t, joined, "processors.batch.send_batch_size: Invalid type")
+	assert.Contains(t, joined, `receivers.doesnotexist: unknown receiver type "doesnotexist"`)
+	assert.Contains(t, joined, `service.extensions[0]: references "health_check" which is not declared in extensions`)
+	assert.Contains(t, joined, `service.pipelines.traces.processors[1]: references "memory_limiter"`)
+	assert.Contains(t, joined, `service.pipelines.spans: unknown pipeline signal "spans"`)
+	assert.Contains(t, joined, "service.pipelines.metrics: pipeline must have at least one exporter")
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Connectors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+exporters:
+  debug:
+connectors:
+  forward:
+service:
+  pipelines:
+    traces/in:
+      receivers: [otlp]
+      exporters: [forward]
+    traces/out:
+      receivers: [forward]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected connectors to be accepted in pipelines: %v", result.Errors)
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Profiles(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+  zipkin:
+processors:
+  batch:
+exporters:
+  debug:
+connectors:
+  count:
+  forward:
+service:
+  pipelines:
+    profiles:
+      receivers: [otlp, zipkin]
+      processors: [batch]
+      exporters: [debug, count]
+    profiles/out:
+      receivers: [count]
+      exporters: [debug]
+    metrics:
+      receivers: [count]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0")
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`service.pipelines.profiles.receivers[1]: receiver "zipkin" does not support the profiles signal`,
+		`service.pipelines.profiles.processors[0]: processor "batch" does not support the profiles signal`,
+		`service.pipelines.profiles/out.receivers[0]: connector "count" does not emit the profiles signal`,
+	}, messages)
+	assert.Equal(t, ErrorCodeUnsupportedSignal, result.Errors[0].Code)
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Malformed(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.ValidateCollectorConfig([]byte("receivers: [unclosed"), "0.138.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse collector configuration")
+
+	_, err = manager.ValidateCollectorConfig([]byte("- just\n- a list"), "0.138.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "collector configuration must be a map")
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Strict(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+processors:
+  batch:
+    send_batch_sise: 100
+exporters:
+  otlphttp:
+    headers:
+      X-Tenant: tenant-1
+    endpont: localhost:4318
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlphttp]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Unknown fields should be accepted by default: %v", result.Errors)
+
+	result, err = manager.ValidateCollectorConfig(config, "0.138.0", Strict())
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`exporters.otlphttp: unknown field "endpont"`,
+		`processors.batch: unknown field "send_batch_sise"`,
+	}, messages, "Map fields like headers should accept arbitrary keys")
+}
+
+func TestSchemaManager_ValidateCollectorConfig_StrictProtocols(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+        transport: tcp
+        max_recv_msg_size_mib: 16
+      http:
+        endpoint: 0.0.0.0:4318
+        cors:
+          allowed_origins: ["https://*.example.com"]
+  jaeger:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:14250
+      thrift_compact:
+        endpoint: 0.0.0.0:6831
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, jaeger]
+      exporters: [debug]
+`)
+
+	for _, version := range []string{"0.135.0", "0.139.0"} {
+		result, err := manager.ValidateCollectorConfig(config, version, Strict())
+		require.NoError(t, err)
+		assert.True(t, result.Valid(), "Protocols of %s should be accepted in strict mode: %v", version, result.Errors)
+	}
+
+	config = []byte(`
+receivers:
+  otlp:
+    grpc:
+      endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`)
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0", Strict())
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `receivers.otlp: unknown field "grpc"`, result.Errors[0].String())
+}
+
+func TestCloseObjectSchemas(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+			},
+			"labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
+			},
+		},
+		"default": map[string]interface{}{"properties": map[string]interface{}{}},
+	}
+
+	closed := closeObjectSchemas(schema)
+	properties := closed["properties"].(map[string]interface{})
+
+	assert.Equal(t, false, closed["additionalProperties"])
+	assert.Equal(t, false, properties["properties"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["labels"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, false, properties["items"].(map[string]interface{})["items"].(map[string]interface{})["additionalProperties"])
+	assert.NotContains(t, properties, "additionalProperties", "Property maps are not schemas")
+	assert.Equal(t, schema["default"], closed["default"], "Non-schema values should not be modified")
+	assert.NotContains(t, schema, "additionalProperties", "The original schema should not be modified")
+}
@@ -0,0 +1,78 @@
+package collectorconfigschema
+
+import "context"
+
+// CompatibilityReport holds the outcome of validating a collector configuration against several versions, see
+// CheckCompatibility
+type CompatibilityReport struct {
+	Versions []VersionCompatibility `json:"versions"`
+}
+
+// CompatibleVersions returns the versions the configuration is valid for, in the order they were checked
+func (r *CompatibilityReport) CompatibleVersions() []string {
+	var versions []string
+	for _, version := range r.Versions {
+		if version.Compatible() {
+			versions = append(versions, version.Version)
+		}
+	}
+	return versions
+}
+
+// VersionCompatibility summarizes the problems of a collector configuration with the schemas of a version
+type VersionCompatibility struct {
+	Version string `json:"version"`
+	// RemovedComponents are declared components without a schema in the version
+	RemovedComponents []ConfigValidationError `json:"removedComponents,omitempty"`
+	// RemovedFields are configured fields that are not defined in the schema of their component
+	RemovedFields []ConfigValidationError `json:"removedFields,omitempty"`
+	// TypeChanges are configured values whose type does not match the schema, e.g. a duration that became a number
+	TypeChanges []ConfigValidationError `json:"typeChanges,omitempty"`
+	// Errors are the other validation errors, e.g. values out of range or invalid pipelines
+	Errors []ConfigValidationError `json:"errors,omitempty"`
+}
+
+// Compatible returns true if the configuration is valid for the version
+func (v VersionCompatibility) Compatible() bool {
+	return len(v.RemovedComponents) == 0 && len(v.RemovedFields) == 0 && len(v.TypeChanges) == 0 && len(v.Errors) == 0
+}
+
+// CheckCompatibility validates a collector configuration (YAML or JSON) against the schemas of several versions and
+// summarizes the breakages per version, so an upgrade target can be picked in one call. Configurations are
+// validated in strict mode to report fields removed from a schema, the options are applied in addition. An error is
+// returned if the configuration cannot be parsed or a version is not available.
+func (sm *SchemaManager) CheckCompatibility(config []byte, versions []string, opts ...ValidationOption) (*CompatibilityReport, error) {
+	return sm.CheckCompatibilityContext(context.Background(), config, versions, opts...)
+}
+
+// CheckCompatibilityContext is CheckCompatibility with a context, the check stops with the error of the context when
+// it is canceled
+func (sm *SchemaManager) CheckCompatibilityContext(ctx context.Context, config []byte, versions []string, opts ...ValidationOption) (*CompatibilityReport, error) {
+	report := &CompatibilityReport{}
+	for _, version := range versions {
+		if _, err := sm.ListAvailableComponents(version); err != nil {
+			return nil, err
+		}
+
+		result, err := sm.ValidateCollectorConfigContext(ctx, config, version, append([]ValidationOption{Strict()}, opts...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		compatibility := VersionCompatibility{Version: version}
+		for _, validationError := range result.Errors {
+			switch validationError.Code {
+			case ErrorCodeUnknownComponent:
+				compatibility.RemovedComponents = append(compatibility.RemovedComponents, validationError)
+			case ErrorCodeUnknownField:
+				compatibility.RemovedFields = append(compatibility.RemovedFields, validationError)
+			case ErrorCodeInvalidType:
+				compatibility.TypeChanges = append(compatibility.TypeChanges, validationError)
+			default:
+				compatibility.Errors = append(compatibility.Errors, validationError)
+			}
+		}
+		report.Versions = append(report.Versions, compatibility)
+	}
+	return report, nil
+}
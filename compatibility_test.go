@@ -0,0 +1,59 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_CheckCompatibility(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "1.0.0", []byte(`{
+		"type": "object",
+		"properties": {"endpoint": {"type": "string"}, "timeout": {"type": "string"}}
+	}`)))
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "2.0.0", []byte(`{
+		"type": "object",
+		"properties": {"endpoints": {"type": "array"}, "timeout": {"type": "integer"}}
+	}`)))
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "debug", "3.0.0", []byte(`{"type": "object"}`)))
+
+	config := []byte(`
+exporters:
+  inhouse:
+    endpoint: backend:4317
+    timeout: 5s
+`)
+	report, err := manager.CheckCompatibility(config, []string{"1.0.0", "2.0.0", "3.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0"}, report.CompatibleVersions())
+	require.Len(t, report.Versions, 3)
+
+	assert.Equal(t, VersionCompatibility{Version: "1.0.0"}, report.Versions[0])
+	assert.Equal(t, VersionCompatibility{
+		Version:       "2.0.0",
+		RemovedFields: []ConfigValidationError{{Path: "exporters.inhouse", Message: `unknown field "endpoint"`, Code: ErrorCodeUnknownField, Position: &Position{Line: 3, Column: 3}}},
+		TypeChanges:   []ConfigValidationError{{Path: "exporters.inhouse.timeout", Message: "Invalid type. Expected: integer, given: string", Code: ErrorCodeInvalidType, Position: &Position{Line: 5, Column: 5}}},
+	}, report.Versions[1])
+	assert.Equal(t, VersionCompatibility{
+		Version:           "3.0.0",
+		RemovedComponents: []ConfigValidationError{{Path: "exporters.inhouse", Message: `unknown exporter type "inhouse"`, Code: ErrorCodeUnknownComponent, Position: &Position{Line: 3, Column: 3}}},
+	}, report.Versions[2])
+	assert.False(t, report.Versions[2].Compatible())
+}
+
+func TestSchemaManager_CheckCompatibility_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.CheckCompatibility([]byte("receivers: {}"), []string{"0.139.0", "0.0.1"})
+	require.Error(t, err)
+	_, err = manager.CheckCompatibility([]byte("receivers: ["), []string{"0.139.0"})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = manager.CheckCompatibilityContext(ctx, []byte("receivers: {otlp: {}}"), []string{"0.139.0"})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,237 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// componentConfigKeyword marks maps whose entries embed the configurations of other components keyed by their ID,
+// e.g. the receiver templates of the receiver_creator or the scrapers of the hostmetrics receiver. The value of the
+// keyword is a componentComposition.
+const componentConfigKeyword = "x-component-config"
+
+// componentComposition describes the component configurations embedded in a map, see componentConfigKeyword
+type componentComposition struct {
+	// Category is the category of the embedded components, e.g. "receiver". Categories without component schemas,
+	// e.g. "scraper", are resolved to the definitions of the embedding schema named "<category>_<name>".
+	Category string `json:"category"`
+	// Property is the property of an entry that holds the configuration, e.g. "config". The entry itself is the
+	// configuration if it is empty.
+	Property string `json:"property,omitempty"`
+	// Expressions marks configurations whose string values may hold backquoted expressions that are evaluated at
+	// runtime, e.g. "`endpoint`:8080". Such values are not validated.
+	Expressions bool `json:"expressions,omitempty"`
+}
+
+// embeddedConfig is the configuration of a component embedded in the configuration of another component
+type embeddedConfig struct {
+	composition componentComposition
+	// entryPath is the location of the map entry, e.g. ["receivers", "prometheus/internal"]
+	entryPath []string
+	// path is the location of the configuration, e.g. ["receivers", "prometheus/internal", "config"]
+	path  []string
+	name  string
+	value interface{}
+}
+
+// parseComponentComposition returns the value of the component config keyword, or nil if it is not a composition
+func parseComponentComposition(value interface{}) *componentComposition {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	composition := &componentComposition{}
+	composition.Category, _ = fields["category"].(string)
+	composition.Property, _ = fields["property"].(string)
+	composition.Expressions, _ = fields["expressions"].(bool)
+	if composition.Category == "" {
+		return nil
+	}
+	return composition
+}
+
+// collectEmbeddedConfigs walks a component configuration along its schema and returns the configurations of the
+// components embedded in maps marked with the component config keyword
+func collectEmbeddedConfigs(schema map[string]interface{}, config interface{}) []embeddedConfig {
+	var configs []embeddedConfig
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	walkEmbeddedConfigs(schema, definitions, nil, config, &configs)
+	return configs
+}
+
+// walkEmbeddedConfigs collects the embedded configurations of a single value and its children
+func walkEmbeddedConfigs(schema map[string]interface{}, definitions map[string]interface{}, path []string, value interface{}, configs *[]embeddedConfig) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+	if alternative := unionAlternative(schema, definitions, value); alternative != nil {
+		walkEmbeddedConfigs(alternative, definitions, path, value, configs)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if composition := parseComponentComposition(schema[componentConfigKeyword]); composition != nil {
+			for _, key := range sortedKeys(v) {
+				id, err := ParseComponentID(key)
+				if err != nil {
+					continue
+				}
+				embedded := embeddedConfig{
+					composition: *composition,
+					entryPath:   appendPath(path, key),
+					name:        id.Component,
+					value:       v[key],
+				}
+				embedded.path = embedded.entryPath
+				if composition.Property != "" {
+					entry, _ := v[key].(map[string]interface{})
+					embedded.path = appendPath(embedded.entryPath, composition.Property)
+					embedded.value = entry[composition.Property]
+				}
+				*configs = append(*configs, embedded)
+			}
+			return
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			if propertySchema != nil {
+				walkEmbeddedConfigs(propertySchema, definitions, appendPath(path, key), v[key], configs)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				walkEmbeddedConfigs(items, definitions, appendPath(path, fmt.Sprint(i)), item, configs)
+			}
+		}
+	}
+}
+
+// validateEmbeddedConfigs validates the configurations of the components embedded in the configuration of a
+// component against their own schemas, e.g. the receivers created by the receiver_creator. Embedded components
+// without a schema are reported, configurations of categories without component schemas and without a matching
+// definition are not checked.
+func (sm *SchemaManager) validateEmbeddedConfigs(ctx context.Context, componentSchema *ComponentSchema, config interface{}, version string, strict bool) ([]semanticError, error) {
+	var embeddedErrors []semanticError
+	for _, embedded := range collectEmbeddedConfigs(componentSchema.Schema, config) {
+		if embedded.value == nil {
+			continue
+		}
+
+		embeddedSchema := sm.embeddedSchema(ctx, componentSchema, embedded, version)
+		if embeddedSchema == nil {
+			if isValidComponentType(ComponentType(embedded.composition.Category)) {
+				embeddedErrors = append(embeddedErrors, semanticError{
+					path:      embedded.entryPath,
+					errorType: "unknown_component",
+					value:     embedded.name,
+					message:   fmt.Sprintf("unknown %s type %q", embedded.composition.Category, embedded.name),
+				})
+			}
+			continue
+		}
+
+		value := embedded.value
+		if embedded.composition.Expressions {
+			value = withoutExpressions(value)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode configuration of %s %s: %w", embedded.composition.Category, embedded.name, err)
+		}
+		result, err := sm.validateJSON(embeddedSchema, strict, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, resultError := range result.Errors() {
+			embeddedErrors = append(embeddedErrors, embeddedError(embedded.path, resultError))
+		}
+	}
+	return embeddedErrors, nil
+}
+
+// embeddedSchema returns the schema of an embedded configuration: the schema of the embedded component, or the
+// definition named "<category>_<name>" of the embedding schema. It returns nil if there is no schema.
+func (sm *SchemaManager) embeddedSchema(ctx context.Context, componentSchema *ComponentSchema, embedded embeddedConfig, version string) *ComponentSchema {
+	category := embedded.composition.Category
+	if componentType := ComponentType(category); isValidComponentType(componentType) {
+		schema, err := sm.getComponentSchema(ctx, componentType, embedded.name, version)
+		if err != nil {
+			return nil
+		}
+		return schema
+	}
+
+	definitions, _ := componentSchema.Schema["$defs"].(map[string]interface{})
+	definition := category + "_" + embedded.name
+	if _, ok := definitions[definition]; !ok {
+		return nil
+	}
+	// Compiled definitions are cached like components named after the embedding component, e.g. scraper
+	// hostmetrics/cpu
+	return &ComponentSchema{
+		Name:    componentSchema.Name + "/" + embedded.name,
+		Type:    ComponentType(category),
+		Version: componentSchema.Version,
+		Schema: map[string]interface{}{
+			"$ref":  definitionReferencePrefix + definition,
+			"$defs": definitions,
+		},
+	}
+}
+
+// embeddedError returns a schema validation error of an embedded configuration located in the embedding
+// configuration
+func embeddedError(path []string, resultError gojsonschema.ResultError) semanticError {
+	errorPath := path
+	if field := resultError.Field(); field != "" && field != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+		errorPath = appendPath(path, strings.Split(field, ".")...)
+	}
+	return semanticError{
+		path:      errorPath,
+		errorType: resultError.Type(),
+		value:     fmt.Sprint(resultError.Value()),
+		message:   resultError.Description(),
+		details:   resultError.Details(),
+	}
+}
+
+// withoutExpressions returns a copy of a configuration without the string values that hold backquoted
+// expressions, they are only known when the expressions are evaluated
+func withoutExpressions(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if s, ok := item.(string); ok && strings.Contains(s, "`") {
+				continue
+			}
+			copied[key] = withoutExpressions(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.Contains(s, "`") {
+				continue
+			}
+			copied = append(copied, withoutExpressions(item))
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,101 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ValidateComponentJSON_ReceiverCreator(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`{
+		"watch_observers": ["k8s_observer"],
+		"receivers": {
+			"redis/pods": {
+				"rule": "type == \"port\" && port == 6379",
+				"config": {"endpoint": "` + "`endpoint`" + `", "collection_interval": "10s", "transport": 5}
+			},
+			"doesnotexist": {
+				"rule": "type == \"pod\"",
+				"config": {}
+			},
+			"nginx": {
+				"rule": "type == \"port\" && port == 80"
+			}
+		}
+	}`)
+
+	result, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "receiver_creator", "0.139.0", config)
+	require.NoError(t, err)
+
+	var errors []string
+	for _, resultError := range result.Errors() {
+		errors = append(errors, resultError.Field()+": "+resultError.Description())
+	}
+	assert.ElementsMatch(t, []string{
+		`receivers.doesnotexist: unknown receiver type "doesnotexist"`,
+		"receivers.redis/pods.config.transport: Invalid type. Expected: string, given: integer",
+	}, errors)
+}
+
+func TestSchemaManager_ValidateCollectorConfig_EmbeddedConfigs(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+extensions:
+  k8s_observer:
+receivers:
+  receiver_creator:
+    watch_observers: [k8s_observer]
+    receivers:
+      redis:
+        rule: type == "port" && port == 6379
+        config:
+          endpoint: "` + "`endpoint`" + `"
+          unknown: true
+exporters:
+  debug:
+service:
+  extensions: [k8s_observer]
+  pipelines:
+    metrics:
+      receivers: [receiver_creator]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0", Strict())
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `receivers.receiver_creator.receivers.redis.config: unknown field "unknown"`, result.Errors[0].String())
+	assert.Equal(t, ErrorCodeUnknownField, result.Errors[0].Code)
+	require.NotNil(t, result.Errors[0].Position)
+	assert.Equal(t, 10, result.Errors[0].Position.Line)
+}
+
+func TestSchemaManager_ValidateComponentJSON_EmbeddedDefinitions(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "hostmetrics", "0.139.0", []byte(`{
+		"type": "object",
+		"properties": {
+			"scrapers": {
+				"type": "object",
+				"x-component-config": {"category": "scraper"}
+			}
+		},
+		"$defs": {
+			"scraper_cpu": {
+				"type": "object",
+				"properties": {"report_per_cpu": {"type": "boolean"}}
+			}
+		}
+	}`)))
+
+	result, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "hostmetrics", "0.139.0", []byte(`{
+		"scrapers": {"cpu": {"report_per_cpu": "yes"}, "memory": {"anything": 1}}
+	}`))
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "scrapers.cpu.report_per_cpu", result.Errors()[0].Field())
+}
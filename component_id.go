@@ -0,0 +1,64 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// componentIDSeparator separates the component type from the instance name, e.g. "otlp/internal"
+const componentIDSeparator = "/"
+
+// maxComponentNameLength is the maximum length of the instance name of a component ID
+const maxComponentNameLength = 1024
+
+var (
+	// componentTypeRegexp matches the type part of a component ID, mirrors the collector's component.Type validation
+	componentTypeRegexp = regexp.MustCompile(`^[a-zA-Z][0-9a-zA-Z_]{0,62}$`)
+	// componentNameRegexp matches the name part of a component ID: no whitespace, control characters, symbols or "/"
+	componentNameRegexp = regexp.MustCompile(`^[^\pZ\pC\pS/]+$`)
+)
+
+// componentNamePattern is the JSON schema (ECMA-262 compatible) pattern of the optional name part of a component ID
+const componentNamePattern = `(/[^\s/]+)?`
+
+// ComponentID identifies a component instance in a collector configuration, e.g. "otlp" or "otlp/internal"
+type ComponentID struct {
+	// Component is the component name the schemas are registered for, e.g. "otlp"
+	Component string `json:"component"`
+	// Name is the optional instance name, e.g. "internal"
+	Name string `json:"name,omitempty"`
+}
+
+// String returns the ID in "component[/name]" form
+func (id ComponentID) String() string {
+	if id.Name == "" {
+		return id.Component
+	}
+	return id.Component + componentIDSeparator + id.Name
+}
+
+// ParseComponentID parses a component ID of the form "component[/name]" as used for the keys of
+// the receivers, processors, exporters, extensions and connectors sections and in service pipelines
+func ParseComponentID(id string) (ComponentID, error) {
+	component, name, hasName := strings.Cut(strings.TrimSpace(id), componentIDSeparator)
+
+	if !componentTypeRegexp.MatchString(component) {
+		return ComponentID{}, fmt.Errorf("invalid component ID %q: type %q must start with a letter and contain only letters, digits and underscores (at most 63 characters)", id, component)
+	}
+
+	if hasName {
+		if name == "" {
+			return ComponentID{}, fmt.Errorf("invalid component ID %q: name must not be empty after %q", id, componentIDSeparator)
+		}
+		if utf8.RuneCountInString(name) > maxComponentNameLength {
+			return ComponentID{}, fmt.Errorf("invalid component ID %q: name must not be longer than %d characters", id, maxComponentNameLength)
+		}
+		if !componentNameRegexp.MatchString(name) {
+			return ComponentID{}, fmt.Errorf("invalid component ID %q: name %q must not contain whitespace, control characters, symbols or %q", id, name, componentIDSeparator)
+		}
+	}
+
+	return ComponentID{Component: component, Name: name}, nil
+}
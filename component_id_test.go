@@ -0,0 +1,87 @@
+package collectorconfigschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComponentID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected ComponentID
+	}{
+		{"otlp", ComponentID{Component: "otlp"}},
+		{"otlp/internal", ComponentID{Component: "otlp", Name: "internal"}},
+		{"batch/2", ComponentID{Component: "batch", Name: "2"}},
+		{"k8s_cluster/my-cluster.prod", ComponentID{Component: "k8s_cluster", Name: "my-cluster.prod"}},
+		{" traces/ingest ", ComponentID{Component: "traces", Name: "ingest"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			id, err := ParseComponentID(test.id)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, id)
+			assert.Equal(t, strings.TrimSpace(test.id), id.String())
+		})
+	}
+}
+
+func TestParseComponentID_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                                  "must start with a letter",
+		"2otlp":                             "must start with a letter",
+		"otlp-http":                         "must start with a letter",
+		"otlp/":                             "name must not be empty",
+		"otlp/internal/2":                   `must not contain whitespace, control characters, symbols or "/"`,
+		"otlp/my name":                      "must not contain whitespace",
+		"otlp/a+b":                          "must not contain whitespace, control characters, symbols",
+		"otlp/" + strings.Repeat("a", 1025): "must not be longer than 1024 characters",
+	}
+
+	for id, message := range tests {
+		_, err := ParseComponentID(id)
+		require.Error(t, err, id)
+		assert.Contains(t, err.Error(), message, id)
+	}
+}
+
+func TestSchemaManager_ValidateCollectorConfig_ComponentIDs(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp/internal:
+  otlp/:
+processors:
+  batch/2:
+    send_batch_size: "not a number"
+exporters:
+  debug/my exporter:
+  debug:
+service:
+  pipelines:
+    traces/:
+      receivers: [otlp/internal]
+      processors: [batch/2]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	joined := strings.Join(messages, "\n")
+
+	assert.Contains(t, joined, `receivers.otlp/: invalid component ID "otlp/": name must not be empty`)
+	assert.Contains(t, joined, "processors.batch/2.send_batch_size: Invalid type")
+	assert.Contains(t, joined, `exporters.debug/my exporter: invalid component ID "debug/my exporter"`)
+	assert.Contains(t, joined, `service.pipelines.traces/: invalid component ID "traces/"`)
+	assert.NotContains(t, joined, "otlp/internal")
+}
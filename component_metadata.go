@@ -0,0 +1,145 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// componentMetadataKeyword is the keyword of generated schemas that holds the component metadata
+const componentMetadataKeyword = "x-otel"
+
+// stabilityLevels are the stability levels of components from least to most stable
+var stabilityLevels = []string{"unmaintained", "deprecated", "development", "alpha", "beta", "stable"}
+
+// ComponentMetadata describes a component, it is recorded in generated schemas under the x-otel keyword
+type ComponentMetadata struct {
+	// Module is the Go module path of the component, e.g. go.opentelemetry.io/collector/receiver/otlpreceiver
+	Module string `json:"module,omitempty"`
+	// Version is the version of the module
+	Version string `json:"version,omitempty"`
+	// Signals are the signals the component supports: traces, metrics, logs and profiles
+	Signals []string `json:"signals,omitempty"`
+	// Stability is the stability level (e.g. alpha, beta, stable) per signal, following the component metadata.yaml:
+	// connectors use keys like traces_to_metrics and extensions the key extension
+	Stability map[string]string `json:"stability,omitempty"`
+	// Documentation is the URL of the component sources and README
+	Documentation string `json:"documentation,omitempty"`
+}
+
+// parseComponentMetadata returns the metadata recorded in a component schema, or nil if the schema has none
+func parseComponentMetadata(schema map[string]interface{}) *ComponentMetadata {
+	value, exists := schema[componentMetadataKeyword]
+	if !exists {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var metadata ComponentMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil
+	}
+	return &metadata
+}
+
+// SignalStability returns the most stable level of a signal, including connectors from and to the signal,
+// or of any signal for an empty signal. It returns an empty string if the signal is not supported.
+func (m *ComponentMetadata) SignalStability(signal string) string {
+	stability := ""
+	for key, level := range m.Stability {
+		if signal != "" && key != signal && !strings.HasPrefix(key, signal+"_to_") && !strings.HasSuffix(key, "_to_"+signal) {
+			continue
+		}
+		if stabilityRank(level) > stabilityRank(stability) {
+			stability = level
+		}
+	}
+	return stability
+}
+
+// stabilityRank returns the position of a stability level in stabilityLevels, or -1 for unknown levels
+func stabilityRank(level string) int {
+	for i, known := range stabilityLevels {
+		if known == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetComponentMetadata returns the metadata recorded in the schema of a component
+func (sm *SchemaManager) GetComponentMetadata(componentType ComponentType, componentName string, version string) (*ComponentMetadata, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Metadata == nil {
+		return nil, fmt.Errorf("no metadata for component %s %s", componentType, componentName)
+	}
+	return schema.Metadata, nil
+}
+
+// ListOption filters the components returned by ListComponents
+type ListOption func(*listOptions)
+
+// listOptions holds the filters applied by ListOption
+type listOptions struct {
+	signal       string
+	minStability string
+}
+
+// WithSignal lists components supporting a signal: traces, metrics, logs or profiles. Extensions do not support signals.
+func WithSignal(signal string) ListOption {
+	return func(options *listOptions) {
+		options.signal = signal
+	}
+}
+
+// WithMinStability lists components with at least the given stability level, e.g. beta hides development and
+// alpha components. With WithSignal the stability of that signal is compared.
+func WithMinStability(level string) ListOption {
+	return func(options *listOptions) {
+		options.minStability = level
+	}
+}
+
+// ListComponents returns the components of a version by type, filtered by signal and stability.
+// Components without metadata are only listed without filters.
+func (sm *SchemaManager) ListComponents(version string, opts ...ListOption) (map[ComponentType][]string, error) {
+	options := &listOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.minStability != "" && stabilityRank(options.minStability) < 0 {
+		return nil, fmt.Errorf("unknown stability level %q, expected one of %s", options.minStability, strings.Join(stabilityLevels, ", "))
+	}
+
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+	if options.signal == "" && options.minStability == "" {
+		return components, nil
+	}
+
+	filtered := make(map[ComponentType][]string)
+	for componentType, names := range components {
+		for _, componentName := range names {
+			metadata, err := sm.GetComponentMetadata(componentType, componentName, version)
+			if err != nil {
+				continue
+			}
+
+			stability := metadata.SignalStability(options.signal)
+			if stability == "" || stabilityRank(stability) < stabilityRank(options.minStability) {
+				continue
+			}
+			filtered[componentType] = append(filtered[componentType], componentName)
+		}
+	}
+
+	return filtered, nil
+}
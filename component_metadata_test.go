@@ -0,0 +1,119 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inhouseSchemaWithMetadata = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "endpoint": {"type": "string"}
+  },
+  "x-otel": {
+    "module": "example.com/inhouse/inhousereceiver",
+    "version": "v1.2.0",
+    "signals": ["traces", "logs"],
+    "stability": {"logs": "alpha", "traces": "beta"}
+  }
+}`
+
+func TestSchemaManager_ComponentMetadata(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(inhouseSchemaWithMetadata)))
+
+	schema, err := manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, &ComponentMetadata{
+		Module:    "example.com/inhouse/inhousereceiver",
+		Version:   "v1.2.0",
+		Signals:   []string{"traces", "logs"},
+		Stability: map[string]string{"logs": "alpha", "traces": "beta"},
+	}, schema.Metadata)
+
+	schema, err = manager.GetComponentSchema(ComponentTypeConnector, "spanmetrics", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, &ComponentMetadata{
+		Module:        "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector",
+		Version:       "v0.139.0",
+		Signals:       []string{"traces", "metrics"},
+		Stability:     map[string]string{"traces_to_metrics": "alpha"},
+		Documentation: "https://github.com/open-telemetry/opentelemetry-collector-contrib/tree/v0.139.0/connector/spanmetricsconnector",
+	}, schema.Metadata)
+
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "plain", "", []byte(inhouseSchema)))
+	schema, err = manager.GetComponentSchema(ComponentTypeReceiver, "plain", "0.139.0")
+	require.NoError(t, err)
+	assert.Nil(t, schema.Metadata, "Schemas without the x-otel keyword have no metadata")
+}
+
+func TestParseComponentMetadata_Invalid(t *testing.T) {
+	assert.Nil(t, parseComponentMetadata(map[string]interface{}{"x-otel": "beta"}))
+	assert.Nil(t, parseComponentMetadata(map[string]interface{}{}))
+}
+
+func TestSchemaManager_GetComponentMetadata(t *testing.T) {
+	manager := NewSchemaManager()
+
+	metadata, err := manager.GetComponentMetadata(ComponentTypeExtension, "zpages", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, "go.opentelemetry.io/collector/extension/zpagesextension", metadata.Module)
+	assert.Equal(t, map[string]string{"extension": "beta"}, metadata.Stability)
+
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "plain", "", []byte(inhouseSchema)))
+	_, err = manager.GetComponentMetadata(ComponentTypeReceiver, "plain", "0.139.0")
+	assert.ErrorContains(t, err, "no metadata")
+
+	_, err = manager.GetComponentMetadata(ComponentTypeReceiver, "missing", "0.139.0")
+	assert.Error(t, err)
+}
+
+func TestSchemaManager_ListComponents(t *testing.T) {
+	manager := NewSchemaManager()
+
+	all, err := manager.ListComponents("0.139.0")
+	require.NoError(t, err)
+	available, err := manager.ListAvailableComponents("0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, available, all)
+
+	metrics, err := manager.ListComponents("0.139.0", WithSignal("metrics"))
+	require.NoError(t, err)
+	assert.Contains(t, metrics[ComponentTypeReceiver], "prometheus")
+	assert.NotContains(t, metrics[ComponentTypeReceiver], "filelog", "filelog only receives logs")
+	assert.Contains(t, metrics[ComponentTypeConnector], "spanmetrics", "Connectors support the signals of both pipelines")
+	assert.Empty(t, metrics[ComponentTypeExtension])
+
+	stable, err := manager.ListComponents("0.139.0", WithMinStability("stable"))
+	require.NoError(t, err)
+	assert.Contains(t, stable[ComponentTypeReceiver], "otlp")
+	assert.NotContains(t, stable[ComponentTypeReceiver], "kafka")
+
+	// The otlp receiver is stable for traces but in development for profiles
+	profiles, err := manager.ListComponents("0.139.0", WithSignal("profiles"), WithMinStability("beta"))
+	require.NoError(t, err)
+	assert.NotContains(t, profiles[ComponentTypeReceiver], "otlp")
+	for componentType, names := range profiles {
+		for _, name := range names {
+			metadata, err := manager.GetComponentMetadata(componentType, name, "0.139.0")
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, stabilityRank(metadata.SignalStability("profiles")), stabilityRank("beta"), name)
+		}
+	}
+
+	_, err = manager.ListComponents("0.139.0", WithMinStability("experimental"))
+	assert.ErrorContains(t, err, "unknown stability level")
+}
+
+func TestComponentMetadata_SignalStability(t *testing.T) {
+	metadata := &ComponentMetadata{Stability: map[string]string{"traces_to_metrics": "alpha", "metrics_to_metrics": "beta", "logs_to_logs": "development"}}
+
+	assert.Equal(t, "beta", metadata.SignalStability("metrics"))
+	assert.Equal(t, "alpha", metadata.SignalStability("traces"))
+	assert.Equal(t, "development", metadata.SignalStability("logs"))
+	assert.Equal(t, "", metadata.SignalStability("profiles"))
+	assert.Equal(t, "beta", metadata.SignalStability(""))
+}
@@ -0,0 +1,167 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// componentReferenceKeyword marks schema properties whose value is the ID of another component,
+// the value of the keyword is the section of the referenced component, e.g. "extension"
+const componentReferenceKeyword = "x-component-reference"
+
+// componentReference is a component ID used in the configuration of another component
+type componentReference struct {
+	// path is the location of the reference, e.g. "exporters.otlp.auth.authenticator"
+	path string
+	// field is the configuration key holding the reference, e.g. "authenticator"
+	field string
+	id    string
+}
+
+// extensionKind groups extensions that provide the same capability, e.g. authenticators
+type extensionKind struct {
+	name string
+	// fields are the configuration keys that reference extensions of this kind
+	fields []string
+	// extensions are the names of the extensions of this kind
+	extensions []string
+	// suffix matches extension names of this kind by naming convention, e.g. "_encoding"
+	suffix string
+}
+
+// extensionKinds lists the extension capabilities checked for references. References to extensions
+// that are not listed here, e.g. custom extensions, are accepted for any field.
+var extensionKinds = []extensionKind{
+	{
+		name:       "authenticator",
+		fields:     []string{"authenticator"},
+		extensions: []string{"asapclient", "azureauth", "basicauth", "bearertokenauth", "googleclientauth", "headers_setter", "oauth2client", "oidc", "sigv4auth", "sumologic"},
+	},
+	{
+		name:       "storage",
+		fields:     []string{"storage"},
+		extensions: []string{"db_storage", "file_storage", "redis_storage"},
+	},
+	{
+		name:   "encoding",
+		fields: []string{"encoding", "encoding_extension"},
+		suffix: "_encoding",
+	},
+	{
+		name:   "observer",
+		fields: []string{"watch_observers"},
+		suffix: "_observer",
+	},
+}
+
+// matches reports whether an extension is of this kind
+func (k extensionKind) matches(extension string) bool {
+	return contains(k.extensions, extension) || (k.suffix != "" && strings.HasSuffix(extension, k.suffix))
+}
+
+// lookupExtensionKind returns the kind of extensions a field references or nil if the field is not classified
+func lookupExtensionKind(field string) *extensionKind {
+	for i := range extensionKinds {
+		if contains(extensionKinds[i].fields, field) {
+			return &extensionKinds[i]
+		}
+	}
+	return nil
+}
+
+// classifyExtension returns the kind of an extension or nil if it is not classified
+func classifyExtension(extension string) *extensionKind {
+	for i := range extensionKinds {
+		if extensionKinds[i].matches(extension) {
+			return &extensionKinds[i]
+		}
+	}
+	return nil
+}
+
+// collectComponentReferences walks a component configuration along its schema and returns the values
+// of properties marked with the component reference keyword
+func collectComponentReferences(schema map[string]interface{}, path string, value interface{}) []componentReference {
+	var references []componentReference
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	walkComponentReferences(schema, definitions, path, "", value, &references)
+	return references
+}
+
+// walkComponentReferences collects the references of a single value, field is the configuration key of the value
+func walkComponentReferences(schema map[string]interface{}, definitions map[string]interface{}, path string, field string, value interface{}, references *[]componentReference) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+
+	if section, ok := schema[componentReferenceKeyword].(string); ok && section == "extension" {
+		if id, ok := value.(string); ok {
+			*references = append(*references, componentReference{path: path, field: field, id: id})
+		}
+		return
+	}
+	if alternative := unionAlternative(schema, definitions, value); alternative != nil {
+		walkComponentReferences(alternative, definitions, path, field, value, references)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			if propertySchema != nil {
+				walkComponentReferences(propertySchema, definitions, path+"."+key, key, v[key], references)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				walkComponentReferences(items, definitions, fmt.Sprintf("%s[%d]", path, i), field, item, references)
+			}
+		}
+	}
+}
+
+// validateComponentReferences checks that referenced extensions are declared, enabled in the service
+// and of the kind the referencing field expects
+func validateComponentReferences(references []componentReference, declared map[string]map[string]bool, enabled []interface{}, result *ConfigValidationResult) {
+	for _, reference := range references {
+		id, err := ParseComponentID(reference.id)
+		if err != nil {
+			// Malformed IDs are reported by the schema pattern
+			continue
+		}
+
+		if !declared[sectionExtensions][reference.id] {
+			result.addError(reference.path, "references %q which is not declared in %s", reference.id, sectionExtensions)
+			continue
+		}
+
+		if !containsValue(enabled, reference.id) {
+			result.addError(reference.path, "references %q which is not enabled in %s.%s", reference.id, sectionService, sectionExtensions)
+		}
+
+		expected := lookupExtensionKind(reference.field)
+		actual := classifyExtension(id.Component)
+		if expected != nil && actual != nil && expected.name != actual.name {
+			result.addError(reference.path, "references %q of kind %s, expected an extension of kind %s", reference.id, actual.name, expected.name)
+		}
+	}
+}
+
+// containsValue checks if a decoded list contains a string value
+func containsValue(values []interface{}, value string) bool {
+	for _, v := range values {
+		if s, ok := v.(string); ok && s == value {
+			return true
+		}
+	}
+	return false
+}
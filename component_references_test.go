@@ -0,0 +1,140 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ValidateCollectorConfig_ComponentReferences(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  zipkin:
+    auth:
+      authenticator: oidc
+exporters:
+  elasticsearch:
+    auth:
+      authenticator: basicauth/client
+    sending_queue:
+      storage: file_storage/queue
+extensions:
+  oidc:
+  basicauth/client:
+  file_storage/queue:
+service:
+  extensions: [oidc, basicauth/client, file_storage/queue]
+  pipelines:
+    logs:
+      receivers: [zipkin]
+      exporters: [elasticsearch]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected declared and enabled extensions to be accepted: %v", result.Errors)
+}
+
+func TestSchemaManager_ValidateCollectorConfig_InvalidComponentReferences(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  zipkin:
+    auth:
+      authenticator: oidc
+exporters:
+  elasticsearch:
+    sending_queue:
+      storage: basicauth
+  elasticsearch/2:
+    sending_queue:
+      storage: file_storage
+extensions:
+  basicauth:
+  file_storage:
+service:
+  extensions: [basicauth]
+  pipelines:
+    traces:
+      receivers: [zipkin]
+      exporters: [elasticsearch, elasticsearch/2]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`receivers.zipkin.auth.authenticator: references "oidc" which is not declared in extensions`,
+		`exporters.elasticsearch.sending_queue.storage: references "basicauth" of kind authenticator, expected an extension of kind storage`,
+		`exporters.elasticsearch/2.sending_queue.storage: references "file_storage" which is not enabled in service.extensions`,
+	}, messages)
+}
+
+func TestCollectComponentReferences(t *testing.T) {
+	reference := map[string]interface{}{"type": "string", componentReferenceKeyword: "extension"}
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"auth":          map[string]interface{}{"$ref": "#/$defs/auth"},
+			"observers":     map[string]interface{}{"type": "array", "items": reference},
+			"named_storage": map[string]interface{}{"type": "object", "additionalProperties": reference},
+			"endpoint":      map[string]interface{}{"type": "string"},
+		},
+		"$defs": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"authenticator": reference},
+			},
+		},
+	}
+
+	value := map[string]interface{}{
+		"auth":          map[string]interface{}{"authenticator": "oidc"},
+		"observers":     []interface{}{"host_observer", "k8s_observer/pods"},
+		"named_storage": map[string]interface{}{"queue": "file_storage"},
+		"endpoint":      "localhost:4317",
+		"unknown":       map[string]interface{}{"authenticator": "ignored"},
+	}
+
+	assert.Equal(t, []componentReference{
+		{path: "receivers.test.auth.authenticator", field: "authenticator", id: "oidc"},
+		{path: "receivers.test.named_storage.queue", field: "queue", id: "file_storage"},
+		{path: "receivers.test.observers[0]", field: "observers", id: "host_observer"},
+		{path: "receivers.test.observers[1]", field: "observers", id: "k8s_observer/pods"},
+	}, collectComponentReferences(schema, "receivers.test", value))
+}
+
+func TestCollectComponentReferences_StringOrObject(t *testing.T) {
+	reference := map[string]interface{}{"type": "string", componentReferenceKeyword: "extension"}
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"oneOf": []interface{}{
+					reference,
+					map[string]interface{}{"$ref": "#/$defs/auth"},
+				},
+			},
+		},
+		"$defs": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"authenticator": reference},
+			},
+		},
+	}
+
+	assert.Equal(t, []componentReference{{path: "receivers.test.auth", field: "auth", id: "oidc"}},
+		collectComponentReferences(schema, "receivers.test", map[string]interface{}{"auth": "oidc"}), "Shorthand form")
+	assert.Equal(t, []componentReference{{path: "receivers.test.auth.authenticator", field: "authenticator", id: "oidc"}},
+		collectComponentReferences(schema, "receivers.test", map[string]interface{}{"auth": map[string]interface{}{"authenticator": "oidc"}}),
+		"Expanded form")
+}
@@ -0,0 +1,198 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// constraintErrorType is the type of the validation errors of component rules
+const constraintErrorType = "constraint"
+
+// defaultSendBatchSize is the send_batch_size of the batch processor when it is not configured
+const defaultSendBatchSize = 8192
+
+// componentRule checks a constraint between the values of a component configuration that the JSON schema cannot
+// express, e.g. that the spike limit of the memory_limiter is below its limit. The collector rejects configurations
+// that violate these constraints when it starts. Rules only check values that are present, a fragment is not
+// reported for the values it omits, except for the policies of the tail_sampling processor and the routes of the
+// routing connector that are required.
+type componentRule func(config map[string]interface{}) []semanticError
+
+// componentRules lists the built-in rules of components keyed by "<type>/<name>"
+var componentRules = map[string][]componentRule{
+	"processor/memory_limiter": {checkMemoryLimiterInterval, checkMemoryLimiterSpike},
+	"processor/batch":          {checkBatchSizes},
+	"processor/tail_sampling":  {checkTailSamplingPolicies},
+	"connector/routing":        {checkRoutingTable},
+}
+
+// validateComponentRules checks the built-in rules of a component and returns the violated constraints
+func validateComponentRules(componentType ComponentType, componentName string, config interface{}) []semanticError {
+	fields, ok := config.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var constraintErrors []semanticError
+	for _, rule := range componentRules[fmt.Sprintf("%s/%s", componentType, componentName)] {
+		constraintErrors = append(constraintErrors, rule(fields)...)
+	}
+	return constraintErrors
+}
+
+// checkMemoryLimiterInterval reports a check_interval that is not positive, the memory_limiter never checks the
+// memory usage then
+func checkMemoryLimiterInterval(config map[string]interface{}) []semanticError {
+	interval, ok := durationValue(config["check_interval"])
+	if !ok || interval > 0 {
+		return nil
+	}
+	return []semanticError{constraintError([]string{"check_interval"}, config["check_interval"], "check_interval must be greater than zero")}
+}
+
+// checkMemoryLimiterSpike reports spike limits that are not below the limits of the memory_limiter, the soft limit
+// would not be positive
+func checkMemoryLimiterSpike(config map[string]interface{}) []semanticError {
+	var constraintErrors []semanticError
+	for _, unit := range []string{"mib", "percentage"} {
+		limitKey, spikeKey := "limit_"+unit, "spike_limit_"+unit
+		limit, ok := numberValue(config[limitKey])
+		if !ok || limit == 0 {
+			continue
+		}
+		spike, ok := numberValue(config[spikeKey])
+		if !ok || spike < limit {
+			continue
+		}
+		constraintErrors = append(constraintErrors, constraintError([]string{spikeKey}, config[spikeKey],
+			fmt.Sprintf("%s (%v) must be smaller than %s (%v)", spikeKey, config[spikeKey], limitKey, config[limitKey])))
+	}
+	return constraintErrors
+}
+
+// checkBatchSizes reports a send_batch_max_size below the send_batch_size of the batch processor, 0 disables the
+// maximum
+func checkBatchSizes(config map[string]interface{}) []semanticError {
+	maxSize, ok := numberValue(config["send_batch_max_size"])
+	if !ok || maxSize == 0 {
+		return nil
+	}
+	size, ok := numberValue(config["send_batch_size"])
+	sizeText := fmt.Sprint(config["send_batch_size"])
+	if !ok {
+		size, sizeText = defaultSendBatchSize, fmt.Sprintf("%d by default", defaultSendBatchSize)
+	}
+	if maxSize >= size {
+		return nil
+	}
+	return []semanticError{constraintError([]string{"send_batch_max_size"}, config["send_batch_max_size"],
+		fmt.Sprintf("send_batch_max_size (%v) must be greater or equal to send_batch_size (%s)", config["send_batch_max_size"], sizeText))}
+}
+
+// checkTailSamplingPolicies reports a tail_sampling processor without policies, which samples no traces, and
+// policies that reuse the name of another policy
+func checkTailSamplingPolicies(config map[string]interface{}) []semanticError {
+	policies, ok := config["policies"].([]interface{})
+	if !ok && config["policies"] != nil {
+		// Other types are reported by the schema
+		return nil
+	}
+	if len(policies) == 0 {
+		return []semanticError{constraintError([]string{"policies"}, config["policies"], "tail_sampling requires at least one policy")}
+	}
+
+	var constraintErrors []semanticError
+	names := make(map[string]int)
+	for i, policy := range policies {
+		fields, _ := policy.(map[string]interface{})
+		name, ok := fields["name"].(string)
+		if !ok {
+			continue
+		}
+		if first, ok := names[name]; ok {
+			constraintErrors = append(constraintErrors, constraintError([]string{"policies", strconv.Itoa(i), "name"}, name,
+				fmt.Sprintf("policy name %q is already used by policies[%d]", name, first)))
+			continue
+		}
+		names[name] = i
+	}
+	return constraintErrors
+}
+
+// checkRoutingTable reports a routing connector without routes and routes that do not have exactly one of a
+// statement or a condition or no pipelines. The statements and conditions are parsed, the collector fails to
+// create the router otherwise.
+func checkRoutingTable(config map[string]interface{}) []semanticError {
+	table, ok := config["table"].([]interface{})
+	if !ok && config["table"] != nil {
+		return nil
+	}
+	if len(table) == 0 {
+		return []semanticError{constraintError([]string{"table"}, config["table"], "the routing table must have at least one route")}
+	}
+
+	var constraintErrors []semanticError
+	for i, item := range table {
+		route, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := []string{"table", strconv.Itoa(i)}
+		statement, hasStatement := route["statement"].(string)
+		condition, hasCondition := route["condition"].(string)
+		hasStatement, hasCondition = hasStatement && statement != "", hasCondition && condition != ""
+		switch {
+		case hasStatement && hasCondition:
+			constraintErrors = append(constraintErrors, constraintError(path, "", "route must have either a statement or a condition, not both"))
+		case !hasStatement && !hasCondition:
+			constraintErrors = append(constraintErrors, constraintError(path, "", "route must have a statement or a condition"))
+		}
+		if hasStatement {
+			constraintErrors = append(constraintErrors, ottlSyntaxErrors(appendPath(path, "statement"), ottlStatement, statement)...)
+		}
+		if hasCondition {
+			constraintErrors = append(constraintErrors, ottlSyntaxErrors(appendPath(path, "condition"), ottlCondition, condition)...)
+		}
+		if pipelines, _ := route["pipelines"].([]interface{}); len(pipelines) == 0 {
+			constraintErrors = append(constraintErrors, constraintError(path, "", "route must have at least one pipeline"))
+		}
+	}
+	return constraintErrors
+}
+
+// constraintError returns the semantic error of a violated constraint
+func constraintError(path []string, value interface{}, message string) semanticError {
+	return semanticError{
+		path:      path,
+		errorType: constraintErrorType,
+		value:     fmt.Sprint(value),
+		message:   message,
+	}
+}
+
+// numberValue returns the value of a number decoded from JSON or YAML
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// durationValue returns the value of a duration, a time.ParseDuration string or a number of nanoseconds. Invalid
+// durations are reported by the schema pattern.
+func durationValue(value interface{}) (time.Duration, bool) {
+	if s, ok := value.(string); ok {
+		duration, err := time.ParseDuration(s)
+		return duration, err == nil
+	}
+	number, ok := numberValue(value)
+	return time.Duration(number), ok
+}
@@ -0,0 +1,106 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// constraintMessages returns the messages of the constraint errors of a component configuration keyed by field
+func constraintMessages(t *testing.T, manager *SchemaManager, componentName string, config string) map[string]string {
+	t.Helper()
+	result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, componentName, "0.139.0", []byte(config))
+	require.NoError(t, err)
+
+	messages := make(map[string]string)
+	for _, resultError := range result.Errors() {
+		require.Equal(t, constraintErrorType, resultError.Type(), "Unexpected error: %v", resultError)
+		messages[resultError.Field()] = resultError.Description()
+	}
+	return messages
+}
+
+func TestComponentRules_MemoryLimiter(t *testing.T) {
+	manager := NewSchemaManager()
+
+	assert.Empty(t, constraintMessages(t, manager, "memory_limiter", `{"check_interval": "1s", "limit_mib": 4000, "spike_limit_mib": 800}`))
+	assert.Empty(t, constraintMessages(t, manager, "memory_limiter", `{"limit_percentage": 80}`), "Omitted values are not checked")
+
+	assert.Equal(t, map[string]string{
+		"check_interval":         "check_interval must be greater than zero",
+		"spike_limit_mib":        "spike_limit_mib (4000) must be smaller than limit_mib (4000)",
+		"spike_limit_percentage": "spike_limit_percentage (90) must be smaller than limit_percentage (80)",
+	}, constraintMessages(t, manager, "memory_limiter", `{
+		"check_interval": "0s",
+		"limit_mib": 4000,
+		"spike_limit_mib": 4000,
+		"limit_percentage": 80,
+		"spike_limit_percentage": 90
+	}`))
+}
+
+func TestComponentRules_Batch(t *testing.T) {
+	manager := NewSchemaManager()
+
+	assert.Empty(t, constraintMessages(t, manager, "batch", `{"send_batch_size": 1000, "send_batch_max_size": 1000}`))
+	assert.Empty(t, constraintMessages(t, manager, "batch", `{"send_batch_size": 100000, "send_batch_max_size": 0}`), "0 disables the maximum")
+
+	assert.Equal(t, map[string]string{
+		"send_batch_max_size": "send_batch_max_size (500) must be greater or equal to send_batch_size (1000)",
+	}, constraintMessages(t, manager, "batch", `{"send_batch_size": 1000, "send_batch_max_size": 500}`))
+	assert.Equal(t, map[string]string{
+		"send_batch_max_size": "send_batch_max_size (1000) must be greater or equal to send_batch_size (8192 by default)",
+	}, constraintMessages(t, manager, "batch", `{"send_batch_max_size": 1000}`))
+}
+
+func TestComponentRules_TailSampling(t *testing.T) {
+	manager := NewSchemaManager()
+
+	assert.Empty(t, constraintMessages(t, manager, "tail_sampling", `{"policies": [
+		{"name": "errors", "type": "status_code", "status_code": {"status_codes": ["ERROR"]}},
+		{"name": "slow", "type": "latency", "latency": {"threshold_ms": 5000}}
+	]}`))
+
+	assert.Equal(t, map[string]string{"policies": "tail_sampling requires at least one policy"},
+		constraintMessages(t, manager, "tail_sampling", `{"decision_wait": "10s"}`))
+	assert.Equal(t, map[string]string{"policies": "tail_sampling requires at least one policy"},
+		constraintMessages(t, manager, "tail_sampling", `{"policies": []}`))
+	assert.Equal(t, map[string]string{"policies.2.name": `policy name "errors" is already used by policies[0]`},
+		constraintMessages(t, manager, "tail_sampling", `{"policies": [
+			{"name": "errors", "type": "status_code", "status_code": {"status_codes": ["ERROR"]}},
+			{"name": "slow", "type": "latency", "latency": {"threshold_ms": 5000}},
+			{"name": "errors", "type": "always_sample"}
+		]}`))
+}
+
+func TestSchemaManager_ValidateCollectorConfig_ComponentRules(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  memory_limiter:
+    check_interval: 1s
+    limit_mib: 512
+    spike_limit_mib: 1024
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [memory_limiter]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "processors.memory_limiter.spike_limit_mib: spike_limit_mib (1024) must be smaller than limit_mib (512)", result.Errors[0].String())
+	require.NotNil(t, result.Errors[0].Position)
+	assert.Equal(t, 10, result.Errors[0].Position.Line)
+}
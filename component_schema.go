@@ -1,19 +1,17 @@
 package collectorconfigschema
 
 import (
-	"embed"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
-	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemas"
 	"github.com/xeipuuv/gojsonschema"
 )
 
-//go:embed schemas
-var embeddedSchemas embed.FS
-
 // ComponentType represents the type of OpenTelemetry component
 type ComponentType string
 
@@ -31,6 +29,11 @@ type ComponentSchema struct {
 	Type    ComponentType          `json:"type"`
 	Version string                 `json:"version,omitempty"`
 	Schema  map[string]interface{} `json:"schema"`
+	// Metadata is the metadata recorded in the schema, it is nil for schemas without metadata
+	Metadata *ComponentMetadata `json:"metadata,omitempty"`
+
+	// source is the schema document of registered schemas, it keeps the order of properties, see GetUISchema
+	source []byte
 }
 
 // DeprecatedField represents a deprecated field with its information
@@ -42,34 +45,77 @@ type DeprecatedField struct {
 
 // SchemaManager manages component schemas
 type SchemaManager struct {
-	cache map[string]*ComponentSchema
+	cache         *lruCache[*ComponentSchema]
+	compiled      *lruCache[*gojsonschema.Schema]
+	custom        map[string]*ComponentSchema
+	customMu      sync.RWMutex
+	distributions map[string]*Distribution
+	lintRules     []LintRule
+	telemetry     *telemetry
+	// registry fetches the schemas of versions that are not embedded, it is nil without WithRemoteRegistry
+	registry     *remoteRegistry
+	verification schemaVerification
 }
 
-// NewSchemaManager creates a new schema manager
-func NewSchemaManager() *SchemaManager {
-	return &SchemaManager{
-		cache: make(map[string]*ComponentSchema),
+// NewSchemaManager creates a new schema manager, parsed schemas are cached in a least recently used cache of
+// DefaultCacheSize schemas unless WithCacheSize is set
+func NewSchemaManager(opts ...ManagerOption) *SchemaManager {
+	options := &managerOptions{cacheSize: DefaultCacheSize}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	sm := &SchemaManager{
+		cache:         newLRUCache[*ComponentSchema](options.cacheSize),
+		compiled:      newLRUCache[*gojsonschema.Schema](options.cacheSize),
+		custom:        make(map[string]*ComponentSchema),
+		distributions: make(map[string]*Distribution),
+		registry:      options.registry,
+		verification:  options.verification,
 	}
+	if sm.registry != nil {
+		sm.registry.verification = options.verification
+	}
+	sm.telemetry = newTelemetry(sm, options.tracerProvider, options.meterProvider)
+	return sm
 }
 
 // GetComponentSchema returns the JSON schema for a specific component
 func (sm *SchemaManager) GetComponentSchema(componentType ComponentType, componentName string, version string) (*ComponentSchema, error) {
+	return sm.getComponentSchema(context.Background(), componentType, componentName, version)
+}
+
+// getComponentSchema returns the JSON schema of a component, loading the schema is traced in the context
+func (sm *SchemaManager) getComponentSchema(ctx context.Context, componentType ComponentType, componentName string, version string) (*ComponentSchema, error) {
+	// Registered schemas take precedence over embedded schemas
+	if schema := sm.getCustomSchema(componentType, componentName, version); schema != nil {
+		return schema, nil
+	}
+
 	// Create cache key
-	cacheKey := fmt.Sprintf("%s_%s_%s", componentType, componentName, version)
+	cacheKey := schemaCacheKey(componentType, componentName, version)
 
 	// Check cache first
-	if schema, exists := sm.cache[cacheKey]; exists {
+	if schema, exists := sm.cache.get(cacheKey); exists {
 		return schema, nil
 	}
 
 	// Load schema from file
-	schema, err := sm.loadSchemaFromFile(componentType, componentName, version)
+	end := sm.telemetry.startSchemaLoad(ctx, componentType, componentName, version)
+	var schema *ComponentSchema
+	var err error
+	if _, embedded := schemas.Lookup(version); !embedded && sm.registry != nil {
+		schema, err = sm.registry.componentSchema(ctx, componentType, componentName, version)
+	} else {
+		schema, err = sm.loadSchemaFromFile(componentType, componentName, version)
+	}
+	end(err)
 	if err != nil {
 		return nil, err
 	}
 
 	// Cache the result
-	sm.cache[cacheKey] = schema
+	sm.cache.add(cacheKey, schema)
 
 	return schema, nil
 }
@@ -84,35 +130,75 @@ func (sm *SchemaManager) GetComponentSchemaJSON(componentType ComponentType, com
 	return json.MarshalIndent(schema.Schema, "", "  ")
 }
 
-// ListAvailableComponents returns a list of all available components by type
+// ListAvailableComponents returns a list of all available components by type, including registered custom components
 func (sm *SchemaManager) ListAvailableComponents(version string) (map[ComponentType][]string, error) {
-	return sm.listEmbeddedComponents(version)
+	components, err := sm.listEmbeddedComponents(version)
+	if err != nil && sm.registry != nil {
+		components, err = sm.registry.components(context.Background(), version)
+	}
+	if err != nil {
+		// Versions that are not embedded are available if schemas were registered for them, e.g. from a bundle
+		if !sm.hasCustomVersion(version) {
+			return nil, err
+		}
+		components = make(map[ComponentType][]string)
+	}
+
+	sm.addCustomComponents(components, version)
+
+	return components, nil
+}
+
+// ValidateComponentJSON validates a component configuration JSON against its schema. Configurations of other
+// components embedded in the configuration, e.g. the receiver templates of the receiver_creator, are validated
+// against the schemas of these components. Constraints between values that the schema cannot express, e.g. the
+// spike limit of the memory_limiter below its limit, are checked by built-in rules.
+// The Strict, ValidateOTTL and ValidatePatterns options are applied, other options only affect collector configurations.
+func (sm *SchemaManager) ValidateComponentJSON(componentType ComponentType, componentName string, version string, jsonData []byte, opts ...ValidationOption) (*gojsonschema.Result, error) {
+	ctx, end := sm.telemetry.startValidation(context.Background(), componentType, componentName, version)
+	result, err := sm.validateComponentJSON(ctx, componentType, componentName, version, jsonData, opts...)
+	if result != nil {
+		end(len(result.Errors()), err)
+	} else {
+		end(0, err)
+	}
+	return result, err
 }
 
-// ValidateComponentJSON validates a component configuration JSON against its schema
-func (sm *SchemaManager) ValidateComponentJSON(componentType ComponentType, componentName string, version string, jsonData []byte) (*gojsonschema.Result, error) {
+// validateComponentJSON validates a component configuration JSON, see ValidateComponentJSON
+func (sm *SchemaManager) validateComponentJSON(ctx context.Context, componentType ComponentType, componentName string, version string, jsonData []byte, opts ...ValidationOption) (*gojsonschema.Result, error) {
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	// Get the component schema
-	componentSchema, err := sm.GetComponentSchema(componentType, componentName, version)
+	componentSchema, err := sm.getComponentSchema(ctx, componentType, componentName, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schema for %s %s v%s: %w", componentType, componentName, version, err)
 	}
 
-	// Convert schema to JSON bytes for gojsonschema
-	schemaBytes, err := json.Marshal(componentSchema.Schema)
+	result, err := sm.validateJSON(componentSchema, options.strict, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal schema for %s %s: %w", componentType, componentName, err)
+		return nil, err
 	}
 
-	// Create schema loader
-	schemaLoader := gojsonschema.NewBytesLoader(schemaBytes)
-
-	// Create document loader from the provided JSON data
-	documentLoader := gojsonschema.NewBytesLoader(jsonData)
-
-	// Validate the document against the schema
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	var config interface{}
+	if err := json.Unmarshal(jsonData, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration for %s %s: %w", componentType, componentName, err)
+	}
+	embeddedErrors, err := sm.validateEmbeddedConfigs(ctx, componentSchema, config, version, options.strict)
 	if err != nil {
-		return nil, fmt.Errorf("validation failed for %s %s: %w", componentType, componentName, err)
+		return nil, err
+	}
+	addSemanticErrors(result, embeddedErrors)
+	addSemanticErrors(result, validateComponentRules(componentType, componentName, config))
+	if options.ottl {
+		addSemanticErrors(result, validateOTTL(componentType, componentName, config))
+	}
+	if options.patterns {
+		result = withoutRegexFormatErrors(result)
+		addSemanticErrors(result, validatePatterns(componentSchema.Schema, config))
 	}
 
 	return result, nil
@@ -124,9 +210,7 @@ func (sm *SchemaManager) GetComponentReadme(componentType ComponentType, compone
 	filename := fmt.Sprintf("%s_%s.md", componentType, componentName)
 
 	// Load from embedded filesystem
-	schemaPath := fmt.Sprintf("schemas/%s", version)
-	embeddedFilepath := filepath.Join(schemaPath, filename)
-	data, err := fs.ReadFile(embeddedSchemas, embeddedFilepath)
+	data, err := readEmbeddedFile(version, filename)
 	if err != nil {
 		return "", fmt.Errorf("README not found for component %s %s v%s", componentType, componentName, version)
 	}
@@ -137,9 +221,7 @@ func (sm *SchemaManager) GetComponentReadme(componentType ComponentType, compone
 // GetChangelog returns the changelog content for a specific collector version
 func (sm *SchemaManager) GetChangelog(version string) (string, error) {
 	// Load changelog.md from embedded filesystem
-	schemaPath := fmt.Sprintf("schemas/%s", version)
-	embeddedFilepath := filepath.Join(schemaPath, "changelog.md")
-	data, err := fs.ReadFile(embeddedSchemas, embeddedFilepath)
+	data, err := readEmbeddedFile(version, "changelog.md")
 	if err != nil {
 		return "", fmt.Errorf("changelog not found for version %s", version)
 	}
@@ -152,8 +234,7 @@ func (sm *SchemaManager) listEmbeddedComponents(version string) (map[ComponentTy
 	components := make(map[ComponentType][]string)
 
 	// Read embedded directory
-	schemaPath := fmt.Sprintf("schemas/%s", version)
-	entries, err := fs.ReadDir(embeddedSchemas, schemaPath)
+	entries, err := readEmbeddedDir(version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read embedded schema directory: %w", err)
 	}
@@ -192,9 +273,7 @@ func (sm *SchemaManager) loadSchemaFromFile(componentType ComponentType, compone
 	filename := fmt.Sprintf("%s_%s.json", componentType, componentName)
 
 	// Load from embedded filesystem
-	schemaPath := fmt.Sprintf("schemas/%s", version)
-	embeddedFilepath := filepath.Join(schemaPath, filename)
-	data, err := fs.ReadFile(embeddedSchemas, embeddedFilepath)
+	data, err := readEmbeddedFile(version, filename)
 	if err != nil {
 		return nil, fmt.Errorf("schema not found for component %s %s", componentType, componentName)
 	}
@@ -209,10 +288,11 @@ func (sm *SchemaManager) loadSchemaFromFile(componentType ComponentType, compone
 	componentVersion := version
 
 	return &ComponentSchema{
-		Name:    componentName,
-		Type:    componentType,
-		Version: componentVersion,
-		Schema:  schemaData,
+		Name:     componentName,
+		Type:     componentType,
+		Version:  componentVersion,
+		Schema:   schemaData,
+		Metadata: parseComponentMetadata(schemaData),
 	}, nil
 }
 
@@ -226,56 +306,40 @@ func isValidComponentType(componentType ComponentType) bool {
 	}
 }
 
-// GetLatestVersion returns the latest version available in the schemas directory
+// GetLatestVersion returns the latest version of the embedded schemas
 func (sm *SchemaManager) GetLatestVersion() (string, error) {
-	entries, err := fs.ReadDir(embeddedSchemas, "schemas")
+	versions, err := sm.GetAllVersions()
 	if err != nil {
-		return "", fmt.Errorf("failed to read schemas directory: %w", err)
+		return "", err
 	}
-
-	var latestVersion string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check if the directory name looks like a version (contains dots)
-			version := entry.Name()
-			if strings.Contains(version, ".") {
-				if latestVersion == "" || version > latestVersion {
-					latestVersion = version
-				}
-			}
-		}
-	}
-
-	if latestVersion == "" {
-		return "", fmt.Errorf("no versions found in schemas directory")
-	}
-
-	return latestVersion, nil
+	return versions[len(versions)-1], nil
 }
 
-// GetAllVersions returns all versions available in the schemas directory
+// GetAllVersions returns all versions of the embedded schemas, the versions of the imported sub-packages of schemas
 func (sm *SchemaManager) GetAllVersions() ([]string, error) {
-	entries, err := fs.ReadDir(embeddedSchemas, "schemas")
-	if err != nil {
-		return nil, fmt.Errorf("failed to read schemas directory: %w", err)
+	versions := schemas.Versions()
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions found in schemas directory")
 	}
+	return versions, nil
+}
 
-	var versions []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check if the directory name looks like a version (contains dots)
-			version := entry.Name()
-			if strings.Contains(version, ".") {
-				versions = append(versions, version)
-			}
-		}
+// readEmbeddedFile reads a file of the embedded schemas of a version
+func readEmbeddedFile(version string, name string) ([]byte, error) {
+	files, ok := schemas.Lookup(version)
+	if !ok {
+		return nil, fmt.Errorf("version %s is not embedded: %w", version, fs.ErrNotExist)
 	}
+	return fs.ReadFile(files, name)
+}
 
-	if len(versions) == 0 {
-		return nil, fmt.Errorf("no versions found in schemas directory")
+// readEmbeddedDir lists the files of the embedded schemas of a version
+func readEmbeddedDir(version string) ([]fs.DirEntry, error) {
+	files, ok := schemas.Lookup(version)
+	if !ok {
+		return nil, fmt.Errorf("version %s is not embedded: %w", version, fs.ErrNotExist)
 	}
-
-	return versions, nil
+	return fs.ReadDir(files, ".")
 }
 
 // GetComponentNames returns all component names for a given version and component type
@@ -286,8 +350,7 @@ func (sm *SchemaManager) GetComponentNames(componentType ComponentType, version
 	}
 
 	// Read embedded directory for the specific version
-	schemaPath := fmt.Sprintf("schemas/%s", version)
-	entries, err := fs.ReadDir(embeddedSchemas, schemaPath)
+	entries, err := readEmbeddedDir(version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema directory for version %s: %w", version, err)
 	}
@@ -311,6 +374,10 @@ func (sm *SchemaManager) GetComponentNames(componentType ComponentType, version
 		}
 	}
 
+	custom := map[ComponentType][]string{componentType: componentNames}
+	sm.addCustomComponents(custom, version)
+	componentNames = custom[componentType]
+
 	if len(componentNames) == 0 {
 		return nil, fmt.Errorf("no %s components found for version %s", componentType, version)
 	}
@@ -212,11 +212,13 @@ func TestSchemaManager_ValidateComponentJSON_Invalid(t *testing.T) {
 
 	// Test invalid JSON (include_metadata should be a boolean, not a string)
 	invalidJSON := []byte(`{
-		"grpc": {
-			"include_metadata": "invalid_boolean_value",
-			"keepalive": {
-				"server_parameters": {
-					"max_connection_idle": "invalid_duration_format"
+		"protocols": {
+			"grpc": {
+				"include_metadata": "invalid_boolean_value",
+				"keepalive": {
+					"server_parameters": {
+						"max_connection_idle": "invalid_duration_format"
+					}
 				}
 			}
 		}
@@ -237,6 +239,114 @@ func TestSchemaManager_ValidateComponentJSON_Invalid(t *testing.T) {
 	}
 }
 
+func TestSchemaManager_ValidateComponentJSON_Durations(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		duration string
+		valid    bool
+	}{
+		{duration: `"30s"`, valid: true},
+		{duration: `"1h30m"`, valid: true},
+		{duration: `"1.5s"`, valid: true},
+		{duration: `"-1s"`, valid: true},
+		{duration: `"0"`, valid: true},
+		{duration: `5000000000`, valid: true},
+		{duration: `"5 seconds"`, valid: false},
+		{duration: `"1d"`, valid: false},
+		{duration: `1.5`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.duration, func(t *testing.T) {
+			config := []byte(`{"timeout": ` + test.duration + `}`)
+			result, err := manager.ValidateComponentJSON(ComponentTypeExporter, "elasticsearch", "0.138.0", config)
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, result.Valid(), "%v", result.Errors())
+		})
+	}
+}
+
+func TestSchemaManager_ValidateComponentJSON_Bounds(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		componentType ComponentType
+		name          string
+		config        string
+		valid         bool
+	}{
+		{componentType: ComponentTypeExporter, name: "cassandra", config: `{"port": 9042}`, valid: true},
+		{componentType: ComponentTypeExporter, name: "cassandra", config: `{"port": 99999}`, valid: false},
+		{componentType: ComponentTypeExporter, name: "cassandra", config: `{"port": -1}`, valid: false},
+		{componentType: ComponentTypeProcessor, name: "memory_limiter", config: `{"limit_percentage": 80}`, valid: true},
+		{componentType: ComponentTypeProcessor, name: "memory_limiter", config: `{"limit_percentage": 150}`, valid: false},
+		{componentType: ComponentTypeProcessor, name: "memory_limiter", config: `{"limit_mib": -512}`, valid: false},
+		{componentType: ComponentTypeReceiver, name: "otlp", config: `{"protocols": {"grpc": {"max_recv_msg_size_mib": -1}}}`, valid: false},
+	}
+
+	for _, version := range []string{"0.135.0", "0.139.0"} {
+		for _, test := range tests {
+			t.Run(version+"/"+test.name+test.config, func(t *testing.T) {
+				result, err := manager.ValidateComponentJSON(test.componentType, test.name, version, []byte(test.config))
+				require.NoError(t, err)
+				assert.Equal(t, test.valid, result.Valid(), "%v", result.Errors())
+			})
+		}
+	}
+}
+
+func TestSchemaManager_ValidateComponentJSON_Headers(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		headers string
+		valid   bool
+	}{
+		{headers: `{"Authorization": "Bearer token", "X-Tenant": "tenant"}`, valid: true},
+		{headers: `[{"name": "Authorization", "value": "Bearer token"}, {"name": "X-Tenant", "value": "tenant"}]`, valid: true},
+		{headers: `{"X-Retries": 3}`, valid: false},
+		{headers: `[{"name": "X-Retries", "value": 3}]`, valid: false},
+		{headers: `"Authorization: Bearer token"`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.headers, func(t *testing.T) {
+			config := []byte(`{"endpoint": "https://backend:4318", "headers": ` + test.headers + `}`)
+			result, err := manager.ValidateComponentJSON(ComponentTypeExporter, "otlphttp", "0.139.0", config)
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, result.Valid(), "%v", result.Errors())
+		})
+	}
+}
+
+func TestSchemaManager_ValidateComponentJSON_ComponentIDs(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		storage string
+		valid   bool
+	}{
+		{storage: `"file_storage"`, valid: true},
+		{storage: `"file_storage/queue"`, valid: true},
+		{storage: `"file_storage/queue-1.0"`, valid: true},
+		{storage: `""`, valid: false},
+		{storage: `"file storage"`, valid: false},
+		{storage: `"1storage"`, valid: false},
+		{storage: `"file_storage/"`, valid: false},
+		{storage: `{}`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.storage, func(t *testing.T) {
+			config := []byte(`{"sending_queue": {"storage": ` + test.storage + `}}`)
+			result, err := manager.ValidateComponentJSON(ComponentTypeExporter, "elasticsearch", "0.138.0", config)
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, result.Valid(), "%v", result.Errors())
+		})
+	}
+}
+
 func TestSchemaManager_ValidateComponentJSON_MalformedJSON(t *testing.T) {
 	manager := NewSchemaManager()
 
@@ -326,8 +436,8 @@ func TestSchemaManager_GetLatestVersion(t *testing.T) {
 	// Verify the version has a valid format (major.minor.patch)
 	assert.Contains(t, version, ".", "Version should contain dots")
 
-	// Since we know we have v0.138.0 in the schemas directory, verify it's returned
-	assert.Equal(t, "0.138.0", version, "Expected version 0.138.0 as the latest")
+	// Since we know we have v0.139.0 in the schemas directory, verify it's returned
+	assert.Equal(t, "0.139.0", version, "Expected version 0.139.0 as the latest")
 
 	t.Logf("Latest version found: %s", version)
 }
@@ -508,3 +618,182 @@ func BenchmarkSchemaManager_GetComponentSchema(b *testing.B) {
 		}
 	}
 }
+
+func TestSchemaManager_ValidateComponentJSON_PrometheusConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	valid := []byte(`{
+		"config": {
+			"global": {"scrape_interval": "30s", "external_labels": {"cluster": "prod"}},
+			"scrape_configs": [{
+				"job_name": "kubernetes-pods",
+				"scrape_interval": "1m30s",
+				"kubernetes_sd_configs": [{"role": "pod"}],
+				"relabel_configs": [{"source_labels": ["__meta_kubernetes_pod_name"], "action": "replace", "target_label": "pod"}],
+				"static_configs": [{"targets": ["localhost:8888"], "labels": {"env": "dev"}}],
+				"tls_config": {"insecure_skip_verify": false, "min_version": "TLS12"}
+			}]
+		}
+	}`)
+	result, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "prometheus", "0.139.0", valid)
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected a valid Prometheus configuration: %v", result.Errors())
+
+	invalid := []byte(`{
+		"config": {
+			"scrape_configs": [{
+				"job_name": "otel",
+				"scrape_intervall": "10s",
+				"scrape_timeout": "ten seconds",
+				"relabel_configs": [{"action": "rename"}]
+			}]
+		}
+	}`)
+	result, err = manager.ValidateComponentJSON(ComponentTypeReceiver, "prometheus", "0.139.0", invalid)
+	require.NoError(t, err)
+
+	fields := make(map[string]string)
+	for _, resultError := range result.Errors() {
+		fields[resultError.Field()] = resultError.Type()
+	}
+	assert.Equal(t, map[string]string{
+		"config.scrape_configs.0":                          "additional_property_not_allowed",
+		"config.scrape_configs.0.scrape_timeout":           "pattern",
+		"config.scrape_configs.0.relabel_configs.0.action": "enum",
+	}, fields)
+}
+
+func TestSchemaManager_ValidateComponentJSON_Kafka(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		name          string
+		componentType ComponentType
+		config        string
+		// invalid is the field expected to fail the enum, empty for valid configurations
+		invalid string
+	}{
+		{
+			name:          "exporter",
+			componentType: ComponentTypeExporter,
+			config: `{
+				"brokers": ["kafka:9092"],
+				"auth": {"sasl": {"mechanism": "SCRAM-SHA-512", "username": "otel", "password": "secret", "version": 1}},
+				"producer": {"compression": "zstd", "required_acks": -1},
+				"traces": {"encoding": "zipkin_json"},
+				"logs": {"encoding": "text_encoding"}
+			}`,
+		},
+		{
+			name:          "exporter acks alias",
+			componentType: ComponentTypeExporter,
+			config:        `{"producer": {"required_acks": "all"}}`,
+		},
+		{
+			name:          "exporter compression",
+			componentType: ComponentTypeExporter,
+			config:        `{"producer": {"compression": "brotli"}}`,
+			invalid:       "producer.compression",
+		},
+		{
+			name:          "exporter acks",
+			componentType: ComponentTypeExporter,
+			config:        `{"producer": {"required_acks": 2}}`,
+			invalid:       "producer.required_acks",
+		},
+		{
+			name:          "exporter sasl mechanism",
+			componentType: ComponentTypeExporter,
+			config:        `{"auth": {"sasl": {"mechanism": "scram-sha-256"}}}`,
+			invalid:       "auth.sasl.mechanism",
+		},
+		{
+			name:          "receiver",
+			componentType: ComponentTypeReceiver,
+			config: `{
+				"brokers": ["kafka:9092"],
+				"auth": {"sasl": {"mechanism": "AWS_MSK_IAM_OAUTHBEARER", "aws_msk": {"region": "us-east-1"}}},
+				"initial_offset": "earliest",
+				"group_rebalance_strategy": "cooperative-sticky",
+				"metrics": {"encoding": "otlp_json"}
+			}`,
+		},
+		{
+			name:          "receiver initial offset",
+			componentType: ComponentTypeReceiver,
+			config:        `{"initial_offset": "oldest"}`,
+			invalid:       "initial_offset",
+		},
+		{
+			name:          "receiver rebalance strategy",
+			componentType: ComponentTypeReceiver,
+			config:        `{"group_rebalance_strategy": "round_robin"}`,
+			invalid:       "group_rebalance_strategy",
+		},
+		{
+			name:          "receiver sasl version",
+			componentType: ComponentTypeReceiver,
+			config:        `{"auth": {"sasl": {"mechanism": "PLAIN", "version": 2}}}`,
+			invalid:       "auth.sasl.version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := manager.ValidateComponentJSON(tt.componentType, "kafka", "0.139.0", []byte(tt.config))
+			require.NoError(t, err)
+			if tt.invalid == "" {
+				assert.True(t, result.Valid(), "Expected a valid configuration: %v", result.Errors())
+				return
+			}
+			require.Len(t, result.Errors(), 1)
+			assert.Equal(t, tt.invalid, result.Errors()[0].Field())
+			assert.Equal(t, "enum", result.Errors()[0].Type())
+		})
+	}
+}
+
+func TestSchemaManager_ValidateComponentJSON_TailSamplingPolicies(t *testing.T) {
+	manager := NewSchemaManager()
+
+	valid := []byte(`{
+		"decision_wait": "10s",
+		"policies": [
+			{"name": "errors", "type": "status_code", "status_code": {"status_codes": ["ERROR", "UNSET"]}},
+			{"name": "slow", "type": "latency", "latency": {"threshold_ms": 5000}},
+			{"name": "everything", "type": "always_sample"},
+			{"name": "slow-checkout", "type": "and", "and": {"and_sub_policy": [
+				{"name": "checkout", "type": "string_attribute", "string_attribute": {"key": "service.name", "values": ["checkout"]}},
+				{"name": "slow", "type": "latency", "latency": {"threshold_ms": 1000}}
+			]}},
+			{"name": "budget", "type": "composite", "composite": {
+				"max_total_spans_per_second": 1000,
+				"policy_order": ["sampled"],
+				"composite_sub_policy": [{"name": "sampled", "type": "probabilistic", "probabilistic": {"sampling_percentage": 10}}],
+				"rate_allocation": [{"policy": "sampled", "percent": 100}]
+			}},
+			{"name": "health", "type": "drop", "drop": {"drop_sub_policy": [
+				{"name": "health", "type": "string_attribute", "string_attribute": {"key": "url.path", "values": ["/health"]}}
+			]}}
+		]
+	}`)
+	result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "tail_sampling", "0.139.0", valid, Strict())
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected valid policies: %v", result.Errors())
+
+	for name, policy := range map[string]string{
+		"unknown type":             `{"name": "p", "type": "sometimes_sample"}`,
+		"invalid body":             `{"name": "p", "type": "latency", "latency": {"threshold_ms": "5s"}}`,
+		"invalid status code":      `{"name": "p", "type": "status_code", "status_code": {"status_codes": ["FAILED"]}}`,
+		"nested composite":         `{"name": "p", "type": "and", "and": {"and_sub_policy": [{"name": "c", "type": "composite"}]}}`,
+		"configuration of another": `{"name": "p", "type": "latency", "probabilistic": {"sampling_percentage": 10}}`,
+		"percentage out of range":  `{"name": "p", "type": "probabilistic", "probabilistic": {"sampling_percentage": 150}}`,
+		"invalid sub-policy body":  `{"name": "p", "type": "drop", "drop": {"drop_sub_policy": [{"name": "d", "type": "span_count", "span_count": {"min_spans": "2"}}]}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "tail_sampling", "0.139.0", []byte(`{"policies": [`+policy+`]}`), Strict())
+			require.NoError(t, err)
+			assert.False(t, result.Valid(), "Expected an invalid policy")
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GetCollectorConfigSchema returns a JSON schema for a full collector configuration of a version.
+// Component schemas are referenced from $defs (named "<type>_<name>", e.g. "receiver_otlp") and matched by
+// component ID, so named instances like "otlp/internal" are validated by the schema of the otlp receiver.
+// Unknown components and sections are rejected.
+func (sm *SchemaManager) GetCollectorConfigSchema(version string) (map[string]interface{}, error) {
+	return sm.GetCollectorConfigSchemaContext(context.Background(), version)
+}
+
+// GetCollectorConfigSchemaContext is GetCollectorConfigSchema with a context, the schema is not built and the
+// error of the context is returned when it is canceled
+func (sm *SchemaManager) GetCollectorConfigSchemaContext(ctx context.Context, version string) (map[string]interface{}, error) {
+	definitions := make(map[string]interface{})
+	properties, err := sm.collectorConfigProperties(ctx, version, func(definitionName string, schema map[string]interface{}) string {
+		definitions[definitionName] = hoistDefinitions(definitionName, schema, definitions)
+		return "#/$defs/" + definitionName
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+		"$defs":                definitions,
+	}, nil
+}
+
+// collectorConfigProperties returns the properties of the schema of a collector configuration. The component
+// sections reference the component schemas, reference adds the schema of a component named "<type>_<name>"
+// to the aggregated schema and returns the reference to it. Building stops when the context is canceled.
+func (sm *SchemaManager) collectorConfigProperties(ctx context.Context, version string, reference func(definitionName string, schema map[string]interface{}) string) (map[string]interface{}, error) {
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]interface{})
+
+	for _, cs := range componentSections {
+		patternProperties := make(map[string]interface{})
+
+		for _, componentName := range components[cs.componentType] {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			componentSchema, err := sm.GetComponentSchema(cs.componentType, componentName, version)
+			if err != nil {
+				return nil, err
+			}
+
+			definitionName := fmt.Sprintf("%s_%s", cs.componentType, componentName)
+			ref := reference(definitionName, componentSchema.Schema)
+
+			// An empty component body (e.g. "otlp:") is null in the parsed configuration
+			patternProperties[componentIDPattern(componentName)] = map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"type": "null"},
+					map[string]interface{}{"$ref": ref},
+				},
+			}
+		}
+
+		properties[cs.section] = map[string]interface{}{
+			"type":                 []interface{}{"object", "null"},
+			"patternProperties":    patternProperties,
+			"additionalProperties": false,
+		}
+	}
+
+	properties[sectionService] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"extensions": componentIDListSchema(),
+			"pipelines": map[string]interface{}{
+				"type": "object",
+				"patternProperties": map[string]interface{}{
+					fmt.Sprintf("^(%s)%s$", strings.Join(pipelineSignals, "|"), componentNamePattern): map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"receivers":  componentIDListSchema(),
+							"processors": componentIDListSchema(),
+							"exporters":  componentIDListSchema(),
+						},
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+
+	return properties, nil
+}
+
+// componentIDPattern returns the pattern matching the IDs of a component, e.g. "otlp" and "otlp/internal"
+func componentIDPattern(componentName string) string {
+	return fmt.Sprintf("^%s%s$", regexp.QuoteMeta(componentName), componentNamePattern)
+}
+
+// componentIDListSchema returns the schema of a list of component IDs
+func componentIDListSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":    "string",
+			"pattern": "^[a-zA-Z][0-9a-zA-Z_]{0,62}" + componentNamePattern + "$",
+		},
+	}
+}
+
+// hoistDefinitions moves the $defs of a component schema into the definitions of the aggregated schema,
+// prefixed with the component definition name, and rewrites the references accordingly
+func hoistDefinitions(prefix string, schema map[string]interface{}, definitions map[string]interface{}) map[string]interface{} {
+	componentDefinitions, _ := schema["$defs"].(map[string]interface{})
+
+	rewritten := rewriteReferences(schema, prefix, componentDefinitions).(map[string]interface{})
+	delete(rewritten, "$schema")
+	delete(rewritten, "$defs")
+
+	for name, definition := range componentDefinitions {
+		definitions[prefix+"."+name] = rewriteReferences(definition, prefix, componentDefinitions)
+	}
+
+	return rewritten
+}
+
+// rewriteReferences returns a copy of a schema value where references to the given local definitions point to their hoisted names
+func rewriteReferences(value interface{}, prefix string, componentDefinitions map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		rewritten := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if name := strings.TrimPrefix(ref, "#/$defs/"); name != ref && componentDefinitions[name] != nil {
+					child = "#/$defs/" + prefix + "." + name
+				}
+			}
+			rewritten[key] = rewriteReferences(child, prefix, componentDefinitions)
+		}
+		return rewritten
+	case []interface{}:
+		rewritten := make([]interface{}, len(v))
+		for i, child := range v {
+			rewritten[i] = rewriteReferences(child, prefix, componentDefinitions)
+		}
+		return rewritten
+	default:
+		return value
+	}
+}
@@ -0,0 +1,100 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// validateAgainstConfigSchema validates a YAML collector configuration against the aggregated schema
+func validateAgainstConfigSchema(t *testing.T, schema map[string]interface{}, config string) *gojsonschema.Result {
+	t.Helper()
+
+	configMap, err := parseCollectorConfig([]byte(config))
+	require.NoError(t, err)
+	configJSON, err := json.Marshal(configMap)
+	require.NoError(t, err)
+
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewBytesLoader(configJSON))
+	require.NoError(t, err)
+	return result
+}
+
+func TestSchemaManager_GetCollectorConfigSchema(t *testing.T) {
+	manager := NewSchemaManager()
+
+	schema, err := manager.GetCollectorConfigSchema("0.138.0")
+	require.NoError(t, err)
+
+	definitions := schema["$defs"].(map[string]interface{})
+	assert.Contains(t, definitions, "receiver_otlp")
+	assert.NotContains(t, definitions["receiver_otlp"], "$schema")
+
+	receivers := schema["properties"].(map[string]interface{})["receivers"].(map[string]interface{})
+	assert.Contains(t, receivers["patternProperties"], `^otlp(/[^\s/]+)?$`)
+
+	valid := `
+receivers:
+  otlp:
+  otlp/internal:
+    sampling_initial: 2
+processors:
+  batch/2:
+    send_batch_size: 100
+exporters:
+  debug:
+service:
+  pipelines:
+    traces/2:
+      receivers: [otlp/internal]
+      processors: [batch/2]
+      exporters: [debug]
+`
+	result := validateAgainstConfigSchema(t, schema, valid)
+	assert.True(t, result.Valid(), "Config should be valid: %v", result.Errors())
+
+	for name, config := range map[string]string{
+		"invalid named instance body": "processors:\n  batch/2:\n    send_batch_size: large\n",
+		"empty instance name":         "receivers:\n  otlp/:\n",
+		"unknown component":           "receivers:\n  doesnotexist/2:\n",
+		"unknown pipeline signal":     "service:\n  pipelines:\n    spans/2: {}\n",
+		"unknown section":             "unknown: {}\n",
+	} {
+		result := validateAgainstConfigSchema(t, schema, config)
+		assert.False(t, result.Valid(), name)
+	}
+}
+
+func TestHoistDefinitions(t *testing.T) {
+	definitions := make(map[string]interface{})
+
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"retry":    map[string]interface{}{"$ref": "#/$defs/retry"},
+			"external": map[string]interface{}{"$ref": "https://example.com/schema.json"},
+		},
+		"$defs": map[string]interface{}{
+			"retry":   map[string]interface{}{"type": "object", "properties": map[string]interface{}{"backoff": map[string]interface{}{"$ref": "#/$defs/backoff"}}},
+			"backoff": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	hoisted := hoistDefinitions("exporter_otlp", schema, definitions)
+
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"retry":    map[string]interface{}{"$ref": "#/$defs/exporter_otlp.retry"},
+			"external": map[string]interface{}{"$ref": "https://example.com/schema.json"},
+		},
+	}, hoisted)
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/exporter_otlp.backoff"},
+		definitions["exporter_otlp.retry"].(map[string]interface{})["properties"].(map[string]interface{})["backoff"])
+	assert.Contains(t, definitions, "exporter_otlp.backoff")
+	assert.Contains(t, schema, "$defs", "The original schema should not be modified")
+}
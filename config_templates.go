@@ -0,0 +1,86 @@
+package collectorconfigschema
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemas"
+)
+
+// templatesDir is the directory of the reference configurations of a version, e.g. schemas/0.139.0/templates
+const templatesDir = "templates"
+
+// ConfigTemplate is a curated reference configuration embedded with the schemas of a version, see ListTemplates
+type ConfigTemplate struct {
+	// Name is the name of the template file without .yaml, e.g. gateway-traces
+	Name string `json:"name"`
+	// Description is the leading comment of the template
+	Description string `json:"description"`
+}
+
+// ListTemplates returns the reference configurations embedded with the schemas of a version sorted by name, e.g. a
+// Kubernetes DaemonSet collecting logs, a tail sampling trace gateway and a Prometheus scraper. Templates are
+// validated against the schemas of their version and linted in the tests of the library, tools can offer them as
+// starting points that are schema-valid. Versions without templates return an empty list.
+func (sm *SchemaManager) ListTemplates(version string) ([]ConfigTemplate, error) {
+	files, ok := schemas.Lookup(version)
+	if !ok {
+		return nil, fmt.Errorf("version %s is not embedded", version)
+	}
+
+	entries, err := fs.ReadDir(files, templatesDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []ConfigTemplate{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates of version %s: %w", version, err)
+	}
+
+	templates := make([]ConfigTemplate, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		data, err := fs.ReadFile(files, path.Join(templatesDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s of version %s: %w", name, version, err)
+		}
+		templates = append(templates, ConfigTemplate{Name: name, Description: templateDescription(data)})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// GetTemplate returns the YAML of a reference configuration of a version, see ListTemplates
+func (sm *SchemaManager) GetTemplate(version string, name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := readEmbeddedFile(version, path.Join(templatesDir, name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("template %s not found for version %s", name, version)
+	}
+	return data, nil
+}
+
+// templateDescription returns the comment lines at the start of a template joined into one paragraph
+func templateDescription(data []byte) string {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		comment, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+		if !ok {
+			break
+		}
+		if comment = strings.TrimSpace(comment); comment != "" {
+			lines = append(lines, comment)
+		}
+	}
+	return strings.Join(lines, " ")
+}
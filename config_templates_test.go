@@ -0,0 +1,70 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ListTemplates(t *testing.T) {
+	manager := NewSchemaManager()
+
+	templates, err := manager.ListTemplates("0.139.0")
+	require.NoError(t, err)
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.Name)
+		assert.NotEmpty(t, template.Description, template.Name)
+	}
+	assert.Equal(t, []string{"gateway-traces", "k8s-daemonset-logs", "prometheus-scrape"}, names)
+	assert.Contains(t, templates[1].Description, "Collects the container logs of a Kubernetes node, deployed as a DaemonSet")
+
+	_, err = manager.ListTemplates("0.1.0")
+	assert.EqualError(t, err, "version 0.1.0 is not embedded")
+}
+
+func TestSchemaManager_GetTemplate(t *testing.T) {
+	manager := NewSchemaManager()
+
+	template, err := manager.GetTemplate("0.139.0", "gateway-traces")
+	require.NoError(t, err)
+	assert.Contains(t, string(template), "tail_sampling:")
+
+	_, err = manager.GetTemplate("0.139.0", "doesnotexist")
+	assert.EqualError(t, err, "template doesnotexist not found for version 0.139.0")
+	_, err = manager.GetTemplate("0.139.0", "../receiver_otlp")
+	assert.EqualError(t, err, `invalid template name "../receiver_otlp"`)
+}
+
+// TestTemplates_Valid guarantees that every embedded template is valid for its version and free of lint warnings
+func TestTemplates_Valid(t *testing.T) {
+	manager := NewSchemaManager()
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+
+	for _, version := range versions {
+		templates, err := manager.ListTemplates(version)
+		require.NoError(t, err)
+		for _, template := range templates {
+			t.Run(version+"/"+template.Name, func(t *testing.T) {
+				config, err := manager.GetTemplate(version, template.Name)
+				require.NoError(t, err)
+
+				result, err := manager.ValidateCollectorConfig(config, version, ValidateOTTL(), ValidatePatterns())
+				require.NoError(t, err)
+				assert.Empty(t, result.Errors)
+
+				diagnostics, err := manager.Diagnose(config, version,
+					WithLintOptions(WithLintProfile(LintProfileProduction)), WithMinSeverity(LintSeverityWarning))
+				require.NoError(t, err)
+				assert.Empty(t, diagnostics)
+			})
+		}
+	}
+}
+
+func TestTemplateDescription(t *testing.T) {
+	assert.Equal(t, "First line second line", templateDescription([]byte("# First line\n#   second line\n#\nreceivers:\n# not part\n")))
+	assert.Empty(t, templateDescription([]byte("receivers:\n")))
+}
@@ -0,0 +1,132 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Codes of the errors reported for config provider references, e.g. ${env:API_KEY}
+const (
+	ErrorCodeUnbalancedProviderReference = "confmap-unbalanced-braces"
+	ErrorCodeUnknownProviderScheme       = "confmap-unknown-scheme"
+	ErrorCodeEmptyProviderSelector       = "confmap-empty-selector"
+	ErrorCodeInvalidEnvVarName           = "confmap-invalid-env-name"
+)
+
+// configProviderSchemes are the schemes of the config providers of the core and contrib distributions
+var configProviderSchemes = []string{
+	"env", "file", "http", "https", "yaml", "aes", "s3", "secretsmanager", "googlesecretmanager",
+}
+
+// envVarNamePattern matches the names accepted by the env provider
+var envVarNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// WithConfigProviders accepts references to config providers with the given schemes in addition to the providers
+// of the core and contrib distributions, e.g. for custom builds with their own confmap providers
+func WithConfigProviders(schemes ...string) ValidationOption {
+	return func(options *validationOptions) {
+		options.configProviders = append(options.configProviders, schemes...)
+	}
+}
+
+// validateProviderReferences checks the syntax of the config provider references in the values of a configuration:
+// braces are balanced, the scheme is a known provider and the selector is not empty. $$ escapes a literal $.
+func validateProviderReferences(path string, value interface{}, schemes []string, result *ConfigValidationResult) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			validateProviderReferences(childPath, v[key], schemes, result)
+		}
+	case []interface{}:
+		for i, item := range v {
+			validateProviderReferences(fmt.Sprintf("%s[%d]", path, i), item, schemes, result)
+		}
+	case string:
+		validateProviderReferencesInString(path, v, schemes, result)
+	}
+}
+
+// validateProviderReferencesInString checks the references of a string value, references nested in the selector
+// of another reference are checked on their own, e.g. ${file:${env:TOKEN_FILE}}
+func validateProviderReferencesInString(path string, value string, schemes []string, result *ConfigValidationResult) {
+	for i := 0; i < len(value); i++ {
+		if strings.HasPrefix(value[i:], "$$") {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			continue
+		}
+
+		end := closingBrace(value, i+2)
+		if end < 0 {
+			result.addErrorWithCode(ErrorCodeUnbalancedProviderReference, path, "config provider reference in %q is missing a closing brace", value)
+			return
+		}
+		reference := value[i : end+1]
+		content := value[i+2 : end]
+		if strings.Contains(content, "${") {
+			validateProviderReferencesInString(path, content, schemes, result)
+		} else {
+			validateProviderReference(path, reference, content, schemes, result)
+		}
+		i = end
+	}
+}
+
+// validateProviderReference checks a single reference without nested references, content is the reference
+// without ${ and }
+func validateProviderReference(path string, reference string, content string, schemes []string, result *ConfigValidationResult) {
+	if content == "" {
+		result.addErrorWithCode(ErrorCodeEmptyProviderSelector, path, "%s has an empty selector", reference)
+		return
+	}
+
+	scheme, selector, hasScheme := strings.Cut(content, ":")
+	if !hasScheme {
+		// ${VAR} is read from the environment
+		if !envVarNamePattern.MatchString(content) {
+			result.addErrorWithCode(ErrorCodeInvalidEnvVarName, path, "%s is not a valid environment variable reference", reference)
+		}
+		return
+	}
+
+	if !contains(schemes, scheme) {
+		known := append([]string(nil), schemes...)
+		sort.Strings(known)
+		result.addErrorWithCode(ErrorCodeUnknownProviderScheme, path, "%s uses unknown config provider %q, expected one of %s", reference, scheme, strings.Join(known, ", "))
+		return
+	}
+	if selector == "" {
+		result.addErrorWithCode(ErrorCodeEmptyProviderSelector, path, "%s has an empty selector", reference)
+		return
+	}
+	// The env provider accepts a default value, e.g. ${env:LOG_LEVEL:-info}
+	if name, _, _ := strings.Cut(selector, ":-"); scheme == "env" && !envVarNamePattern.MatchString(name) {
+		result.addErrorWithCode(ErrorCodeInvalidEnvVarName, path, "%s references the invalid environment variable name %q", reference, name)
+	}
+}
+
+// closingBrace returns the index of the brace closing a reference whose content starts at start, or -1
+func closingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
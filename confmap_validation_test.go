@@ -0,0 +1,89 @@
+package collectorconfigschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCollectorConfig_ProviderReferences(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateCollectorConfig([]byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: ${env:MY_POD_IP}:4317
+exporters:
+  otlphttp:
+    endpoint: ${https://config.example.com/endpoint}
+    headers:
+      Authorization: Bearer ${file:/etc/otel/token}
+      X-Tenant: ${env:TENANT:-default}
+      X-Price: $${literal}
+  otlp:
+    endpoint: ${vault:secret/otlp}
+    headers:
+      api-key: ${file:${env:API_KEY_FILE}}
+      x-token: "${env:"
+      x-scope: ${env:}
+      x-user: ${env:1USER}
+      x-org: ${ORG_ID}
+      x-team: ${}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlphttp, otlp]
+`), "0.139.0")
+	require.NoError(t, err)
+
+	var providerErrors []ConfigValidationError
+	for _, validationError := range result.Errors {
+		if strings.HasPrefix(validationError.Code, "confmap-") {
+			providerErrors = append(providerErrors, validationError)
+		}
+	}
+	assert.Equal(t, []ConfigValidationError{
+		{
+			Path:     "exporters.otlp.endpoint",
+			Message:  `${vault:secret/otlp} uses unknown config provider "vault", expected one of aes, env, file, googlesecretmanager, http, https, s3, secretsmanager, yaml`,
+			Code:     ErrorCodeUnknownProviderScheme,
+			Position: &Position{Line: 15, Column: 5},
+		},
+		{Path: "exporters.otlp.headers.x-scope", Message: "${env:} has an empty selector", Code: ErrorCodeEmptyProviderSelector, Position: &Position{Line: 19, Column: 7}},
+		{Path: "exporters.otlp.headers.x-team", Message: "${} has an empty selector", Code: ErrorCodeEmptyProviderSelector, Position: &Position{Line: 22, Column: 7}},
+		{Path: "exporters.otlp.headers.x-token", Message: `config provider reference in "${env:" is missing a closing brace`, Code: ErrorCodeUnbalancedProviderReference, Position: &Position{Line: 18, Column: 7}},
+		{Path: "exporters.otlp.headers.x-user", Message: `${env:1USER} references the invalid environment variable name "1USER"`, Code: ErrorCodeInvalidEnvVarName, Position: &Position{Line: 20, Column: 7}},
+	}, providerErrors)
+}
+
+func TestValidateCollectorConfig_CustomConfigProviders(t *testing.T) {
+	manager := NewSchemaManager()
+	config := []byte(`
+exporters:
+  otlp:
+    endpoint: ${vault:secret/otlp}
+service:
+  telemetry:
+    logs:
+      level: ${consul:otel/log_level}
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0")
+	require.NoError(t, err)
+	var paths []string
+	for _, validationError := range result.Errors {
+		if validationError.Code == ErrorCodeUnknownProviderScheme {
+			paths = append(paths, validationError.Path)
+		}
+	}
+	assert.Equal(t, []string{"exporters.otlp.endpoint", "service.telemetry.logs.level"}, paths)
+
+	result, err = manager.ValidateCollectorConfig(config, "0.139.0", WithConfigProviders("vault", "consul"))
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), result.Errors)
+}
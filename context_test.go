@@ -0,0 +1,50 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contextTestConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`
+
+func TestSchemaManager_Context(t *testing.T) {
+	manager := NewSchemaManager()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := manager.ValidateCollectorConfigContext(canceled, []byte(contextTestConfig), "0.139.0")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = manager.LintContext(canceled, []byte(contextTestConfig), "0.139.0")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = manager.GetCollectorConfigSchemaContext(canceled, "0.139.0")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = manager.GetSchemaBundleContext(canceled, "0.139.0")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, manager.PreloadContext(canceled, "0.139.0", WithConcurrency(4)), context.Canceled)
+	assert.ErrorIs(t, manager.PreloadAllContext(canceled), context.Canceled)
+
+	results := manager.ValidateConfigFilesContext(canceled, []string{"testdata/a.yaml", "testdata/b.yaml"}, "0.139.0")
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+
+	// A live context validates as the methods without a context
+	result, err := manager.ValidateCollectorConfigContext(context.Background(), []byte(contextTestConfig), "0.139.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Config should be valid: %v", result.Errors)
+}
@@ -0,0 +1,313 @@
+// Package crd converts component JSON schemas into Kubernetes structural schemas
+// that can be embedded into the OpenTelemetryCollector CRD config field.
+package crd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// DefaultMaxDepth is the default nesting depth after which sub-schemas are collapsed
+const DefaultMaxDepth = 16
+
+// Kubernetes OpenAPI extension keywords
+const (
+	preserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+	intOrString           = "x-kubernetes-int-or-string"
+)
+
+// supportedFormats lists the string formats understood by the Kubernetes API server
+var supportedFormats = map[string]bool{
+	"bsonobjectid": true, "uri": true, "email": true, "hostname": true, "ipv4": true, "ipv6": true,
+	"cidr": true, "mac": true, "uuid": true, "uuid3": true, "uuid4": true, "uuid5": true,
+	"isbn": true, "isbn10": true, "isbn13": true, "creditcard": true, "ssn": true, "hexcolor": true,
+	"rgbcolor": true, "byte": true, "password": true, "date": true, "duration": true, "datetime": true,
+	"date-time": true,
+}
+
+// Converter transforms JSON schemas into Kubernetes structural schemas
+type Converter struct {
+	maxDepth int
+}
+
+// NewConverter creates a new converter that collapses sub-schemas nested deeper than maxDepth.
+// A maxDepth <= 0 uses DefaultMaxDepth.
+func NewConverter(maxDepth int) *Converter {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	return &Converter{
+		maxDepth: maxDepth,
+	}
+}
+
+// Convert converts a JSON schema into a structural schema: $ref is inlined, recursion is bounded, every node is
+// typed or preserves unknown fields and defaults and keywords unknown to Kubernetes are removed
+func (c *Converter) Convert(schema map[string]interface{}) (map[string]interface{}, error) {
+	// Definitions are taken from the root so that nested $ref values can be inlined
+	definitions := make(map[string]interface{})
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := schema[key].(map[string]interface{}); ok {
+			for name, def := range defs {
+				definitions[fmt.Sprintf("#/%s/%s", key, name)] = def
+			}
+		}
+	}
+
+	return c.convertNode(schema, definitions, 0, nil)
+}
+
+// ComponentSchema converts the schema of a single component into a structural schema
+func (c *Converter) ComponentSchema(sm *collectorschema.SchemaManager, componentType collectorschema.ComponentType, componentName string, version string) (map[string]interface{}, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for %s %s v%s: %w", componentType, componentName, version, err)
+	}
+
+	return c.Convert(schema.Schema)
+}
+
+// ConfigSchema builds a structural schema for the OpenTelemetryCollector CRD config field.
+// Components listed in the selection are validated by their converted schema when they are
+// referenced without a name (e.g. "otlp"); named instances (e.g. "otlp/internal") are preserved as-is.
+// A nil selection includes every component available in the version.
+func (c *Converter) ConfigSchema(sm *collectorschema.SchemaManager, version string, selection map[collectorschema.ComponentType][]string) (map[string]interface{}, error) {
+	if selection == nil {
+		components, err := sm.ListAvailableComponents(version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list components for version %s: %w", version, err)
+		}
+		selection = components
+	}
+
+	sections := map[collectorschema.ComponentType]string{
+		collectorschema.ComponentTypeReceiver:  "receivers",
+		collectorschema.ComponentTypeProcessor: "processors",
+		collectorschema.ComponentTypeExporter:  "exporters",
+		collectorschema.ComponentTypeExtension: "extensions",
+		collectorschema.ComponentTypeConnector: "connectors",
+	}
+
+	properties := make(map[string]interface{})
+	for componentType, section := range sections {
+		sectionProperties := make(map[string]interface{})
+
+		names := append([]string(nil), selection[componentType]...)
+		sort.Strings(names)
+		for _, name := range names {
+			converted, err := c.ComponentSchema(sm, componentType, name, version)
+			if err != nil {
+				return nil, err
+			}
+			sectionProperties[name] = converted
+		}
+
+		sectionSchema := map[string]interface{}{
+			"type":                "object",
+			preserveUnknownFields: true,
+		}
+		if len(sectionProperties) > 0 {
+			sectionSchema["properties"] = sectionProperties
+		}
+		properties[section] = sectionSchema
+	}
+
+	properties["service"] = map[string]interface{}{
+		"type":                "object",
+		preserveUnknownFields: true,
+	}
+
+	return map[string]interface{}{
+		"type":                "object",
+		"properties":          properties,
+		preserveUnknownFields: true,
+	}, nil
+}
+
+// convertNode converts a single schema node, refs tracks the $ref chain to detect recursion
+func (c *Converter) convertNode(node map[string]interface{}, definitions map[string]interface{}, depth int, refs []string) (map[string]interface{}, error) {
+	// Nodes past the maximum depth accept any value, they may be scalars or lists
+	if depth > c.maxDepth {
+		return opaqueValue(node), nil
+	}
+
+	// Inline references, collapsing recursive ones
+	if ref, ok := node["$ref"].(string); ok {
+		for _, seen := range refs {
+			if seen == ref {
+				return opaqueObject(), nil
+			}
+		}
+
+		target, ok := definitions[ref].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+
+		merged := make(map[string]interface{}, len(target)+len(node))
+		for key, value := range target {
+			merged[key] = value
+		}
+		for key, value := range node {
+			if key != "$ref" {
+				merged[key] = value
+			}
+		}
+		return c.convertNode(merged, definitions, depth, append(refs, ref))
+	}
+
+	result := make(map[string]interface{})
+
+	for key, value := range node {
+		switch key {
+		case "description", "pattern", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+			"minLength", "maxLength", "minItems", "maxItems", "uniqueItems", "multipleOf",
+			"minProperties", "maxProperties", "enum", "required", "nullable", "title":
+			result[key] = value
+		case "type":
+			c.convertType(value, result)
+		case "format":
+			if format, ok := value.(string); ok && supportedFormats[format] {
+				result[key] = format
+			}
+		case "const":
+			result["enum"] = []interface{}{value}
+		case "examples":
+			if examples, ok := value.([]interface{}); ok && len(examples) > 0 {
+				result["example"] = examples[0]
+			}
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			converted := make(map[string]interface{}, len(properties))
+			for name, property := range properties {
+				propertySchema, ok := property.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				convertedProperty, err := c.convertNode(propertySchema, definitions, depth+1, refs)
+				if err != nil {
+					return nil, fmt.Errorf("property %s: %w", name, err)
+				}
+				converted[name] = convertedProperty
+			}
+			result[key] = converted
+		case "items":
+			items, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			converted, err := c.convertNode(items, definitions, depth+1, refs)
+			if err != nil {
+				return nil, fmt.Errorf("items: %w", err)
+			}
+			result[key] = converted
+		case "additionalProperties":
+			switch additional := value.(type) {
+			case bool:
+				if additional {
+					result[preserveUnknownFields] = true
+				}
+			case map[string]interface{}:
+				converted, err := c.convertNode(additional, definitions, depth+1, refs)
+				if err != nil {
+					return nil, fmt.Errorf("additionalProperties: %w", err)
+				}
+				result[key] = converted
+			}
+		case "oneOf", "anyOf", "allOf", "patternProperties", "propertyNames", "if", "then", "else", "not":
+			// Structural schemas cannot express unions, keep the value and let the collector validate it
+			result[preserveUnknownFields] = true
+		default:
+			// Drop $schema, $defs, deprecated, x-* metadata and other keywords Kubernetes rejects. Defaults are
+			// dropped too, the API server would write them into the configuration, e.g. add every selected
+			// component to its section, while the collector applies them itself
+			if strings.HasPrefix(key, "x-kubernetes-") {
+				result[key] = value
+			}
+		}
+	}
+
+	// Kubernetes forbids properties and additionalProperties on the same node
+	if _, hasProperties := result["properties"]; hasProperties {
+		if _, hasAdditional := result["additionalProperties"]; hasAdditional {
+			delete(result, "additionalProperties")
+			result[preserveUnknownFields] = true
+		}
+	}
+
+	// Structural schemas require a type on every node
+	if _, typed := result["type"]; !typed && result[intOrString] == nil {
+		if result[preserveUnknownFields] == true {
+			return opaqueValue(result), nil
+		}
+		result["type"] = "object"
+		result[preserveUnknownFields] = true
+	}
+
+	// Unions collapse to an opaque value; nested property schemas would not be structural
+	if result[preserveUnknownFields] == true && result["type"] != "object" {
+		return opaqueValue(result), nil
+	}
+
+	return result, nil
+}
+
+// convertType maps JSON schema type values to a single structural type
+func (c *Converter) convertType(value interface{}, result map[string]interface{}) {
+	switch typeValue := value.(type) {
+	case string:
+		if typeValue == "null" {
+			result["nullable"] = true
+			return
+		}
+		result["type"] = typeValue
+	case []interface{}:
+		var types []string
+		for _, t := range typeValue {
+			if s, ok := t.(string); ok {
+				if s == "null" {
+					result["nullable"] = true
+					continue
+				}
+				types = append(types, s)
+			}
+		}
+		sort.Strings(types)
+		switch {
+		case len(types) == 1:
+			result["type"] = types[0]
+		case len(types) == 2 && types[0] == "integer" && types[1] == "string":
+			result[intOrString] = true
+		default:
+			result[preserveUnknownFields] = true
+		}
+	}
+}
+
+// opaqueObject returns a schema accepting any object
+func opaqueObject() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                "object",
+		preserveUnknownFields: true,
+	}
+}
+
+// opaqueValue returns a schema accepting any value, keeping only annotations from the original node
+func opaqueValue(node map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{
+		preserveUnknownFields: true,
+	}
+	if description, ok := node["description"]; ok {
+		result["description"] = description
+	}
+	if nullable, ok := node["nullable"]; ok {
+		result["nullable"] = nullable
+	}
+	return result
+}
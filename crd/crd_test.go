@@ -0,0 +1,221 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+func TestConverter_Convert(t *testing.T) {
+	converter := NewConverter(0)
+
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"default": map[string]interface{}{"endpoint": "localhost:4317"},
+		"properties": map[string]interface{}{
+			"endpoint": map[string]interface{}{
+				"type":       "string",
+				"deprecated": true,
+				"format":     "regex",
+				"default":    "localhost:4317",
+			},
+			"headers": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": true,
+			},
+			"labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"port": map[string]interface{}{
+				"type": []interface{}{"string", "integer"},
+			},
+			"auth": map[string]interface{}{
+				"$ref": "#/$defs/auth",
+			},
+			"encoding": map[string]interface{}{
+				"oneOf": []interface{}{
+					map[string]interface{}{"type": "string"},
+					map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+		"$defs": map[string]interface{}{
+			"auth": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"authenticator": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+
+	converted, err := converter.Convert(schema)
+	require.NoError(t, err)
+
+	assert.NotContains(t, converted, "$schema")
+	assert.NotContains(t, converted, "$defs")
+	assert.NotContains(t, converted, "default", "The API server should not write defaults into the configuration")
+
+	properties := converted["properties"].(map[string]interface{})
+
+	endpoint := properties["endpoint"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, endpoint, "deprecated, defaults and unsupported formats should be removed")
+
+	headers := properties["headers"].(map[string]interface{})
+	assert.Equal(t, true, headers[preserveUnknownFields])
+	assert.NotContains(t, headers, "additionalProperties")
+
+	labels := properties["labels"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, labels["additionalProperties"])
+
+	port := properties["port"].(map[string]interface{})
+	assert.Equal(t, true, port[intOrString])
+	assert.NotContains(t, port, "type")
+
+	auth := properties["auth"].(map[string]interface{})
+	assert.NotContains(t, auth, "$ref")
+	assert.Contains(t, auth["properties"], "authenticator")
+
+	encoding := properties["encoding"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{preserveUnknownFields: true}, encoding)
+}
+
+func TestConverter_Convert_RecursiveRef(t *testing.T) {
+	converter := NewConverter(0)
+
+	schema := map[string]interface{}{
+		"$ref": "#/$defs/rule",
+		"$defs": map[string]interface{}{
+			"rule": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":  map[string]interface{}{"type": "string"},
+					"child": map[string]interface{}{"$ref": "#/$defs/rule"},
+				},
+			},
+		},
+	}
+
+	converted, err := converter.Convert(schema)
+	require.NoError(t, err)
+
+	child := converted["properties"].(map[string]interface{})["child"].(map[string]interface{})
+	assert.Equal(t, opaqueObject(), child, "recursive reference should be collapsed")
+}
+
+func TestConverter_Convert_UnresolvableRef(t *testing.T) {
+	converter := NewConverter(0)
+
+	_, err := converter.Convert(map[string]interface{}{"$ref": "#/$defs/missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unresolvable $ref")
+}
+
+func TestConverter_Convert_MaxDepth(t *testing.T) {
+	converter := NewConverter(1)
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"a": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"b": map[string]interface{}{"type": "string", "description": "B"},
+					"c": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				},
+			},
+		},
+	}
+
+	converted, err := converter.Convert(schema)
+	require.NoError(t, err)
+
+	// Collapsed nodes accept any value, not only objects
+	a := converted["properties"].(map[string]interface{})["a"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{preserveUnknownFields: true, "description": "B"}, a["properties"].(map[string]interface{})["b"])
+	assert.Equal(t, map[string]interface{}{preserveUnknownFields: true}, a["properties"].(map[string]interface{})["c"])
+}
+
+func TestConverter_ComponentSchema(t *testing.T) {
+	converter := NewConverter(0)
+	manager := collectorschema.NewSchemaManager()
+
+	converted, err := converter.ComponentSchema(manager, collectorschema.ComponentTypeExporter, "otlp", "0.138.0")
+	require.NoError(t, err)
+
+	assertStructural(t, "exporter.otlp", converted)
+}
+
+func TestConverter_ConfigSchema(t *testing.T) {
+	converter := NewConverter(0)
+	manager := collectorschema.NewSchemaManager()
+
+	selection := map[collectorschema.ComponentType][]string{
+		collectorschema.ComponentTypeReceiver: {"otlp"},
+		collectorschema.ComponentTypeExporter: {"debug", "otlp"},
+	}
+
+	converted, err := converter.ConfigSchema(manager, "0.138.0", selection)
+	require.NoError(t, err)
+
+	properties := converted["properties"].(map[string]interface{})
+	for _, section := range []string{"receivers", "processors", "exporters", "extensions", "connectors", "service"} {
+		assert.Contains(t, properties, section)
+	}
+
+	exporters := properties["exporters"].(map[string]interface{})
+	assert.Len(t, exporters["properties"], 2)
+	assert.NotContains(t, exporters["properties"].(map[string]interface{})["debug"], "default")
+
+	// Defaults of the embedded schemas are not applied by the API server
+	otlp := properties["receivers"].(map[string]interface{})["properties"].(map[string]interface{})["otlp"].(map[string]interface{})
+	assert.NotContains(t, otlp, "default")
+	protocols := otlp["properties"].(map[string]interface{})["protocols"].(map[string]interface{})
+	assert.NotContains(t, protocols["properties"].(map[string]interface{})["grpc"], "default")
+
+	assertStructural(t, "config", converted)
+
+	_, err = converter.ConfigSchema(manager, "0.138.0", map[collectorschema.ComponentType][]string{
+		collectorschema.ComponentTypeReceiver: {"nonexistent"},
+	})
+	require.Error(t, err)
+}
+
+// assertStructural verifies the structural schema invariants Kubernetes enforces
+func assertStructural(t *testing.T, path string, schema map[string]interface{}) {
+	t.Helper()
+
+	_, typed := schema["type"]
+	if !typed && schema[preserveUnknownFields] != true && schema[intOrString] != true {
+		t.Errorf("%s: node without type", path)
+	}
+
+	for _, forbidden := range []string{"$ref", "$schema", "$defs", "deprecated", "oneOf", "anyOf", "allOf"} {
+		if _, exists := schema[forbidden]; exists {
+			t.Errorf("%s: forbidden keyword %s", path, forbidden)
+		}
+	}
+
+	if additional, exists := schema["additionalProperties"]; exists {
+		if _, ok := additional.(map[string]interface{}); !ok {
+			t.Errorf("%s: additionalProperties must be a schema", path)
+		}
+		if _, hasProperties := schema["properties"]; hasProperties {
+			t.Errorf("%s: properties and additionalProperties are mutually exclusive", path)
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			assertStructural(t, path+"."+name, property.(map[string]interface{}))
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		assertStructural(t, path+"[]", items)
+	}
+}
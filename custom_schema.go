@@ -0,0 +1,131 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// RegisterCustomSchema registers the JSON schema of a component that is not part of the embedded schemas,
+// e.g. an in-house receiver. An empty version registers the schema for all versions.
+// Registered schemas take precedence over embedded schemas of the same component.
+func (sm *SchemaManager) RegisterCustomSchema(componentType ComponentType, componentName string, version string, schema []byte) error {
+	var schemaData map[string]interface{}
+	if err := json.Unmarshal(schema, &schemaData); err != nil {
+		return fmt.Errorf("failed to parse schema JSON for %s %s: %w", componentType, componentName, err)
+	}
+
+	return sm.RegisterComponentSchema(&ComponentSchema{
+		Name:    componentName,
+		Type:    componentType,
+		Version: version,
+		Schema:  schemaData,
+		source:  schema,
+	})
+}
+
+// RegisterCustomSchemaFile registers the JSON schema of a component from a file, the file is verified with the
+// checksums.json in its directory, see RequireVerifiedSchemas
+func (sm *SchemaManager) RegisterCustomSchemaFile(componentType ComponentType, componentName string, version string, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file for %s %s: %w", componentType, componentName, err)
+	}
+	if err := sm.verification.verifyFile(path, data); err != nil {
+		return fmt.Errorf("failed to verify schema file for %s %s: %w", componentType, componentName, err)
+	}
+
+	return sm.RegisterCustomSchema(componentType, componentName, version, data)
+}
+
+// RegisterComponentSchema registers a component schema, e.g. one generated by the schemagen package.
+// An empty version registers the schema for all versions.
+func (sm *SchemaManager) RegisterComponentSchema(schema *ComponentSchema) error {
+	if !isValidComponentType(schema.Type) {
+		return fmt.Errorf("invalid component type: %s", schema.Type)
+	}
+	if schema.Name == "" || strings.ContainsAny(schema.Name, "/ ") {
+		return fmt.Errorf("invalid component name: %q", schema.Name)
+	}
+	if schema.Schema == nil {
+		return fmt.Errorf("schema for %s %s must not be empty", schema.Type, schema.Name)
+	}
+	if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema.Schema)); err != nil {
+		return fmt.Errorf("invalid schema for %s %s: %w", schema.Type, schema.Name, err)
+	}
+
+	// The caller's schema is not modified, it may be registered again, e.g. for another version
+	registered := *schema
+	if registered.Metadata == nil {
+		registered.Metadata = parseComponentMetadata(schema.Schema)
+	}
+
+	sm.customMu.Lock()
+	sm.custom[customSchemaKey(schema.Type, schema.Name, schema.Version)] = &registered
+	sm.customMu.Unlock()
+
+	// Validators compiled from the schema this one replaces are dropped, a schema for all versions replaces the
+	// validators of every version
+	prefix := fmt.Sprintf("%s/%s/", schema.Type, schema.Name)
+	sm.compiled.remove(func(key string) bool { return strings.HasPrefix(key, prefix) })
+
+	return nil
+}
+
+// getCustomSchema returns the registered schema of a component for a version, falling back to the schema registered for all versions
+func (sm *SchemaManager) getCustomSchema(componentType ComponentType, componentName string, version string) *ComponentSchema {
+	sm.customMu.RLock()
+	defer sm.customMu.RUnlock()
+	schema, exists := sm.custom[customSchemaKey(componentType, componentName, version)]
+	if !exists {
+		schema, exists = sm.custom[customSchemaKey(componentType, componentName, "")]
+	}
+	if !exists {
+		return nil
+	}
+
+	return &ComponentSchema{
+		Name:     schema.Name,
+		Type:     schema.Type,
+		Version:  version,
+		Schema:   schema.Schema,
+		Metadata: schema.Metadata,
+		source:   schema.source,
+	}
+}
+
+// addCustomComponents adds the names of registered components of a version to a component listing
+func (sm *SchemaManager) addCustomComponents(components map[ComponentType][]string, version string) {
+	sm.customMu.RLock()
+	defer sm.customMu.RUnlock()
+	for _, schema := range sm.custom {
+		if schema.Version != "" && schema.Version != version {
+			continue
+		}
+		if !contains(components[schema.Type], schema.Name) {
+			components[schema.Type] = append(components[schema.Type], schema.Name)
+			sort.Strings(components[schema.Type])
+		}
+	}
+}
+
+// hasCustomVersion returns whether schemas were registered for a specific version
+func (sm *SchemaManager) hasCustomVersion(version string) bool {
+	sm.customMu.RLock()
+	defer sm.customMu.RUnlock()
+	for _, schema := range sm.custom {
+		if schema.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// customSchemaKey returns the key of a registered schema
+func customSchemaKey(componentType ComponentType, componentName string, version string) string {
+	return fmt.Sprintf("%s_%s_%s", componentType, componentName, version)
+}
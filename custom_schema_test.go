@@ -0,0 +1,195 @@
+package collectorconfigschema
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inhouseSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "endpoint": {"type": "string"},
+    "batch_size": {"type": "integer"}
+  }
+}`
+
+func TestSchemaManager_RegisterCustomSchema(t *testing.T) {
+	manager := NewSchemaManager()
+
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "0.138.0", []byte(inhouseSchema)))
+
+	schema, err := manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, "inhouse", schema.Name)
+	assert.Equal(t, ComponentTypeReceiver, schema.Type)
+	assert.Equal(t, "0.138.0", schema.Version)
+
+	components, err := manager.ListAvailableComponents("0.138.0")
+	require.NoError(t, err)
+	assert.Contains(t, components[ComponentTypeReceiver], "inhouse")
+	assert.Contains(t, components[ComponentTypeReceiver], "otlp")
+
+	names, err := manager.GetComponentNames(ComponentTypeReceiver, "0.138.0")
+	require.NoError(t, err)
+	assert.Contains(t, names, "inhouse")
+
+	// The schema is only registered for 0.138.0
+	_, err = manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.137.0")
+	require.Error(t, err)
+	components, err = manager.ListAvailableComponents("0.137.0")
+	require.NoError(t, err)
+	assert.NotContains(t, components[ComponentTypeReceiver], "inhouse")
+}
+
+func TestSchemaManager_RegisterCustomSchema_AllVersions(t *testing.T) {
+	manager := NewSchemaManager()
+
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(inhouseSchema)))
+
+	for _, version := range []string{"0.137.0", "0.138.0"} {
+		schema, err := manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", version)
+		require.NoError(t, err)
+		assert.Equal(t, version, schema.Version)
+	}
+}
+
+func TestSchemaManager_RegisterCustomSchema_ValidateCollectorConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  inhouse:
+    endpoint: localhost:1234
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [inhouse]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `receivers.inhouse: unknown receiver type "inhouse"`, result.Errors[0].String())
+
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "0.138.0", []byte(inhouseSchema)))
+
+	result, err = manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Config should be valid: %v", result.Errors)
+
+	invalid := []byte(`
+receivers:
+  inhouse:
+    batch_size: large
+`)
+	result, err = manager.ValidateCollectorConfig(invalid, "0.138.0")
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Errors)
+	assert.Equal(t, "receivers.inhouse.batch_size", result.Errors[0].Path)
+}
+
+func TestSchemaManager_RegisterCustomSchemaFile(t *testing.T) {
+	manager := NewSchemaManager()
+
+	path := filepath.Join(t.TempDir(), "receiver_inhouse.json")
+	require.NoError(t, os.WriteFile(path, []byte(inhouseSchema), 0644))
+
+	require.NoError(t, manager.RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "0.138.0", path))
+	_, err := manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.138.0")
+	require.NoError(t, err)
+
+	err = manager.RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "0.138.0", filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read schema file")
+}
+
+func TestSchemaManager_RegisterComponentSchema_Generated(t *testing.T) {
+	manager := NewSchemaManager()
+
+	type inhouseConfig struct {
+		Endpoint string `mapstructure:"endpoint"`
+	}
+	schema, err := schemagen.GenerateSchema(inhouseConfig{}, schemagen.WithComments(false))
+	require.NoError(t, err)
+
+	require.NoError(t, manager.RegisterComponentSchema(&ComponentSchema{
+		Name:   "inhouse",
+		Type:   ComponentTypeExporter,
+		Schema: schema,
+	}))
+
+	result, err := manager.ValidateComponentJSON(ComponentTypeExporter, "inhouse", "0.138.0", []byte(`{"endpoint": 1}`))
+	require.NoError(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestSchemaManager_RegisterComponentSchema_Copy(t *testing.T) {
+	manager := NewSchemaManager()
+
+	schema := &ComponentSchema{
+		Name:   "inhouse",
+		Type:   ComponentTypeExporter,
+		Schema: map[string]interface{}{"type": "object", "x-otel": map[string]interface{}{"signals": []interface{}{"logs"}}},
+	}
+	require.NoError(t, manager.RegisterComponentSchema(schema))
+	assert.Nil(t, schema.Metadata, "The registered schema should not be modified")
+
+	registered, err := manager.GetComponentSchema(ComponentTypeExporter, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	require.NotNil(t, registered.Metadata)
+	assert.Equal(t, []string{"logs"}, registered.Metadata.Signals)
+}
+
+func TestSchemaManager_RegisterCustomSchema_Concurrent(t *testing.T) {
+	manager := NewSchemaManager()
+
+	var wg sync.WaitGroup
+	for _, version := range []string{"", "0.135.0", "0.136.0", "0.137.0", "0.138.0", "0.139.0"} {
+		wg.Add(2)
+		go func(version string) {
+			defer wg.Done()
+			assert.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", version, []byte(inhouseSchema)))
+		}(version)
+		go func(version string) {
+			defer wg.Done()
+			_, err := manager.ListAvailableComponents("0.139.0")
+			assert.NoError(t, err)
+			_, _ = manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.139.0")
+		}(version)
+	}
+	wg.Wait()
+
+	schema, err := manager.GetComponentSchema(ComponentTypeReceiver, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, "inhouse", schema.Name)
+}
+
+func TestSchemaManager_RegisterCustomSchema_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	err := manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte("{"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse schema JSON")
+
+	err = manager.RegisterCustomSchema("unknown", "inhouse", "", []byte(inhouseSchema))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid component type: unknown")
+
+	err = manager.RegisterCustomSchema(ComponentTypeReceiver, "otlp/2", "", []byte(inhouseSchema))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid component name: "otlp/2"`)
+
+	err = manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(`{"type": 1}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid schema for receiver inhouse")
+}
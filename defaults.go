@@ -0,0 +1,133 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyDefaults expands a sparse component configuration (YAML or JSON) with the default values recorded in the
+// component schema and returns the effective configuration as JSON. Values set by the user take precedence, maps are
+// merged and lists replace their defaults. Defaults of optional sections, e.g. the protocols of the otlp receiver,
+// are only applied when the section is present in the configuration. An empty configuration returns the defaults.
+func (sm *SchemaManager) ApplyDefaults(componentType ComponentType, componentName string, version string, config []byte) ([]byte, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(config, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s %s configuration: %w", componentType, componentName, err)
+	}
+	value := normalizeYAMLValue(raw)
+	if _, ok := value.(map[string]interface{}); !ok && value != nil {
+		return nil, fmt.Errorf("%s %s configuration must be a map, got %s", componentType, componentName, describeValue(value))
+	}
+
+	return json.MarshalIndent(applyComponentDefaults(schema, value), "", "  ")
+}
+
+// GetDefaultConfig returns the default configuration of a component as JSON, i.e. the configuration created by the
+// component factory when the schema was generated, so forms can be prefilled with the values the collector uses.
+// Optional sections that are disabled by default are not part of the default configuration. An error is returned
+// if the schema records no default configuration, e.g. for schemas that were not generated from the factory.
+func (sm *SchemaManager) GetDefaultConfig(componentType ComponentType, componentName string, version string) ([]byte, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := schema.Schema["default"]; !ok {
+		return nil, fmt.Errorf("no default configuration recorded for %s %s in version %s", componentType, componentName, version)
+	}
+
+	return json.MarshalIndent(applyComponentDefaults(schema, nil), "", "  ")
+}
+
+// applyComponentDefaults merges the defaults of a component schema into a parsed component configuration
+func applyComponentDefaults(schema *ComponentSchema, value interface{}) interface{} {
+	definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+	effective := applySchemaDefaults(schema.Schema, definitions, value)
+	if effective == nil {
+		return map[string]interface{}{}
+	}
+	return effective
+}
+
+// applySchemaDefaults merges the default of a schema into a value and applies the defaults of its properties
+func applySchemaDefaults(schema map[string]interface{}, definitions map[string]interface{}, value interface{}) interface{} {
+	// Defaults are recorded next to references, e.g. for optional sections using a shared definition
+	if defaults, ok := schema["default"]; ok {
+		value = mergeDefaults(deepCopyValue(defaults), value)
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			schema = definition
+			if defaults, ok := schema["default"]; ok {
+				value = mergeDefaults(deepCopyValue(defaults), value)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for key, item := range v {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			if propertySchema != nil {
+				v[key] = applySchemaDefaults(propertySchema, definitions, item)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				v[i] = applySchemaDefaults(items, definitions, item)
+			}
+		}
+	}
+
+	return value
+}
+
+// mergeDefaults deep merges a user value into its defaults, the user value wins unless it is null
+func mergeDefaults(defaults interface{}, value interface{}) interface{} {
+	if value == nil {
+		return defaults
+	}
+
+	defaultMap, ok := defaults.(map[string]interface{})
+	valueMap, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return value
+	}
+	for key, item := range valueMap {
+		defaultMap[key] = mergeDefaults(defaultMap[key], item)
+	}
+	return defaultMap
+}
+
+// deepCopyValue copies the maps and lists of a JSON compatible value so schema defaults are never modified
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyValue(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
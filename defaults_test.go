@@ -0,0 +1,150 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultsSchema records defaults like schemas generated by schemagen, the http section is disabled by default
+const defaultsSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "default": {
+    "grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}},
+    "interval": "30s",
+    "tags": ["a", "b"]
+  },
+  "properties": {
+    "grpc": {"$ref": "#/$defs/server"},
+    "http": {"$ref": "#/$defs/server", "default": {"endpoint": "localhost:4318"}},
+    "interval": {"type": "string"},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  },
+  "$defs": {
+    "server": {
+      "type": "object",
+      "properties": {
+        "endpoint": {"type": "string"},
+        "keepalive": {"type": "object", "properties": {"time": {"type": "string"}, "timeout": {"type": "string"}}}
+      }
+    }
+  }
+}`
+
+func TestSchemaManager_ApplyDefaults(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(defaultsSchema)))
+
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:     "empty configuration",
+			config:   ``,
+			expected: `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}}, "interval": "30s", "tags": ["a", "b"]}`,
+		},
+		{
+			name: "user values win and maps are merged",
+			config: `
+grpc:
+  keepalive:
+    timeout: 10s
+interval: 1m
+tags: [c]
+`,
+			expected: `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h", "timeout": "10s"}}, "interval": "1m", "tags": ["c"]}`,
+		},
+		{
+			name: "enabled optional section",
+			config: `
+http:
+`,
+			expected: `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}}, "http": {"endpoint": "localhost:4318"}, "interval": "30s", "tags": ["a", "b"]}`,
+		},
+		{
+			name:     "JSON configuration",
+			config:   `{"http": {"endpoint": "0.0.0.0:4318"}}`,
+			expected: `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}}, "http": {"endpoint": "0.0.0.0:4318"}, "interval": "30s", "tags": ["a", "b"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective, err := manager.ApplyDefaults(ComponentTypeReceiver, "inhouse", "0.138.0", []byte(tt.config))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(effective))
+		})
+	}
+
+	// The schema defaults are not modified by merging
+	_, err := manager.ApplyDefaults(ComponentTypeReceiver, "inhouse", "0.138.0", []byte(`grpc: {endpoint: "0.0.0.0:4317"}`))
+	require.NoError(t, err)
+	effective, err := manager.ApplyDefaults(ComponentTypeReceiver, "inhouse", "0.138.0", nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(effective), `"endpoint": "localhost:4317"`)
+}
+
+func TestSchemaManager_ApplyDefaults_Embedded(t *testing.T) {
+	manager := NewSchemaManager()
+
+	effective, err := manager.ApplyDefaults(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`
+protocols:
+  grpc:
+    endpoint: 0.0.0.0:4317
+`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"protocols": {"grpc": {"endpoint": "0.0.0.0:4317", "read_buffer_size": 524288, "transport": "tcp"}}}`, string(effective))
+
+	effective, err = manager.ApplyDefaults(ComponentTypeReceiver, "otlp", "0.135.0", []byte("protocols:\n  http:\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"protocols": {"http": {"endpoint": "localhost:4318", "logs_url_path": "/v1/logs", "metrics_url_path": "/v1/metrics", "traces_url_path": "/v1/traces"}}}`, string(effective))
+
+	effective, err = manager.ApplyDefaults(ComponentTypeProcessor, "batch", "0.139.0", []byte(`timeout: 1s`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metadata_cardinality_limit": 1000, "send_batch_size": 8192, "timeout": "1s"}`, string(effective))
+}
+
+func TestSchemaManager_ApplyDefaults_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.ApplyDefaults(ComponentTypeReceiver, "nonexistent", "0.138.0", nil)
+	assert.Error(t, err)
+
+	_, err = manager.ApplyDefaults(ComponentTypeReceiver, "otlp", "0.138.0", []byte(`[endpoint]`))
+	assert.EqualError(t, err, "receiver otlp configuration must be a map, got list")
+
+	_, err = manager.ApplyDefaults(ComponentTypeReceiver, "otlp", "0.138.0", []byte(`endpoint: [`))
+	assert.Error(t, err)
+}
+
+func TestSchemaManager_GetDefaultConfig(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(defaultsSchema)))
+
+	defaults, err := manager.GetDefaultConfig(ComponentTypeReceiver, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}}, "interval": "30s", "tags": ["a", "b"]}`, string(defaults))
+
+	// The returned configuration is a copy of the recorded defaults
+	defaults, err = manager.GetDefaultConfig(ComponentTypeReceiver, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"grpc": {"endpoint": "localhost:4317", "keepalive": {"time": "2h"}}, "interval": "30s", "tags": ["a", "b"]}`, string(defaults))
+
+	defaults, err = manager.GetDefaultConfig(ComponentTypeProcessor, "batch", "0.139.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metadata_cardinality_limit": 1000, "send_batch_size": 8192, "timeout": "200ms"}`, string(defaults))
+
+	// Protocols of the otlp receiver are disabled by default
+	defaults, err = manager.GetDefaultConfig(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(defaults))
+
+	_, err = manager.GetDefaultConfig(ComponentTypeReceiver, "kafka", "0.139.0")
+	assert.EqualError(t, err, "no default configuration recorded for receiver kafka in version 0.139.0")
+	_, err = manager.GetDefaultConfig(ComponentTypeReceiver, "doesnotexist", "0.139.0")
+	require.Error(t, err)
+}
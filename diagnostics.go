@@ -0,0 +1,367 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic codes of schema validation, see ValidateCollectorConfig
+const (
+	DiagnosticCodeInvalidConfig               = "OTELCFG001"
+	DiagnosticCodeUnknownComponent            = "OTELCFG002"
+	DiagnosticCodeUnknownField                = "OTELCFG003"
+	DiagnosticCodeInvalidType                 = "OTELCFG004"
+	DiagnosticCodeUnbalancedProviderReference = "OTELCFG005"
+	DiagnosticCodeUnknownProviderScheme       = "OTELCFG006"
+	DiagnosticCodeEmptyProviderSelector       = "OTELCFG007"
+	DiagnosticCodeInvalidEnvVarName           = "OTELCFG008"
+	DiagnosticCodeUnsupportedSignal           = "OTELCFG009"
+)
+
+// Diagnostic codes of the built-in lint rules, see Lint
+const (
+	DiagnosticCodeDuplicatePipeline  = "OTELCFG010"
+	DiagnosticCodeMemoryLimiterFirst = "OTELCFG011"
+	DiagnosticCodeBatchProcessor     = "OTELCFG012"
+	DiagnosticCodeDebugExporter      = "OTELCFG013"
+	DiagnosticCodeUnusedComponent    = "OTELCFG014"
+	DiagnosticCodeEndpointConflict   = "OTELCFG015"
+	DiagnosticCodeStorageDirectory   = "OTELCFG016"
+)
+
+// Diagnostic codes of deprecated settings, see Diagnose
+const (
+	DiagnosticCodeDeprecatedField     = "OTELCFG020"
+	DiagnosticCodeDeprecatedComponent = "OTELCFG021"
+)
+
+// Diagnostic codes of the security audit, see Audit
+const (
+	DiagnosticCodeInsecureTLS          = "OTELCFG030"
+	DiagnosticCodePlaintextEndpoint    = "OTELCFG031"
+	DiagnosticCodePublicEndpoint       = "OTELCFG032"
+	DiagnosticCodeMissingAuthenticator = "OTELCFG033"
+	DiagnosticCodeInlineCredential     = "OTELCFG034"
+)
+
+// diagnosticCodes maps the codes of validation errors and the IDs of audit checks to diagnostic codes
+var diagnosticCodes = map[string]string{
+	ErrorCodeUnknownComponent:            DiagnosticCodeUnknownComponent,
+	ErrorCodeUnknownField:                DiagnosticCodeUnknownField,
+	ErrorCodeInvalidType:                 DiagnosticCodeInvalidType,
+	ErrorCodeUnbalancedProviderReference: DiagnosticCodeUnbalancedProviderReference,
+	ErrorCodeUnknownProviderScheme:       DiagnosticCodeUnknownProviderScheme,
+	ErrorCodeEmptyProviderSelector:       DiagnosticCodeEmptyProviderSelector,
+	ErrorCodeInvalidEnvVarName:           DiagnosticCodeInvalidEnvVarName,
+	ErrorCodeUnsupportedSignal:           DiagnosticCodeUnsupportedSignal,
+	AuditCheckInsecureTLS:                DiagnosticCodeInsecureTLS,
+	AuditCheckPlaintextEndpoint:          DiagnosticCodePlaintextEndpoint,
+	AuditCheckPublicEndpoint:             DiagnosticCodePublicEndpoint,
+	AuditCheckMissingAuthenticator:       DiagnosticCodeMissingAuthenticator,
+	AuditCheckInlineCredential:           DiagnosticCodeInlineCredential,
+}
+
+// Diagnostic is a problem found in a collector configuration by validation, linting, deprecation checks or the
+// security audit, in a common form so tools can filter and suppress problems by code
+type Diagnostic struct {
+	// Code identifies the kind of problem, e.g. DiagnosticCodeUnknownField. Findings of custom lint rules without
+	// a code use the rule ID.
+	Code     string       `json:"code"`
+	Severity LintSeverity `json:"severity"`
+	// Path is the dot separated location of the problem, e.g. "receivers.otlp.protocols"
+	Path string `json:"path,omitempty"`
+	// Position is the location of Path in the configuration, it is nil if the path is not in the configuration
+	Position *Position `json:"position,omitempty"`
+	Message  string    `json:"message"`
+	// Suggestion describes how to fix the problem, if known
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// String returns the diagnostic in "severity: path: message (code)" form
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s: %s (%s)", d.Severity, d.Message, d.Code)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", d.Severity, d.Path, d.Message, d.Code)
+}
+
+// Diagnostic returns the validation error as an error diagnostic, errors without a dedicated code use
+// DiagnosticCodeInvalidConfig
+func (e ConfigValidationError) Diagnostic() Diagnostic {
+	code, ok := diagnosticCodes[e.Code]
+	if !ok {
+		code = DiagnosticCodeInvalidConfig
+	}
+	return Diagnostic{
+		Code:     code,
+		Severity: LintSeverityError,
+		Path:     e.Path,
+		Position: e.Position,
+		Message:  e.Message,
+	}
+}
+
+// Diagnostic returns the lint finding as a diagnostic
+func (f LintFinding) Diagnostic() Diagnostic {
+	code := f.Code
+	if code == "" {
+		code = f.RuleID
+	}
+	return Diagnostic{
+		Code:     code,
+		Severity: f.Severity,
+		Path:     f.Path,
+		Message:  f.Message,
+	}
+}
+
+// Diagnostic returns the audit finding as a diagnostic, the remediation is the suggestion
+func (f AuditFinding) Diagnostic() Diagnostic {
+	code, ok := diagnosticCodes[f.CheckID]
+	if !ok {
+		code = f.CheckID
+	}
+	return Diagnostic{
+		Code:       code,
+		Severity:   f.Severity,
+		Path:       f.Path,
+		Message:    f.Message,
+		Suggestion: f.Remediation,
+	}
+}
+
+// DiagnosticOption configures which diagnostics Diagnose reports
+type DiagnosticOption func(*diagnosticOptions)
+
+// diagnosticOptions holds the settings applied by DiagnosticOption
+type diagnosticOptions struct {
+	validation  []ValidationOption
+	lint        []LintOption
+	minSeverity LintSeverity
+	suppressed  []string
+	ignores     []DiagnosticIgnore
+}
+
+// WithValidationOptions validates the configuration with the given options, e.g. Strict
+func WithValidationOptions(opts ...ValidationOption) DiagnosticOption {
+	return func(options *diagnosticOptions) {
+		options.validation = append(options.validation, opts...)
+	}
+}
+
+// WithLintOptions lints the configuration with the given options, e.g. WithLintProfile
+func WithLintOptions(opts ...LintOption) DiagnosticOption {
+	return func(options *diagnosticOptions) {
+		options.lint = append(options.lint, opts...)
+	}
+}
+
+// WithMinSeverity skips diagnostics less severe than the given severity, e.g. LintSeverityWarning skips info
+// diagnostics
+func WithMinSeverity(severity LintSeverity) DiagnosticOption {
+	return func(options *diagnosticOptions) {
+		options.minSeverity = severity
+	}
+}
+
+// SuppressDiagnostics skips diagnostics with the given codes, e.g. when a problem is accepted for a deployment
+func SuppressDiagnostics(codes ...string) DiagnosticOption {
+	return func(options *diagnosticOptions) {
+		options.suppressed = append(options.suppressed, codes...)
+	}
+}
+
+// Diagnose validates, lints, checks for deprecated settings and audits a collector configuration (YAML or JSON)
+// and returns all problems as diagnostics sorted by position, diagnostics without a position come last. Deprecated
+// settings are fields marked deprecated in the component schemas and components whose stability is deprecated for
+// all signals. Comments like "# otelschema:ignore OTELCFG013" suppress the diagnostics of a configuration entry,
+// see IgnoreDiagnostics. An error is returned if the configuration cannot be parsed or an option references an
+// unknown lint rule.
+func (sm *SchemaManager) Diagnose(config []byte, version string, opts ...DiagnosticOption) ([]Diagnostic, error) {
+	return sm.DiagnoseContext(context.Background(), config, version, opts...)
+}
+
+// DiagnoseContext is Diagnose with a context, it stops with the error of the context when it is canceled
+func (sm *SchemaManager) DiagnoseContext(ctx context.Context, config []byte, version string, opts ...DiagnosticOption) ([]Diagnostic, error) {
+	options := &diagnosticOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	validation, err := sm.ValidateCollectorConfigContext(ctx, config, version, options.validation...)
+	if err != nil {
+		return nil, err
+	}
+	lint, err := sm.LintContext(ctx, config, version, options.lint...)
+	if err != nil {
+		return nil, err
+	}
+	deprecations, err := sm.deprecationDiagnostics(ctx, config, version)
+	if err != nil {
+		return nil, err
+	}
+	audit, err := sm.AuditContext(ctx, config, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+	}
+	positions := yamlPositions(&document)
+	ignores := append(yamlSuppressions(&document), options.ignores...)
+
+	var diagnostics []Diagnostic
+	for _, validationError := range validation.Errors {
+		diagnostics = append(diagnostics, validationError.Diagnostic())
+	}
+	for _, finding := range lint.Findings {
+		diagnostics = append(diagnostics, finding.Diagnostic())
+	}
+	diagnostics = append(diagnostics, deprecations...)
+	for _, finding := range audit.Findings {
+		diagnostics = append(diagnostics, finding.Diagnostic())
+	}
+
+	filtered := make([]Diagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		if contains(options.suppressed, diagnostic.Code) || ignored(diagnostic, ignores) {
+			continue
+		}
+		if options.minSeverity != "" && severityRank(diagnostic.Severity) < severityRank(options.minSeverity) {
+			continue
+		}
+		if diagnostic.Position == nil {
+			diagnostic.Position = lookupPosition(positions, diagnostic.Path)
+		}
+		filtered = append(filtered, diagnostic)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i].Position, filtered[j].Position
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case a.Line != b.Line:
+			return a.Line < b.Line
+		default:
+			return a.Column < b.Column
+		}
+	})
+	return filtered, nil
+}
+
+// severityRank orders severities from info to error, unknown severities rank lowest
+func severityRank(severity LintSeverity) int {
+	switch severity {
+	case LintSeverityError:
+		return 3
+	case LintSeverityWarning:
+		return 2
+	case LintSeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// deprecationDiagnostics reports the deprecated fields and components used by a configuration
+func (sm *SchemaManager) deprecationDiagnostics(ctx context.Context, config []byte, version string) ([]Diagnostic, error) {
+	configMap, err := parseCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	for _, cs := range componentSections {
+		section, _ := configMap[cs.section].(map[string]interface{})
+		for _, id := range sortedKeys(section) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			componentID, err := ParseComponentID(id)
+			if err != nil {
+				continue
+			}
+			componentSchema, err := sm.getComponentSchema(ctx, cs.componentType, componentID.Component, version)
+			if err != nil {
+				continue
+			}
+
+			path := cs.section + "." + id
+			if metadata := componentSchema.Metadata; metadata != nil && metadata.SignalStability("") == "deprecated" {
+				diagnostics = append(diagnostics, Diagnostic{
+					Code:       DiagnosticCodeDeprecatedComponent,
+					Severity:   LintSeverityWarning,
+					Path:       path,
+					Message:    fmt.Sprintf("%s %q is deprecated", cs.componentType, componentID.Component),
+					Suggestion: fmt.Sprintf("replace the component, see %s", metadata.Documentation),
+				})
+			}
+			definitions, _ := componentSchema.Schema["$defs"].(map[string]interface{})
+			diagnostics = appendDeprecatedFields(diagnostics, componentSchema.Schema, definitions, path, section[id])
+		}
+	}
+	return diagnostics, nil
+}
+
+// appendDeprecatedFields appends diagnostics for the deprecated fields set in a configuration value, schema is the
+// schema of the value or nil
+func appendDeprecatedFields(diagnostics []Diagnostic, schema map[string]interface{}, definitions map[string]interface{}, path string, value interface{}) []Diagnostic {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, definitionReferencePrefix)].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			if deprecated, _ := propertySchema["deprecated"].(bool); ok && deprecated {
+				description, _ := propertySchema["description"].(string)
+				diagnostics = append(diagnostics, Diagnostic{
+					Code:       DiagnosticCodeDeprecatedField,
+					Severity:   LintSeverityWarning,
+					Path:       path + "." + key,
+					Message:    fmt.Sprintf("field %q is deprecated", key),
+					Suggestion: deprecationNote(description),
+				})
+			}
+			diagnostics = appendDeprecatedFields(diagnostics, propertySchema, definitions, path+"."+key, v[key])
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			diagnostics = appendDeprecatedFields(diagnostics, items, definitions, fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	}
+	return diagnostics
+}
+
+// deprecationNote returns the first sentence of the deprecation notice in a field description, e.g. "Use
+// TranslationStrategy instead." for "... Deprecated: Use TranslationStrategy instead. This setting is ignored ..."
+func deprecationNote(description string) string {
+	i := strings.Index(description, "Deprecated")
+	if i < 0 {
+		return ""
+	}
+	note := description[i+len("Deprecated"):]
+	if _, after, found := strings.Cut(note, ":"); found {
+		note = after
+	}
+	note = strings.TrimSpace(note)
+	if end := strings.Index(note, ". "); end >= 0 {
+		note = note[:end+1]
+	}
+	return note
+}
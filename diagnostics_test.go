@@ -0,0 +1,165 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diagnosticsTestConfig = `receivers:
+  zipkin:
+    endpoint: 0.0.0.0:9411
+processors:
+  batch:
+    send_batch_size: 100
+    unknown_field: true
+exporters:
+  prometheus:
+    endpoint: localhost:8889
+    add_metric_suffixes: false
+  debug:
+service:
+  pipelines:
+    metrics:
+      receivers: [zipkin]
+      processors: [batch]
+      exporters: [prometheus]
+`
+
+// diagnosticStrings returns the diagnostics in "line:column: severity: path: message (code)" form
+func diagnosticStrings(diagnostics []Diagnostic) []string {
+	var result []string
+	for _, diagnostic := range diagnostics {
+		result = append(result, diagnostic.Position.String()+": "+diagnostic.String())
+	}
+	return result
+}
+
+func TestSchemaManager_Diagnose(t *testing.T) {
+	manager := NewSchemaManager()
+
+	diagnostics, err := manager.Diagnose([]byte(diagnosticsTestConfig), "0.139.0", WithValidationOptions(Strict()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`2:3: warning: receivers.zipkin: the receiver accepts data from any network client without authentication (OTELCFG033)`,
+		`3:5: warning: receivers.zipkin.endpoint: "0.0.0.0:9411" listens on all network interfaces (OTELCFG032)`,
+		`5:3: error: processors.batch: unknown field "unknown_field" (OTELCFG003)`,
+		`11:5: warning: exporters.prometheus.add_metric_suffixes: field "add_metric_suffixes" is deprecated (OTELCFG020)`,
+		`12:3: warning: exporters.debug: exporter "debug" is declared but not used in any pipeline (OTELCFG014)`,
+	}, diagnosticStrings(diagnostics))
+	assert.Equal(t, "Use TranslationStrategy instead.", diagnostics[3].Suggestion)
+	assert.Equal(t, "bind the endpoint to localhost or the address of a single interface, e.g. ${env:MY_POD_IP}:4317", diagnostics[1].Suggestion)
+}
+
+func TestSchemaManager_Diagnose_Options(t *testing.T) {
+	manager := NewSchemaManager()
+
+	diagnostics, err := manager.Diagnose([]byte(diagnosticsTestConfig), "0.139.0",
+		WithValidationOptions(Strict()),
+		WithMinSeverity(LintSeverityError))
+	require.NoError(t, err)
+	assert.Equal(t, []string{`5:3: error: processors.batch: unknown field "unknown_field" (OTELCFG003)`}, diagnosticStrings(diagnostics))
+
+	diagnostics, err = manager.Diagnose([]byte(diagnosticsTestConfig), "0.139.0",
+		SuppressDiagnostics(DiagnosticCodeMissingAuthenticator, DiagnosticCodePublicEndpoint),
+		WithLintOptions(DisableLintRules("unused-component")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`11:5: warning: exporters.prometheus.add_metric_suffixes: field "add_metric_suffixes" is deprecated (OTELCFG020)`,
+	}, diagnosticStrings(diagnostics))
+
+	_, err = manager.Diagnose([]byte(diagnosticsTestConfig), "0.139.0", WithLintOptions(EnableLintRules("unknown")))
+	require.Error(t, err)
+	_, err = manager.Diagnose([]byte("receivers: ["), "0.139.0")
+	require.Error(t, err)
+}
+
+func TestDiagnostic_Conversions(t *testing.T) {
+	assert.Equal(t, Diagnostic{
+		Code:     DiagnosticCodeInvalidConfig,
+		Severity: LintSeverityError,
+		Path:     "service",
+		Position: &Position{Line: 1, Column: 1},
+		Message:  "expected a map",
+	}, ConfigValidationError{Path: "service", Message: "expected a map", Position: &Position{Line: 1, Column: 1}}.Diagnostic())
+	assert.Equal(t, DiagnosticCodeUnknownProviderScheme, ConfigValidationError{Code: ErrorCodeUnknownProviderScheme}.Diagnostic().Code)
+
+	assert.Equal(t, "custom-rule", LintFinding{RuleID: "custom-rule", Severity: LintSeverityInfo}.Diagnostic().Code)
+	assert.Equal(t, DiagnosticCodeDebugExporter, LintFinding{RuleID: "debug-exporter", Code: DiagnosticCodeDebugExporter}.Diagnostic().Code)
+
+	assert.Equal(t, Diagnostic{
+		Code:       DiagnosticCodeInsecureTLS,
+		Severity:   LintSeverityWarning,
+		Path:       "exporters.otlp.tls.insecure",
+		Message:    "TLS is disabled",
+		Suggestion: "remove tls.insecure",
+	}, AuditFinding{
+		CheckID:     AuditCheckInsecureTLS,
+		Severity:    LintSeverityWarning,
+		Path:        "exporters.otlp.tls.insecure",
+		Message:     "TLS is disabled",
+		Remediation: "remove tls.insecure",
+	}.Diagnostic())
+}
+
+func TestDeprecationNote(t *testing.T) {
+	assert.Equal(t, "Use TranslationStrategy instead.", deprecationNote("AddMetricSuffixes controls suffixes. Deprecated: Use TranslationStrategy instead. This setting is ignored."))
+	assert.Equal(t, "use SASL with Mechanism set to PLAIN instead.", deprecationNote("PlainText is an alias. Deprecated [v0.123.0]: use SASL with Mechanism set to PLAIN instead."))
+	assert.Equal(t, "", deprecationNote("Endpoint of the server"))
+}
+
+func TestSchemaManager_Diagnose_Ignore(t *testing.T) {
+	manager := NewSchemaManager()
+
+	diagnostics, err := manager.Diagnose([]byte(`receivers:
+  zipkin: # otelschema:ignore OTELCFG033
+    endpoint: 0.0.0.0:9411 # otelschema:ignore OTELCFG032, OTELCFG033 -- behind the load balancer
+  jaeger:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:14250
+exporters:
+  # otelschema:ignore OTELCFG014 -- used by the on-call pipeline
+  debug:
+  prometheus:
+    endpoint: localhost:8889
+    add_metric_suffixes: false # otelschema:ignore
+service:
+  pipelines:
+    metrics:
+      receivers: [zipkin]
+      exporters: [prometheus]
+`), "0.139.0", IgnoreDiagnostics(DiagnosticIgnore{Code: DiagnosticCodeUnusedComponent, Path: "receivers.jaeger"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`6:7: warning: receivers.jaeger.protocols.grpc: the receiver accepts data from any network client without authentication (OTELCFG033)`,
+		`7:9: warning: receivers.jaeger.protocols.grpc.endpoint: "0.0.0.0:14250" listens on all network interfaces (OTELCFG032)`,
+		`16:5: info: service.pipelines.metrics: pipeline does not batch telemetry, add the batch processor or enable sending_queue.batch on its exporters (OTELCFG012)`,
+	}, diagnosticStrings(diagnostics))
+
+	diagnostics, err = manager.Diagnose([]byte(`# otelschema:ignore OTELCFG032 OTELCFG033
+
+receivers:
+  zipkin:
+    endpoint: 0.0.0.0:9411
+`), "0.139.0", IgnoreDiagnostics(DiagnosticIgnore{Code: DiagnosticCodeUnusedComponent}))
+	require.NoError(t, err)
+	assert.Empty(t, diagnostics)
+}
+
+func TestParseIgnoreComments(t *testing.T) {
+	assert.Equal(t, []DiagnosticIgnore{
+		{Code: "OTELCFG012", Path: "exporters.debug"},
+		{Code: "OTELCFG013", Path: "exporters.debug"},
+		{Path: "exporters.debug"},
+	}, parseIgnoreComments("exporters.debug", "# otelschema:ignore OTELCFG012,OTELCFG013 -- known\n# unrelated", "#otelschema:ignore"))
+	assert.Empty(t, parseIgnoreComments("exporters.debug", "# otelschema:ignored OTELCFG012", "# see otelschema:ignore"))
+}
+
+func TestIsPathOrChild(t *testing.T) {
+	assert.True(t, isPathOrChild("exporters.debug", "exporters.debug"))
+	assert.True(t, isPathOrChild("exporters.debug.verbosity", "exporters.debug"))
+	assert.True(t, isPathOrChild("service.pipelines.traces.receivers[0]", "service.pipelines.traces.receivers"))
+	assert.False(t, isPathOrChild("exporters.debugger", "exporters.debug"))
+}
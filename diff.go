@@ -0,0 +1,227 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of a change between two collector configurations
+type ChangeKind string
+
+// Change kinds
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// FieldChange is a changed field of a component, pipeline or of the service
+type FieldChange struct {
+	// Path is the dot separated location of the field, e.g. "protocols.grpc.endpoint"
+	Path     string      `json:"path"`
+	Kind     ChangeKind  `json:"kind"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// String returns the change in "path: old -> new" form
+func (c FieldChange) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %s", c.Path, formatDiffValue(c.NewValue))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %s", c.Path, formatDiffValue(c.OldValue))
+	default:
+		return fmt.Sprintf("%s: %s -> %s", c.Path, formatDiffValue(c.OldValue), formatDiffValue(c.NewValue))
+	}
+}
+
+// ComponentDiff holds the changes of a component declared in a configuration section
+type ComponentDiff struct {
+	Type ComponentType `json:"type"`
+	// ID is the component ID, e.g. "otlp/internal"
+	ID     string        `json:"id"`
+	Kind   ChangeKind    `json:"kind"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// PipelineDiff holds the changes of a pipeline of the service section
+type PipelineDiff struct {
+	// ID is the pipeline ID, e.g. "traces/internal"
+	ID     string        `json:"id"`
+	Kind   ChangeKind    `json:"kind"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// ConfigDiff holds the semantic differences between two collector configurations
+type ConfigDiff struct {
+	Components []ComponentDiff `json:"components"`
+	Pipelines  []PipelineDiff  `json:"pipelines"`
+	// Service holds the changes of the service section outside of pipelines, e.g. "extensions" and "telemetry.logs.level"
+	Service []FieldChange `json:"service"`
+}
+
+// Empty returns true if the configurations are semantically equal
+func (d *ConfigDiff) Empty() bool {
+	return len(d.Components) == 0 && len(d.Pipelines) == 0 && len(d.Service) == 0
+}
+
+// DiffConfigs compares two collector configurations (YAML or JSON) and returns the changed fields grouped per component
+// and pipeline. The configurations are normalized like NormalizeConfig and components declared in both are expanded
+// with the defaults of their schemas for the version, so reordered keys, anchors and explicitly set defaults are not
+// reported as changes. Added and removed components and pipelines are reported as a single change with an empty path
+// and their configuration as written. The order of lists is significant, e.g. the order of pipeline processors.
+func (sm *SchemaManager) DiffConfigs(a, b []byte, version string) (*ConfigDiff, error) {
+	oldConfig, err := normalizeCollectorConfig(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read old configuration: %w", err)
+	}
+	newConfig, err := normalizeCollectorConfig(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new configuration: %w", err)
+	}
+	sm.applySharedDefaults(oldConfig, newConfig, version)
+	if oldConfig, err = decodeAsJSON(oldConfig); err != nil {
+		return nil, fmt.Errorf("failed to read old configuration: %w", err)
+	}
+	if newConfig, err = decodeAsJSON(newConfig); err != nil {
+		return nil, fmt.Errorf("failed to read new configuration: %w", err)
+	}
+
+	diff := &ConfigDiff{}
+	for _, cs := range componentSections {
+		oldSection, _ := oldConfig[cs.section].(map[string]interface{})
+		newSection, _ := newConfig[cs.section].(map[string]interface{})
+		for _, id := range unionKeys(oldSection, newSection) {
+			kind, fields := diffEntry(oldSection, newSection, id)
+			if len(fields) > 0 {
+				diff.Components = append(diff.Components, ComponentDiff{Type: cs.componentType, ID: id, Kind: kind, Fields: fields})
+			}
+		}
+	}
+
+	oldService, _ := oldConfig[sectionService].(map[string]interface{})
+	newService, _ := newConfig[sectionService].(map[string]interface{})
+	oldPipelines, _ := oldService["pipelines"].(map[string]interface{})
+	newPipelines, _ := newService["pipelines"].(map[string]interface{})
+	for _, id := range unionKeys(oldPipelines, newPipelines) {
+		kind, fields := diffEntry(oldPipelines, newPipelines, id)
+		if len(fields) > 0 {
+			diff.Pipelines = append(diff.Pipelines, PipelineDiff{ID: id, Kind: kind, Fields: fields})
+		}
+	}
+
+	for _, key := range unionKeys(oldService, newService) {
+		if key != "pipelines" {
+			diffValues(key, oldService[key], newService[key], &diff.Service)
+		}
+	}
+
+	return diff, nil
+}
+
+// applySharedDefaults applies the defaults of their schemas to the components declared in both normalized
+// configurations. Added and removed components are kept as written, so their change is the configuration the user
+// wrote and not the expanded defaults.
+func (sm *SchemaManager) applySharedDefaults(oldConfig, newConfig map[string]interface{}, version string) {
+	for _, cs := range componentSections {
+		oldSection, _ := oldConfig[cs.section].(map[string]interface{})
+		newSection, _ := newConfig[cs.section].(map[string]interface{})
+		for id, oldBody := range oldSection {
+			newBody, ok := newSection[id]
+			if !ok {
+				continue
+			}
+			componentID, err := ParseComponentID(id)
+			if err != nil {
+				continue
+			}
+			// Components without a schema are compared as written
+			if schema, err := sm.GetComponentSchema(cs.componentType, componentID.Component, version); err == nil {
+				oldSection[id] = applyComponentDefaults(schema, oldBody)
+				newSection[id] = applyComponentDefaults(schema, newBody)
+			}
+		}
+	}
+}
+
+// decodeAsJSON encodes and decodes a configuration as JSON, so numbers from the configuration and from schema
+// defaults compare equal
+func decodeAsJSON(config map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// diffEntry compares an entry of two maps, e.g. a component of a section, and returns its kind of change and changed
+// fields. Added and removed entries are a single change with an empty path and the value of the entry.
+func diffEntry(oldEntries, newEntries map[string]interface{}, key string) (ChangeKind, []FieldChange) {
+	oldValue, inOld := oldEntries[key]
+	newValue, inNew := newEntries[key]
+	switch {
+	case !inOld:
+		return ChangeAdded, []FieldChange{{Kind: ChangeAdded, NewValue: newValue}}
+	case !inNew:
+		return ChangeRemoved, []FieldChange{{Kind: ChangeRemoved, OldValue: oldValue}}
+	}
+
+	var fields []FieldChange
+	diffValues("", oldValue, newValue, &fields)
+	return ChangeModified, fields
+}
+
+// diffValues appends the changes between two values, maps are compared per key while lists and scalars are compared as a whole
+func diffValues(path string, oldValue, newValue interface{}, changes *[]FieldChange) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		for _, key := range unionKeys(oldMap, newMap) {
+			oldItem, inOld := oldMap[key]
+			newItem, inNew := newMap[key]
+			keyPath := strings.TrimPrefix(path+"."+key, ".")
+			switch {
+			case !inOld:
+				*changes = append(*changes, FieldChange{Path: keyPath, Kind: ChangeAdded, NewValue: newItem})
+			case !inNew:
+				*changes = append(*changes, FieldChange{Path: keyPath, Kind: ChangeRemoved, OldValue: oldItem})
+			default:
+				diffValues(keyPath, oldItem, newItem, changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, FieldChange{Path: path, Kind: ChangeModified, OldValue: oldValue, NewValue: newValue})
+	}
+}
+
+// unionKeys returns the sorted keys of two maps
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := sortedKeys(a)
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDiffValue formats a changed value as compact JSON
+func formatDiffValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
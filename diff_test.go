@@ -0,0 +1,121 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_DiffConfigs(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(defaultsSchema)))
+
+	oldConfig := []byte(`
+receivers:
+  inhouse:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  batch:
+    timeout: 5s
+  memory_limiter:
+    limit_mib: 512
+exporters:
+  debug:
+service:
+  telemetry:
+    logs:
+      level: info
+  pipelines:
+    traces:
+      receivers: [otlp, inhouse]
+      processors: [memory_limiter, batch]
+      exporters: [debug]
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`)
+
+	// Reordered keys and explicitly set defaults are not changes
+	newConfig := []byte(`
+service:
+  pipelines:
+    traces:
+      exporters: [debug, otlp/backend]
+      processors: [batch, memory_limiter]
+      receivers: [otlp, inhouse]
+  telemetry:
+    logs:
+      level: debug
+exporters:
+  otlp/backend:
+    endpoint: backend:4317
+  debug:
+processors:
+  memory_limiter:
+    limit_mib: 1024
+    spike_limit_mib: 256
+  batch:
+    timeout: 5s
+receivers:
+  otlp:
+    protocols:
+      grpc:
+  inhouse:
+    interval: 30s
+    grpc:
+      endpoint: localhost:4317
+`)
+
+	diff, err := manager.DiffConfigs(oldConfig, newConfig, "0.138.0")
+	require.NoError(t, err)
+	assert.False(t, diff.Empty())
+
+	assert.Equal(t, []ComponentDiff{
+		{Type: ComponentTypeProcessor, ID: "memory_limiter", Kind: ChangeModified, Fields: []FieldChange{
+			{Path: "limit_mib", Kind: ChangeModified, OldValue: float64(512), NewValue: float64(1024)},
+			{Path: "spike_limit_mib", Kind: ChangeAdded, NewValue: float64(256)},
+		}},
+		// Added components are a single change with their configuration as written, without defaults
+		{Type: ComponentTypeExporter, ID: "otlp/backend", Kind: ChangeAdded, Fields: []FieldChange{
+			{Kind: ChangeAdded, NewValue: map[string]interface{}{"endpoint": "backend:4317"}},
+		}},
+	}, diff.Components)
+
+	assert.Equal(t, []PipelineDiff{
+		{ID: "logs", Kind: ChangeRemoved, Fields: []FieldChange{
+			{Kind: ChangeRemoved, OldValue: map[string]interface{}{"exporters": []interface{}{"debug"}, "receivers": []interface{}{"otlp"}}},
+		}},
+		{ID: "traces", Kind: ChangeModified, Fields: []FieldChange{
+			{Path: "exporters", Kind: ChangeModified, OldValue: []interface{}{"debug"}, NewValue: []interface{}{"debug", "otlp/backend"}},
+			{Path: "processors", Kind: ChangeModified, OldValue: []interface{}{"memory_limiter", "batch"}, NewValue: []interface{}{"batch", "memory_limiter"}},
+		}},
+	}, diff.Pipelines)
+
+	require.Len(t, diff.Service, 1)
+	assert.Equal(t, `telemetry.logs.level: "info" -> "debug"`, diff.Service[0].String())
+}
+
+func TestSchemaManager_DiffConfigs_Equal(t *testing.T) {
+	manager := NewSchemaManager()
+
+	diff, err := manager.DiffConfigs([]byte("exporters:\n  debug:\n"), []byte(`{"exporters": {"debug": {}}}`), "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+
+	diff, err = manager.DiffConfigs([]byte("exporters:\n  debug:\n"), nil, "0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, []ComponentDiff{{Type: ComponentTypeExporter, ID: "debug", Kind: ChangeRemoved, Fields: []FieldChange{
+		{Kind: ChangeRemoved, OldValue: map[string]interface{}{}},
+	}}}, diff.Components)
+
+	diff, err = manager.DiffConfigs(nil, []byte("processors:\n  batch:\n    timeout: 5s\n"), "0.138.0")
+	require.NoError(t, err)
+	require.Len(t, diff.Components, 1)
+	assert.Equal(t, []FieldChange{{Kind: ChangeAdded, NewValue: map[string]interface{}{"timeout": "5s"}}}, diff.Components[0].Fields)
+
+	_, err = manager.DiffConfigs([]byte("receivers: ["), nil, "0.138.0")
+	assert.ErrorContains(t, err, "failed to read old configuration")
+}
@@ -0,0 +1,210 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the distributions published by opentelemetry-collector-releases
+const (
+	DistributionCore    = "core"
+	DistributionContrib = "contrib"
+	DistributionK8s     = "k8s"
+	DistributionOTLP    = "otlp"
+)
+
+// distributionsLineRegexp matches the distributions row of the status table in component READMEs
+var distributionsLineRegexp = regexp.MustCompile(`^\|\s*Distributions\s*\|(.*)\|\s*$`)
+
+// distributionNameRegexp matches a single distribution reference like [contrib]
+var distributionNameRegexp = regexp.MustCompile(`\[([^\]]+)\]`)
+
+// moduleNameAliases maps module directory names to component names that cannot be derived from the module path
+var moduleNameAliases = map[string]string{
+	"asapauthextension":        "asapclient",
+	"podmanreceiver":           "podman_stats",
+	"simpleprometheusreceiver": "prometheus_simple",
+}
+
+// Distribution is a set of components available in a collector distribution
+type Distribution struct {
+	Name string
+	// Unresolved lists builder manifest modules that could not be mapped to a known component
+	Unresolved []string
+	components map[ComponentType]map[string]bool
+}
+
+// NewDistribution creates a distribution containing the given components
+func NewDistribution(name string, components map[ComponentType][]string) *Distribution {
+	distribution := &Distribution{
+		Name:       name,
+		components: make(map[ComponentType]map[string]bool),
+	}
+	for componentType, names := range components {
+		for _, componentName := range names {
+			distribution.Add(componentType, componentName)
+		}
+	}
+	return distribution
+}
+
+// Add adds a component to the distribution, e.g. a custom component with a registered schema
+func (d *Distribution) Add(componentType ComponentType, componentName string) {
+	if d.components[componentType] == nil {
+		d.components[componentType] = make(map[string]bool)
+	}
+	d.components[componentType][componentName] = true
+}
+
+// Has checks if the distribution contains a component
+func (d *Distribution) Has(componentType ComponentType, componentName string) bool {
+	return d.components[componentType][componentName]
+}
+
+// Components returns the sorted component names of the distribution by type
+func (d *Distribution) Components() map[ComponentType][]string {
+	components := make(map[ComponentType][]string)
+	for componentType, names := range d.components {
+		for componentName := range names {
+			components[componentType] = append(components[componentType], componentName)
+		}
+		sort.Strings(components[componentType])
+	}
+	return components
+}
+
+// GetDistribution returns one of the official distributions (core, contrib, k8s, otlp) for a version.
+// Membership is taken from the status table of the embedded component READMEs.
+func (sm *SchemaManager) GetDistribution(name string, version string) (*Distribution, error) {
+	cacheKey := fmt.Sprintf("%s_%s", name, version)
+	if distribution, exists := sm.distributions[cacheKey]; exists {
+		return distribution, nil
+	}
+
+	components, err := sm.listEmbeddedComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := NewDistribution(name, nil)
+	for componentType, names := range components {
+		for _, componentName := range names {
+			readme, err := sm.GetComponentReadme(componentType, componentName, version)
+			if err != nil {
+				continue
+			}
+			if contains(readmeDistributions(readme), name) {
+				distribution.Add(componentType, componentName)
+			}
+		}
+	}
+
+	if len(distribution.components) == 0 {
+		return nil, fmt.Errorf("distribution %s not found for version %s", name, version)
+	}
+
+	sm.distributions[cacheKey] = distribution
+
+	return distribution, nil
+}
+
+// DistributionFromBuilderManifest creates a distribution from an OpenTelemetry Collector Builder (ocb) manifest.
+// Modules are mapped to component names of the given version; modules without a matching component
+// are recorded in Distribution.Unresolved.
+func (sm *SchemaManager) DistributionFromBuilderManifest(manifest []byte, version string) (*Distribution, error) {
+	var builderManifest BuilderManifest
+	if err := yaml.Unmarshal(manifest, &builderManifest); err != nil {
+		return nil, fmt.Errorf("failed to parse builder manifest: %w", err)
+	}
+
+	available, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := NewDistribution(builderManifest.Dist.Name, nil)
+	for componentType, modules := range builderManifest.modulesByType() {
+		for _, module := range modules {
+			modulePath := strings.Fields(module.GoMod)
+			if len(modulePath) == 0 {
+				continue
+			}
+
+			componentName := componentNameForModule(componentType, modulePath[0], available[componentType])
+			if componentName == "" {
+				distribution.Unresolved = append(distribution.Unresolved, modulePath[0])
+				continue
+			}
+			distribution.Add(componentType, componentName)
+		}
+	}
+	sort.Strings(distribution.Unresolved)
+
+	return distribution, nil
+}
+
+// ListDistributionComponents returns the components of a version that are part of a distribution
+func (sm *SchemaManager) ListDistributionComponents(version string, distribution *Distribution) (map[ComponentType][]string, error) {
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make(map[ComponentType][]string)
+	for componentType, names := range components {
+		for _, componentName := range names {
+			if distribution.Has(componentType, componentName) {
+				filtered[componentType] = append(filtered[componentType], componentName)
+			}
+		}
+	}
+
+	return filtered, nil
+}
+
+// readmeDistributions extracts distribution names from the status table of a component README
+func readmeDistributions(readme string) []string {
+	for _, line := range strings.Split(readme, "\n") {
+		match := distributionsLineRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		var distributions []string
+		for _, name := range distributionNameRegexp.FindAllStringSubmatch(match[1], -1) {
+			distributions = append(distributions, name[1])
+		}
+		return distributions
+	}
+	return nil
+}
+
+// componentNameForModule maps a Go module path such as ".../receiver/otlpreceiver" to a component name
+func componentNameForModule(componentType ComponentType, modulePath string, available []string) string {
+	moduleName := path.Base(modulePath)
+	if alias, ok := moduleNameAliases[moduleName]; ok {
+		return alias
+	}
+
+	trimmed := strings.TrimSuffix(moduleName, string(componentType))
+	candidates := []string{moduleName, trimmed, strings.TrimSuffix(trimmed, "auth")}
+
+	// Component names often contain underscores that module names do not have (k8s_cluster vs k8sclusterreceiver)
+	normalize := func(name string) string {
+		return strings.ReplaceAll(name, "_", "")
+	}
+	for _, candidate := range candidates {
+		for _, componentName := range available {
+			if normalize(componentName) == normalize(candidate) {
+				return componentName
+			}
+		}
+	}
+
+	return ""
+}
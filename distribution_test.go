@@ -0,0 +1,138 @@
+package collectorconfigschema
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_GetDistribution(t *testing.T) {
+	manager := NewSchemaManager()
+
+	core, err := manager.GetDistribution(DistributionCore, "0.138.0")
+	require.NoError(t, err, "Failed to get core distribution")
+
+	assert.True(t, core.Has(ComponentTypeReceiver, "otlp"), "otlp receiver should be part of core")
+	assert.True(t, core.Has(ComponentTypeProcessor, "batch"), "batch processor should be part of core")
+	assert.False(t, core.Has(ComponentTypeReceiver, "sqlquery"), "sqlquery receiver should not be part of core")
+
+	contrib, err := manager.GetDistribution(DistributionContrib, "0.138.0")
+	require.NoError(t, err, "Failed to get contrib distribution")
+	assert.True(t, contrib.Has(ComponentTypeReceiver, "sqlquery"))
+
+	components := core.Components()
+	assert.Less(t, len(components[ComponentTypeReceiver]), len(contrib.Components()[ComponentTypeReceiver]))
+
+	// Distributions are cached per version
+	cached, err := manager.GetDistribution(DistributionCore, "0.138.0")
+	require.NoError(t, err)
+	assert.Same(t, core, cached)
+
+	_, err = manager.GetDistribution("nonexistent", "0.138.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "distribution nonexistent not found for version 0.138.0")
+}
+
+func TestSchemaManager_DistributionFromBuilderManifest(t *testing.T) {
+	manager := NewSchemaManager()
+
+	manifest := []byte(`
+dist:
+  name: otelcol-custom
+receivers:
+  - gomod: go.opentelemetry.io/collector/receiver/otlpreceiver v0.138.0
+  - gomod: github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver v0.138.0
+  - gomod: github.com/open-telemetry/opentelemetry-collector-contrib/receiver/simpleprometheusreceiver v0.138.0
+  - gomod: github.com/example/private/receiver/inhousereceiver v1.2.3
+processors:
+  - gomod: go.opentelemetry.io/collector/processor/batchprocessor v0.138.0
+exporters:
+  - gomod: go.opentelemetry.io/collector/exporter/debugexporter v0.138.0
+extensions:
+  - gomod: github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension v0.138.0
+  - gomod: github.com/open-telemetry/opentelemetry-collector-contrib/extension/oidcauthextension v0.138.0
+`)
+
+	distribution, err := manager.DistributionFromBuilderManifest(manifest, "0.138.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "otelcol-custom", distribution.Name)
+	assert.Equal(t, map[ComponentType][]string{
+		ComponentTypeReceiver:  {"k8s_cluster", "otlp", "prometheus_simple"},
+		ComponentTypeProcessor: {"batch"},
+		ComponentTypeExporter:  {"debug"},
+		ComponentTypeExtension: {"health_check", "oidc"},
+	}, distribution.Components())
+	assert.Equal(t, []string{"github.com/example/private/receiver/inhousereceiver"}, distribution.Unresolved)
+
+	_, err = manager.DistributionFromBuilderManifest([]byte("receivers: {"), "0.138.0")
+	require.Error(t, err)
+}
+
+func TestSchemaManager_DistributionFromBuilderManifest_Contrib(t *testing.T) {
+	manager := NewSchemaManager()
+
+	manifest, err := os.ReadFile("manifest-0.138.0.yaml")
+	require.NoError(t, err)
+
+	distribution, err := manager.DistributionFromBuilderManifest(manifest, "0.138.0")
+	require.NoError(t, err)
+
+	// The skywalking encoding extension has no generated schema in 0.138.0
+	assert.Equal(t, []string{"github.com/open-telemetry/opentelemetry-collector-contrib/extension/encoding/skywalkingencodingextension"}, distribution.Unresolved)
+	assert.True(t, distribution.Has(ComponentTypeReceiver, "awscontainerinsightreceiver"))
+	assert.True(t, distribution.Has(ComponentTypeExtension, "oauth2client"))
+}
+
+func TestSchemaManager_ListDistributionComponents(t *testing.T) {
+	manager := NewSchemaManager()
+
+	distribution := NewDistribution("custom", map[ComponentType][]string{
+		ComponentTypeReceiver: {"otlp", "inhouse"},
+		ComponentTypeExporter: {"debug"},
+	})
+
+	components, err := manager.ListDistributionComponents("0.138.0", distribution)
+	require.NoError(t, err)
+	assert.Equal(t, map[ComponentType][]string{
+		ComponentTypeReceiver: {"otlp"},
+		ComponentTypeExporter: {"debug"},
+	}, components, "Only components with embedded schemas should be listed")
+}
+
+func TestSchemaManager_ValidateCollectorConfig_WithDistribution(t *testing.T) {
+	manager := NewSchemaManager()
+
+	core, err := manager.GetDistribution(DistributionCore, "0.138.0")
+	require.NoError(t, err)
+
+	config := []byte(`
+receivers:
+  otlp:
+  sqlquery:
+exporters:
+  debug:
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp, sqlquery]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Config should be valid without a distribution: %v", result.Errors)
+
+	result, err = manager.ValidateCollectorConfig(config, "0.138.0", WithDistribution(core))
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `receivers.sqlquery: receiver "sqlquery" is not part of the core distribution`, result.Errors[0].String())
+}
+
+func TestReadmeDistributions(t *testing.T) {
+	readme := "| Status        |           |\n| Distributions | [core], [contrib], [k8s] |\n"
+	assert.Equal(t, []string{"core", "contrib", "k8s"}, readmeDistributions(readme))
+	assert.Nil(t, readmeDistributions("# No status table"))
+}
@@ -0,0 +1,139 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// embeddedConfigPaths are the locations of collector configurations in wrapping documents: spec.config of
+// OpenTelemetryCollector custom resources and config of the values of the opentelemetry-collector Helm chart
+var embeddedConfigPaths = [][]string{
+	{"spec", "config"},
+	{"config"},
+}
+
+// EmbeddedConfig is a collector configuration found inside a wrapping document, see FindEmbeddedConfig
+type EmbeddedConfig struct {
+	// Path is the dot separated location of the configuration in the wrapping document, e.g. "spec.config"
+	Path string
+	// Config is the collector configuration in YAML
+	Config []byte
+}
+
+// FindEmbeddedConfig extracts the collector configuration of a document that wraps it, e.g. an
+// OpenTelemetryCollector custom resource (spec.config) or Helm values (config). The configuration may be embedded
+// as a map or as a YAML string, e.g. in v1alpha1 custom resources.
+func FindEmbeddedConfig(document []byte) (*EmbeddedConfig, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	for _, path := range embeddedConfigPaths {
+		node := lookupNode(&root, path...)
+		if node == nil {
+			continue
+		}
+
+		embedded := &EmbeddedConfig{Path: strings.Join(path, ".")}
+		switch {
+		case node.Kind == yaml.ScalarNode && node.Tag == "!!str":
+			embedded.Config = []byte(node.Value)
+		case node.Kind == yaml.MappingNode:
+			config, err := yaml.Marshal(node)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", embedded.Path, err)
+			}
+			embedded.Config = config
+		default:
+			return nil, fmt.Errorf("%s must be a map or a YAML string", embedded.Path)
+		}
+		return embedded, nil
+	}
+	return nil, fmt.Errorf("no collector configuration found, expected it in spec.config or config")
+}
+
+// ValidateEmbeddedConfig validates the collector configuration of a wrapping document like ValidateCollectorConfig,
+// see FindEmbeddedConfig. The paths of the errors are locations in the wrapping document, e.g.
+// "spec.config.receivers.otlp". An error is returned if no configuration is found or it cannot be parsed.
+func (sm *SchemaManager) ValidateEmbeddedConfig(document []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	embedded, err := FindEmbeddedConfig(document)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sm.ValidateCollectorConfig(embedded.Config, version, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", embedded.Path, err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	node := lookupNode(&root, strings.Split(embedded.Path, ".")...)
+	lineOffset, columnOffset, hasOffset := embeddedStringOffset(document, node)
+
+	for i := range result.Errors {
+		if result.Errors[i].Path == "" {
+			result.Errors[i].Path = embedded.Path
+		} else {
+			result.Errors[i].Path = embedded.Path + "." + result.Errors[i].Path
+		}
+		position := result.Errors[i].Position
+		switch {
+		case node.Kind == yaml.MappingNode:
+			// Located again in the wrapping document below
+			result.Errors[i].Position = nil
+		case position != nil && hasOffset:
+			result.Errors[i].Position = &Position{Line: position.Line + lineOffset, Column: position.Column + columnOffset}
+		default:
+			result.Errors[i].Position = nil
+		}
+	}
+	if node.Kind == yaml.MappingNode {
+		result.addPositions(yamlPositions(&root))
+	}
+	return result, nil
+}
+
+// embeddedStringOffset returns the offset of positions in a configuration embedded as a literal block string
+// (config: |) to positions in the wrapping document. Positions in other strings cannot be translated.
+func embeddedStringOffset(document []byte, node *yaml.Node) (int, int, bool) {
+	if node.Kind != yaml.ScalarNode || node.Style != yaml.LiteralStyle {
+		return 0, 0, false
+	}
+	// The block starts on the line after the indicator and is indented like its first line
+	lines := strings.Split(string(document), "\n")
+	for line := node.Line; line < len(lines); line++ {
+		if content := strings.TrimLeft(lines[line], " "); content != "" {
+			return node.Line, len(lines[line]) - len(content), true
+		}
+	}
+	return 0, 0, false
+}
+
+// lookupNode returns the node at the given keys of nested mappings or nil
+func lookupNode(node *yaml.Node, keys ...string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
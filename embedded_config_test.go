@@ -0,0 +1,109 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindEmbeddedConfig(t *testing.T) {
+	embedded, err := FindEmbeddedConfig([]byte(`
+apiVersion: opentelemetry.io/v1beta1
+kind: OpenTelemetryCollector
+spec:
+  config:
+    receivers:
+      otlp:
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "spec.config", embedded.Path)
+	assert.YAMLEq(t, "receivers: {otlp: null}", string(embedded.Config))
+
+	embedded, err = FindEmbeddedConfig([]byte(`
+apiVersion: opentelemetry.io/v1alpha1
+kind: OpenTelemetryCollector
+spec:
+  config: |
+    receivers:
+      otlp:
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "spec.config", embedded.Path)
+	assert.Equal(t, "receivers:\n  otlp:\n", string(embedded.Config))
+
+	embedded, err = FindEmbeddedConfig([]byte(`
+mode: deployment
+config:
+  exporters:
+    debug: {}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "config", embedded.Path)
+
+	_, err = FindEmbeddedConfig([]byte("mode: deployment"))
+	require.ErrorContains(t, err, "no collector configuration found")
+	_, err = FindEmbeddedConfig([]byte("config: [otlp]"))
+	require.ErrorContains(t, err, "config must be a map or a YAML string")
+	_, err = FindEmbeddedConfig([]byte("config: ["))
+	require.Error(t, err)
+}
+
+func TestSchemaManager_ValidateEmbeddedConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateEmbeddedConfig([]byte(`
+mode: deployment
+config:
+  receivers:
+    unknown: {}
+  exporters:
+    debug:
+      verbosity: loud
+  telemetry: {}
+`), "0.139.0")
+	require.NoError(t, err)
+	var paths []string
+	for _, validationError := range result.Errors {
+		paths = append(paths, validationError.Path)
+	}
+	assert.Equal(t, []string{"config.telemetry", "config.receivers.unknown", "config.exporters.debug.verbosity"}, paths)
+
+	_, err = manager.ValidateEmbeddedConfig([]byte("spec:\n  config: \"receivers: [\""), "0.139.0")
+	require.ErrorContains(t, err, "spec.config: failed to parse collector configuration")
+}
+
+func TestSchemaManager_ValidateEmbeddedConfig_Positions(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateEmbeddedConfig([]byte(`apiVersion: opentelemetry.io/v1beta1
+kind: OpenTelemetryCollector
+spec:
+  config:
+    receivers:
+      unknown:
+`), "0.139.0")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, &Position{Line: 6, Column: 7}, result.Errors[0].Position)
+
+	result, err = manager.ValidateEmbeddedConfig([]byte(`apiVersion: opentelemetry.io/v1alpha1
+kind: OpenTelemetryCollector
+spec:
+  config: |
+
+    receivers:
+      unknown:
+`), "0.139.0")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "spec.config.receivers.unknown", result.Errors[0].Path)
+	assert.Equal(t, &Position{Line: 7, Column: 7}, result.Errors[0].Position)
+
+	result, err = manager.ValidateEmbeddedConfig([]byte(`spec:
+  config: "receivers: {unknown: {}}"
+`), "0.139.0")
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	assert.Nil(t, result.Errors[0].Position)
+}
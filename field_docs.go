@@ -0,0 +1,294 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FieldDoc documents a field of a component configuration and its nested fields, see Explain
+type FieldDoc struct {
+	// Name is the key of the field, "[]" for the items of a list and "*" for the values of a map
+	Name string `json:"name"`
+	// Path is the dotted path of the field like the paths of SearchFields, e.g. sending_queue.num_consumers or
+	// headers.*, it is empty for the component itself
+	Path        string        `json:"path"`
+	Type        string        `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Deprecated  bool          `json:"deprecated,omitempty"`
+	Fields      []*FieldDoc   `json:"fields,omitempty"`
+}
+
+// Field returns the nested field with a name, or nil
+func (d *FieldDoc) Field(name string) *FieldDoc {
+	for _, field := range d.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Explain returns the documentation of the fields of a component, like kubectl explain: the type, default value,
+// allowed values and description of every field and its nested fields. Fields of shared definitions and of the
+// alternatives of unions are merged into the field that uses them, recursive definitions are expanded once. The
+// field is a path like the paths of SearchFields, e.g. "sending_queue" or "headers.*", an empty field returns the
+// documentation of the whole component.
+func (sm *SchemaManager) Explain(componentType ComponentType, componentName string, version string, field string) (*FieldDoc, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+	root := &FieldDoc{Name: componentName}
+	explainSchema(root, schema.Schema, definitions, nil, applyComponentDefaults(schema, nil))
+
+	doc := root
+	for _, segment := range fieldPathSegments(field) {
+		if doc = doc.Field(segment); doc == nil {
+			return nil, fmt.Errorf("%s %s has no field %q in version %s", componentType, componentName, field, version)
+		}
+	}
+	return doc, nil
+}
+
+// ExplainedField is a field of a component matched by ExplainPath
+type ExplainedField struct {
+	ComponentType ComponentType `json:"componentType"`
+	Component     string        `json:"component"`
+	// Path is the path of the field in a collector configuration, e.g. receivers.otlp.protocols.grpc.endpoint
+	Path string    `json:"path"`
+	Doc  *FieldDoc `json:"doc"`
+}
+
+// ExplainPath documents the fields at a path of a collector configuration with wildcards, e.g.
+// "receivers.otlp.protocols.*.endpoint" or "exporters.*.sending_queue". Every segment is a pattern of path.Match,
+// "*" matches any component or field and "**" matches any number of nested fields, e.g. "receivers.*.**.endpoint"
+// finds the endpoints of all receivers. Fields of shared definitions and union alternatives are matched like the
+// fields of the component, see Explain. The component segment may be a component ID, e.g. otlp/internal, whose type
+// is matched. Matches are sorted by component type, component and path.
+func (sm *SchemaManager) ExplainPath(version string, query string) ([]ExplainedField, error) {
+	segments := strings.Split(query, ".")
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return nil, fmt.Errorf("explain path %q must start with a section and a component, e.g. receivers.otlp", query)
+	}
+	namePattern, idSuffix, hasSuffix := strings.Cut(segments[1], "/")
+	fieldPatterns := fieldPathSegments(strings.Join(segments[2:], "."))
+	for _, pattern := range append([]string{segments[0], namePattern}, fieldPatterns...) {
+		if _, err := path.Match(pattern, ""); err != nil && pattern != "[]" {
+			return nil, fmt.Errorf("invalid pattern %q in explain path %q: %w", pattern, query, err)
+		}
+	}
+
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []ExplainedField
+	for _, cs := range componentSections {
+		if !segmentMatches(segments[0], cs.section) {
+			continue
+		}
+		for _, name := range components[cs.componentType] {
+			if !segmentMatches(namePattern, name) {
+				continue
+			}
+			doc, err := sm.Explain(cs.componentType, name, version, "")
+			if err != nil {
+				return nil, err
+			}
+			id := name
+			if hasSuffix {
+				id += "/" + idSuffix
+			}
+			// "**" matches a field along several paths, it is reported once
+			found := make(map[string]bool)
+			matchFieldDocs(doc, fieldPatterns, func(field *FieldDoc) {
+				if found[field.Path] {
+					return
+				}
+				found[field.Path] = true
+				fieldPath := cs.section + "." + id
+				switch {
+				case field.Path == "":
+				case strings.HasPrefix(field.Path, "[]"):
+					fieldPath += field.Path
+				default:
+					fieldPath += "." + field.Path
+				}
+				matches = append(matches, ExplainedField{ComponentType: cs.componentType, Component: name, Path: fieldPath, Doc: field})
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].ComponentType != matches[j].ComponentType {
+			return matches[i].ComponentType < matches[j].ComponentType
+		}
+		if matches[i].Component != matches[j].Component {
+			return matches[i].Component < matches[j].Component
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	return matches, nil
+}
+
+// matchFieldDocs calls visit for the fields below doc whose names match a list of patterns, "**" matches any
+// number of nested fields
+func matchFieldDocs(doc *FieldDoc, patterns []string, visit func(field *FieldDoc)) {
+	if len(patterns) == 0 {
+		visit(doc)
+		return
+	}
+	if patterns[0] == "**" {
+		matchFieldDocs(doc, patterns[1:], visit)
+		for _, field := range doc.Fields {
+			matchFieldDocs(field, patterns, visit)
+		}
+		return
+	}
+	for _, field := range doc.Fields {
+		if segmentMatches(patterns[0], field.Name) {
+			matchFieldDocs(field, patterns[1:], visit)
+		}
+	}
+}
+
+// segmentMatches returns whether a segment of a path matches a pattern of path.Match, list items ([]) are matched
+// literally
+func segmentMatches(pattern string, segment string) bool {
+	if pattern == segment {
+		return true
+	}
+	matched, err := path.Match(pattern, segment)
+	return err == nil && matched
+}
+
+// fieldPathSegments splits a field path into the names of FieldDoc, e.g. include.metric_names[] into include,
+// metric_names and []
+func fieldPathSegments(path string) []string {
+	var segments []string
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		items := 0
+		for strings.HasSuffix(key, "[]") {
+			key = strings.TrimSuffix(key, "[]")
+			items++
+		}
+		if key != "" {
+			segments = append(segments, key)
+		}
+		for ; items > 0; items-- {
+			segments = append(segments, "[]")
+		}
+	}
+	return segments
+}
+
+// explainSchema documents a schema in doc and adds its nested fields. refs holds the chain of followed references
+// so recursive definitions are expanded once, defaults holds the default value of the field in the default
+// configuration of the component.
+func explainSchema(doc *FieldDoc, schema map[string]interface{}, definitions map[string]interface{}, refs []string, defaults interface{}) {
+	// Keywords next to a reference take precedence over the keywords of the definition
+	explainKeywords(doc, schema, definitions, defaults)
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		definition, ok := definitions[name].(map[string]interface{})
+		switch {
+		case !ok:
+		case contains(refs, name):
+			// The fields of a recursive definition are documented at its first use
+			explainKeywords(doc, definition, definitions, defaults)
+		default:
+			explainSchema(doc, definition, definitions, append(refs, name), defaults)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	defaultFields, _ := defaults.(map[string]interface{})
+	for _, key := range sortedKeys(properties) {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := doc.nestedField(key)
+		field.Required = field.Required || containsValue(required, key)
+		explainSchema(field, property, definitions, refs, defaultFields[key])
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		explainSchema(doc.nestedField("[]"), items, definitions, refs, nil)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		explainSchema(doc.nestedField("*"), additional, definitions, refs, nil)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		alternatives, _ := schema[keyword].([]interface{})
+		for _, alternative := range alternatives {
+			if alternativeSchema, ok := alternative.(map[string]interface{}); ok {
+				explainSchema(doc, alternativeSchema, definitions, refs, defaults)
+			}
+		}
+	}
+}
+
+// explainKeywords records the type, description, default, allowed values and deprecation of a schema in doc,
+// values that are already documented are kept
+func explainKeywords(doc *FieldDoc, schema map[string]interface{}, definitions map[string]interface{}, defaults interface{}) {
+	if doc.Type == "" {
+		doc.Type = schemaTypeName(schema, definitions)
+	}
+	if description, ok := schema["description"].(string); ok && doc.Description == "" {
+		doc.Description = description
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && doc.Enum == nil {
+		doc.Enum = enum
+	}
+	// The constants of the alternatives of a union discriminated by a field are its allowed values
+	if value, ok := schema["const"].(string); ok && !containsValue(doc.Enum, value) {
+		doc.Enum = append(doc.Enum, value)
+		if doc.Type == "" {
+			doc.Type = "string"
+		}
+	}
+	if deprecated, ok := schema["deprecated"].(bool); ok && deprecated {
+		doc.Deprecated = true
+	}
+	if doc.Default != nil {
+		return
+	}
+	// The defaults of objects are documented at their fields
+	if value, ok := schema["default"]; ok {
+		defaults = value
+	}
+	if _, object := defaults.(map[string]interface{}); !object && defaults != nil {
+		doc.Default = defaults
+	}
+}
+
+// nestedField returns the nested field with a name, it is added if it is not documented yet
+func (d *FieldDoc) nestedField(name string) *FieldDoc {
+	if field := d.Field(name); field != nil {
+		return field
+	}
+	path := name
+	switch {
+	case d.Path == "":
+	case name == "[]":
+		path = d.Path + name
+	default:
+		path = d.Path + "." + name
+	}
+	field := &FieldDoc{Name: name, Path: path}
+	d.Fields = append(d.Fields, field)
+	return field
+}
@@ -0,0 +1,152 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_Explain(t *testing.T) {
+	manager := NewSchemaManager()
+
+	doc, err := manager.Explain(ComponentTypeExporter, "otlp", "0.139.0", "")
+	require.NoError(t, err)
+	assert.Equal(t, "otlp", doc.Name)
+	assert.Equal(t, "object", doc.Type)
+	require.NotNil(t, doc.Field("sending_queue"))
+
+	// Fields of shared definitions are documented at the field that uses them
+	queue, err := manager.Explain(ComponentTypeExporter, "otlp", "0.139.0", "sending_queue")
+	require.NoError(t, err)
+	assert.Equal(t, "sending_queue", queue.Path)
+	sizer := queue.Field("sizer")
+	require.NotNil(t, sizer)
+	assert.Equal(t, "sending_queue.sizer", sizer.Path)
+	assert.Equal(t, "string", sizer.Type)
+	assert.Equal(t, []interface{}{"requests", "items", "bytes"}, sizer.Enum)
+	assert.NotEmpty(t, sizer.Description)
+
+	batchSizer, err := manager.Explain(ComponentTypeExporter, "otlp", "0.139.0", "sending_queue.batch.sizer")
+	require.NoError(t, err)
+	assert.Equal(t, "sending_queue.batch.sizer", batchSizer.Path)
+
+	// The alternatives of the policies of tail_sampling are merged, the discriminator lists the policy types
+	policyType, err := manager.Explain(ComponentTypeProcessor, "tail_sampling", "0.139.0", "policies[].type")
+	require.NoError(t, err)
+	assert.True(t, policyType.Required)
+	assert.Contains(t, policyType.Enum, "latency")
+	assert.Contains(t, policyType.Enum, "probabilistic")
+	latency, err := manager.Explain(ComponentTypeProcessor, "tail_sampling", "0.139.0", "policies[].latency.threshold_ms")
+	require.NoError(t, err)
+	assert.Equal(t, "integer", latency.Type)
+
+	_, err = manager.Explain(ComponentTypeExporter, "otlp", "0.139.0", "sending_queue.doesnotexist")
+	assert.EqualError(t, err, `exporter otlp has no field "sending_queue.doesnotexist" in version 0.139.0`)
+	_, err = manager.Explain(ComponentTypeExporter, "doesnotexist", "0.139.0", "")
+	assert.Error(t, err)
+}
+
+func TestSchemaManager_Explain_Definitions(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeProcessor, "tree", "0.139.0", []byte(`{
+		"type": "object",
+		"required": ["root"],
+		"default": {"mode": "fast", "limits": {"depth": 3}},
+		"properties": {
+			"root": {"$ref": "#/$defs/node", "description": "Root of the tree"},
+			"mode": {"type": "string", "enum": ["fast", "slow"]},
+			"limits": {"type": "object", "properties": {"depth": {"type": "integer"}, "width": {"type": "integer", "default": 10}}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"legacy": {"type": "boolean", "deprecated": true}
+		},
+		"$defs": {
+			"node": {
+				"type": "object",
+				"description": "A node of the tree",
+				"properties": {
+					"node_name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`)))
+
+	doc, err := manager.Explain(ComponentTypeProcessor, "tree", "0.139.0", "")
+	require.NoError(t, err)
+
+	root := doc.Field("root")
+	require.NotNil(t, root)
+	assert.True(t, root.Required)
+	assert.Equal(t, "object", root.Type)
+	assert.Equal(t, "Root of the tree", root.Description, "The description next to the reference takes precedence")
+
+	// Recursive definitions are expanded once
+	children := root.Field("children")
+	require.NotNil(t, children)
+	item := children.Field("[]")
+	require.NotNil(t, item)
+	assert.Equal(t, "root.children[]", item.Path)
+	assert.Equal(t, "A node of the tree", item.Description)
+	assert.Empty(t, item.Fields)
+
+	assert.Equal(t, "fast", doc.Field("mode").Default, "Defaults of the component are documented at their fields")
+	assert.Nil(t, doc.Field("limits").Default)
+	assert.Equal(t, float64(3), doc.Field("limits").Field("depth").Default)
+	assert.Equal(t, float64(10), doc.Field("limits").Field("width").Default)
+	assert.Equal(t, "labels.*", doc.Field("labels").Field("*").Path)
+	assert.True(t, doc.Field("legacy").Deprecated)
+
+	nested, err := manager.Explain(ComponentTypeProcessor, "tree", "0.139.0", "root.children[]")
+	require.NoError(t, err)
+	assert.Equal(t, "object", nested.Type)
+}
+
+func TestSchemaManager_ExplainPath(t *testing.T) {
+	manager := NewSchemaManager()
+
+	matches, err := manager.ExplainPath("0.139.0", "receivers.otlp.*.grpc.endpoint")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, ComponentTypeReceiver, matches[0].ComponentType)
+	assert.Equal(t, "otlp", matches[0].Component)
+	assert.Equal(t, "receivers.otlp.protocols.grpc.endpoint", matches[0].Path)
+	assert.Equal(t, "string", matches[0].Doc.Type)
+
+	// "**" matches fields at any depth, the fields of shared settings included
+	matches, err = manager.ExplainPath("0.139.0", "receivers.otlp.**.endpoint")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"receivers.otlp.protocols.grpc.endpoint", "receivers.otlp.protocols.http.endpoint"}, explainedPaths(matches))
+
+	matches, err = manager.ExplainPath("0.139.0", "exporters.otlp*.sending_queue.enabled")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"exporters.otlp.sending_queue.enabled", "exporters.otlphttp.sending_queue.enabled"}, explainedPaths(matches))
+
+	matches, err = manager.ExplainPath("0.139.0", "processors.tail_sampling.policies[].type")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"processors.tail_sampling.policies[].type"}, explainedPaths(matches))
+
+	// The name of a component ID is kept in the paths
+	matches, err = manager.ExplainPath("0.139.0", "*.otlp/internal.protocols.grpc.tls")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"receivers.otlp/internal.protocols.grpc.tls"}, explainedPaths(matches))
+
+	matches, err = manager.ExplainPath("0.139.0", "receivers.otlp.doesnotexist.*")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	_, err = manager.ExplainPath("0.139.0", "receivers")
+	assert.ErrorContains(t, err, "must start with a section and a component")
+	_, err = manager.ExplainPath("0.139.0", "receivers.otlp.[")
+	assert.ErrorContains(t, err, `invalid pattern "["`)
+	_, err = manager.ExplainPath("0.1.0", "receivers.otlp")
+	assert.Error(t, err)
+}
+
+func explainedPaths(matches []ExplainedField) []string {
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+	return paths
+}
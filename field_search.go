@@ -0,0 +1,173 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldMatch is a field of a component schema found by SearchFields
+type FieldMatch struct {
+	ComponentType ComponentType `json:"componentType"`
+	Component     string        `json:"component"`
+	// Path is the dotted path of the field in the component configuration, list items are addressed with [] and
+	// the values of maps with *, e.g. headers.* or include.metric_names[]
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// SearchFields finds the fields of all components of a version whose path or description contains the query, the
+// match is case insensitive. It helps to discover where a setting lives, e.g. "tls" or "sampling_percentage".
+// Matches are sorted by component type, component and path.
+func (sm *SchemaManager) SearchFields(version string, query string) ([]FieldMatch, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, fmt.Errorf("search query must not be empty")
+	}
+
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []FieldMatch
+	for componentType, names := range components {
+		for _, name := range names {
+			schema, err := sm.GetComponentSchema(componentType, name, version)
+			if err != nil {
+				return nil, err
+			}
+			definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+			// Fields of union alternatives are found once
+			found := make(map[string]bool)
+			searchSchemaFields(schema.Schema, definitions, "", nil, func(path string, field map[string]interface{}) {
+				description, _ := field["description"].(string)
+				if found[path] {
+					return
+				}
+				if !strings.Contains(strings.ToLower(path), query) && !strings.Contains(strings.ToLower(description), query) {
+					return
+				}
+				found[path] = true
+				matches = append(matches, FieldMatch{
+					ComponentType: componentType,
+					Component:     name,
+					Path:          path,
+					Type:          schemaTypeName(field, definitions),
+					Description:   description,
+				})
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].ComponentType != matches[j].ComponentType {
+			return matches[i].ComponentType < matches[j].ComponentType
+		}
+		if matches[i].Component != matches[j].Component {
+			return matches[i].Component < matches[j].Component
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	return matches, nil
+}
+
+// searchSchemaFields calls visit for every property of a schema and its nested schemas, refs holds the chain of
+// followed references so recursive definitions are visited once
+func searchSchemaFields(schema map[string]interface{}, definitions map[string]interface{}, path string, refs []string, visit func(path string, field map[string]interface{})) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		if definition, ok := definitions[name].(map[string]interface{}); ok && !contains(refs, name) {
+			searchSchemaFields(definition, definitions, path, append(refs, name), visit)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, key := range sortedKeys(properties) {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		propertyPath := key
+		if path != "" {
+			propertyPath = path + "." + key
+		}
+		visit(propertyPath, property)
+		searchSchemaFields(property, definitions, propertyPath, refs, visit)
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		searchSchemaFields(items, definitions, path+"[]", refs, visit)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		additionalPath := "*"
+		if path != "" {
+			additionalPath = path + ".*"
+		}
+		searchSchemaFields(additional, definitions, additionalPath, refs, visit)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		alternatives, _ := schema[keyword].([]interface{})
+		for _, alternative := range alternatives {
+			if alternativeSchema, ok := alternative.(map[string]interface{}); ok {
+				searchSchemaFields(alternativeSchema, definitions, path, refs, visit)
+			}
+		}
+	}
+}
+
+// schemaTypeName returns the type of a schema, types of unions are joined with |, e.g. string|integer. The type of a
+// reference is the type of its definition.
+func schemaTypeName(schema map[string]interface{}, definitions map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			types = append(types, fmt.Sprint(item))
+		}
+		return strings.Join(types, "|")
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			if _, nested := definition["$ref"]; !nested {
+				return schemaTypeName(definition, definitions)
+			}
+		}
+	}
+	return ""
+}
+
+// unionAlternative returns the alternative of a oneOf or anyOf schema whose type matches a configuration value, e.g.
+// the object form of a field that also accepts a string shorthand. It returns nil if no alternative matches.
+func unionAlternative(schema map[string]interface{}, definitions map[string]interface{}, value interface{}) map[string]interface{} {
+	var valueType string
+	switch value.(type) {
+	case map[string]interface{}:
+		valueType = "object"
+	case []interface{}:
+		valueType = "array"
+	case string:
+		valueType = "string"
+	default:
+		return nil
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		alternatives, _ := schema[keyword].([]interface{})
+		for _, alternative := range alternatives {
+			alternativeSchema, ok := alternative.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, name := range strings.Split(schemaTypeName(alternativeSchema, definitions), "|") {
+				if name == valueType {
+					return alternativeSchema
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_SearchFields(t *testing.T) {
+	manager := NewSchemaManager()
+
+	matches, err := manager.SearchFields("0.139.0", "Sampling_Percentage")
+	require.NoError(t, err)
+	assert.Contains(t, matches, FieldMatch{
+		ComponentType: ComponentTypeProcessor,
+		Component:     "probabilistic_sampler",
+		Path:          "sampling_percentage",
+		Type:          "number",
+		Description:   findField(t, matches, "probabilistic_sampler", "sampling_percentage").Description,
+	})
+
+	matches, err = manager.SearchFields("0.139.0", "tls")
+	require.NoError(t, err)
+	insecure := findField(t, matches, "otlp", "tls.insecure")
+	assert.Equal(t, ComponentTypeExporter, insecure.ComponentType)
+	assert.Equal(t, "boolean", insecure.Type)
+	for i := 1; i < len(matches); i++ {
+		previous, current := matches[i-1], matches[i]
+		assert.True(t, previous.ComponentType < current.ComponentType ||
+			previous.ComponentType == current.ComponentType && (previous.Component < current.Component ||
+				previous.Component == current.Component && previous.Path < current.Path), "matches are sorted")
+	}
+
+	_, err = manager.SearchFields("0.139.0", " ")
+	require.Error(t, err)
+	_, err = manager.SearchFields("0.0.1", "tls")
+	require.Error(t, err)
+}
+
+func TestSchemaManager_SearchFields_Definitions(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeProcessor, "tree", "0.139.0", []byte(`{
+		"type": "object",
+		"properties": {
+			"root": {"$ref": "#/$defs/node"},
+			"labels": {"type": "object", "additionalProperties": {"$ref": "#/$defs/label"}}
+		},
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"node_name": {"type": "string", "description": "Name of the node"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			},
+			"label": {"anyOf": [
+				{"type": "object", "properties": {"label_value": {"type": ["string", "null"]}}},
+				{"type": "object", "properties": {"label_value": {"type": ["string", "null"]}}}
+			]}
+		}
+	}`)))
+
+	matches, err := manager.SearchFields("0.139.0", "node")
+	require.NoError(t, err)
+	var paths []string
+	for _, match := range findFields(matches, "tree") {
+		paths = append(paths, match.Path)
+	}
+	// Recursive definitions are not searched again inside themselves
+	assert.Equal(t, []string{"root.node_name"}, paths)
+
+	matches, err = manager.SearchFields("0.139.0", "label_value")
+	require.NoError(t, err)
+	assert.Contains(t, matches, FieldMatch{ComponentType: ComponentTypeProcessor, Component: "tree", Path: "labels.*.label_value", Type: "string|null"})
+	assert.Len(t, findFields(matches, "tree"), 1, "fields of union alternatives are found once")
+}
+
+// findField returns the match of a component field
+func findField(t *testing.T, matches []FieldMatch, component string, path string) FieldMatch {
+	t.Helper()
+	for _, match := range matches {
+		if match.Component == component && match.Path == path {
+			return match
+		}
+	}
+	require.Failf(t, "field not found", "%s %s", component, path)
+	return FieldMatch{}
+}
+
+// findFields returns the matches of a component
+func findFields(matches []FieldMatch, component string) []FieldMatch {
+	var found []FieldMatch
+	for _, match := range matches {
+		if match.Component == component {
+			found = append(found, match)
+		}
+	}
+	return found
+}
@@ -0,0 +1,209 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormModel holds what a react-jsonschema-form or JSON Forms renderer needs to edit a component configuration
+type FormModel struct {
+	Schema   map[string]interface{} `json:"schema"`
+	UISchema map[string]interface{} `json:"uiSchema"`
+	// FormData is the default configuration of the component, it is nil if the schema records no defaults
+	FormData interface{} `json:"formData,omitempty"`
+}
+
+// GetFormModel returns the schema, uiSchema and default configuration of a component, see GetUISchema
+func (sm *SchemaManager) GetFormModel(componentType ComponentType, componentName string, version string) (*FormModel, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+	uiSchema, err := sm.GetUISchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &FormModel{Schema: schema.Schema, UISchema: uiSchema}
+	if _, ok := schema.Schema["default"]; ok {
+		model.FormData = applyComponentDefaults(schema, nil)
+	}
+	return model, nil
+}
+
+// GetUISchema returns a react-jsonschema-form uiSchema for a component: fields are ordered as in the schema
+// document, i.e. in the declaration order of the struct fields for generated schemas, secrets use the password
+// widget, durations show an example as placeholder, OTTL statements and conditions use the textarea widget and
+// optional sections are collapsed with "ui:collapsed", which form templates can read from the ui options.
+func (sm *SchemaManager) GetUISchema(componentType ComponentType, componentName string, version string) (map[string]interface{}, error) {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	source := schema.source
+	if source == nil {
+		// Embedded schemas are decoded into maps, the order of properties is read from the document
+		source, _ = readEmbeddedFile(version, fmt.Sprintf("%s_%s.json", componentType, componentName))
+	}
+
+	definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+	builder := &uiSchemaBuilder{
+		definitions: definitions,
+		order:       schemaPropertyOrder(source),
+		visiting:    make(map[string]bool),
+	}
+	uiSchema := builder.object("", schema.Schema)
+	for _, field := range ottlFields[fmt.Sprintf("%s/%s", componentType, componentName)] {
+		setUIOption(uiSchema, field.path, "ui:widget", "textarea")
+	}
+	return uiSchema, nil
+}
+
+// durationPatternExpression is the pattern of duration fields in generated schemas
+const durationPatternExpression = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// durationPlaceholder is the placeholder of duration fields
+const durationPlaceholder = "e.g. 5s or 1m30s"
+
+// uiSchemaBuilder builds the uiSchema of a component schema
+type uiSchemaBuilder struct {
+	definitions map[string]interface{}
+	// order holds the property names of object schemas in document order by JSON pointer, e.g. "/$defs/tls"
+	order map[string][]string
+	// visiting holds the definitions being built, recursive definitions are not expanded again
+	visiting map[string]bool
+}
+
+// object returns the uiSchema of an object schema at a JSON pointer
+func (b *uiSchemaBuilder) object(pointer string, schema map[string]interface{}) map[string]interface{} {
+	uiSchema := make(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return uiSchema
+	}
+
+	required, _ := schema["required"].([]interface{})
+	order := b.propertyNames(pointer, properties)
+	uiSchema["ui:order"] = order
+	for _, name := range order {
+		property, _ := properties[name].(map[string]interface{})
+		if fieldUISchema := b.field(pointer+"/properties/"+name, name, property, containsValue(required, name)); len(fieldUISchema) > 0 {
+			uiSchema[name] = fieldUISchema
+		}
+	}
+	return uiSchema
+}
+
+// field returns the uiSchema of a property schema
+func (b *uiSchemaBuilder) field(pointer string, name string, schema map[string]interface{}, required bool) map[string]interface{} {
+	uiSchema := make(map[string]interface{})
+	if writeOnly, _ := schema["writeOnly"].(bool); writeOnly {
+		uiSchema["ui:widget"] = "password"
+		return uiSchema
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		definitionName := strings.TrimPrefix(ref, definitionReferencePrefix)
+		definition, ok := b.definitions[definitionName].(map[string]interface{})
+		if !ok || b.visiting[definitionName] {
+			return uiSchema
+		}
+		b.visiting[definitionName] = true
+		defer delete(b.visiting, definitionName)
+		return b.field("/$defs/"+definitionName, name, definition, required)
+	}
+
+	types := strings.Split(schemaTypeName(schema, b.definitions), "|")
+	_, hasProperties := schema["properties"]
+	pattern, _ := schema["pattern"].(string)
+	switch {
+	case contains(types, "string") && isCredentialKey(name):
+		uiSchema["ui:widget"] = "password"
+	case contains(types, "string") && pattern == durationPatternExpression:
+		uiSchema["ui:placeholder"] = durationPlaceholder
+	case contains(types, "object") || hasProperties:
+		uiSchema = b.object(pointer, schema)
+		if len(uiSchema) > 0 && !required {
+			uiSchema["ui:collapsed"] = true
+		}
+	case contains(types, "array"):
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			if itemsUISchema := b.field(pointer+"/items", name, items, true); len(itemsUISchema) > 0 {
+				uiSchema["items"] = itemsUISchema
+			}
+		}
+	}
+	return uiSchema
+}
+
+// propertyNames returns the names of properties in document order, properties missing from the document come last
+// in sorted order
+func (b *uiSchemaBuilder) propertyNames(pointer string, properties map[string]interface{}) []string {
+	var names []string
+	for _, name := range b.order[pointer] {
+		if _, ok := properties[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedKeys(properties) {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// setUIOption sets an option in a uiSchema at a configuration path, "*" in the path selects list items
+func setUIOption(uiSchema map[string]interface{}, path []string, key string, value interface{}) {
+	for _, segment := range path {
+		if segment == "*" {
+			segment = "items"
+		}
+		child, ok := uiSchema[segment].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			uiSchema[segment] = child
+		}
+		uiSchema = child
+	}
+	uiSchema[key] = value
+}
+
+// schemaPropertyOrder returns the property names of the object schemas of a JSON schema document in document order
+// by JSON pointer, JSON documents are parsed as YAML to keep the order of keys
+func schemaPropertyOrder(document []byte) map[string][]string {
+	order := make(map[string][]string)
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil || root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return order
+	}
+	recordPropertyOrder("", root.Content[0], order)
+	return order
+}
+
+// recordPropertyOrder records the property names of a schema node and its subschemas
+func recordPropertyOrder(pointer string, node *yaml.Node, order map[string][]string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyword, value := node.Content[i].Value, node.Content[i+1]
+		switch keyword {
+		case "properties", "$defs":
+			if value.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name := value.Content[j].Value
+				if keyword == "properties" {
+					order[pointer] = append(order[pointer], name)
+				}
+				recordPropertyOrder(pointer+"/"+keyword+"/"+name, value.Content[j+1], order)
+			}
+		case "items", "additionalProperties":
+			recordPropertyOrder(pointer+"/"+keyword, value, order)
+		}
+	}
+}
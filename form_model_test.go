@@ -0,0 +1,92 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const formSchema = `{
+  "type": "object",
+  "default": {"endpoint": "localhost:4317"},
+  "properties": {
+    "endpoint": {"type": "string"},
+    "timeout": {"type": ["string", "integer"], "pattern": "^[-+]?(0|(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"},
+    "dsn": {"type": "string", "writeOnly": true},
+    "api_key": {"type": "string"},
+    "server": {"$ref": "#/$defs/server"},
+    "tags": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string"}, "key": {"type": "string"}}}}
+  },
+  "required": ["server"],
+  "$defs": {
+    "server": {
+      "type": "object",
+      "properties": {
+        "port": {"type": "integer"},
+        "host": {"type": "string"},
+        "retry": {"type": "object", "properties": {"max": {"type": "integer"}, "enabled": {"type": "boolean"}}},
+        "parent": {"$ref": "#/$defs/server"}
+      }
+    }
+  }
+}`
+
+func TestSchemaManager_GetUISchema(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(formSchema)))
+
+	uiSchema, err := manager.GetUISchema(ComponentTypeReceiver, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	data, err := json.Marshal(uiSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"ui:order": ["endpoint", "timeout", "dsn", "api_key", "server", "tags"],
+		"timeout": {"ui:placeholder": "e.g. 5s or 1m30s"},
+		"dsn": {"ui:widget": "password"},
+		"api_key": {"ui:widget": "password"},
+		"server": {
+			"ui:order": ["port", "host", "retry", "parent"],
+			"retry": {"ui:order": ["max", "enabled"], "ui:collapsed": true}
+		},
+		"tags": {"items": {"ui:order": ["value", "key"]}}
+	}`, string(data))
+}
+
+func TestSchemaManager_GetUISchema_Embedded(t *testing.T) {
+	manager := NewSchemaManager()
+
+	uiSchema, err := manager.GetUISchema(ComponentTypeProcessor, "transform", "0.139.0")
+	require.NoError(t, err)
+	traceStatements := uiSchema["trace_statements"].(map[string]interface{})
+	assert.Equal(t, "textarea", traceStatements["items"].(map[string]interface{})["ui:widget"])
+	assert.Equal(t, map[string]interface{}{"ui:widget": "textarea"}, traceStatements["items"].(map[string]interface{})["statements"].(map[string]interface{})["items"])
+
+	uiSchema, err = manager.GetUISchema(ComponentTypeExporter, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ui:placeholder": durationPlaceholder}, uiSchema["timeout"])
+	assert.Equal(t, true, uiSchema["tls"].(map[string]interface{})["ui:collapsed"])
+
+	_, err = manager.GetUISchema(ComponentTypeExporter, "doesnotexist", "0.139.0")
+	require.Error(t, err)
+}
+
+func TestSchemaManager_GetFormModel(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "", []byte(formSchema)))
+
+	model, err := manager.GetFormModel(ComponentTypeReceiver, "inhouse", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"endpoint": "localhost:4317"}, model.FormData)
+	assert.Equal(t, "object", model.Schema["type"])
+	assert.Contains(t, model.UISchema, "ui:order")
+
+	model, err = manager.GetFormModel(ComponentTypeProcessor, "batch", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"metadata_cardinality_limit": float64(1000), "send_batch_size": float64(8192), "timeout": "200ms"}, model.FormData)
+
+	model, err = manager.GetFormModel(ComponentTypeReceiver, "kafka", "0.139.0")
+	require.NoError(t, err)
+	assert.Nil(t, model.FormData)
+}
@@ -0,0 +1,96 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateFragment validates a part of a collector configuration (YAML or JSON) rooted at a dot separated path,
+// e.g. "exporters", "exporters.otlp" or "exporters.otlp.sending_queue", for tools managing only a section or an
+// override patch. Only problems inside the fragment are reported: required fields outside the fragment, e.g. of
+// the component a nested block belongs to, and references to components that are not part of the fragment are not
+// checked. Paths of errors start with the fragment path and positions are positions in the fragment. An empty path
+// validates a full configuration. An error is returned if the fragment cannot be parsed or the path does not start
+// with a configuration section.
+func (sm *SchemaManager) ValidateFragment(path string, fragment []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	return sm.ValidateFragmentContext(context.Background(), path, fragment, version, opts...)
+}
+
+// ValidateFragmentContext is ValidateFragment with a context, the validation stops with the error of the context
+// when it is canceled
+func (sm *SchemaManager) ValidateFragmentContext(ctx context.Context, path string, fragment []byte, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	if path == "" {
+		return sm.ValidateCollectorConfigContext(ctx, fragment, version, opts...)
+	}
+
+	keys := strings.Split(path, ".")
+	if !isKnownSection(keys[0]) {
+		return nil, fmt.Errorf("fragment path %q must start with a configuration section", path)
+	}
+	for _, key := range keys {
+		if key == "" || strings.ContainsAny(key, "[]") {
+			return nil, fmt.Errorf("invalid fragment path %q, expected map keys separated by dots", path)
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(fragment, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration fragment: %w", err)
+	}
+	var raw interface{}
+	if document.Kind != 0 {
+		if err := document.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration fragment: %w", err)
+		}
+	}
+
+	// The fragment is validated as the only content of a configuration
+	wrapped := normalizeYAMLValue(raw)
+	for i := len(keys) - 1; i >= 0; i-- {
+		wrapped = map[string]interface{}{keys[i]: wrapped}
+	}
+
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	options.fragment = true
+
+	ctx, end := sm.telemetry.startCollectorConfigValidation(ctx, version)
+	result, err := sm.validateCollectorConfigMap(ctx, wrapped.(map[string]interface{}), version, options)
+	end(result, err)
+	if err != nil {
+		return nil, err
+	}
+
+	errors := result.Errors[:0]
+	for _, validationError := range result.Errors {
+		if isPathOrChild(validationError.Path, path) || isPathOrChild(bracketIndexes(validationError.Path), path) {
+			errors = append(errors, validationError)
+		}
+	}
+	result.Errors = errors
+	result.addPositions(fragmentPositions(path, &document))
+	return result, nil
+}
+
+// fragmentPositions returns the positions of the nodes of a fragment by their path in the configuration
+func fragmentPositions(path string, document *yaml.Node) map[string]Position {
+	positions := make(map[string]Position)
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return positions
+	}
+	root := document.Content[0]
+	positions[path] = Position{Line: root.Line, Column: root.Column}
+	for fragmentPath, position := range yamlPositions(document) {
+		if strings.HasPrefix(fragmentPath, "[") {
+			positions[path+fragmentPath] = position
+		} else {
+			positions[path+"."+fragmentPath] = position
+		}
+	}
+	return positions
+}
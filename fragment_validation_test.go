@@ -0,0 +1,94 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fragmentErrorStrings returns the errors in "line:column: path: message" form
+func fragmentErrorStrings(result *ConfigValidationResult) []string {
+	var errors []string
+	for _, validationError := range result.Errors {
+		errors = append(errors, validationError.Position.String()+": "+validationError.String())
+	}
+	return errors
+}
+
+func TestSchemaManager_ValidateFragment(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		name     string
+		path     string
+		fragment string
+		expected []string
+	}{
+		{
+			name: "section",
+			path: "exporters",
+			fragment: `otlp:
+  endpoint: backend:4317
+  auth:
+    authenticator: oauth2client
+  timeout: [1]
+debug:
+`,
+			expected: []string{`5:3: exporters.otlp.timeout: Invalid type. Expected: [string,integer], given: array`},
+		},
+		{
+			name:     "block of a component",
+			path:     "exporters.otlp.sending_queue",
+			fragment: "queue_size: many\n",
+			expected: []string{`1:1: exporters.otlp.sending_queue.queue_size: Invalid type. Expected: integer, given: string`},
+		},
+		{
+			name: "service with references to other sections",
+			path: "service",
+			fragment: `extensions: [health_check]
+pipelines:
+  traces:
+    receivers: [otlp]
+    exporters: [otlp]
+  metrics:
+    receivers: [otlp]
+`,
+			expected: []string{`6:3: service.pipelines.metrics: pipeline must have at least one exporter`},
+		},
+		{
+			name:     "list of a pipeline",
+			path:     "service.pipelines.traces.processors",
+			fragment: "[memory_limiter, batch]",
+		},
+		{
+			name:     "unknown component",
+			path:     "receivers.doesnotexist",
+			fragment: "endpoint: localhost:4317\n",
+			expected: []string{`1:1: receivers.doesnotexist: unknown receiver type "doesnotexist"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := manager.ValidateFragment(tt.path, []byte(tt.fragment), "0.139.0")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fragmentErrorStrings(result))
+		})
+	}
+}
+
+func TestSchemaManager_ValidateFragment_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateFragment("", []byte("receivers:\n  doesnotexist:\n"), "0.139.0")
+	require.NoError(t, err)
+	assert.False(t, result.Valid())
+
+	_, err = manager.ValidateFragment("pipelines", []byte("traces: {}"), "0.139.0")
+	assert.EqualError(t, err, `fragment path "pipelines" must start with a configuration section`)
+	_, err = manager.ValidateFragment("service.pipelines.traces.receivers[0]", []byte("otlp"), "0.139.0")
+	assert.EqualError(t, err, `invalid fragment path "service.pipelines.traces.receivers[0]", expected map keys separated by dots`)
+	_, err = manager.ValidateFragment("exporters", []byte("otlp: ["), "0.139.0")
+	require.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// The fuzz targets check that malformed input is rejected with an error instead of a panic or a hang, run them with
+// e.g. go test -run '^$' -fuzz FuzzValidateCollectorConfig -fuzztime 1m. The seed corpus runs with go test.
+
+// fuzzVersion is the version the fuzz targets validate against
+const fuzzVersion = "0.139.0"
+
+func FuzzParseComponentID(f *testing.F) {
+	for _, seed := range []string{"otlp", "otlp/internal", " batch/2 ", "k8s_cluster/my-cluster.prod", "", "otlp/", "/", "otlp//a", "2otlp", "otlp/\x00", "otlp/日本"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, id string) {
+		parsed, err := ParseComponentID(id)
+		if err != nil {
+			return
+		}
+		reparsed, err := ParseComponentID(parsed.String())
+		if err != nil {
+			t.Fatalf("ParseComponentID(%q) = %q, which does not parse: %v", id, parsed.String(), err)
+		}
+		if reparsed != parsed {
+			t.Fatalf("ParseComponentID(%q) = %#v, parsing its string returns %#v", id, parsed, reparsed)
+		}
+	})
+}
+
+func FuzzNormalizeConfig(f *testing.F) {
+	for _, seed := range fuzzCollectorConfigs() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, config []byte) {
+		normalized, err := NormalizeConfig(config)
+		if err != nil {
+			return
+		}
+		if !json.Valid(normalized) {
+			t.Fatalf("NormalizeConfig returned invalid JSON: %s", normalized)
+		}
+		again, err := NormalizeConfig(normalized)
+		if err != nil {
+			t.Fatalf("NormalizeConfig rejected its own output %s: %v", normalized, err)
+		}
+		if !bytes.Equal(normalized, again) {
+			t.Fatalf("NormalizeConfig is not idempotent:\n%s\n%s", normalized, again)
+		}
+	})
+}
+
+func FuzzValidateCollectorConfig(f *testing.F) {
+	for _, seed := range fuzzCollectorConfigs() {
+		f.Add(seed, false)
+	}
+	manager := NewSchemaManager()
+
+	f.Fuzz(func(t *testing.T, config []byte, strict bool) {
+		opts := []ValidationOption{ValidateOTTL(), ValidatePatterns()}
+		if strict {
+			opts = append(opts, Strict())
+		}
+		result, err := manager.ValidateCollectorConfig(config, fuzzVersion, opts...)
+		if err == nil && result == nil {
+			t.Fatal("ValidateCollectorConfig returned neither a result nor an error")
+		}
+	})
+}
+
+func FuzzValidateComponentJSON(f *testing.F) {
+	seeds := []struct {
+		component string
+		config    string
+	}{
+		{"receiver/otlp", `{}`},
+		{"receiver/otlp", `{"protocols": {"grpc": {"endpoint": "0.0.0.0:4317"}}}`},
+		{"receiver/otlp", `{"protocols": null}`},
+		{"processor/batch", `{"timeout": "5s", "send_batch_size": 8192}`},
+		{"processor/batch", `{"timeout": 5, "send_batch_size": "8192"}`},
+		{"processor/memory_limiter", `{"limit_mib": 100, "spike_limit_mib": 200}`},
+		{"processor/filter", `{"error_mode": "ignore", "traces": {"span": ["attributes[\"x\"] == \"y\""]}}`},
+		{"processor/transform", `{"trace_statements": ["set(attributes[\"x\"], 1"]}`},
+		{"receiver/receiver_creator", `{"receivers": {"redis": {"rule": "type == \"port\"", "config": {"endpoint": 1}}}}`},
+		{"exporter/otlp", `[]`},
+		{"exporter/otlp", `"otlp"`},
+		{"exporter/otlp", `{"endpoint": "backend:4317", "headers": {"a": "b"}}`},
+		{"exporter/otlp", `{`},
+		{"exporter/doesnotexist", `{}`},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.component, []byte(seed.config), false)
+	}
+	manager := NewSchemaManager()
+
+	f.Fuzz(func(t *testing.T, component string, config []byte, strict bool) {
+		componentType, name, ok := strings.Cut(component, "/")
+		if !ok {
+			return
+		}
+		opts := []ValidationOption{ValidateOTTL(), ValidatePatterns()}
+		if strict {
+			opts = append(opts, Strict())
+		}
+		result, err := manager.ValidateComponentJSON(ComponentType(componentType), name, fuzzVersion, config, opts...)
+		if err == nil && result == nil {
+			t.Fatal("ValidateComponentJSON returned neither a result nor an error")
+		}
+	})
+}
+
+// fuzzCollectorConfigs returns the seed corpus of the collector configuration fuzz targets
+func fuzzCollectorConfigs() [][]byte {
+	return [][]byte{
+		[]byte(""),
+		[]byte("null"),
+		[]byte("[]"),
+		[]byte("receivers"),
+		[]byte("{\"receivers\": {\"otlp\": {}}}"),
+		[]byte(`receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+processors:
+  batch/traces:
+    timeout: 5s
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch/traces]
+      exporters: [debug]
+`),
+		[]byte(`base: &base
+  timeout: 5s
+processors:
+  batch:
+    <<: *base
+  batch/2: *base
+`),
+		[]byte(`receivers:
+  1: {}
+  true: [a, b]
+  ? [x]
+  : y
+service:
+  pipelines:
+    traces:
+      receivers: otlp
+`),
+		[]byte(`processors:
+  filter:
+    traces:
+      span: ['attributes["x"] == ']
+  transform:
+    trace_statements:
+      - set(attributes["x"], 1
+  attributes:
+    include:
+      match_type: regexp
+      services: ["(unclosed"]
+exporters:
+  otlp:
+    endpoint: !!binary aGVsbG8=
+    timeout: .nan
+`),
+		[]byte("a: &a [*a]"),
+		[]byte("receivers: {otlp: {protocols: {grpc: {endpoint: ${env:ENDPOINT}}}}}"),
+		[]byte("\t- :\n  -"),
+	}
+}
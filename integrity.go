@@ -0,0 +1,257 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumsFileName is the file name of the checksum manifest of a directory or registry version
+const checksumsFileName = "checksums.json"
+
+// SchemaManifest records the SHA-256 checksums of the schema files of a version, it is published next to the
+// schema files as checksums.json and can be signed, see WithSignatureVerifier. Provenance is recorded separately,
+// see VersionManifest.
+type SchemaManifest struct {
+	Version string `json:"version,omitempty"`
+	// Files holds the hex encoded SHA-256 checksums by file name, e.g. "bundle.json"
+	Files map[string]string `json:"files"`
+}
+
+// NewSchemaManifest returns the manifest of schema files by file name
+func NewSchemaManifest(version string, files map[string][]byte) *SchemaManifest {
+	manifest := &SchemaManifest{Version: version, Files: make(map[string]string, len(files))}
+	for name, data := range files {
+		checksum := sha256.Sum256(data)
+		manifest.Files[name] = hex.EncodeToString(checksum[:])
+	}
+	return manifest
+}
+
+// CreateSchemaManifest returns the manifest of the JSON files of a directory, e.g. schemas/0.139.0, an existing
+// checksums.json is not part of the manifest
+func CreateSchemaManifest(version string, dir string) (*SchemaManifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || entry.Name() == checksumsFileName {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema file: %w", err)
+		}
+		files[entry.Name()] = data
+	}
+	return NewSchemaManifest(version, files), nil
+}
+
+// ParseSchemaManifest parses a checksums.json
+func ParseSchemaManifest(data []byte) (*SchemaManifest, error) {
+	var manifest SchemaManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse schema manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Verify returns an error if a file is not listed in the manifest or its checksum does not match
+func (m *SchemaManifest) Verify(name string, data []byte) error {
+	expected, ok := m.Files[name]
+	if !ok {
+		return fmt.Errorf("%s is not listed in the schema manifest", name)
+	}
+	checksum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(checksum[:]); !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch of %s, expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+// SignatureVerifier verifies the detached signature of a schema manifest
+type SignatureVerifier interface {
+	// SignatureSuffix is appended to the manifest file name to get the signature file name, e.g. ".minisig"
+	SignatureSuffix() string
+	// Verify returns an error if the signature of the data is not valid
+	Verify(data []byte, signature []byte) error
+}
+
+// minisignVerifier verifies minisign signatures
+type minisignVerifier struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// NewMinisignVerifier returns a verifier of signatures created with "minisign -S -l -m checksums.json", the public
+// key is the content of a minisign.pub file or its base64 encoded key line. Only legacy signatures are supported,
+// prehashed signatures are rejected.
+func NewMinisignVerifier(publicKey string) (SignatureVerifier, error) {
+	lines := strings.Split(strings.TrimSpace(publicKey), "\n")
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil || len(decoded) != 2+8+ed25519.PublicKeySize || string(decoded[:2]) != "Ed" {
+		return nil, fmt.Errorf("invalid minisign public key")
+	}
+
+	verifier := &minisignVerifier{key: ed25519.PublicKey(decoded[10:])}
+	copy(verifier.keyID[:], decoded[2:10])
+	return verifier, nil
+}
+
+func (v *minisignVerifier) SignatureSuffix() string {
+	return ".minisig"
+}
+
+func (v *minisignVerifier) Verify(data []byte, signature []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(decoded) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	switch string(decoded[:2]) {
+	case "Ed":
+	case "ED":
+		return fmt.Errorf("prehashed minisign signatures are not supported, sign with minisign -S -l")
+	default:
+		return fmt.Errorf("invalid minisign signature algorithm %q", decoded[:2])
+	}
+	if !bytes.Equal(decoded[2:10], v.keyID[:]) {
+		return fmt.Errorf("minisign signature was created with another key")
+	}
+	if !ed25519.Verify(v.key, data, decoded[10:]) {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	// The global signature signs the signature and the trusted comment
+	trustedComment, found := strings.CutPrefix(strings.TrimSpace(lines[2]), "trusted comment: ")
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if !found || err != nil || !ed25519.Verify(v.key, append(decoded[10:], trustedComment...), globalSignature) {
+		return fmt.Errorf("invalid minisign trusted comment signature")
+	}
+	return nil
+}
+
+// cosignVerifier verifies cosign blob signatures
+type cosignVerifier struct {
+	key *ecdsa.PublicKey
+}
+
+// NewCosignVerifier returns a verifier of signatures created with
+// "cosign sign-blob --key cosign.key --output-signature checksums.json.sig checksums.json", the public key is the
+// content of the PEM encoded cosign.pub file. Keyless signatures are not supported.
+func NewCosignVerifier(publicKeyPEM []byte) (SignatureVerifier, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid cosign public key, expected a PEM encoded key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cosign public key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid cosign public key, expected an ECDSA key")
+	}
+	return &cosignVerifier{key: ecdsaKey}, nil
+}
+
+func (v *cosignVerifier) SignatureSuffix() string {
+	return ".sig"
+}
+
+func (v *cosignVerifier) Verify(data []byte, signature []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid cosign signature: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(v.key, digest[:], decoded) {
+		return fmt.Errorf("invalid cosign signature")
+	}
+	return nil
+}
+
+// WithSignatureVerifier requires the manifests of schema files read from disk or fetched from a remote registry to
+// be signed, the signature is read from the manifest file name with the suffix of the verifier, e.g.
+// checksums.json.minisig
+func WithSignatureVerifier(verifier SignatureVerifier) ManagerOption {
+	return func(options *managerOptions) {
+		options.verification.verifier = verifier
+	}
+}
+
+// RequireVerifiedSchemas refuses schema files read from disk, see RegisterCustomSchemaFile and
+// RegisterSchemaBundleFile, and bundles fetched from a remote registry unless they are listed in a manifest whose
+// signature is verified by the verifier of WithSignatureVerifier. Without this option, files without a manifest
+// next to them are accepted and files with a manifest are verified. Embedded schemas are always trusted.
+func RequireVerifiedSchemas() ManagerOption {
+	return func(options *managerOptions) {
+		options.verification.required = true
+	}
+}
+
+// schemaVerification holds the integrity settings of a SchemaManager
+type schemaVerification struct {
+	verifier SignatureVerifier
+	required bool
+}
+
+// verify verifies a schema file against the manifest next to it, readFile reads the manifest and its signature and
+// returns an error wrapping fs.ErrNotExist if they do not exist. The manifest is optional unless verification is
+// required or requireManifest is set.
+func (v schemaVerification) verify(name string, data []byte, requireManifest bool, readFile func(name string) ([]byte, error)) error {
+	if v.required && v.verifier == nil {
+		return fmt.Errorf("%s is not verified: verified schemas are required but no signature verifier is configured", name)
+	}
+
+	manifestData, err := readFile(checksumsFileName)
+	if errors.Is(err, fs.ErrNotExist) && !v.required && !requireManifest {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s is not verified: failed to read %s: %w", name, checksumsFileName, err)
+	}
+
+	if v.verifier != nil {
+		signatureName := checksumsFileName + v.verifier.SignatureSuffix()
+		signature, err := readFile(signatureName)
+		if err != nil {
+			return fmt.Errorf("%s is not verified: failed to read %s: %w", name, signatureName, err)
+		}
+		if err := v.verifier.Verify(manifestData, signature); err != nil {
+			return fmt.Errorf("%s is not verified: %s: %w", name, signatureName, err)
+		}
+	}
+
+	manifest, err := ParseSchemaManifest(manifestData)
+	if err != nil {
+		return fmt.Errorf("%s is not verified: %w", name, err)
+	}
+	return manifest.Verify(name, data)
+}
+
+// verifyFile verifies a schema file read from disk against the manifest in its directory
+func (v schemaVerification) verifyFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	return v.verify(filepath.Base(path), data, false, func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, name))
+	})
+}
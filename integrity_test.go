@@ -0,0 +1,132 @@
+package collectorconfigschema
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMinisignKey returns a minisign verifier and a function signing data like "minisign -S -l"
+func newTestMinisignKey(t *testing.T) (SignatureVerifier, func(data []byte) []byte) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	keyID := make([]byte, 8)
+	_, err = rand.Read(keyID)
+	require.NoError(t, err)
+
+	encodedKey := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), publicKey...))
+	verifier, err := NewMinisignVerifier("untrusted comment: minisign public key\n" + encodedKey + "\n")
+	require.NoError(t, err)
+
+	return verifier, func(data []byte) []byte {
+		signature := ed25519.Sign(privateKey, data)
+		trustedComment := "timestamp:1760000000\tfile:checksums.json"
+		globalSignature := ed25519.Sign(privateKey, append(append([]byte{}, signature...), trustedComment...))
+		return []byte("untrusted comment: signature from minisign secret key\n" +
+			base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), signature...)) + "\n" +
+			"trusted comment: " + trustedComment + "\n" +
+			base64.StdEncoding.EncodeToString(globalSignature) + "\n")
+	}
+}
+
+func TestSchemaManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "receiver_inhouse.json"), []byte(`{"type": "object"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, checksumsFileName), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(`# inhouse`), 0644))
+
+	manifest, err := CreateSchemaManifest("0.139.0", dir)
+	require.NoError(t, err)
+	checksum := sha256.Sum256([]byte(`{"type": "object"}`))
+	assert.Equal(t, &SchemaManifest{
+		Version: "0.139.0",
+		Files:   map[string]string{"receiver_inhouse.json": hex.EncodeToString(checksum[:])},
+	}, manifest)
+
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	parsed, err := ParseSchemaManifest(data)
+	require.NoError(t, err)
+	assert.NoError(t, parsed.Verify("receiver_inhouse.json", []byte(`{"type": "object"}`)))
+	assert.ErrorContains(t, parsed.Verify("receiver_inhouse.json", []byte(`{}`)), "checksum mismatch of receiver_inhouse.json")
+	assert.EqualError(t, parsed.Verify("exporter_inhouse.json", nil), "exporter_inhouse.json is not listed in the schema manifest")
+}
+
+func TestMinisignVerifier(t *testing.T) {
+	verifier, sign := newTestMinisignKey(t)
+	data := []byte(`{"files": {}}`)
+	signature := sign(data)
+
+	assert.NoError(t, verifier.Verify(data, signature))
+	assert.EqualError(t, verifier.Verify([]byte(`{}`), signature), "invalid minisign signature")
+	assert.EqualError(t, verifier.Verify(data, []byte("untrusted comment")), "invalid minisign signature")
+	assert.Equal(t, ".minisig", verifier.SignatureSuffix())
+
+	_, err := NewMinisignVerifier("not a key")
+	assert.EqualError(t, err, "invalid minisign public key")
+}
+
+func TestCosignVerifier(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	verifier, err := NewCosignVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+	require.NoError(t, err)
+
+	data := []byte(`{"files": {}}`)
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	require.NoError(t, err)
+	encoded := []byte(base64.StdEncoding.EncodeToString(signature))
+
+	assert.NoError(t, verifier.Verify(data, encoded))
+	assert.EqualError(t, verifier.Verify([]byte(`{}`), encoded), "invalid cosign signature")
+	assert.Equal(t, ".sig", verifier.SignatureSuffix())
+
+	_, err = NewCosignVerifier([]byte("not a key"))
+	assert.EqualError(t, err, "invalid cosign public key, expected a PEM encoded key")
+}
+
+func TestSchemaManager_VerifiedSchemaFiles(t *testing.T) {
+	schema := []byte(`{"type": "object", "properties": {"endpoint": {"type": "string"}}}`)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "receiver_inhouse.json")
+	require.NoError(t, os.WriteFile(path, schema, 0644))
+
+	// Files without a manifest are accepted unless verified schemas are required
+	require.NoError(t, NewSchemaManager().RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path))
+	verifier, sign := newTestMinisignKey(t)
+	err := NewSchemaManager(WithSignatureVerifier(verifier), RequireVerifiedSchemas()).RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path)
+	assert.ErrorContains(t, err, "failed to verify schema file for receiver inhouse: receiver_inhouse.json is not verified: failed to read checksums.json")
+
+	manifest, err := json.Marshal(NewSchemaManifest("", map[string][]byte{"receiver_inhouse.json": schema}))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, checksumsFileName), manifest, 0644))
+	require.NoError(t, NewSchemaManager().RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path))
+	err = NewSchemaManager(WithSignatureVerifier(verifier), RequireVerifiedSchemas()).RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path)
+	assert.ErrorContains(t, err, "failed to read checksums.json.minisig")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, checksumsFileName+".minisig"), sign(manifest), 0644))
+	require.NoError(t, NewSchemaManager(WithSignatureVerifier(verifier), RequireVerifiedSchemas()).RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path))
+
+	// Files that do not match the manifest are refused
+	require.NoError(t, os.WriteFile(path, []byte(`{"type": "object"}`), 0644))
+	err = NewSchemaManager().RegisterCustomSchemaFile(ComponentTypeReceiver, "inhouse", "", path)
+	assert.ErrorContains(t, err, "checksum mismatch of receiver_inhouse.json")
+	err = NewSchemaManager().RegisterSchemaBundleFile(path)
+	assert.ErrorContains(t, err, "failed to verify schema bundle: checksum mismatch of receiver_inhouse.json")
+}
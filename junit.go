@@ -0,0 +1,182 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// junitConfigTestCase is the name of the test case holding errors that do not belong to a component,
+// e.g. unknown sections and service references
+const junitConfigTestCase = "config"
+
+// ConfigFileResult is the outcome of validating a collector configuration file
+type ConfigFileResult struct {
+	Path string `json:"path"`
+	// Components are the declared components in "section.id" form, e.g. "receivers.otlp"
+	Components []string                `json:"components"`
+	Result     *ConfigValidationResult `json:"result,omitempty"`
+	// Err is set if the file cannot be read or parsed
+	Err error `json:"-"`
+}
+
+// Valid returns true if the file was validated without errors
+func (r *ConfigFileResult) Valid() bool {
+	return r.Err == nil && r.Result.Valid()
+}
+
+// ValidateConfigFiles validates collector configuration files against the schemas of a version.
+// Files that cannot be read or parsed are reported in the Err of their result.
+func (sm *SchemaManager) ValidateConfigFiles(paths []string, version string, opts ...ValidationOption) []ConfigFileResult {
+	return sm.ValidateConfigFilesContext(context.Background(), paths, version, opts...)
+}
+
+// ValidateConfigFilesContext is ValidateConfigFiles with a context, files that are not validated because the
+// context was canceled report the error of the context
+func (sm *SchemaManager) ValidateConfigFilesContext(ctx context.Context, paths []string, version string, opts ...ValidationOption) []ConfigFileResult {
+	results := make([]ConfigFileResult, 0, len(paths))
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			results = append(results, ConfigFileResult{Path: path, Err: err})
+			continue
+		}
+		results = append(results, sm.validateConfigFile(ctx, path, version, opts...))
+	}
+	return results
+}
+
+// validateConfigFile validates a single collector configuration file
+func (sm *SchemaManager) validateConfigFile(ctx context.Context, path string, version string, opts ...ValidationOption) ConfigFileResult {
+	config, err := os.ReadFile(path)
+	if err != nil {
+		return ConfigFileResult{Path: path, Err: fmt.Errorf("failed to read config file: %w", err)}
+	}
+	return sm.validateConfigData(ctx, path, config, version, opts...)
+}
+
+// validateConfigData validates the content of a collector configuration file
+func (sm *SchemaManager) validateConfigData(ctx context.Context, path string, config []byte, version string, opts ...ValidationOption) ConfigFileResult {
+	fileResult := ConfigFileResult{Path: path}
+	fileResult.Result, fileResult.Err = sm.ValidateCollectorConfigContext(ctx, config, version, opts...)
+	if fileResult.Err != nil {
+		return fileResult
+	}
+
+	configMap, _ := parseCollectorConfig(config)
+	for _, cs := range componentSections {
+		section, _ := configMap[cs.section].(map[string]interface{})
+		for _, id := range sortedKeys(section) {
+			fileResult.Components = append(fileResult.Components, cs.section+"."+id)
+		}
+	}
+	return fileResult
+}
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite holds the test cases of a configuration file
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a component or the remaining configuration of a file
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+// junitFailure describes a failed test case, failures are validation errors and errors are unreadable files
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes validation results of configuration files as a JUnit XML report, e.g. for Jenkins and GitLab.
+// Every file is a test suite with a test case per component and a "config" test case for the remaining errors.
+// Files that cannot be read or parsed are reported as an error of the "config" test case.
+func WriteJUnitReport(w io.Writer, results []ConfigFileResult) error {
+	report := junitTestSuites{Name: "otelschema"}
+	for _, fileResult := range results {
+		suite := junitFileSuite(fileResult)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitFileSuite converts the result of a configuration file into a test suite
+func junitFileSuite(fileResult ConfigFileResult) junitTestSuite {
+	suite := junitTestSuite{Name: fileResult.Path}
+	if fileResult.Err != nil {
+		suite.TestCases = []junitTestCase{{
+			Name:      junitConfigTestCase,
+			ClassName: fileResult.Path,
+			Error:     &junitFailure{Message: fileResult.Err.Error(), Type: "error"},
+		}}
+		suite.Tests, suite.Errors = 1, 1
+		return suite
+	}
+
+	errorsByCase := make(map[string][]string)
+	for _, validationError := range fileResult.Result.Errors {
+		name := junitTestCaseName(fileResult.Components, validationError.Path)
+		errorsByCase[name] = append(errorsByCase[name], validationError.String())
+	}
+
+	for _, name := range append(append([]string(nil), fileResult.Components...), junitConfigTestCase) {
+		testCase := junitTestCase{Name: name, ClassName: fileResult.Path}
+		if errs := errorsByCase[name]; len(errs) > 0 {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation error(s)", len(errs)),
+				Type:    "validation",
+				Text:    strings.Join(errs, "\n"),
+			}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+// junitTestCaseName returns the component an error path belongs to, or the "config" test case
+func junitTestCaseName(components []string, path string) string {
+	name := junitConfigTestCase
+	for _, component := range components {
+		if (path == component || strings.HasPrefix(path, component+".") || strings.HasPrefix(path, component+"[")) &&
+			(name == junitConfigTestCase || len(component) > len(name)) {
+			name = component
+		}
+	}
+	return name
+}
@@ -0,0 +1,86 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`), 0644))
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+receivers:
+  otlp:
+exporters:
+  debug:
+    sampling_initial: many
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp]
+`), 0644))
+
+	manager := NewSchemaManager()
+	results := manager.ValidateConfigFiles([]string{valid, invalid, filepath.Join(dir, "missing.yaml")}, "0.138.0")
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Valid())
+	assert.Equal(t, []string{"receivers.otlp", "exporters.debug"}, results[0].Components)
+	assert.False(t, results[1].Valid())
+	assert.Error(t, results[2].Err)
+
+	var report bytes.Buffer
+	require.NoError(t, WriteJUnitReport(&report, results))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="otelschema" tests="7" failures="2" errors="1">
+  <testsuite name="` + valid + `" tests="3" failures="0" errors="0">
+    <testcase name="receivers.otlp" classname="` + valid + `"></testcase>
+    <testcase name="exporters.debug" classname="` + valid + `"></testcase>
+    <testcase name="config" classname="` + valid + `"></testcase>
+  </testsuite>
+  <testsuite name="` + invalid + `" tests="3" failures="2" errors="0">
+    <testcase name="receivers.otlp" classname="` + invalid + `"></testcase>
+    <testcase name="exporters.debug" classname="` + invalid + `">
+      <failure message="1 validation error(s)" type="validation">` + results[1].Result.Errors[0].String() + `</failure>
+    </testcase>
+    <testcase name="config" classname="` + invalid + `">
+      <failure message="1 validation error(s)" type="validation">service.pipelines.traces.exporters[0]: references &#34;otlp&#34; which is not declared in exporters or connectors</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="` + filepath.Join(dir, "missing.yaml") + `" tests="1" failures="0" errors="1">
+    <testcase name="config" classname="` + filepath.Join(dir, "missing.yaml") + `">
+      <error message="` + results[2].Err.Error() + `" type="error"></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, report.String())
+}
+
+func TestJUnitTestCaseName(t *testing.T) {
+	components := []string{"receivers.otlp", "receivers.otlp/internal", "exporters.otlp"}
+
+	assert.Equal(t, "receivers.otlp", junitTestCaseName(components, "receivers.otlp.protocols"))
+	assert.Equal(t, "receivers.otlp/internal", junitTestCaseName(components, "receivers.otlp/internal"))
+	assert.Equal(t, "exporters.otlp", junitTestCaseName(components, "exporters.otlp"))
+	assert.Equal(t, "config", junitTestCaseName(components, "service.pipelines.traces"))
+	assert.Equal(t, "config", junitTestCaseName(components, "receivers.otlpx"))
+}
@@ -0,0 +1,173 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigLayer is a collector configuration (YAML or JSON) of a layered configuration, e.g. a base configuration or
+// an environment overlay
+type ConfigLayer struct {
+	// Name identifies the layer in validation errors, e.g. the file name
+	Name   string
+	Config []byte
+}
+
+// MergeConfigs merges overlays into a base collector configuration (YAML or JSON) like the collector merges the
+// configurations passed with several --config flags: maps are merged recursively, lists and other values of later
+// configurations replace earlier values, including null. The merged configuration is returned as YAML with sorted
+// keys.
+func MergeConfigs(base []byte, overlays ...[]byte) ([]byte, error) {
+	layers := []ConfigLayer{{Name: "base", Config: base}}
+	for i, overlay := range overlays {
+		layers = append(layers, ConfigLayer{Name: fmt.Sprintf("overlay %d", i+1), Config: overlay})
+	}
+
+	merged, err := mergeConfigLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+	return encodeYAML(merged.config)
+}
+
+// ValidateLayeredConfig merges configuration layers with MergeConfigs semantics and validates the merged
+// configuration. Errors are attributed to the last layer that set the failing value: Layer is the name of that layer
+// and Position the position in it. Errors of maps that several layers contribute to, e.g. a missing required field
+// of a component, are attributed to the last layer that changed the map. An error is returned if a layer cannot be
+// parsed.
+func (sm *SchemaManager) ValidateLayeredConfig(layers []ConfigLayer, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	return sm.ValidateLayeredConfigContext(context.Background(), layers, version, opts...)
+}
+
+// ValidateLayeredConfigContext is ValidateLayeredConfig with a context, the validation stops with the error of the
+// context when it is canceled
+func (sm *SchemaManager) ValidateLayeredConfigContext(ctx context.Context, layers []ConfigLayer, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	merged, err := mergeConfigLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	ctx, end := sm.telemetry.startCollectorConfigValidation(ctx, version)
+	result, err := sm.validateCollectorConfigMap(ctx, merged.config, version, options)
+	end(result, err)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Errors {
+		layer, found := merged.origin(result.Errors[i].Path)
+		if !found {
+			continue
+		}
+		result.Errors[i].Layer = layers[layer].Name
+		result.Errors[i].Position = lookupPosition(merged.positions[layer], result.Errors[i].Path)
+	}
+	return result, nil
+}
+
+// mergedConfig is the result of merging configuration layers
+type mergedConfig struct {
+	config map[string]interface{}
+	// origins holds the index of the last layer that set or changed the value of a path
+	origins map[string]int
+	// positions holds the positions of the nodes of each layer
+	positions []map[string]Position
+}
+
+// mergeConfigLayers parses and merges configuration layers
+func mergeConfigLayers(layers []ConfigLayer) (*mergedConfig, error) {
+	merged := &mergedConfig{
+		config:  make(map[string]interface{}),
+		origins: make(map[string]int),
+	}
+	for i, layer := range layers {
+		var document yaml.Node
+		if err := yaml.Unmarshal(layer.Config, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration layer %s: %w", layer.Name, err)
+		}
+		config, err := decodeCollectorConfig(&document)
+		if err != nil {
+			return nil, fmt.Errorf("configuration layer %s: %w", layer.Name, err)
+		}
+
+		merged.positions = append(merged.positions, yamlPositions(&document))
+		merged.merge("", merged.config, config, i)
+	}
+	return merged, nil
+}
+
+// merge merges the entries of an overlay map into a map at path, values are replaced unless both are maps
+func (m *mergedConfig) merge(path string, target map[string]interface{}, overlay map[string]interface{}, layer int) {
+	for _, key := range sortedKeys(overlay) {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		targetMap, targetIsMap := target[key].(map[string]interface{})
+		overlayMap, overlayIsMap := overlay[key].(map[string]interface{})
+		if targetIsMap && overlayIsMap {
+			m.origins[childPath] = layer
+			m.merge(childPath, targetMap, overlayMap, layer)
+			continue
+		}
+
+		for origin := range m.origins {
+			if isPathOrChild(origin, childPath) {
+				delete(m.origins, origin)
+			}
+		}
+		target[key] = deepCopyValue(overlay[key])
+		m.recordOrigins(childPath, target[key], layer)
+	}
+}
+
+// recordOrigins records a layer as the origin of a value and its children
+func (m *mergedConfig) recordOrigins(path string, value interface{}, layer int) {
+	m.origins[path] = layer
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			m.recordOrigins(path+"."+key, item, layer)
+		}
+	case []interface{}:
+		for i, item := range v {
+			m.recordOrigins(fmt.Sprintf("%s[%d]", path, i), item, layer)
+		}
+	}
+}
+
+// origin returns the layer that set the value of a path or of its closest parent
+func (m *mergedConfig) origin(path string) (int, bool) {
+	for ; path != ""; path = parentPath(path) {
+		if layer, ok := m.origins[path]; ok {
+			return layer, true
+		}
+		if layer, ok := m.origins[bracketIndexes(path)]; ok {
+			return layer, true
+		}
+	}
+	return 0, false
+}
+
+// encodeYAML encodes a value as YAML with an indentation of two spaces
+func encodeYAML(value interface{}) ([]byte, error) {
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode collector configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode collector configuration: %w", err)
+	}
+	return []byte(out.String()), nil
+}
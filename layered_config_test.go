@@ -0,0 +1,121 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const layeredBase = `receivers:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  batch:
+    timeout: 5s
+exporters:
+  otlp:
+    endpoint: backend:4317
+    sending_queue:
+      queue_size: 100
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp]
+`
+
+const layeredOverlay = `processors:
+  batch:
+    send_batch_size: many
+exporters:
+  otlp:
+    endpoint: prod-backend:4317
+    sending_queue:
+      num_consumers: 2
+service:
+  pipelines:
+    traces:
+      processors: []
+`
+
+func TestMergeConfigs(t *testing.T) {
+	merged, err := MergeConfigs([]byte(layeredBase), []byte(layeredOverlay), []byte(`{"exporters": {"otlp": {"sending_queue": null}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, `exporters:
+  otlp:
+    endpoint: prod-backend:4317
+    sending_queue: null
+processors:
+  batch:
+    send_batch_size: many
+    timeout: 5s
+receivers:
+  otlp:
+    protocols:
+      grpc: null
+service:
+  pipelines:
+    traces:
+      exporters:
+        - otlp
+      processors: []
+      receivers:
+        - otlp
+`, string(merged))
+
+	merged, err = MergeConfigs([]byte(layeredBase))
+	require.NoError(t, err)
+	assert.YAMLEq(t, layeredBase, string(merged))
+
+	_, err = MergeConfigs([]byte(layeredBase), []byte("- not a map"))
+	assert.ErrorContains(t, err, "configuration layer overlay 1: collector configuration must be a map")
+	_, err = MergeConfigs([]byte("receivers: ["))
+	assert.ErrorContains(t, err, "failed to parse configuration layer base")
+}
+
+func TestSchemaManager_ValidateLayeredConfig(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateLayeredConfig([]ConfigLayer{
+		{Name: "base.yaml", Config: []byte(layeredBase + "extensions:\n  health_check:\n    endpoint: [1]\n")},
+		{Name: "prod.yaml", Config: []byte(layeredOverlay)},
+	}, "0.139.0")
+	require.NoError(t, err)
+
+	var errors []string
+	for _, validationError := range result.Errors {
+		errors = append(errors, validationError.Layer+":"+validationError.Position.String()+": "+validationError.String())
+	}
+	assert.Equal(t, []string{
+		"prod.yaml:3:5: processors.batch.send_batch_size: Invalid type. Expected: integer, given: string",
+		"base.yaml:21:5: extensions.health_check.endpoint: Invalid type. Expected: string, given: array",
+	}, errors)
+
+	_, err = manager.ValidateLayeredConfig([]ConfigLayer{{Name: "base.yaml", Config: []byte("receivers: [")}}, "0.139.0")
+	assert.ErrorContains(t, err, "failed to parse configuration layer base.yaml")
+}
+
+func TestMergedConfig_Origin(t *testing.T) {
+	merged, err := mergeConfigLayers([]ConfigLayer{{Name: "base", Config: []byte(layeredBase)}, {Name: "overlay", Config: []byte(layeredOverlay)}})
+	require.NoError(t, err)
+
+	for path, expected := range map[string]int{
+		"receivers.otlp.protocols.grpc":              0,
+		"processors.batch.timeout":                   0,
+		"processors.batch.send_batch_size":           1,
+		"processors.batch":                           1,
+		"exporters.otlp.sending_queue.queue_size":    0,
+		"exporters.otlp.sending_queue.num_consumers": 1,
+		"service.pipelines.traces.receivers.0":       0,
+		"service.pipelines.traces.processors":        1,
+	} {
+		layer, found := merged.origin(path)
+		assert.True(t, found, path)
+		assert.Equal(t, expected, layer, path)
+	}
+	_, found := merged.origin("connectors")
+	assert.False(t, found)
+}
@@ -0,0 +1,293 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintSeverity is the severity of a lint finding
+type LintSeverity string
+
+// Lint severities
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// LintProfileProduction is the profile of configurations deployed to production,
+// rules like debug-exporter only report findings for this profile
+const LintProfileProduction = "production"
+
+// LintFinding is a best practice violation found in a collector configuration
+type LintFinding struct {
+	RuleID string `json:"ruleId"`
+	// Code is the diagnostic code of the rule, see LintRule.Code
+	Code     string       `json:"code,omitempty"`
+	Severity LintSeverity `json:"severity"`
+	// Path is the dot separated location of the finding, e.g. "service.pipelines.traces.processors[1]"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String returns the finding in "severity: path: message (rule)" form
+func (f LintFinding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s (%s)", f.Severity, f.Message, f.RuleID)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Path, f.Message, f.RuleID)
+}
+
+// LintResult holds the findings of linting a collector configuration
+type LintResult struct {
+	Findings []LintFinding `json:"findings"`
+}
+
+// HasSeverity returns true if at least one finding has the given severity
+func (r *LintResult) HasSeverity(severity LintSeverity) bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// LintRule checks a collector configuration for a best practice
+type LintRule struct {
+	// ID identifies the rule in findings and when enabling or disabling it, e.g. "memory-limiter-first"
+	ID          string
+	Description string
+	// Code is the diagnostic code of the findings, e.g. DiagnosticCodeMemoryLimiterFirst. Findings of rules without
+	// a code use the rule ID as their code.
+	Code string
+	// Severity is the severity of findings that do not set their own
+	Severity LintSeverity
+	// Disabled rules only run when they are enabled with EnableLintRules
+	Disabled bool
+	// Check returns the findings of the rule, RuleID, Code and empty severities are set by the linter
+	Check func(config *LintConfig) []LintFinding
+}
+
+// LintConfig is the collector configuration passed to lint rules
+type LintConfig struct {
+	// Config is the parsed configuration, sections are JSON compatible maps
+	Config  map[string]interface{}
+	Version string
+	Profile string
+
+	// duplicatePipelines are the pipeline IDs declared more than once, only the first declaration is kept in Config
+	duplicatePipelines []duplicateKey
+}
+
+// duplicateKey is a repeated key of a YAML mapping
+type duplicateKey struct {
+	key  string
+	line int
+}
+
+// LintPipeline is a pipeline of the service section
+type LintPipeline struct {
+	ID         string
+	Receivers  []string
+	Processors []string
+	Exporters  []string
+}
+
+// Section returns a top-level section of the configuration, e.g. "exporters", or nil if it is not a map
+func (c *LintConfig) Section(name string) map[string]interface{} {
+	section, _ := c.Config[name].(map[string]interface{})
+	return section
+}
+
+// Pipelines returns the pipelines of the service section sorted by ID, malformed entries are skipped
+func (c *LintConfig) Pipelines() []LintPipeline {
+	pipelines, _ := c.Section(sectionService)["pipelines"].(map[string]interface{})
+
+	var result []LintPipeline
+	for _, id := range sortedKeys(pipelines) {
+		pipeline, ok := pipelines[id].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, LintPipeline{
+			ID:         id,
+			Receivers:  stringList(pipeline["receivers"]),
+			Processors: stringList(pipeline["processors"]),
+			Exporters:  stringList(pipeline["exporters"]),
+		})
+	}
+	return result
+}
+
+// LintOption configures how a collector configuration is linted
+type LintOption func(*lintOptions)
+
+// lintOptions holds the settings applied by LintOption
+type lintOptions struct {
+	profile  string
+	enabled  []string
+	disabled []string
+}
+
+// WithLintProfile sets the deployment profile of the configuration, e.g. LintProfileProduction
+func WithLintProfile(profile string) LintOption {
+	return func(options *lintOptions) {
+		options.profile = profile
+	}
+}
+
+// EnableLintRules runs rules that are disabled by default
+func EnableLintRules(ids ...string) LintOption {
+	return func(options *lintOptions) {
+		options.enabled = append(options.enabled, ids...)
+	}
+}
+
+// DisableLintRules skips rules, e.g. when a finding is accepted for a deployment
+func DisableLintRules(ids ...string) LintOption {
+	return func(options *lintOptions) {
+		options.disabled = append(options.disabled, ids...)
+	}
+}
+
+// RegisterLintRule registers a custom lint rule that runs after the built-in rules
+func (sm *SchemaManager) RegisterLintRule(rule LintRule) error {
+	if rule.ID == "" {
+		return fmt.Errorf("lint rule ID must not be empty")
+	}
+	if rule.Check == nil {
+		return fmt.Errorf("lint rule %q must have a check", rule.ID)
+	}
+	if sm.lookupLintRule(rule.ID) != nil {
+		return fmt.Errorf("lint rule %q is already registered", rule.ID)
+	}
+	if rule.Severity == "" {
+		rule.Severity = LintSeverityWarning
+	}
+
+	sm.lintRules = append(sm.lintRules, rule)
+	return nil
+}
+
+// ListLintRules returns the built-in and registered lint rules in the order they run
+func (sm *SchemaManager) ListLintRules() []LintRule {
+	return append(append([]LintRule(nil), builtinLintRules...), sm.lintRules...)
+}
+
+// lookupLintRule returns a built-in or registered rule by ID
+func (sm *SchemaManager) lookupLintRule(id string) *LintRule {
+	for _, rule := range sm.ListLintRules() {
+		if rule.ID == id {
+			return &rule
+		}
+	}
+	return nil
+}
+
+// Lint checks a collector configuration (YAML or JSON) for best practices beyond schema validity, e.g. the order
+// of processors and unused components. An error is returned if the configuration cannot be parsed or an
+// option references an unknown rule.
+func (sm *SchemaManager) Lint(config []byte, version string, opts ...LintOption) (*LintResult, error) {
+	return sm.LintContext(context.Background(), config, version, opts...)
+}
+
+// LintContext is Lint with a context, linting stops with the error of the context when it is canceled
+func (sm *SchemaManager) LintContext(ctx context.Context, config []byte, version string, opts ...LintOption) (*LintResult, error) {
+	options := &lintOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	for _, id := range append(append([]string(nil), options.enabled...), options.disabled...) {
+		if sm.lookupLintRule(id) == nil {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+	}
+	// Duplicate pipelines are a finding rather than a parse error
+	duplicates := removeDuplicateKeys(lookupMappingNode(&document, sectionService, "pipelines"))
+
+	configMap, err := decodeCollectorConfig(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	lintConfig := &LintConfig{
+		Config:             configMap,
+		Version:            version,
+		Profile:            options.profile,
+		duplicatePipelines: duplicates,
+	}
+
+	result := &LintResult{}
+	for _, rule := range sm.ListLintRules() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		enabled := !rule.Disabled || contains(options.enabled, rule.ID)
+		if !enabled || contains(options.disabled, rule.ID) {
+			continue
+		}
+
+		for _, finding := range rule.Check(lintConfig) {
+			finding.RuleID = rule.ID
+			finding.Code = rule.Code
+			if finding.Severity == "" {
+				finding.Severity = rule.Severity
+			}
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	return result, nil
+}
+
+// lookupMappingNode follows keys from a YAML document and returns the mapping node found, or nil
+func lookupMappingNode(node *yaml.Node, keys ...string) *yaml.Node {
+	node = lookupNode(node, keys...)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	return node
+}
+
+// removeDuplicateKeys removes repeated keys from a mapping node, keeping the first, and returns the removed keys
+func removeDuplicateKeys(mapping *yaml.Node) []duplicateKey {
+	if mapping == nil {
+		return nil
+	}
+
+	var duplicates []duplicateKey
+	seen := make(map[string]bool)
+	content := mapping.Content[:0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		if seen[key.Value] {
+			duplicates = append(duplicates, duplicateKey{key: key.Value, line: key.Line})
+			continue
+		}
+		seen[key.Value] = true
+		content = append(content, key, mapping.Content[i+1])
+	}
+	mapping.Content = content
+
+	return duplicates
+}
+
+// stringList returns the strings of a decoded list, other items are skipped
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
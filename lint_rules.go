@@ -0,0 +1,302 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinLintRules are the best practices checked by Lint, in the order they run
+var builtinLintRules = []LintRule{
+	{
+		ID:          "duplicate-pipeline",
+		Code:        DiagnosticCodeDuplicatePipeline,
+		Description: "Pipelines must be declared once, the collector rejects repeated pipeline IDs",
+		Severity:    LintSeverityError,
+		Check:       checkDuplicatePipelines,
+	},
+	{
+		ID:          "memory-limiter-first",
+		Code:        DiagnosticCodeMemoryLimiterFirst,
+		Description: "The memory_limiter processor should be the first processor of a pipeline",
+		Severity:    LintSeverityWarning,
+		Check:       checkMemoryLimiterFirst,
+	},
+	{
+		ID:          "batch-processor",
+		Code:        DiagnosticCodeBatchProcessor,
+		Description: "Pipelines should batch telemetry with the batch processor or exporter batching",
+		Severity:    LintSeverityInfo,
+		Check:       checkBatchProcessor,
+	},
+	{
+		ID:          "debug-exporter",
+		Code:        DiagnosticCodeDebugExporter,
+		Description: "The debug exporter should not be used in production",
+		Severity:    LintSeverityWarning,
+		Check:       checkDebugExporter,
+	},
+	{
+		ID:          "unused-component",
+		Code:        DiagnosticCodeUnusedComponent,
+		Description: "Declared components should be used in a pipeline or enabled in the service",
+		Severity:    LintSeverityWarning,
+		Check:       checkUnusedComponents,
+	},
+	{
+		ID:          "endpoint-conflict",
+		Code:        DiagnosticCodeEndpointConflict,
+		Description: "Components must not bind the same address or overlapping addresses, the collector fails to start",
+		Severity:    LintSeverityWarning,
+		Check:       checkEndpointConflicts,
+	},
+	{
+		ID:          "storage-directory",
+		Code:        DiagnosticCodeStorageDirectory,
+		Description: "The directory of the file_storage extension should be a valid absolute path",
+		Severity:    LintSeverityWarning,
+		Check:       checkStorageDirectories,
+	},
+}
+
+// debugExporters are exporters that write telemetry to the collector log, logging is the deprecated name of debug
+var debugExporters = []string{"debug", "logging"}
+
+// checkDuplicatePipelines reports pipeline IDs that are declared more than once
+func checkDuplicatePipelines(config *LintConfig) []LintFinding {
+	var findings []LintFinding
+	for _, duplicate := range config.duplicatePipelines {
+		findings = append(findings, LintFinding{
+			Path:    sectionService + ".pipelines." + duplicate.key,
+			Message: fmt.Sprintf("pipeline %q is declared more than once (line %d)", duplicate.key, duplicate.line),
+		})
+	}
+	return findings
+}
+
+// checkMemoryLimiterFirst reports memory_limiter processors that are not the first processor of their pipeline
+func checkMemoryLimiterFirst(config *LintConfig) []LintFinding {
+	var findings []LintFinding
+	for _, pipeline := range config.Pipelines() {
+		for i, processor := range pipeline.Processors {
+			if i > 0 && componentName(processor) == "memory_limiter" {
+				findings = append(findings, LintFinding{
+					Path:    fmt.Sprintf("%s.pipelines.%s.processors[%d]", sectionService, pipeline.ID, i),
+					Message: fmt.Sprintf("%q should be the first processor so data is refused before other processors allocate memory", processor),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkBatchProcessor reports pipelines without a batch processor whose exporters do not batch either
+func checkBatchProcessor(config *LintConfig) []LintFinding {
+	exporters := config.Section(sectionExporters)
+
+	var findings []LintFinding
+	for _, pipeline := range config.Pipelines() {
+		batched := len(pipeline.Exporters) > 0
+		for _, exporter := range pipeline.Exporters {
+			if !hasExporterBatching(exporters[exporter]) {
+				batched = false
+			}
+		}
+		for _, processor := range pipeline.Processors {
+			if componentName(processor) == "batch" {
+				batched = true
+			}
+		}
+
+		if !batched {
+			findings = append(findings, LintFinding{
+				Path:    fmt.Sprintf("%s.pipelines.%s", sectionService, pipeline.ID),
+				Message: "pipeline does not batch telemetry, add the batch processor or enable sending_queue.batch on its exporters",
+			})
+		}
+	}
+	return findings
+}
+
+// hasExporterBatching checks if an exporter configuration enables batching of its sending queue
+func hasExporterBatching(config interface{}) bool {
+	exporter, _ := config.(map[string]interface{})
+	queue, _ := exporter["sending_queue"].(map[string]interface{})
+	if enabled, ok := queue["enabled"].(bool); ok && !enabled {
+		return false
+	}
+	_, batching := queue["batch"].(map[string]interface{})
+	return batching
+}
+
+// checkDebugExporter reports debug exporters declared in configurations with the production profile
+func checkDebugExporter(config *LintConfig) []LintFinding {
+	if config.Profile != LintProfileProduction {
+		return nil
+	}
+
+	var findings []LintFinding
+	for _, id := range sortedKeys(config.Section(sectionExporters)) {
+		if contains(debugExporters, componentName(id)) {
+			findings = append(findings, LintFinding{
+				Path:    sectionExporters + "." + id,
+				Message: fmt.Sprintf("%q writes telemetry to the collector log and should not be used in production", id),
+			})
+		}
+	}
+	return findings
+}
+
+// checkUnusedComponents reports components that are declared but not used by any pipeline
+// and extensions that are not enabled in the service
+func checkUnusedComponents(config *LintConfig) []LintFinding {
+	used := map[string]map[string]bool{
+		sectionReceivers:  {},
+		sectionProcessors: {},
+		sectionExporters:  {},
+	}
+	for _, pipeline := range config.Pipelines() {
+		for _, id := range pipeline.Receivers {
+			used[sectionReceivers][id] = true
+		}
+		for _, id := range pipeline.Processors {
+			used[sectionProcessors][id] = true
+		}
+		for _, id := range pipeline.Exporters {
+			used[sectionExporters][id] = true
+		}
+	}
+	enabledExtensions := stringList(config.Section(sectionService)["extensions"])
+
+	var findings []LintFinding
+	for _, cs := range componentSections {
+		for _, id := range sortedKeys(config.Section(cs.section)) {
+			var message string
+			switch cs.section {
+			case sectionExtensions:
+				if !contains(enabledExtensions, id) {
+					message = fmt.Sprintf("extension %q is declared but not enabled in %s.extensions", id, sectionService)
+				}
+			case sectionConnectors:
+				// Connectors are the exporter of one pipeline and the receiver of another
+				if !used[sectionExporters][id] || !used[sectionReceivers][id] {
+					message = fmt.Sprintf("connector %q must be used as an exporter and as a receiver of pipelines", id)
+				}
+			default:
+				if !used[cs.section][id] {
+					message = fmt.Sprintf("%s %q is declared but not used in any pipeline", cs.componentType, id)
+				}
+			}
+
+			if message != "" {
+				findings = append(findings, LintFinding{Path: cs.section + "." + id, Message: message})
+			}
+		}
+	}
+	return findings
+}
+
+// checkEndpointConflicts reports addresses bound by more than one of the enabled extensions and the receivers and
+// exporters used in pipelines, including the default addresses of omitted endpoints. Addresses bound to all
+// interfaces overlap the addresses of single interfaces on the same port.
+func checkEndpointConflicts(config *LintConfig) []LintFinding {
+	var addresses []listenAddress
+	extensions := config.Section(sectionExtensions)
+	for _, id := range stringList(config.Section(sectionService)["extensions"]) {
+		if value, declared := extensions[id]; declared {
+			addresses = append(addresses, collectListenAddresses(ComponentTypeExtension, sectionExtensions, id, value)...)
+		}
+	}
+	started := map[string]map[string]bool{sectionReceivers: {}, sectionExporters: {}}
+	for _, pipeline := range config.Pipelines() {
+		for _, id := range pipeline.Receivers {
+			started[sectionReceivers][id] = true
+		}
+		for _, id := range pipeline.Exporters {
+			started[sectionExporters][id] = true
+		}
+	}
+	for _, cs := range componentSections {
+		section := config.Section(cs.section)
+		for _, id := range sortedKeys(section) {
+			if started[cs.section][id] {
+				addresses = append(addresses, collectListenAddresses(cs.componentType, cs.section, id, section[id])...)
+			}
+		}
+	}
+
+	var findings []LintFinding
+	var bound []listenAddress
+	for _, address := range addresses {
+		for _, other := range bound {
+			if !address.overlaps(other) {
+				continue
+			}
+			message := fmt.Sprintf("%s is already bound by %s", address.describe(), other.path)
+			if address.address != other.address {
+				message = fmt.Sprintf("%s overlaps %s bound by %s", address.describe(), other.describe(), other.path)
+			}
+			findings = append(findings, LintFinding{Path: address.path, Message: message})
+			break
+		}
+		bound = append(bound, address)
+	}
+	return findings
+}
+
+// checkStorageDirectories reports directories of file_storage extensions that are empty, contain a NUL character
+// or are relative to the working directory of the collector. The collector also requires the directories to exist,
+// which can only be checked on the collector host.
+func checkStorageDirectories(config *LintConfig) []LintFinding {
+	var findings []LintFinding
+	extensions := config.Section(sectionExtensions)
+	for _, id := range sortedKeys(extensions) {
+		if componentName(id) != "file_storage" {
+			continue
+		}
+		for _, keys := range [][]string{{"directory"}, {"compaction", "directory"}} {
+			block, ok := lookupBlock(extensions[id], keys[:len(keys)-1])
+			if !ok {
+				continue
+			}
+			directory, ok := block[keys[len(keys)-1]].(string)
+			if !ok || usesConfigProvider(directory) {
+				continue
+			}
+
+			var message string
+			switch {
+			case strings.TrimSpace(directory) == "":
+				message = "directory must not be empty"
+			case strings.ContainsRune(directory, 0):
+				message = fmt.Sprintf("directory %q contains a NUL character", directory)
+			case !isAbsolutePath(directory):
+				message = fmt.Sprintf("directory %q is relative to the working directory of the collector, use an absolute path", directory)
+			}
+			if message != "" {
+				findings = append(findings, LintFinding{
+					Path:    sectionExtensions + "." + id + "." + strings.Join(keys, "."),
+					Message: message,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// isAbsolutePath returns whether a path is absolute on Unix or Windows, e.g. /var/lib/otelcol or C:\otelcol
+func isAbsolutePath(path string) bool {
+	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') &&
+		(('a' <= path[0] && path[0] <= 'z') || ('A' <= path[0] && path[0] <= 'Z'))
+}
+
+// componentName returns the component of an ID like "batch/traces", malformed IDs are returned unchanged
+func componentName(id string) string {
+	componentID, err := ParseComponentID(id)
+	if err != nil {
+		return id
+	}
+	return componentID.Component
+}
@@ -0,0 +1,274 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintConfig violates every built-in rule
+var lintConfig = []byte(`
+receivers:
+  otlp:
+  zipkin:
+processors:
+  batch:
+  memory_limiter:
+  attributes/unused:
+exporters:
+  debug:
+  otlp:
+    endpoint: backend:4317
+    sending_queue:
+      batch:
+        flush_timeout: 1s
+connectors:
+  forward:
+extensions:
+  health_check:
+  pprof:
+  zpages:
+    endpoint: localhost:13133
+  file_storage:
+    directory: ./storage
+service:
+  extensions: [health_check, zpages, file_storage]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch, memory_limiter]
+      exporters: [otlp, forward]
+    metrics:
+      receivers: [otlp]
+      exporters: [otlp]
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+    traces:
+      receivers: [zipkin]
+      exporters: [debug]
+`)
+
+func findingStrings(result *LintResult) []string {
+	var findings []string
+	for _, finding := range result.Findings {
+		findings = append(findings, finding.String())
+	}
+	return findings
+}
+
+func TestSchemaManager_Lint(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.Lint(lintConfig, "0.138.0", WithLintProfile(LintProfileProduction))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`error: service.pipelines.traces: pipeline "traces" is declared more than once (line 38) (duplicate-pipeline)`,
+		`warning: service.pipelines.traces.processors[1]: "memory_limiter" should be the first processor so data is refused before other processors allocate memory (memory-limiter-first)`,
+		`info: service.pipelines.logs: pipeline does not batch telemetry, add the batch processor or enable sending_queue.batch on its exporters (batch-processor)`,
+		`warning: exporters.debug: "debug" writes telemetry to the collector log and should not be used in production (debug-exporter)`,
+		`warning: receivers.zipkin: receiver "zipkin" is declared but not used in any pipeline (unused-component)`,
+		`warning: processors.attributes/unused: processor "attributes/unused" is declared but not used in any pipeline (unused-component)`,
+		`warning: extensions.pprof: extension "pprof" is declared but not enabled in service.extensions (unused-component)`,
+		`warning: connectors.forward: connector "forward" must be used as an exporter and as a receiver of pipelines (unused-component)`,
+		`warning: extensions.zpages.endpoint: "localhost:13133" is already bound by extensions.health_check.endpoint (endpoint-conflict)`,
+		`warning: extensions.file_storage.directory: directory "./storage" is relative to the working directory of the collector, use an absolute path (storage-directory)`,
+	}, findingStrings(result))
+	assert.True(t, result.HasSeverity(LintSeverityError))
+}
+
+func TestSchemaManager_Lint_Options(t *testing.T) {
+	manager := NewSchemaManager()
+
+	// The debug exporter is only reported for production
+	result, err := manager.Lint(lintConfig, "0.138.0", DisableLintRules("unused-component", "duplicate-pipeline", "endpoint-conflict", "storage-directory"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`warning: service.pipelines.traces.processors[1]: "memory_limiter" should be the first processor so data is refused before other processors allocate memory (memory-limiter-first)`,
+		`info: service.pipelines.logs: pipeline does not batch telemetry, add the batch processor or enable sending_queue.batch on its exporters (batch-processor)`,
+	}, findingStrings(result))
+	assert.False(t, result.HasSeverity(LintSeverityError))
+
+	_, err = manager.Lint(lintConfig, "0.138.0", DisableLintRules("memory-limiter"))
+	assert.EqualError(t, err, `unknown lint rule "memory-limiter"`)
+
+	_, err = manager.Lint([]byte("receivers: ["), "0.138.0")
+	assert.Error(t, err)
+}
+
+func TestSchemaManager_Lint_CustomRules(t *testing.T) {
+	manager := NewSchemaManager()
+
+	requireTLS := LintRule{
+		ID:          "otlp-insecure",
+		Description: "OTLP exporters must not disable TLS",
+		Severity:    LintSeverityError,
+		Disabled:    true,
+		Check: func(config *LintConfig) []LintFinding {
+			var findings []LintFinding
+			for _, id := range sortedKeys(config.Section(sectionExporters)) {
+				exporter, _ := config.Section(sectionExporters)[id].(map[string]interface{})
+				tls, _ := exporter["tls"].(map[string]interface{})
+				if tls["insecure"] == true {
+					findings = append(findings, LintFinding{Path: "exporters." + id + ".tls.insecure", Message: "TLS is disabled"})
+				}
+			}
+			return findings
+		},
+	}
+	require.NoError(t, manager.RegisterLintRule(requireTLS))
+	assert.EqualError(t, manager.RegisterLintRule(requireTLS), `lint rule "otlp-insecure" is already registered`)
+	assert.EqualError(t, manager.RegisterLintRule(LintRule{ID: "batch-processor", Check: requireTLS.Check}), `lint rule "batch-processor" is already registered`)
+	assert.EqualError(t, manager.RegisterLintRule(LintRule{ID: "no-check"}), `lint rule "no-check" must have a check`)
+
+	config := []byte(`
+receivers:
+  otlp:
+processors:
+  batch:
+exporters:
+  otlp:
+    tls:
+      insecure: true
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp]
+`)
+
+	// Disabled rules only run when enabled
+	result, err := manager.Lint(config, "0.138.0")
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+
+	result, err = manager.Lint(config, "0.138.0", EnableLintRules("otlp-insecure"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{`error: exporters.otlp.tls.insecure: TLS is disabled (otlp-insecure)`}, findingStrings(result))
+
+	rules := manager.ListLintRules()
+	assert.Equal(t, "otlp-insecure", rules[len(rules)-1].ID)
+}
+
+func TestSchemaManager_Lint_EndpointConflicts(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+      http:
+        endpoint: localhost:1777
+  jaeger:
+    protocols:
+      grpc:
+      thrift_compact:
+        endpoint: localhost:4317
+  zipkin:
+    endpoint: ${env:ZIPKIN_ENDPOINT}
+  zipkin/unused:
+    endpoint: localhost:4317
+extensions:
+  pprof:
+  zpages:
+    endpoint: localhost:55679
+  zpages/copy:
+    endpoint: localhost:55679
+  health_check/disabled:
+    endpoint: localhost:4317
+  jaegerremotesampling:
+    grpc:
+service:
+  extensions: [pprof, zpages, zpages/copy, jaegerremotesampling]
+  pipelines:
+    traces:
+      receivers: [otlp, jaeger, zipkin]
+      exporters: [debug]
+`)
+
+	result, err := manager.Lint(config, "0.139.0", EnableLintRules("endpoint-conflict"), DisableLintRules("unused-component", "batch-processor"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`warning: extensions.zpages/copy.endpoint: "localhost:55679" is already bound by extensions.zpages.endpoint (endpoint-conflict)`,
+		`warning: receivers.jaeger.protocols.grpc.endpoint: default address "localhost:14250" is already bound by extensions.jaegerremotesampling.grpc.endpoint (endpoint-conflict)`,
+		`warning: receivers.otlp.protocols.http.endpoint: "localhost:1777" is already bound by extensions.pprof.endpoint (endpoint-conflict)`,
+	}, findingStrings(result))
+}
+
+func TestSchemaManager_Lint_StorageDirectories(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+extensions:
+  file_storage:
+    directory: /var/lib/otelcol/storage
+    compaction:
+      directory: tmp
+  file_storage/windows:
+    directory: C:\ProgramData\otelcol
+  file_storage/env:
+    directory: ${env:STORAGE_DIR}
+  file_storage/empty:
+    directory: ""
+service:
+  extensions: [file_storage, file_storage/windows, file_storage/env, file_storage/empty]
+`)
+
+	result, err := manager.Lint(config, "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`warning: extensions.file_storage.compaction.directory: directory "tmp" is relative to the working directory of the collector, use an absolute path (storage-directory)`,
+		`warning: extensions.file_storage/empty.directory: directory must not be empty (storage-directory)`,
+	}, findingStrings(result))
+}
+
+func TestSchemaManager_Lint_OverlappingEndpoints(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 127.0.0.1:8125
+  otelarrow:
+    protocols:
+      grpc:
+        endpoint: localhost:4317
+  statsd:
+  tcplog:
+    listen_address: ":0"
+  udplog:
+    listen_address: ":0"
+  zipkin:
+    endpoint: localhost:8889
+exporters:
+  prometheus:
+    endpoint: "[::]:8889"
+  debug:
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp, otelarrow, statsd]
+      exporters: [prometheus]
+    logs:
+      receivers: [tcplog, udplog]
+      exporters: [debug]
+    traces:
+      receivers: [zipkin]
+      exporters: [debug]
+`)
+
+	result, err := manager.Lint(config, "0.139.0", DisableLintRules("batch-processor"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`warning: receivers.otlp.protocols.grpc.endpoint: "0.0.0.0:4317" overlaps "localhost:4317" bound by receivers.otelarrow.protocols.grpc.endpoint (endpoint-conflict)`,
+		`warning: exporters.prometheus.endpoint: "[::]:8889" overlaps "localhost:8889" bound by receivers.zipkin.endpoint (endpoint-conflict)`,
+	}, findingStrings(result), "TCP and UDP listeners on the same port and ephemeral ports do not conflict")
+}
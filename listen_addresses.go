@@ -0,0 +1,159 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// listenEndpoint is a setting of a component that holds an address the component binds when it starts
+type listenEndpoint struct {
+	// path is the location of the setting in the component configuration, e.g. "protocols.grpc.endpoint"
+	path string
+	// defaultAddress is bound when the setting is omitted but its parent block is declared, empty if there is none
+	defaultAddress string
+	// network is "udp" for datagram listeners, which do not conflict with TCP listeners on the same port
+	network string
+}
+
+// listenEndpoints lists the listen addresses of components keyed by "<type>/<name>". Endpoints of other components,
+// e.g. the endpoint a redis receiver scrapes, are client addresses.
+var listenEndpoints = map[string][]listenEndpoint{
+	"extension/health_check":         {{path: "endpoint", defaultAddress: "localhost:13133"}},
+	"extension/healthcheckv2":        {{path: "http.endpoint", defaultAddress: "localhost:13133"}, {path: "grpc.endpoint", defaultAddress: "localhost:13132"}},
+	"extension/jaegerremotesampling": {{path: "http.endpoint", defaultAddress: "localhost:5778"}, {path: "grpc.endpoint", defaultAddress: "localhost:14250"}},
+	"extension/pprof":                {{path: "endpoint", defaultAddress: "localhost:1777"}},
+	"extension/remotetap":            {{path: "endpoint", defaultAddress: "localhost:12001"}},
+	"extension/zpages":               {{path: "endpoint", defaultAddress: "localhost:55679"}},
+	"exporter/prometheus":            {{path: "endpoint"}},
+	"receiver/awsfirehose":           {{path: "endpoint"}},
+	"receiver/awsxray":               {{path: "endpoint", defaultAddress: "localhost:2000", network: "udp"}},
+	"receiver/carbon":                {{path: "endpoint", defaultAddress: "localhost:2003"}},
+	"receiver/cloudflare":            {{path: "endpoint"}},
+	"receiver/datadog":               {{path: "endpoint"}},
+	"receiver/faro":                  {{path: "endpoint"}},
+	"receiver/fluentforward":         {{path: "endpoint"}},
+	"receiver/influxdb":              {{path: "endpoint"}},
+	"receiver/jaeger": {
+		{path: "protocols.grpc.endpoint", defaultAddress: "localhost:14250"},
+		{path: "protocols.thrift_http.endpoint", defaultAddress: "localhost:14268"},
+		{path: "protocols.thrift_binary.endpoint", defaultAddress: "localhost:6832", network: "udp"},
+		{path: "protocols.thrift_compact.endpoint", defaultAddress: "localhost:6831", network: "udp"},
+	},
+	"receiver/libhoney":              {{path: "http.endpoint"}},
+	"receiver/loki":                  {{path: "protocols.grpc.endpoint"}, {path: "protocols.http.endpoint"}},
+	"receiver/otelarrow":             {{path: "protocols.grpc.endpoint"}},
+	"receiver/otlp":                  {{path: "protocols.grpc.endpoint", defaultAddress: "localhost:4317"}, {path: "protocols.http.endpoint", defaultAddress: "localhost:4318"}},
+	"receiver/prometheusremotewrite": {{path: "endpoint"}},
+	"receiver/signalfx":              {{path: "endpoint"}},
+	"receiver/skywalking":            {{path: "protocols.grpc.endpoint"}, {path: "protocols.http.endpoint"}},
+	"receiver/splunk_hec":            {{path: "endpoint"}},
+	"receiver/statsd":                {{path: "endpoint", defaultAddress: "localhost:8125", network: "udp"}},
+	"receiver/syslog":                {{path: "tcp.listen_address"}, {path: "udp.listen_address", network: "udp"}},
+	"receiver/tcplog":                {{path: "listen_address"}},
+	"receiver/udplog":                {{path: "listen_address", network: "udp"}},
+	"receiver/webhookevent":          {{path: "endpoint"}},
+	"receiver/zipkin":                {{path: "endpoint", defaultAddress: "localhost:9411"}},
+}
+
+// listenAddress is an address bound by a component of a configuration
+type listenAddress struct {
+	// path is the location of the setting, e.g. "extensions.health_check.endpoint"
+	path    string
+	address string
+	network string
+	// defaulted is true if the setting is omitted and the component binds its default address
+	defaulted bool
+}
+
+// collectListenAddresses returns the addresses a component binds, values read from config providers are skipped
+func collectListenAddresses(componentType ComponentType, section string, id string, config interface{}) []listenAddress {
+	var addresses []listenAddress
+	for _, endpoint := range listenEndpoints[fmt.Sprintf("%s/%s", componentType, componentName(id))] {
+		keys := strings.Split(endpoint.path, ".")
+		parent, ok := lookupBlock(config, keys[:len(keys)-1])
+		if !ok {
+			continue
+		}
+
+		listen := listenAddress{path: section + "." + id + "." + endpoint.path, network: endpoint.network}
+		if listen.network == "" {
+			listen.network = "tcp"
+		}
+		switch value := parent[keys[len(keys)-1]].(type) {
+		case string:
+			if value == "" || usesConfigProvider(value) {
+				continue
+			}
+			listen.address = value
+		case nil:
+			if endpoint.defaultAddress == "" {
+				continue
+			}
+			listen.address, listen.defaulted = endpoint.defaultAddress, true
+		default:
+			continue
+		}
+		addresses = append(addresses, listen)
+	}
+	return addresses
+}
+
+// lookupBlock returns the block of a configuration at a path of keys, blocks declared without settings (e.g.
+// "grpc:") are empty
+func lookupBlock(config interface{}, keys []string) (map[string]interface{}, bool) {
+	for _, key := range keys {
+		block, ok := config.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if config, ok = block[key]; !ok {
+			return nil, false
+		}
+	}
+	switch block := config.(type) {
+	case map[string]interface{}:
+		return block, true
+	case nil:
+		return map[string]interface{}{}, true
+	default:
+		return nil, false
+	}
+}
+
+// socket returns the host and port of a listen address, localhost is bound as 127.0.0.1. Addresses that are not
+// host:port pairs and ephemeral ports have no socket.
+func (a listenAddress) socket() (host string, port string, ok bool) {
+	host, port, err := net.SplitHostPort(a.address)
+	if err != nil || port == "0" {
+		return "", "", false
+	}
+	if host == "localhost" {
+		host = "127.0.0.1"
+	}
+	return host, port, true
+}
+
+// overlaps returns whether two listen addresses bind the same port of an interface, an address bound to all
+// interfaces, e.g. 0.0.0.0:4317 or :4317, overlaps the addresses of every interface
+func (a listenAddress) overlaps(other listenAddress) bool {
+	host, port, ok := a.socket()
+	otherHost, otherPort, otherOK := other.socket()
+	if !ok || !otherOK || a.network != other.network || port != otherPort {
+		return false
+	}
+	return host == otherHost || isAllInterfaces(host) || isAllInterfaces(otherHost)
+}
+
+// isAllInterfaces returns whether a host of a listen address binds all network interfaces
+func isAllInterfaces(host string) bool {
+	return host == "" || host == "0.0.0.0" || host == "::"
+}
+
+// describe returns the address and whether it is the default of the component
+func (a listenAddress) describe() string {
+	if a.defaulted {
+		return fmt.Sprintf("default address %q", a.address)
+	}
+	return fmt.Sprintf("%q", a.address)
+}
@@ -0,0 +1,153 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// NormalizeConfig converts a collector configuration (YAML or JSON) into a canonical JSON representation, so two
+// configurations can be compared semantically rather than textually:
+//   - keys are sorted and YAML anchors, aliases and merge keys are resolved
+//   - component IDs are rewritten in "type[/name]" form, in section keys, pipelines and service.extensions, IDs that
+//     are the same in this form, e.g. "otlp" and "otlp ", are an error
+//   - null list items are removed and null components become empty maps, "otlp:" and "otlp: {}" declare the same
+//     component. Other null values, e.g. "timeout:", are kept.
+func NormalizeConfig(config []byte) ([]byte, error) {
+	normalized, err := normalizeCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(normalized, "", "  ")
+}
+
+// normalizeCollectorConfig parses a collector configuration and normalizes it like NormalizeConfig
+func normalizeCollectorConfig(config []byte) (map[string]interface{}, error) {
+	configMap, err := parseCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, _ := stripNulls(configMap).(map[string]interface{})
+	if err := normalizeComponentIDs(normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// stripNulls removes null list items, null map values are kept
+func stripNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = stripNulls(item)
+		}
+		return v
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				items = append(items, stripNulls(item))
+			}
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// normalizeComponentIDs rewrites the component and pipeline IDs of a configuration in canonical form and replaces
+// null components with empty maps
+func normalizeComponentIDs(config map[string]interface{}) error {
+	for _, cs := range componentSections {
+		section, ok := config[cs.section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		normalized, err := normalizeIDKeys(cs.section, section)
+		if err != nil {
+			return err
+		}
+		for id, component := range normalized {
+			if component == nil {
+				normalized[id] = map[string]interface{}{}
+			}
+		}
+		config[cs.section] = normalized
+	}
+
+	service, ok := config[sectionService].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if extensions, ok := service["extensions"].([]interface{}); ok {
+		service["extensions"] = normalizeIDList(extensions)
+	}
+
+	pipelines, ok := service["pipelines"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	pipelines, err := normalizeIDKeys(sectionService+".pipelines", pipelines)
+	if err != nil {
+		return err
+	}
+	for _, pipeline := range pipelines {
+		pipelineMap, ok := pipeline.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{sectionReceivers, sectionProcessors, sectionExporters} {
+			if ids, ok := pipelineMap[key].([]interface{}); ok {
+				pipelineMap[key] = normalizeIDList(ids)
+			}
+		}
+	}
+	service["pipelines"] = pipelines
+	return nil
+}
+
+// normalizeIDKeys rewrites the keys of a section in canonical component ID form, invalid IDs are kept unchanged. Keys
+// with the same canonical form are an error instead of being merged, e.g. "otlp" and "otlp ".
+func normalizeIDKeys(path string, section map[string]interface{}) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	normalized := make(map[string]interface{}, len(section))
+	originals := make(map[string]string, len(section))
+	for _, key := range keys {
+		id := normalizeID(key)
+		if original, ok := originals[id]; ok {
+			return nil, fmt.Errorf("%s: duplicate ID %s, %q and %q are the same ID", path, id, original, key)
+		}
+		originals[id] = key
+		normalized[id] = section[key]
+	}
+	return normalized, nil
+}
+
+// normalizeIDList rewrites a list of component IDs in canonical form, the order of the list is significant and kept
+func normalizeIDList(ids []interface{}) []interface{} {
+	normalized := make([]interface{}, len(ids))
+	for i, id := range ids {
+		if s, ok := id.(string); ok {
+			normalized[i] = normalizeID(s)
+			continue
+		}
+		normalized[i] = id
+	}
+	return normalized
+}
+
+// normalizeID returns a component ID in "type[/name]" form, e.g. " otlp/internal " becomes "otlp/internal"
+func normalizeID(id string) string {
+	componentID, err := ParseComponentID(id)
+	if err != nil {
+		return id
+	}
+	return componentID.String()
+}
@@ -0,0 +1,84 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeConfig(t *testing.T) {
+	config := []byte(`
+exporters:
+  otlp/backend: &exporter
+    endpoint: backend:4317
+    headers:
+  "otlp/backup ":
+    <<: *exporter
+    endpoint: backup:4317
+receivers:
+  otlp:
+    protocols:
+      grpc:
+service:
+  extensions: [ " health_check" ]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp/backend, "otlp/backup ", ~]
+extensions:
+  health_check:
+`)
+
+	normalized, err := NormalizeConfig(config)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "exporters": {
+    "otlp/backend": {"endpoint": "backend:4317", "headers": null},
+    "otlp/backup": {"endpoint": "backup:4317", "headers": null}
+  },
+  "extensions": {"health_check": {}},
+  "receivers": {"otlp": {"protocols": {"grpc": null}}},
+  "service": {
+    "extensions": ["health_check"],
+    "pipelines": {"traces": {"exporters": ["otlp/backend", "otlp/backup"], "receivers": ["otlp"]}}
+  }
+}`, string(normalized))
+
+	// Equivalent configurations have the same canonical form
+	equivalent, err := NormalizeConfig([]byte(`{"service": {"pipelines": {"traces": {"receivers": ["otlp"], "exporters": ["otlp/backend", "otlp/backup"]}}, "extensions": ["health_check"]},
+"receivers": {"otlp": {"protocols": {"grpc": null}}}, "extensions": {"health_check": null},
+"exporters": {"otlp/backup": {"headers": null, "endpoint": "backup:4317"}, "otlp/backend": {"endpoint": "backend:4317", "headers": null}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, string(normalized), string(equivalent))
+}
+
+func TestNormalizeConfig_Nulls(t *testing.T) {
+	normalized, err := NormalizeConfig([]byte("processors:\n  batch:\n  memory_limiter:\n    check_interval:\n    limit_mib: 100\nexporters:\n  debug:\n    sampling_thereafter: ~\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "exporters": {"debug": {"sampling_thereafter": null}},
+  "processors": {"batch": {}, "memory_limiter": {"check_interval": null, "limit_mib": 100}}
+}`, string(normalized), "Only null components become empty maps")
+}
+
+func TestNormalizeConfig_DuplicateIDs(t *testing.T) {
+	_, err := NormalizeConfig([]byte("receivers:\n  otlp:\n  \"otlp \":\n    protocols:\n"))
+	assert.EqualError(t, err, `receivers: duplicate ID otlp, "otlp" and "otlp " are the same ID`)
+
+	_, err = NormalizeConfig([]byte("service:\n  pipelines:\n    traces/a:\n      receivers: [otlp]\n    \" traces/a\":\n      receivers: [zipkin]\n"))
+	assert.EqualError(t, err, `service.pipelines: duplicate ID traces/a, " traces/a" and "traces/a" are the same ID`)
+}
+
+func TestNormalizeConfig_Errors(t *testing.T) {
+	_, err := NormalizeConfig([]byte("receivers: ["))
+	assert.Error(t, err)
+
+	_, err = NormalizeConfig([]byte("- receivers"))
+	assert.EqualError(t, err, "collector configuration must be a map, got list")
+
+	// Invalid component IDs are kept unchanged
+	normalized, err := NormalizeConfig([]byte("receivers:\n  otlp/:\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"receivers": {"otlp/": {}}}`, string(normalized))
+}
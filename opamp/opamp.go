@@ -0,0 +1,140 @@
+// Package opamp validates collector configurations delivered through OpAMP remote configuration
+// and reports the outcome as an OpAMP RemoteConfigStatus.
+//
+// The types mirror the OpAMP protobuf messages so that this package does not depend on opamp-go;
+// converting them to and from protobufs.AgentRemoteConfig and protobufs.RemoteConfigStatus is a field-by-field copy.
+package opamp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// RemoteConfigStatuses mirrors the OpAMP RemoteConfigStatuses enum
+type RemoteConfigStatuses int32
+
+const (
+	RemoteConfigStatusesUnset    RemoteConfigStatuses = 0
+	RemoteConfigStatusesApplied  RemoteConfigStatuses = 1
+	RemoteConfigStatusesApplying RemoteConfigStatuses = 2
+	RemoteConfigStatusesFailed   RemoteConfigStatuses = 3
+)
+
+// AgentConfigFile mirrors the OpAMP AgentConfigFile message
+type AgentConfigFile struct {
+	Body        []byte `json:"body"`
+	ContentType string `json:"content_type"`
+}
+
+// AgentRemoteConfig mirrors the OpAMP AgentRemoteConfig message
+type AgentRemoteConfig struct {
+	// ConfigMap holds the config files keyed by file name
+	ConfigMap  map[string]AgentConfigFile `json:"config_map"`
+	ConfigHash []byte                     `json:"config_hash"`
+}
+
+// RemoteConfigStatus mirrors the OpAMP RemoteConfigStatus message
+type RemoteConfigStatus struct {
+	LastRemoteConfigHash []byte               `json:"last_remote_config_hash"`
+	Status               RemoteConfigStatuses `json:"status"`
+	ErrorMessage         string               `json:"error_message,omitempty"`
+}
+
+// ValidateRemoteConfig validates every config file of a remote configuration against the schemas of the
+// agent's collector version. Files are merged in file name order (maps are merged, other values replaced)
+// the same way the collector merges multiple --config sources, and the merged config is validated as a whole.
+// The returned status is FAILED with a descriptive error message if any problem was found, otherwise it is
+// UNSET so the agent can report APPLYING/APPLIED itself. An error is returned if the agent version has no schemas.
+func ValidateRemoteConfig(schemaManager *collectorschema.SchemaManager, remoteConfig AgentRemoteConfig, agentVersion string) (*RemoteConfigStatus, error) {
+	version := strings.TrimPrefix(agentVersion, "v")
+	if _, err := schemaManager.GetComponentNames(collectorschema.ComponentTypeReceiver, version); err != nil {
+		return nil, fmt.Errorf("no schemas available for collector version %q: %w", agentVersion, err)
+	}
+
+	status := &RemoteConfigStatus{
+		LastRemoteConfigHash: remoteConfig.ConfigHash,
+		Status:               RemoteConfigStatusesUnset,
+	}
+
+	fileNames := make([]string, 0, len(remoteConfig.ConfigMap))
+	for name := range remoteConfig.ConfigMap {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	var problems []string
+	merged := make(map[string]interface{})
+	for _, name := range fileNames {
+		file := remoteConfig.ConfigMap[name]
+		if !isYAMLContentType(file.ContentType) {
+			problems = append(problems, fmt.Sprintf("%s: unsupported content type %q", displayName(name), file.ContentType))
+			continue
+		}
+
+		var parsed map[string]interface{}
+		if err := yaml.Unmarshal(file.Body, &parsed); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: failed to parse config: %v", displayName(name), err))
+			continue
+		}
+		mergeMaps(merged, parsed)
+	}
+
+	if len(problems) == 0 {
+		mergedConfig, err := yaml.Marshal(merged)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode merged config: %w", err)
+		}
+
+		result, err := schemaManager.ValidateCollectorConfig(mergedConfig, version)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			for _, validationError := range result.Errors {
+				problems = append(problems, validationError.String())
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		status.Status = RemoteConfigStatusesFailed
+		status.ErrorMessage = fmt.Sprintf("invalid collector configuration for version %s: %s", version, strings.Join(problems, "; "))
+	}
+
+	return status, nil
+}
+
+// isYAMLContentType checks if a config file content type can be parsed as YAML
+func isYAMLContentType(contentType string) bool {
+	switch strings.ToLower(strings.TrimSpace(contentType)) {
+	case "", "text/yaml", "text/x-yaml", "application/yaml", "application/x-yaml", "application/json", "text/json":
+		return true
+	default:
+		return false
+	}
+}
+
+// displayName returns a printable name for a config file, OpAMP uses the empty name for single-file configs
+func displayName(name string) string {
+	if name == "" {
+		return "<default>"
+	}
+	return name
+}
+
+// mergeMaps merges src into dst, nested maps are merged recursively and other values are replaced
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
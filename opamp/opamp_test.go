@@ -0,0 +1,131 @@
+package opamp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+func TestValidateRemoteConfig(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+
+	remoteConfig := AgentRemoteConfig{
+		ConfigHash: []byte("hash"),
+		ConfigMap: map[string]AgentConfigFile{
+			"base.yaml": {
+				ContentType: "text/yaml",
+				Body: []byte(`
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`),
+			},
+			"overlay.yaml": {
+				ContentType: "text/yaml",
+				Body: []byte(`
+processors:
+  batch:
+service:
+  pipelines:
+    traces:
+      processors: [batch]
+`),
+			},
+		},
+	}
+
+	status, err := ValidateRemoteConfig(manager, remoteConfig, "v0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, RemoteConfigStatusesUnset, status.Status, "unexpected error: %s", status.ErrorMessage)
+	assert.Equal(t, []byte("hash"), status.LastRemoteConfigHash)
+	assert.Empty(t, status.ErrorMessage)
+}
+
+func TestValidateRemoteConfig_Invalid(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+
+	remoteConfig := AgentRemoteConfig{
+		ConfigHash: []byte("hash"),
+		ConfigMap: map[string]AgentConfigFile{
+			"": {
+				Body: []byte(`
+receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [debug]
+`),
+			},
+		},
+	}
+
+	status, err := ValidateRemoteConfig(manager, remoteConfig, "0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, RemoteConfigStatusesFailed, status.Status)
+	assert.Equal(t, []byte("hash"), status.LastRemoteConfigHash)
+	assert.Contains(t, status.ErrorMessage, `service.pipelines.traces.processors[0]: references "batch"`)
+}
+
+func TestValidateRemoteConfig_UnparsableFiles(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+
+	remoteConfig := AgentRemoteConfig{
+		ConfigMap: map[string]AgentConfigFile{
+			"a.yaml":   {Body: []byte("receivers: [unclosed")},
+			"b.binary": {Body: []byte{0x1}, ContentType: "application/octet-stream"},
+		},
+	}
+
+	status, err := ValidateRemoteConfig(manager, remoteConfig, "0.138.0")
+	require.NoError(t, err)
+	assert.Equal(t, RemoteConfigStatusesFailed, status.Status)
+	assert.Contains(t, status.ErrorMessage, "a.yaml: failed to parse config")
+	assert.Contains(t, status.ErrorMessage, `b.binary: unsupported content type "application/octet-stream"`)
+}
+
+func TestValidateRemoteConfig_UnknownVersion(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+
+	_, err := ValidateRemoteConfig(manager, AgentRemoteConfig{}, "999.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no schemas available for collector version")
+}
+
+func TestMergeMaps(t *testing.T) {
+	dst := map[string]interface{}{
+		"receivers": map[string]interface{}{"otlp": nil},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces": map[string]interface{}{"receivers": []interface{}{"otlp"}},
+			},
+		},
+	}
+	src := map[string]interface{}{
+		"receivers": map[string]interface{}{"jaeger": nil},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces": map[string]interface{}{"receivers": []interface{}{"jaeger"}},
+			},
+		},
+	}
+
+	mergeMaps(dst, src)
+
+	assert.Equal(t, map[string]interface{}{"otlp": nil, "jaeger": nil}, dst["receivers"])
+	traces := dst["service"].(map[string]interface{})["pipelines"].(map[string]interface{})["traces"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"jaeger"}, traces["receivers"], "lists should be replaced")
+}
@@ -0,0 +1,140 @@
+package ottl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Token types of the OTTL lexer
+const (
+	tokenBytes      = "Bytes"
+	tokenFloat      = "Float"
+	tokenInt        = "Int"
+	tokenString     = "String"
+	tokenNot        = "OpNot"
+	tokenOr         = "OpOr"
+	tokenAnd        = "OpAnd"
+	tokenComparison = "OpComparison"
+	tokenAddSub     = "OpAddSub"
+	tokenMultDiv    = "OpMultDiv"
+	tokenBoolean    = "Boolean"
+	tokenEqual      = "Equal"
+	tokenLParen     = "LParen"
+	tokenRParen     = "RParen"
+	tokenLBrace     = "LBrace"
+	tokenRBrace     = "RBrace"
+	tokenColon      = "Colon"
+	tokenPunct      = "Punct"
+	tokenUppercase  = "Uppercase"
+	tokenLowercase  = "Lowercase"
+	tokenWhitespace = "whitespace"
+	tokenEOF        = "EOF"
+)
+
+// maxTokenQuoteSize limits the length of input quoted in error messages
+const maxTokenQuoteSize = 32
+
+// lexerRules mirror the lexer of the OTTL grammar, rules are tried in order and the first match wins
+var lexerRules = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{tokenBytes, regexp.MustCompile(`^0x[a-fA-F0-9]+`)},
+	{tokenFloat, regexp.MustCompile(`^[-+]?\d*\.\d+([eE][-+]?\d+)?`)},
+	{tokenInt, regexp.MustCompile(`^[-+]?\d+`)},
+	{tokenString, regexp.MustCompile(`^"(\\.|[^\\"])*"`)},
+	{tokenNot, regexp.MustCompile(`^\b(not)\b`)},
+	{tokenOr, regexp.MustCompile(`^\b(or)\b`)},
+	{tokenAnd, regexp.MustCompile(`^\b(and)\b`)},
+	{tokenComparison, regexp.MustCompile(`^(==|!=|>=|<=|>|<)`)},
+	{tokenAddSub, regexp.MustCompile(`^(\+|\-)`)},
+	{tokenMultDiv, regexp.MustCompile(`^(\/|\*)`)},
+	{tokenBoolean, regexp.MustCompile(`^\b(true|false)\b`)},
+	{tokenEqual, regexp.MustCompile(`^=`)},
+	{tokenLParen, regexp.MustCompile(`^\(`)},
+	{tokenRParen, regexp.MustCompile(`^\)`)},
+	{tokenLBrace, regexp.MustCompile(`^\{`)},
+	{tokenRBrace, regexp.MustCompile(`^\}`)},
+	{tokenColon, regexp.MustCompile(`^\:`)},
+	{tokenPunct, regexp.MustCompile(`^[,.\[\]]`)},
+	{tokenUppercase, regexp.MustCompile(`^[A-Z][A-Z0-9_]*`)},
+	{tokenLowercase, regexp.MustCompile(`^[a-z][a-z0-9_]*`)},
+	{tokenWhitespace, regexp.MustCompile(`^\s+`)},
+}
+
+// Position is the location of a token in the parsed text, lines and columns start at 1
+type Position struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String returns the position in "line:column" form
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// token is a lexed token, whitespace is not emitted
+type token struct {
+	kind  string
+	value string
+	pos   Position
+}
+
+// String returns the token as quoted in error messages
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "<EOF>"
+	}
+	return fmt.Sprintf("%q", quotePrefix(t.value))
+}
+
+// tokenize splits text into tokens, the last token is always EOF
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	pos := Position{Line: 1, Column: 1}
+
+	for pos.Offset < len(text) {
+		rest := text[pos.Offset:]
+		matched := false
+		for _, rule := range lexerRules {
+			match := rule.pattern.FindString(rest)
+			if match == "" {
+				continue
+			}
+			if rule.name != tokenWhitespace {
+				tokens = append(tokens, token{kind: rule.name, value: match, pos: pos})
+			}
+			pos = advance(pos, match)
+			matched = true
+			break
+		}
+		if !matched {
+			return nil, &SyntaxError{Pos: pos, Message: fmt.Sprintf("invalid input text %q", quotePrefix(rest))}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: pos}), nil
+}
+
+// advance moves a position past a matched text
+func advance(pos Position, text string) Position {
+	for _, r := range text {
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	pos.Offset += len(text)
+	return pos
+}
+
+// quotePrefix shortens text quoted in error messages
+func quotePrefix(text string) string {
+	if len(text) > maxTokenQuoteSize {
+		return text[:maxTokenQuoteSize] + "..."
+	}
+	return text
+}
@@ -0,0 +1,369 @@
+// Package ottl checks the syntax of OpenTelemetry Transformation Language (OTTL) statements, conditions and
+// value expressions without depending on the collector. It follows the grammar of the contrib pkg/ottl parser
+// but does not resolve paths, functions or enums, those are validated by the collector at startup.
+package ottl
+
+import (
+	"fmt"
+)
+
+// whereKeyword separates the editor of a statement from its condition
+const whereKeyword = "where"
+
+// SyntaxError describes an OTTL syntax error and its position in the parsed text
+type SyntaxError struct {
+	Pos     Position `json:"pos"`
+	Message string   `json:"message"`
+}
+
+// Error returns the error in "line:column: message" form
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// ParseStatement checks the syntax of a statement, e.g. `set(attributes["env"], "prod") where name == "GET"`
+func ParseStatement(statement string) error {
+	return parse(statement, (*parser).statement)
+}
+
+// ParseCondition checks the syntax of a condition, e.g. `attributes["http.route"] == "/health"`
+func ParseCondition(condition string) error {
+	return parse(condition, (*parser).booleanExpression)
+}
+
+// ParseValueExpression checks the syntax of a value expression, e.g. `Concat([name, "suffix"], "-")`
+func ParseValueExpression(expression string) error {
+	return parse(expression, (*parser).value)
+}
+
+// parse tokenizes text and checks that the rule consumes all tokens
+func parse(text string, rule func(*parser) bool) error {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return err
+	}
+
+	p := &parser{tokens: tokens}
+	if rule(p) && p.accept(tokenEOF, "") {
+		return nil
+	}
+	return p.syntaxError()
+}
+
+// parser is a backtracking recursive descent parser over the OTTL grammar. Alternatives are tried in the order
+// of the contrib grammar, the first alternative that matches is used.
+type parser struct {
+	tokens []token
+	pos    int
+	// furthest is the index of the furthest token that failed to match, expected lists what was expected there
+	furthest int
+	expected []string
+}
+
+// peek returns the current token
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the current token if it has the given kind and, unless empty, the given value
+func (p *parser) accept(kind string, value string) bool {
+	current := p.peek()
+	if current.kind == kind && (value == "" || current.value == value) {
+		if kind != tokenEOF {
+			p.pos++
+		}
+		return true
+	}
+
+	expectation := kind
+	if value != "" {
+		expectation = fmt.Sprintf("%q", value)
+	}
+	switch {
+	case p.pos > p.furthest:
+		p.furthest = p.pos
+		p.expected = []string{expectation}
+	case p.pos == p.furthest && !contains(p.expected, expectation):
+		p.expected = append(p.expected, expectation)
+	}
+	return false
+}
+
+// acceptPunct consumes a punctuation token
+func (p *parser) acceptPunct(value string) bool {
+	kind := tokenPunct
+	switch value {
+	case "(":
+		kind = tokenLParen
+	case ")":
+		kind = tokenRParen
+	case "{":
+		kind = tokenLBrace
+	case "}":
+		kind = tokenRBrace
+	case ":":
+		kind = tokenColon
+	case "=":
+		kind = tokenEqual
+	}
+	return p.accept(kind, value)
+}
+
+// try runs a rule and restores the position if it does not match
+func (p *parser) try(rule func() bool) bool {
+	start := p.pos
+	if rule() {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// syntaxError returns the error at the furthest position the parser reached
+func (p *parser) syntaxError() error {
+	unexpected := p.tokens[p.furthest]
+	message := fmt.Sprintf("unexpected token %s", unexpected)
+	if len(p.expected) == 1 {
+		message += fmt.Sprintf(" (expected %s)", p.expected[0])
+	}
+	return &SyntaxError{Pos: unexpected.pos, Message: message}
+}
+
+// statement = (editor | converter) ("where" booleanExpression)?
+func (p *parser) statement() bool {
+	if !p.try(p.editor) && !p.try(p.converter) {
+		return false
+	}
+	if p.accept(tokenLowercase, whereKeyword) {
+		return p.booleanExpression()
+	}
+	return true
+}
+
+// booleanExpression = term ("or" term)*
+func (p *parser) booleanExpression() bool {
+	if !p.term() {
+		return false
+	}
+	for p.try(func() bool { return p.accept(tokenOr, "") && p.term() }) {
+	}
+	return true
+}
+
+// term = booleanValue ("and" booleanValue)*
+func (p *parser) term() bool {
+	if !p.booleanValue() {
+		return false
+	}
+	for p.try(func() bool { return p.accept(tokenAnd, "") && p.booleanValue() }) {
+	}
+	return true
+}
+
+// booleanValue = "not"? (comparison | constExpr | "(" booleanExpression ")")
+func (p *parser) booleanValue() bool {
+	p.accept(tokenNot, "")
+	return p.try(p.comparison) ||
+		p.try(p.constExpr) ||
+		p.try(func() bool { return p.acceptPunct("(") && p.booleanExpression() && p.acceptPunct(")") })
+}
+
+// constExpr = Boolean | converter
+func (p *parser) constExpr() bool {
+	return p.accept(tokenBoolean, "") || p.try(p.converter)
+}
+
+// comparison = value OpComparison value
+func (p *parser) comparison() bool {
+	return p.value() && p.accept(tokenComparison, "") && p.value()
+}
+
+// editor = lowercaseName "(" arguments ")" key*
+func (p *parser) editor() bool {
+	return p.lowercaseName() && p.call()
+}
+
+// converter = uppercaseName "(" arguments ")" key*
+func (p *parser) converter() bool {
+	return p.uppercaseName() && p.call()
+}
+
+// call = "(" (argument ("," argument)*)? ")" key*
+func (p *parser) call() bool {
+	if !p.acceptPunct("(") {
+		return false
+	}
+	if !p.acceptPunct(")") {
+		if !p.argument() {
+			return false
+		}
+		for p.acceptPunct(",") {
+			if !p.argument() {
+				return false
+			}
+		}
+		if !p.acceptPunct(")") {
+			return false
+		}
+	}
+	p.keys()
+	return true
+}
+
+// argument = (lowercaseName "=")? (value | uppercaseName)
+func (p *parser) argument() bool {
+	p.try(func() bool { return p.lowercaseName() && p.acceptPunct("=") })
+	return p.try(p.value) || p.try(p.uppercaseName)
+}
+
+// lowercaseName = Lowercase (Uppercase | Lowercase)*
+func (p *parser) lowercaseName() bool {
+	return p.accept(tokenLowercase, "") && p.nameRest()
+}
+
+// uppercaseName = Uppercase (Uppercase | Lowercase)*
+func (p *parser) uppercaseName() bool {
+	return p.accept(tokenUppercase, "") && p.nameRest()
+}
+
+// nameRest consumes the remaining tokens of a function name
+func (p *parser) nameRest() bool {
+	for kind := p.peek().kind; kind == tokenUppercase || kind == tokenLowercase; kind = p.peek().kind {
+		p.pos++
+	}
+	return true
+}
+
+// value = "nil" | mathExprLiteral (?! OpAddSub | OpMultDiv) | mathExpression | Bytes | String | Boolean
+// | Uppercase (?! Lowercase) | map | list
+func (p *parser) value() bool {
+	return p.accept(tokenLowercase, "nil") ||
+		p.try(func() bool {
+			if !p.mathExprLiteral() {
+				return false
+			}
+			next := p.peek().kind
+			return next != tokenAddSub && next != tokenMultDiv
+		}) ||
+		p.try(p.mathExpression) ||
+		p.accept(tokenBytes, "") ||
+		p.accept(tokenString, "") ||
+		p.accept(tokenBoolean, "") ||
+		p.try(func() bool { return p.accept(tokenUppercase, "") && p.peek().kind != tokenLowercase }) ||
+		p.try(p.mapValue) ||
+		p.try(p.list)
+}
+
+// mathExprLiteral = editor | converter | Float | Int | path
+func (p *parser) mathExprLiteral() bool {
+	return p.try(p.editor) ||
+		p.try(p.converter) ||
+		p.accept(tokenFloat, "") ||
+		p.accept(tokenInt, "") ||
+		p.try(p.path)
+}
+
+// mathExpression = addSubTerm (OpAddSub addSubTerm)*
+func (p *parser) mathExpression() bool {
+	if !p.addSubTerm() {
+		return false
+	}
+	for p.try(func() bool { return p.accept(tokenAddSub, "") && p.addSubTerm() }) {
+	}
+	return true
+}
+
+// addSubTerm = mathValue (OpMultDiv mathValue)*
+func (p *parser) addSubTerm() bool {
+	if !p.mathValue() {
+		return false
+	}
+	for p.try(func() bool { return p.accept(tokenMultDiv, "") && p.mathValue() }) {
+	}
+	return true
+}
+
+// mathValue = mathExprLiteral | "(" mathExpression ")"
+func (p *parser) mathValue() bool {
+	return p.try(p.mathExprLiteral) ||
+		p.try(func() bool { return p.acceptPunct("(") && p.mathExpression() && p.acceptPunct(")") })
+}
+
+// path = (Lowercase ".")? field ("." field)*, field = Lowercase key*
+func (p *parser) path() bool {
+	if !p.field() {
+		return false
+	}
+	for p.try(func() bool { return p.acceptPunct(".") && p.field() }) {
+	}
+	return true
+}
+
+// field = Lowercase key*
+func (p *parser) field() bool {
+	if !p.accept(tokenLowercase, "") {
+		return false
+	}
+	p.keys()
+	return true
+}
+
+// keys consumes any number of keys
+func (p *parser) keys() {
+	for p.try(p.key) {
+	}
+}
+
+// key = "[" (String | Int | mathExpression | mathExprLiteral) "]"
+func (p *parser) key() bool {
+	if !p.acceptPunct("[") {
+		return false
+	}
+	for _, alternative := range []func() bool{
+		func() bool { return p.accept(tokenString, "") },
+		func() bool { return p.accept(tokenInt, "") },
+		p.mathExpression,
+		p.mathExprLiteral,
+	} {
+		if p.try(func() bool { return alternative() && p.acceptPunct("]") }) {
+			return true
+		}
+	}
+	return false
+}
+
+// list = "[" value* ("," value)* "]"
+func (p *parser) list() bool {
+	if !p.acceptPunct("[") {
+		return false
+	}
+	for p.try(p.value) {
+	}
+	for p.acceptPunct(",") {
+		if !p.value() {
+			return false
+		}
+	}
+	return p.acceptPunct("]")
+}
+
+// mapValue = "{" (String ":" value ","?)* "}"
+func (p *parser) mapValue() bool {
+	if !p.acceptPunct("{") {
+		return false
+	}
+	for p.try(func() bool { return p.accept(tokenString, "") && p.acceptPunct(":") && p.value() }) {
+		p.acceptPunct(",")
+	}
+	return p.acceptPunct("}")
+}
+
+// contains checks if a string slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
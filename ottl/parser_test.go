@@ -0,0 +1,108 @@
+package ottl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatement(t *testing.T) {
+	statements := []string{
+		`set(attributes["env"], "prod")`,
+		`set(resource.attributes["k8s.namespace.name"], "default") where resource.attributes["k8s.namespace.name"] == nil`,
+		`delete_key(attributes, "http.request.header.authorization")`,
+		`keep_keys(attributes, ["http.method", "http.route"])`,
+		`replace_pattern(attributes["message"], "password=[^\\s]*", "password=***")`,
+		`replace_all_matches(attributes, "*/user/*", "{userId}")`,
+		`set(name, Concat([attributes["http.method"], attributes["http.route"]], " "))`,
+		`merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\{")`,
+		`set(attributes["count"], attributes["count"] + 1) where attributes["count"] != nil and not (status.code == STATUS_CODE_ERROR or kind == SPAN_KIND_SERVER)`,
+		`set(attributes["ratio"], (attributes["a"] * 2.5) / attributes["b"] - 1)`,
+		`set(attributes["id"], SHA256(trace_id.string))`,
+		`set(attributes["map"], {"key": "value", "nested": {"list": [1, 2, 3]}, "bytes": 0xa1b2})`,
+		`truncate_all(attributes, 4096)`,
+		`set(attributes["first"], Split(attributes["path"], "/")[0])`,
+		`set(attributes["converted"], ConvertCase(name, "snake")) where name != "" and false`,
+		`set(attributes["fn"], Sort(attributes["list"], order = "desc"))`,
+		`set(attributes["hash"], Hex(attributes["value"]))`,
+		`set(attributes["duration"], Duration("3s"))`,
+		`limit(attributes, 100, [])`,
+		`extract_patterns(body, "^(?P<level>\\w+)")`,
+		`Concat(["a", "b"], "")`,
+	}
+
+	for _, statement := range statements {
+		t.Run(statement, func(t *testing.T) {
+			assert.NoError(t, ParseStatement(statement))
+		})
+	}
+}
+
+func TestParseStatement_Invalid(t *testing.T) {
+	tests := []struct {
+		statement string
+		error     string
+	}{
+		{statement: ``, error: `1:1: unexpected token <EOF>`},
+		{statement: `set(attributes["env"], "prod"`, error: `1:30: unexpected token <EOF>`},
+		{statement: `set(attributes["env"] "prod")`, error: `1:23: unexpected token "\"prod\""`},
+		{statement: `set(attributes["env"], "prod") when name == "x"`, error: `1:32: unexpected token "when"`},
+		{statement: `set(attributes["env"], "prod") where`, error: `1:37: unexpected token <EOF>`},
+		{statement: `set(attributes["env"], "prod") where name = "x"`, error: `1:43: unexpected token "="`},
+		{statement: `set(attributes['env'], "prod")`, error: `1:16: invalid input text "'env'], \"prod\")"`},
+		{statement: `set(attributes["env"], "prod"))`, error: `1:31: unexpected token ")"`},
+		{statement: `attributes["env"] = "prod"`, error: `1:11: unexpected token "[" (expected "(")`},
+		{statement: `set(attributes["count"], attributes["count"] -1)`, error: `1:46: unexpected token "-1"`},
+		{statement: "set(attributes[\"a\"],\n  \"b\" where name == \"x\"", error: `2:7: unexpected token "where"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.statement, func(t *testing.T) {
+			err := ParseStatement(test.statement)
+			require.Error(t, err)
+			assert.Equal(t, test.error, err.Error())
+
+			var syntaxError *SyntaxError
+			require.ErrorAs(t, err, &syntaxError)
+		})
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	conditions := []string{
+		`attributes["http.route"] == "/health"`,
+		`name == "GET /health" or IsMatch(attributes["http.target"], "^/ready")`,
+		`resource.attributes["service.name"] != nil and duration > 1000`,
+		`not IsString(body)`,
+		`(severity_number < SEVERITY_NUMBER_WARN) and true`,
+		`metric.type == METRIC_DATA_TYPE_SUM`,
+		`HasAttrOnDatapoint("key", "value")`,
+		`Len(attributes) >= 10`,
+	}
+
+	for _, condition := range conditions {
+		t.Run(condition, func(t *testing.T) {
+			assert.NoError(t, ParseCondition(condition))
+		})
+	}
+
+	for _, condition := range []string{
+		`set(attributes["a"], "b")`,
+		`attributes["http.route"] === "/health"`,
+		`name == "a" or`,
+		`(name == "a"`,
+		`name`,
+	} {
+		t.Run(condition, func(t *testing.T) {
+			assert.Error(t, ParseCondition(condition))
+		})
+	}
+}
+
+func TestParseValueExpression(t *testing.T) {
+	assert.NoError(t, ParseValueExpression(`Concat([name, "suffix"], "-")`))
+	assert.NoError(t, ParseValueExpression(`attributes["key"]`))
+	assert.NoError(t, ParseValueExpression(`"literal"`))
+	assert.Error(t, ParseValueExpression(`attributes["key"`))
+}
@@ -0,0 +1,106 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/ottl"
+)
+
+// Kinds of OTTL expressions found in component configurations
+const (
+	ottlStatement = "statement"
+	ottlCondition = "condition"
+)
+
+// ottlField is a configuration location holding OTTL expressions, "*" in the path matches every list item
+type ottlField struct {
+	path []string
+	kind string
+}
+
+// ottlFields lists the OTTL locations of components keyed by "<type>/<name>"
+var ottlFields = map[string][]ottlField{
+	"processor/transform": transformOTTLFields(),
+	"processor/filter": {
+		{path: []string{"traces", "span", "*"}, kind: ottlCondition},
+		{path: []string{"traces", "spanevent", "*"}, kind: ottlCondition},
+		{path: []string{"metrics", "metric", "*"}, kind: ottlCondition},
+		{path: []string{"metrics", "datapoint", "*"}, kind: ottlCondition},
+		{path: []string{"logs", "log_record", "*"}, kind: ottlCondition},
+		{path: []string{"profiles", "profile", "*"}, kind: ottlCondition},
+	},
+}
+
+// transformOTTLFields returns the OTTL locations of the transform processor. Statements are either listed
+// directly or grouped with optional conditions.
+func transformOTTLFields() []ottlField {
+	var fields []ottlField
+	for _, signal := range []string{"trace", "metric", "log", "profile"} {
+		key := signal + "_statements"
+		fields = append(fields,
+			ottlField{path: []string{key, "*"}, kind: ottlStatement},
+			ottlField{path: []string{key, "*", "statements", "*"}, kind: ottlStatement},
+			ottlField{path: []string{key, "*", "conditions", "*"}, kind: ottlCondition},
+		)
+	}
+	return fields
+}
+
+// validateOTTL parses the OTTL expressions of a component configuration and returns the syntax errors
+func validateOTTL(componentType ComponentType, componentName string, config interface{}) []semanticError {
+	var syntaxErrors []semanticError
+	for _, field := range ottlFields[fmt.Sprintf("%s/%s", componentType, componentName)] {
+		walkOTTLField(field, 0, nil, config, &syntaxErrors)
+	}
+	return syntaxErrors
+}
+
+// walkOTTLField follows the path of a field from the given segment and parses the string values it reaches
+func walkOTTLField(field ottlField, segment int, path []string, value interface{}, syntaxErrors *[]semanticError) {
+	if segment == len(field.path) {
+		expression, ok := value.(string)
+		if !ok {
+			// Groups and other non-string values are handled by longer paths and the schema
+			return
+		}
+
+		*syntaxErrors = append(*syntaxErrors, ottlSyntaxErrors(append([]string(nil), path...), field.kind, expression)...)
+		return
+	}
+
+	if field.path[segment] == "*" {
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			walkOTTLField(field, segment+1, append(path, fmt.Sprint(i)), item, syntaxErrors)
+		}
+		return
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if child, exists := object[field.path[segment]]; exists {
+		walkOTTLField(field, segment+1, append(path, field.path[segment]), child, syntaxErrors)
+	}
+}
+
+// ottlSyntaxErrors parses an OTTL expression of the given kind and returns its syntax error
+func ottlSyntaxErrors(path []string, kind string, expression string) []semanticError {
+	parse := ottl.ParseStatement
+	if kind == ottlCondition {
+		parse = ottl.ParseCondition
+	}
+	if err := parse(expression); err != nil {
+		return []semanticError{{
+			path:      path,
+			errorType: "ottl_syntax",
+			value:     expression,
+			message:   fmt.Sprintf("invalid OTTL %s: %v", kind, err),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ValidateComponentJSON_OTTL(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`{
+		"error_mode": "ignore",
+		"trace_statements": [
+			{
+				"context": "span",
+				"statements": [
+					"set(attributes[\"env\"], \"prod\")",
+					"set(attributes[\"env\"] \"prod\")"
+				],
+				"conditions": ["name = \"GET\""]
+			}
+		]
+	}`)
+
+	result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "transform", "0.138.0", config)
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected OTTL not to be parsed without the option: %v", result.Errors())
+
+	result, err = manager.ValidateComponentJSON(ComponentTypeProcessor, "transform", "0.138.0", config, ValidateOTTL())
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 2)
+
+	assert.Equal(t, "trace_statements.0.statements.1", result.Errors()[0].Field())
+	assert.Equal(t, "ottl_syntax", result.Errors()[0].Type())
+	assert.Equal(t, `invalid OTTL statement: 1:23: unexpected token "\"prod\""`, result.Errors()[0].Description())
+	assert.Equal(t, "trace_statements.0.conditions.0", result.Errors()[1].Field())
+	assert.Equal(t, `invalid OTTL condition: 1:6: unexpected token "="`, result.Errors()[1].Description())
+}
+
+func TestSchemaManager_ValidateCollectorConfig_OTTL(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  zipkin:
+processors:
+  filter:
+    error_mode: ignore
+    traces:
+      span:
+        - 'attributes["http.route"] == "/health"'
+        - 'attributes["http.route"] == "/ready" or'
+  transform:
+    log_statements:
+      - context: log
+        statements:
+          - 'set(attributes["{{template}}"], body)'
+          - 'merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\{")'
+exporters:
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [zipkin]
+      processors: [filter, transform]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0", ValidateOTTL())
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	assert.Equal(t, []string{
+		`processors.filter.traces.span.1: invalid OTTL condition: 1:40: unexpected token <EOF>`,
+	}, messages)
+}
+
+func TestValidateOTTL(t *testing.T) {
+	config := map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"metric":    []interface{}{`name == "a"`, `name ==`},
+			"datapoint": []interface{}{`value_int > 10`, 42},
+		},
+		"logs": map[string]interface{}{"log_record": "not a list"},
+	}
+
+	syntaxErrors := validateOTTL(ComponentTypeProcessor, "filter", config)
+	require.Len(t, syntaxErrors, 1)
+	assert.Equal(t, []string{"metrics", "metric", "1"}, syntaxErrors[0].path)
+	assert.Equal(t, "ottl_syntax", syntaxErrors[0].errorType)
+	assert.Equal(t, `name ==`, syntaxErrors[0].value)
+	assert.Equal(t, `invalid OTTL condition: 1:8: unexpected token <EOF>`, syntaxErrors[0].message)
+
+	assert.Empty(t, validateOTTL(ComponentTypeProcessor, "batch", config))
+}
@@ -0,0 +1,179 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PropertyOverride is a property assignment passed to the collector with --set, e.g.
+// --set=exporters::otlp::endpoint=backend:4317
+type PropertyOverride struct {
+	// Keys are the keys of the property, e.g. ["exporters", "otlp", "endpoint"]
+	Keys []string
+	// Value is the value parsed as YAML like the collector does, e.g. [a, b] is a list
+	Value interface{}
+}
+
+// Path returns the dot separated path of the property, e.g. "exporters.otlp.endpoint"
+func (o PropertyOverride) Path() string {
+	return strings.Join(o.Keys, ".")
+}
+
+// ParsePropertyOverride parses a property assignment in "key=value" form, optionally prefixed with --set=. Keys are
+// separated by "::" like in the collector, or by dots if the key contains no "::".
+func ParsePropertyOverride(override string) (*PropertyOverride, error) {
+	assignment := strings.TrimPrefix(override, "--set=")
+	key, value, found := strings.Cut(assignment, "=")
+	if !found {
+		return nil, fmt.Errorf("invalid override %q, expected key=value", override)
+	}
+
+	separator := "."
+	if strings.Contains(key, "::") {
+		separator = "::"
+	}
+	keys := strings.Split(key, separator)
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid override %q, the key must not have empty parts", override)
+		}
+	}
+
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid value of override %q: %w", override, err)
+	}
+	return &PropertyOverride{Keys: keys, Value: normalizeYAMLValue(parsed)}, nil
+}
+
+// ValidateWithOverrides applies property overrides (see ParsePropertyOverride) in order on top of a collector
+// configuration (YAML or JSON) and validates the result. Errors caused by an override have the override as Layer and
+// no position: paths that are not defined in the component schemas and values of the wrong type. Other errors are
+// located in the configuration. An error is returned if the configuration or an override cannot be parsed.
+func (sm *SchemaManager) ValidateWithOverrides(config []byte, overrides []string, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	return sm.ValidateWithOverridesContext(context.Background(), config, overrides, version, opts...)
+}
+
+// ValidateWithOverridesContext is ValidateWithOverrides with a context, the validation stops with the error of the
+// context when it is canceled
+func (sm *SchemaManager) ValidateWithOverridesContext(ctx context.Context, config []byte, overrides []string, version string, opts ...ValidationOption) (*ConfigValidationResult, error) {
+	layers := []ConfigLayer{{Config: config}}
+	var parsed []*PropertyOverride
+	for _, override := range overrides {
+		propertyOverride, err := ParsePropertyOverride(override)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, propertyOverride)
+
+		var value interface{} = propertyOverride.Value
+		for i := len(propertyOverride.Keys) - 1; i >= 0; i-- {
+			value = map[string]interface{}{propertyOverride.Keys[i]: value}
+		}
+		data, err := encodeYAML(value)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, ConfigLayer{Name: override, Config: data})
+	}
+
+	result, err := sm.ValidateLayeredConfigContext(ctx, layers, version, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathErrors []ConfigValidationError
+	for i, override := range parsed {
+		if pathError := sm.checkOverridePath(ctx, override, version); pathError != nil {
+			pathError.Layer = overrides[i]
+			pathErrors = append(pathErrors, *pathError)
+		}
+	}
+	for i := range result.Errors {
+		// Positions of overrides would point into the generated layer
+		if result.Errors[i].Layer != "" {
+			result.Errors[i].Position = nil
+		}
+	}
+	result.Errors = append(pathErrors, result.Errors...)
+	return result, nil
+}
+
+// checkOverridePath returns an error if the path of an override is not defined in the schema of its component.
+// Unknown sections and components are reported by the validation of the configuration, paths of the service section
+// and of free-form maps are not checked.
+func (sm *SchemaManager) checkOverridePath(ctx context.Context, override *PropertyOverride, version string) *ConfigValidationError {
+	componentType, isComponentSection := sectionComponentType(override.Keys[0])
+	if !isComponentSection || len(override.Keys) < 2 {
+		return nil
+	}
+
+	componentPath := override.Keys[0] + "." + override.Keys[1]
+	id, err := ParseComponentID(override.Keys[1])
+	if err != nil {
+		return &ConfigValidationError{Path: componentPath, Message: err.Error()}
+	}
+	componentSchema, err := sm.getComponentSchema(ctx, componentType, id.Component, version)
+	if err != nil {
+		return nil
+	}
+
+	definitions, _ := componentSchema.Schema["$defs"].(map[string]interface{})
+	schema := componentSchema.Schema
+	path := componentPath
+	for _, key := range override.Keys[2:] {
+		property, message := lookupSchemaProperty(schema, definitions, key)
+		if message != "" {
+			return &ConfigValidationError{Path: path, Message: message, Code: ErrorCodeUnknownField}
+		}
+		if property == nil {
+			return nil
+		}
+		schema = property
+		path += "." + key
+	}
+	return nil
+}
+
+// lookupSchemaProperty returns the schema of a key of an object schema, nil if the schema accepts any key, or a
+// message if the key is not defined
+func lookupSchemaProperty(schema map[string]interface{}, definitions map[string]interface{}, key string) (map[string]interface{}, string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, definitionReferencePrefix)].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+
+	properties, hasProperties := schema["properties"].(map[string]interface{})
+	if property, ok := properties[key].(map[string]interface{}); ok {
+		return property, ""
+	}
+	switch additional := schema["additionalProperties"].(type) {
+	case map[string]interface{}:
+		return additional, ""
+	case bool:
+		if additional {
+			return nil, ""
+		}
+		return nil, fmt.Sprintf("unknown field %q", key)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		alternatives, _ := schema[keyword].([]interface{})
+		for _, alternative := range alternatives {
+			if alternativeSchema, ok := alternative.(map[string]interface{}); ok {
+				if property, message := lookupSchemaProperty(alternativeSchema, definitions, key); message == "" {
+					return property, ""
+				}
+			}
+		}
+	}
+
+	if hasProperties {
+		return nil, fmt.Sprintf("unknown field %q", key)
+	}
+	// Keys of scalar values are reported as type mismatches by the validation
+	return nil, ""
+}
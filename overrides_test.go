@@ -0,0 +1,76 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePropertyOverride(t *testing.T) {
+	tests := []struct {
+		override string
+		expected *PropertyOverride
+	}{
+		{override: "--set=exporters.otlp.endpoint=backend:4317", expected: &PropertyOverride{Keys: []string{"exporters", "otlp", "endpoint"}, Value: "backend:4317"}},
+		{override: "exporters::otlp/2::timeout=10s", expected: &PropertyOverride{Keys: []string{"exporters", "otlp/2", "timeout"}, Value: "10s"}},
+		{override: "processors.batch.send_batch_size=100", expected: &PropertyOverride{Keys: []string{"processors", "batch", "send_batch_size"}, Value: 100}},
+		{override: "service.pipelines.traces.exporters=[otlp, debug]", expected: &PropertyOverride{Keys: []string{"service", "pipelines", "traces", "exporters"}, Value: []interface{}{"otlp", "debug"}}},
+		{override: "exporters.otlp.sending_queue=", expected: &PropertyOverride{Keys: []string{"exporters", "otlp", "sending_queue"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.override, func(t *testing.T) {
+			override, err := ParsePropertyOverride(test.override)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, override)
+		})
+	}
+
+	_, err := ParsePropertyOverride("exporters.otlp.endpoint")
+	assert.EqualError(t, err, `invalid override "exporters.otlp.endpoint", expected key=value`)
+	_, err = ParsePropertyOverride("exporters..endpoint=x")
+	assert.EqualError(t, err, `invalid override "exporters..endpoint=x", the key must not have empty parts`)
+}
+
+func TestValidateWithOverrides(t *testing.T) {
+	sm := NewSchemaManager()
+
+	result, err := sm.ValidateWithOverrides([]byte(layeredBase), []string{
+		"--set=exporters.otlp.endpoint=prod-backend:4317",
+		"exporters::otlp::compression=zstd",
+	}, "0.139.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), result.Errors)
+
+	result, err = sm.ValidateWithOverrides([]byte(layeredBase), []string{
+		"--set=exporters.otlp.endpoit=prod-backend:4317",
+		"--set=processors.batch.timeout.seconds=5",
+		"--set=processors.batch.send_batch_size=many",
+		"--set=extensions.nope.x=1",
+	}, "0.139.0")
+	require.NoError(t, err)
+	assert.False(t, result.Valid())
+
+	errors := make(map[string]ConfigValidationError)
+	for _, validationError := range result.Errors {
+		errors[validationError.Layer+" "+validationError.Path] = validationError
+	}
+
+	unknownField := errors["--set=exporters.otlp.endpoit=prod-backend:4317 exporters.otlp"]
+	assert.Equal(t, ErrorCodeUnknownField, unknownField.Code)
+	assert.Equal(t, `unknown field "endpoit"`, unknownField.Message)
+	assert.Nil(t, unknownField.Position)
+
+	scalar := errors["--set=processors.batch.timeout.seconds=5 processors.batch.timeout"]
+	assert.Equal(t, ErrorCodeInvalidType, scalar.Code)
+
+	mismatch := errors["--set=processors.batch.send_batch_size=many processors.batch.send_batch_size"]
+	assert.Equal(t, ErrorCodeInvalidType, mismatch.Code)
+	assert.Nil(t, mismatch.Position)
+
+	unknownComponent := errors["--set=extensions.nope.x=1 extensions.nope"]
+	assert.Equal(t, ErrorCodeUnknownComponent, unknownComponent.Code)
+
+	_, err = sm.ValidateWithOverrides([]byte(layeredBase), []string{"exporters"}, "0.139.0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package collectorconfigschema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Formats of schema properties holding patterns, see schemagen.FormatRegex and schemagen.FormatGlob
+const (
+	formatRegex = "regex"
+	formatGlob  = "glob"
+)
+
+// matchTypeRegexp is the match_type of filter match properties whose values are regular expressions
+const matchTypeRegexp = "regexp"
+
+// validatePatterns compiles the regular expressions and globs of a component configuration and returns the invalid ones.
+// Patterns are found by the format of their schema properties and in match properties with "match_type: regexp".
+func validatePatterns(schema map[string]interface{}, config interface{}) []semanticError {
+	var patternErrors []semanticError
+	definitions, _ := schema["$defs"].(map[string]interface{})
+	walkPatterns(schema, definitions, nil, config, &patternErrors)
+	return patternErrors
+}
+
+// walkPatterns validates the patterns of a single value and its children
+func walkPatterns(schema map[string]interface{}, definitions map[string]interface{}, path []string, value interface{}, patternErrors *[]semanticError) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+	if alternative := unionAlternative(schema, definitions, value); alternative != nil {
+		walkPatterns(alternative, definitions, path, value, patternErrors)
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		if format, ok := schema["format"].(string); ok {
+			checkPattern(format, path, v, patternErrors)
+		}
+	case map[string]interface{}:
+		if v["match_type"] == matchTypeRegexp {
+			checkMatchProperties(path, v, patternErrors)
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for _, key := range sortedKeys(v) {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			if propertySchema != nil {
+				walkPatterns(propertySchema, definitions, appendPath(path, key), v[key], patternErrors)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				walkPatterns(items, definitions, appendPath(path, fmt.Sprint(i)), item, patternErrors)
+			}
+		}
+	}
+}
+
+// checkMatchProperties compiles the values of filter match properties, e.g. services, span_names and metric_names,
+// and the values of their attribute and library matchers. Attribute keys are always matched exactly.
+func checkMatchProperties(path []string, properties map[string]interface{}, patternErrors *[]semanticError) {
+	for _, key := range sortedKeys(properties) {
+		if key == "match_type" {
+			continue
+		}
+		switch v := properties[key].(type) {
+		case string:
+			checkPattern(formatRegex, appendPath(path, key), v, patternErrors)
+		case []interface{}:
+			for i, item := range v {
+				itemPath := appendPath(path, key, fmt.Sprint(i))
+				switch itemValue := item.(type) {
+				case string:
+					checkPattern(formatRegex, itemPath, itemValue, patternErrors)
+				case map[string]interface{}:
+					for _, field := range sortedKeys(itemValue) {
+						if s, ok := itemValue[field].(string); ok && field != "key" {
+							checkPattern(formatRegex, appendPath(itemPath, field), s, patternErrors)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkPattern compiles a regular expression or glob and records it if it is invalid, other formats are ignored
+func checkPattern(format string, path []string, pattern string, patternErrors *[]semanticError) {
+	var message string
+	switch format {
+	case formatRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			message = fmt.Sprintf("invalid regular expression: %v", err)
+		}
+	case formatGlob:
+		if err := validateGlob(pattern); err != nil {
+			message = fmt.Sprintf("invalid glob pattern: %v", err)
+		}
+	}
+	if message == "" {
+		return
+	}
+
+	*patternErrors = append(*patternErrors, semanticError{
+		path:      path,
+		errorType: "pattern_syntax",
+		value:     pattern,
+		message:   message,
+	})
+}
+
+// validateGlob checks the syntax of a glob as matched by the filelog receiver: "*", "**", "?",
+// character classes like "[a-z]" or "[!0-9]", alternatives like "{a,b}" and "\" escapes
+func validateGlob(pattern string) error {
+	braces := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i+1 == len(pattern) {
+				return errors.New("trailing escape character")
+			}
+			i++
+		case '[':
+			end := i + 1
+			if end < len(pattern) && (pattern[end] == '!' || pattern[end] == '^') {
+				end++
+			}
+			closing := strings.IndexByte(pattern[end:], ']')
+			if closing <= 0 {
+				return fmt.Errorf("unterminated character class at offset %d", i)
+			}
+			i = end + closing
+		case '{':
+			braces++
+		case '}':
+			if braces > 0 {
+				braces--
+			}
+		}
+	}
+	if braces > 0 {
+		return errors.New("unterminated alternatives, missing '}'")
+	}
+	return nil
+}
+
+// withoutRegexFormatErrors returns a result without the generic format errors of regular expressions,
+// they are reported with the compile error by validatePatterns
+func withoutRegexFormatErrors(result *gojsonschema.Result) *gojsonschema.Result {
+	filtered := &gojsonschema.Result{}
+	for _, resultError := range result.Errors() {
+		if isRegexFormatError(resultError) {
+			continue
+		}
+		filtered.AddError(resultError, resultError.Details())
+	}
+	return filtered
+}
+
+// isRegexFormatError checks if a schema validation error is a regular expression that did not compile
+func isRegexFormatError(resultError gojsonschema.ResultError) bool {
+	return resultError.Type() == "format" && resultError.Details()["format"] == formatRegex
+}
+
+// appendPath returns a copy of a path extended with segments, so sibling paths do not share a backing array
+func appendPath(path []string, segments ...string) []string {
+	return append(append(make([]string, 0, len(path)+len(segments)), path...), segments...)
+}
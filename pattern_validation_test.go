@@ -0,0 +1,107 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_ValidateComponentJSON_Patterns(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`{
+		"actions": [
+			{"key": "user", "action": "extract", "pattern": "^/api/(?P<version>v[0-9]+"},
+			{"key": "path", "action": "extract", "pattern": "^/api/(?P<version>v[0-9]+)/"}
+		]
+	}`)
+
+	// Without the option the schema only reports that the format does not match
+	result, err := manager.ValidateComponentJSON(ComponentTypeProcessor, "attributes", "0.138.0", config)
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "format", result.Errors()[0].Type())
+
+	result, err = manager.ValidateComponentJSON(ComponentTypeProcessor, "attributes", "0.138.0", config, ValidatePatterns())
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "actions.0.pattern", result.Errors()[0].Field())
+	assert.Equal(t, "pattern_syntax", result.Errors()[0].Type())
+	assert.Equal(t, "invalid regular expression: error parsing regexp: missing closing ): `^/api/(?P<version>v[0-9]+`", result.Errors()[0].Description())
+}
+
+func TestSchemaManager_ValidateCollectorConfig_Patterns(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlpjsonfile:
+    include: [/var/log/pods/*/*/*.log, "/var/log/app/[a-z.log"]
+    exclude: ["/var/log/pods/{kube-system,monitoring}_*/**"]
+processors:
+  filter:
+    spans:
+      include:
+        match_type: regexp
+        services: ["checkout-.*", "payment-(eu|us"]
+        libraries:
+          - name: "io.opentelemetry.(contrib"
+      exclude:
+        match_type: strict
+        services: ["cart-("]
+  redaction:
+    blocked_values: ["4[0-9]{12}(?:[0-9]{3})?", "[a-z"]
+exporters:
+  debug:
+service:
+  pipelines:
+    logs:
+      receivers: [otlpjsonfile]
+      exporters: [debug]
+    traces:
+      receivers: [otlpjsonfile]
+      processors: [filter, redaction]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.138.0", ValidatePatterns())
+	require.NoError(t, err)
+
+	var messages []string
+	for _, validationError := range result.Errors {
+		messages = append(messages, validationError.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"receivers.otlpjsonfile.include.1: invalid glob pattern: unterminated character class at offset 13",
+		"processors.filter.spans.include.libraries.0.name: invalid regular expression: error parsing regexp: missing closing ): `io.opentelemetry.(contrib`",
+		"processors.filter.spans.include.services.1: invalid regular expression: error parsing regexp: missing closing ): `payment-(eu|us`",
+		"processors.redaction.blocked_values.1: invalid regular expression: error parsing regexp: missing closing ]: `[a-z`",
+	}, messages)
+}
+
+func TestValidateGlob(t *testing.T) {
+	for _, pattern := range []string{
+		"/var/log/*.log",
+		"/var/log/**/*.log",
+		"/var/log/app-?.log",
+		"/var/log/[a-z]*.log",
+		"/var/log/[!.]*",
+		"/var/log/{app,web}/*.log",
+		`/var/log/\[literal\].log`,
+		"/var/log/}",
+	} {
+		assert.NoError(t, validateGlob(pattern), pattern)
+	}
+
+	for pattern, message := range map[string]string{
+		"/var/log/[a-z.log":  "unterminated character class at offset 9",
+		"/var/log/[]":        "unterminated character class at offset 9",
+		"/var/log/{app,web/": "unterminated alternatives, missing '}'",
+		`/var/log/app\`:      "trailing escape character",
+	} {
+		err := validateGlob(pattern)
+		require.Error(t, err, pattern)
+		assert.Equal(t, message, err.Error())
+	}
+}
@@ -0,0 +1,237 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineRecipe describes the pipelines of a collector configuration generated by GeneratePipeline
+type PipelineRecipe struct {
+	// Pipelines maps pipeline IDs to their components, e.g. "traces" or "metrics/internal"
+	Pipelines map[string]RecipePipeline `json:"pipelines" yaml:"pipelines"`
+	// Extensions are declared and enabled in the service, e.g. health_check
+	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	// Config is merged into the generated configuration, e.g. {"exporters": {"otlphttp": {"endpoint": "https://backend"}}}
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// RecipePipeline lists the component IDs of a pipeline. Connectors are listed as the exporter of one pipeline and
+// the receiver of another, they are declared in the connectors section.
+type RecipePipeline struct {
+	Receivers  []string `json:"receivers" yaml:"receivers"`
+	Processors []string `json:"processors,omitempty" yaml:"processors,omitempty"`
+	Exporters  []string `json:"exporters" yaml:"exporters"`
+}
+
+// ParseRecipePipeline parses a pipeline in "receivers -> processors -> exporters" form, e.g.
+// "otlp -> memory_limiter, batch -> otlphttp, debug". The first step lists the receivers and the last step the
+// exporters, the steps in between list the processors in order.
+func ParseRecipePipeline(pipeline string) (RecipePipeline, error) {
+	steps := strings.Split(pipeline, "->")
+	if len(steps) < 2 {
+		return RecipePipeline{}, fmt.Errorf("invalid pipeline %q, expected receivers -> processors -> exporters", pipeline)
+	}
+
+	var parsed [][]string
+	for _, step := range steps {
+		var ids []string
+		for _, id := range strings.Split(step, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return RecipePipeline{}, fmt.Errorf("invalid pipeline %q, every step needs a component", pipeline)
+		}
+		parsed = append(parsed, ids)
+	}
+
+	recipe := RecipePipeline{Receivers: parsed[0], Exporters: parsed[len(parsed)-1]}
+	for _, ids := range parsed[1 : len(parsed)-1] {
+		recipe.Processors = append(recipe.Processors, ids...)
+	}
+	return recipe, nil
+}
+
+// UnmarshalYAML decodes a pipeline in "receivers -> processors -> exporters" form (see ParseRecipePipeline) or as a
+// map of the component lists like the pipelines of the service
+func (p *RecipePipeline) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		pipeline, err := ParseRecipePipeline(node.Value)
+		if err != nil {
+			return err
+		}
+		*p = pipeline
+		return nil
+	}
+
+	type plain RecipePipeline
+	return node.Decode((*plain)(p))
+}
+
+// ParsePipelineRecipe parses a recipe in YAML or JSON, e.g.
+//
+//	pipelines:
+//	  traces: otlp -> batch -> otlphttp
+//	extensions: [health_check]
+func ParsePipelineRecipe(data []byte) (*PipelineRecipe, error) {
+	var recipe PipelineRecipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline recipe: %w", err)
+	}
+	recipe.Config, _ = normalizeYAMLValue(recipe.Config).(map[string]interface{})
+	return &recipe, nil
+}
+
+// GeneratePipeline generates a complete collector configuration (YAML) from a recipe: the components of the
+// pipelines and the extensions are declared with their default configuration recorded in the schemas, the pipelines
+// are wired in the service and the Config of the recipe is merged in. Components that only exist as a connector are
+// declared as connectors. The configuration is validated strictly before it is returned and the components must
+// support the signal of their pipelines, an error lists the validation errors.
+func (sm *SchemaManager) GeneratePipeline(recipe *PipelineRecipe, version string) ([]byte, error) {
+	if len(recipe.Pipelines) == 0 {
+		return nil, fmt.Errorf("pipeline recipe has no pipelines")
+	}
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]interface{})
+	declare := func(componentType ComponentType, id string) error {
+		section := componentTypeSection(componentType)
+		declared, _ := config[section].(map[string]interface{})
+		if declared == nil {
+			declared = make(map[string]interface{})
+			config[section] = declared
+		}
+		if _, ok := declared[id]; ok {
+			return nil
+		}
+
+		componentID, err := ParseComponentID(id)
+		if err != nil {
+			return err
+		}
+		schema, err := sm.GetComponentSchema(componentType, componentID.Component, version)
+		if err != nil {
+			return err
+		}
+		declared[id] = applyComponentDefaults(schema, nil)
+		return nil
+	}
+	// Receivers and exporters that are not available as such are connectors
+	pipelineComponentType := func(componentType ComponentType, id string) ComponentType {
+		name := componentName(id)
+		if !contains(components[componentType], name) && contains(components[ComponentTypeConnector], name) {
+			return ComponentTypeConnector
+		}
+		return componentType
+	}
+
+	pipelineIDs := make([]string, 0, len(recipe.Pipelines))
+	for pipelineID := range recipe.Pipelines {
+		pipelineIDs = append(pipelineIDs, pipelineID)
+	}
+	sort.Strings(pipelineIDs)
+
+	// The validator only checks the signals of profiles pipelines, the components of the other pipelines are checked
+	// while they are wired
+	signals := &ConfigValidationResult{}
+	pipelines := make(map[string]interface{})
+	for _, pipelineID := range pipelineIDs {
+		pipeline := recipe.Pipelines[pipelineID]
+		signal := componentName(pipelineID)
+		lists := []struct {
+			name          string
+			componentType ComponentType
+			ids           []string
+		}{
+			{"receivers", ComponentTypeReceiver, pipeline.Receivers},
+			{"processors", ComponentTypeProcessor, pipeline.Processors},
+			{"exporters", ComponentTypeExporter, pipeline.Exporters},
+		}
+
+		wired := make(map[string]interface{})
+		for _, list := range lists {
+			if len(list.ids) == 0 {
+				continue
+			}
+			ids := make([]interface{}, 0, len(list.ids))
+			for i, id := range list.ids {
+				componentType := pipelineComponentType(list.componentType, id)
+				if err := declare(componentType, id); err != nil {
+					return nil, fmt.Errorf("pipeline %s: %w", pipelineID, err)
+				}
+				ids = append(ids, id)
+
+				section := componentTypeSection(componentType)
+				metadata := sm.componentMetadata(context.Background(), section, id, version)
+				if signal == "profiles" || metadata == nil || len(metadata.Signals) == 0 || supportsPipelineSignal(metadata, signal, section, list.name) {
+					continue
+				}
+				path := fmt.Sprintf("%s.pipelines.%s.%s[%d]", sectionService, pipelineID, list.name, i)
+				switch {
+				case componentType != ComponentTypeConnector:
+					signals.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "%s %q does not support the %s signal", componentType, id, signal)
+				case list.name == "receivers":
+					signals.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "connector %q does not emit the %s signal", id, signal)
+				default:
+					signals.addErrorWithCode(ErrorCodeUnsupportedSignal, path, "connector %q does not consume the %s signal", id, signal)
+				}
+			}
+			wired[list.name] = ids
+		}
+		pipelines[pipelineID] = wired
+	}
+
+	service := map[string]interface{}{"pipelines": pipelines}
+	if len(recipe.Extensions) > 0 {
+		enabled := make([]interface{}, 0, len(recipe.Extensions))
+		for _, id := range recipe.Extensions {
+			if err := declare(ComponentTypeExtension, id); err != nil {
+				return nil, err
+			}
+			enabled = append(enabled, id)
+		}
+		service["extensions"] = enabled
+	}
+	config[sectionService] = service
+
+	if recipe.Config != nil {
+		config = mergeDefaults(config, deepCopyValue(recipe.Config)).(map[string]interface{})
+	}
+
+	data, err := encodeYAML(config)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sm.ValidateCollectorConfig(data, version, Strict())
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(result.Errors, signals.Errors...)
+	if !result.Valid() {
+		var messages []string
+		for _, validationError := range result.Errors {
+			messages = append(messages, validationError.String())
+		}
+		return nil, fmt.Errorf("generated collector configuration is invalid: %s", strings.Join(messages, "; "))
+	}
+	return data, nil
+}
+
+// componentTypeSection returns the configuration section declaring components of a type, e.g. receivers
+func componentTypeSection(componentType ComponentType) string {
+	for _, cs := range componentSections {
+		if cs.componentType == componentType {
+			return cs.section
+		}
+	}
+	return string(componentType) + "s"
+}
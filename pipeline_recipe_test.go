@@ -0,0 +1,116 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRecipePipeline(t *testing.T) {
+	pipeline, err := ParseRecipePipeline("otlp, zipkin -> memory_limiter -> batch/traces -> otlphttp")
+	require.NoError(t, err)
+	assert.Equal(t, RecipePipeline{
+		Receivers:  []string{"otlp", "zipkin"},
+		Processors: []string{"memory_limiter", "batch/traces"},
+		Exporters:  []string{"otlphttp"},
+	}, pipeline)
+
+	pipeline, err = ParseRecipePipeline("otlp -> debug")
+	require.NoError(t, err)
+	assert.Nil(t, pipeline.Processors)
+
+	_, err = ParseRecipePipeline("otlp")
+	assert.ErrorContains(t, err, "expected receivers -> processors -> exporters")
+	_, err = ParseRecipePipeline("otlp -> , -> debug")
+	assert.ErrorContains(t, err, "every step needs a component")
+}
+
+func TestParsePipelineRecipe(t *testing.T) {
+	recipe, err := ParsePipelineRecipe([]byte(`
+pipelines:
+  traces: otlp -> batch -> otlphttp
+  metrics:
+    receivers: [otlp]
+    exporters: [debug]
+extensions: [health_check]
+config:
+  exporters:
+    otlphttp:
+      endpoint: https://backend:4318
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch"}, recipe.Pipelines["traces"].Processors)
+	assert.Equal(t, []string{"debug"}, recipe.Pipelines["metrics"].Exporters)
+	assert.Equal(t, []string{"health_check"}, recipe.Extensions)
+	assert.Equal(t, map[string]interface{}{"exporters": map[string]interface{}{"otlphttp": map[string]interface{}{"endpoint": "https://backend:4318"}}}, recipe.Config)
+
+	_, err = ParsePipelineRecipe([]byte("pipelines:\n  traces: otlp\n"))
+	assert.ErrorContains(t, err, "failed to parse pipeline recipe")
+}
+
+func TestSchemaManager_GeneratePipeline(t *testing.T) {
+	manager := NewSchemaManager()
+	recipe, err := ParsePipelineRecipe([]byte(`
+pipelines:
+  traces: otlp -> memory_limiter, batch -> otlphttp, spanmetrics
+  metrics: spanmetrics -> batch -> prometheus
+extensions: [health_check]
+config:
+  exporters:
+    otlphttp:
+      endpoint: https://backend:4318
+`))
+	require.NoError(t, err)
+
+	data, err := manager.GeneratePipeline(recipe, "0.139.0")
+	require.NoError(t, err)
+
+	var config map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &config))
+	assert.Equal(t, map[string]interface{}{"otlp": map[string]interface{}{}}, config["receivers"])
+	assert.Equal(t, map[string]interface{}{"spanmetrics": map[string]interface{}{}}, config["connectors"], "Components that are only connectors are declared as connectors")
+	assert.Equal(t, map[string]interface{}{
+		"otlphttp":   map[string]interface{}{"endpoint": "https://backend:4318"},
+		"prometheus": map[string]interface{}{},
+	}, config["exporters"])
+	assert.Len(t, config["processors"], 2)
+	assert.Equal(t, map[string]interface{}{"health_check": map[string]interface{}{}}, config["extensions"])
+
+	service := config["service"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"health_check"}, service["extensions"])
+	traces := service["pipelines"].(map[string]interface{})["traces"]
+	assert.Equal(t, map[string]interface{}{
+		"receivers":  []interface{}{"otlp"},
+		"processors": []interface{}{"memory_limiter", "batch"},
+		"exporters":  []interface{}{"otlphttp", "spanmetrics"},
+	}, traces)
+
+	result, err := manager.ValidateCollectorConfig(data, "0.139.0", Strict())
+	require.NoError(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestSchemaManager_GeneratePipeline_Invalid(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.GeneratePipeline(&PipelineRecipe{Pipelines: map[string]RecipePipeline{
+		"logs": {Receivers: []string{"otlp"}, Exporters: []string{"prometheus"}},
+	}}, "0.139.0")
+	assert.EqualError(t, err, `generated collector configuration is invalid: service.pipelines.logs.exporters[0]: exporter "prometheus" does not support the logs signal`)
+
+	_, err = manager.GeneratePipeline(&PipelineRecipe{
+		Pipelines: map[string]RecipePipeline{"traces": {Receivers: []string{"otlp"}, Exporters: []string{"debug"}}},
+		Config:    map[string]interface{}{"exporters": map[string]interface{}{"debug": map[string]interface{}{"verbosityy": "basic"}}},
+	}, "0.139.0")
+	assert.EqualError(t, err, `generated collector configuration is invalid: exporters.debug: unknown field "verbosityy"`)
+
+	_, err = manager.GeneratePipeline(&PipelineRecipe{Pipelines: map[string]RecipePipeline{
+		"traces": {Receivers: []string{"otlp"}, Exporters: []string{"doesnotexist"}},
+	}}, "0.139.0")
+	assert.ErrorContains(t, err, "pipeline traces: ")
+
+	_, err = manager.GeneratePipeline(&PipelineRecipe{}, "0.139.0")
+	assert.EqualError(t, err, "pipeline recipe has no pipelines")
+}
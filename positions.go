@@ -0,0 +1,117 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a location in a YAML or JSON document, lines and columns start at 1
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String returns the position in "line:column" form
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// yamlPositions records the position of every node of a parsed document by its dot separated path. Map entries are
+// located at their key, list items at the item, e.g. "service.pipelines.traces.receivers[0]".
+func yamlPositions(document *yaml.Node) map[string]Position {
+	positions := make(map[string]Position)
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		recordYAMLPositions("", document.Content[0], positions)
+	}
+	return positions
+}
+
+// recordYAMLPositions records the positions of the children of a node at path
+func recordYAMLPositions(path string, node *yaml.Node, positions map[string]Position) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			// Merge keys (<<) add the entries of another mapping
+			if key.Value == "<<" {
+				recordYAMLPositions(path, value, positions)
+				continue
+			}
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			if _, exists := positions[childPath]; !exists {
+				positions[childPath] = Position{Line: key.Line, Column: key.Column}
+			}
+			recordYAMLPositions(childPath, value, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			positions[itemPath] = Position{Line: item.Line, Column: item.Column}
+			recordYAMLPositions(itemPath, item, positions)
+		}
+	}
+}
+
+// addPositions sets the position of errors without one to the position of their path in the document, errors of
+// missing fields are located at the closest parent in the document
+func (r *ConfigValidationResult) addPositions(positions map[string]Position) {
+	for i := range r.Errors {
+		if r.Errors[i].Position == nil {
+			r.Errors[i].Position = lookupPosition(positions, r.Errors[i].Path)
+		}
+	}
+}
+
+// lookupPosition returns the position of a path, or of its closest parent in the document, or nil
+func lookupPosition(positions map[string]Position, path string) *Position {
+	for ; path != ""; path = parentPath(path) {
+		position, ok := positions[path]
+		if !ok {
+			position, ok = positions[bracketIndexes(path)]
+		}
+		if ok {
+			return &position
+		}
+	}
+	return nil
+}
+
+// parentPath removes the last key or list index of a dot separated path
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if i := strings.LastIndex(path, "["); i >= 0 {
+			return path[:i]
+		}
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// bracketIndexes converts the list indexes of the JSON schema validator to brackets, e.g. "processors.filter.traces.span.1"
+// to "processors.filter.traces.span[1]"
+func bracketIndexes(path string) string {
+	segments := strings.Split(path, ".")
+	var converted strings.Builder
+	for i, segment := range segments {
+		if i > 0 && segment != "" && strings.Trim(segment, "0123456789") == "" {
+			converted.WriteString("[" + segment + "]")
+			continue
+		}
+		if i > 0 {
+			converted.WriteString(".")
+		}
+		converted.WriteString(segment)
+	}
+	return converted.String()
+}
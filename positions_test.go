@@ -0,0 +1,60 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCollectorConfig_Positions(t *testing.T) {
+	manager := NewSchemaManager()
+
+	result, err := manager.ValidateCollectorConfig([]byte(`receivers:
+  otlp:
+processors:
+  filter:
+    traces:
+      span:
+        - 'attributes["a"] == "b"'
+        - 'attributes["a"] =='
+exporters:
+  debug:
+    verbosity: loud
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, unknown]
+      exporters: [debug]
+`), "0.139.0", ValidateOTTL())
+	require.NoError(t, err)
+
+	positions := make(map[string]string)
+	for _, validationError := range result.Errors {
+		require.NotNil(t, validationError.Position, validationError.Path)
+		positions[validationError.Path] = validationError.Position.String()
+	}
+	assert.Equal(t, map[string]string{
+		"processors.filter.traces.span.1":       "8:11",
+		"exporters.debug.verbosity":             "11:5",
+		"service.pipelines.traces.receivers[1]": "15:25",
+	}, positions)
+}
+
+func TestConfigValidationResult_AddPositions(t *testing.T) {
+	result := &ConfigValidationResult{Errors: []ConfigValidationError{
+		{Path: "receivers.otlp.protocols.grpc.endpoint"},
+		{Path: "unknown"},
+		{Path: ""},
+		{Path: "receivers.otlp", Position: &Position{Line: 1, Column: 1}},
+	}}
+	result.addPositions(map[string]Position{
+		"receivers":      {Line: 2, Column: 1},
+		"receivers.otlp": {Line: 3, Column: 3},
+	})
+
+	assert.Equal(t, &Position{Line: 3, Column: 3}, result.Errors[0].Position)
+	assert.Nil(t, result.Errors[1].Position)
+	assert.Nil(t, result.Errors[2].Position)
+	assert.Equal(t, &Position{Line: 1, Column: 1}, result.Errors[3].Position)
+}
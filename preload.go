@@ -0,0 +1,111 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PreloadOption configures how schemas are preloaded
+type PreloadOption func(*preloadOptions)
+
+// preloadOptions holds the settings applied by PreloadOption
+type preloadOptions struct {
+	concurrency int
+}
+
+// WithConcurrency parses the schemas with the given number of goroutines, schemas are parsed sequentially by default
+func WithConcurrency(concurrency int) PreloadOption {
+	return func(options *preloadOptions) {
+		options.concurrency = concurrency
+	}
+}
+
+// Preload parses all embedded component schemas of a version into the cache, so the first validations of a
+// latency sensitive service do not pay the parse cost. The cache must be large enough to hold the schemas,
+// see WithCacheSize. Schemas that cannot be parsed are returned as one error.
+func (sm *SchemaManager) Preload(version string, opts ...PreloadOption) error {
+	return sm.PreloadContext(context.Background(), version, opts...)
+}
+
+// PreloadContext is Preload with a context, preloading stops with the error of the context when it is canceled.
+// Schemas parsed before stay cached.
+func (sm *SchemaManager) PreloadContext(ctx context.Context, version string, opts ...PreloadOption) error {
+	options := &preloadOptions{concurrency: 1}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	components, err := sm.listEmbeddedComponents(version)
+	if err != nil {
+		return fmt.Errorf("failed to list components of version %s: %w", version, err)
+	}
+
+	type component struct {
+		componentType ComponentType
+		name          string
+	}
+	work := make(chan component)
+	go func() {
+		defer close(work)
+		for componentType, names := range components {
+			for _, name := range names {
+				select {
+				case work <- component{componentType: componentType, name: name}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := 0; i < max(options.concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range work {
+				if _, err := sm.GetComponentSchema(c.componentType, c.name, version); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("%s %s: %w", c.componentType, c.name, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// PreloadAll preloads the component schemas of every embedded version, see Preload
+func (sm *SchemaManager) PreloadAll(opts ...PreloadOption) error {
+	return sm.PreloadAllContext(context.Background(), opts...)
+}
+
+// PreloadAllContext is PreloadAll with a context, see PreloadContext
+func (sm *SchemaManager) PreloadAllContext(ctx context.Context, opts ...PreloadOption) error {
+	versions, err := sm.GetAllVersions()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, version := range versions {
+		if err := sm.PreloadContext(ctx, version, opts...); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_Preload(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.Preload("0.139.0", WithConcurrency(4)))
+
+	components, err := manager.ListAvailableComponents("0.139.0")
+	require.NoError(t, err)
+	count := 0
+	for _, names := range components {
+		count += len(names)
+	}
+	assert.Equal(t, CacheStats{Misses: uint64(count), Size: count, Capacity: DefaultCacheSize}, manager.CacheStats())
+
+	// Validations are served from the cache
+	_, err = manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), manager.CacheStats().Hits)
+
+	assert.Error(t, manager.Preload("0.1.0"))
+}
+
+func TestSchemaManager_PreloadAll(t *testing.T) {
+	manager := NewSchemaManager(WithCacheSize(0))
+	require.NoError(t, manager.PreloadAll())
+
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+	count := 0
+	for _, version := range versions {
+		components, err := manager.ListAvailableComponents(version)
+		require.NoError(t, err)
+		for _, names := range components {
+			count += len(names)
+		}
+	}
+	assert.Equal(t, count, manager.CacheStats().Size)
+}
@@ -0,0 +1,408 @@
+// Package protoschema generates protocol buffer messages mirroring component JSON schemas, e.g. for a gRPC
+// management API that exposes collector configurations.
+package protoschema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// Well-known types used for values that cannot be expressed with proto3 scalars and messages
+const (
+	structType    = "google.protobuf.Struct"
+	valueType     = "google.protobuf.Value"
+	listValueType = "google.protobuf.ListValue"
+	structImport  = "google/protobuf/struct.proto"
+)
+
+// invalidIdentifierChars matches the characters that are not allowed in proto identifiers
+var invalidIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// Generator generates .proto files from component schemas
+type Generator struct {
+	packageName string
+}
+
+// NewGenerator creates a generator of .proto files with the given proto package.
+// An empty package name uses otelcol.config.v<version>, e.g. otelcol.config.v0_139_0.
+func NewGenerator(packageName string) *Generator {
+	return &Generator{packageName: packageName}
+}
+
+// Generate returns a .proto file with a message per selected component of a version, e.g. ReceiverOtlpConfig.
+// Nested configuration blocks and shared definitions become nested messages, descriptions become comments and
+// deprecated fields are marked with the deprecated option. Fields are numbered in the alphabetical order of
+// their names, so adding a field to a schema can renumber the fields after it.
+func (g *Generator) Generate(sm *collectorschema.SchemaManager, version string, selection map[collectorschema.ComponentType][]string) ([]byte, error) {
+	file := &protoFile{}
+
+	for _, componentType := range []collectorschema.ComponentType{
+		collectorschema.ComponentTypeReceiver,
+		collectorschema.ComponentTypeProcessor,
+		collectorschema.ComponentTypeExporter,
+		collectorschema.ComponentTypeConnector,
+		collectorschema.ComponentTypeExtension,
+	} {
+		names := append([]string(nil), selection[componentType]...)
+		sort.Strings(names)
+		for _, name := range names {
+			schema, err := sm.GetComponentSchema(componentType, name, version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get schema for %s %s v%s: %w", componentType, name, version, err)
+			}
+			message, err := file.componentMessage(componentType, name, schema.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert schema for %s %s: %w", componentType, name, err)
+			}
+			file.messages = append(file.messages, message)
+		}
+	}
+
+	packageName := g.packageName
+	if packageName == "" {
+		packageName = "otelcol.config.v" + strings.ReplaceAll(version, ".", "_")
+	}
+	return file.render(packageName, version), nil
+}
+
+// protoFile holds the messages of a generated file and whether the well-known struct types are used
+type protoFile struct {
+	messages   []*message
+	usesStruct bool
+}
+
+// message is a proto message with its nested messages
+type message struct {
+	name     string
+	comment  string
+	fields   []field
+	messages []*message
+	// names are the names of the nested messages, they are unique within a message
+	names map[string]bool
+}
+
+// field is a field of a proto message
+type field struct {
+	name       string
+	jsonName   string
+	typeName   string
+	repeated   bool
+	comment    string
+	deprecated bool
+}
+
+// converter converts the nodes of a single component schema
+type converter struct {
+	file        *protoFile
+	root        *message
+	definitions map[string]interface{}
+	// definitionMessages are the names of the messages generated for definitions, by reference
+	definitionMessages map[string]string
+}
+
+// componentMessage converts the schema of a component into a message named <Type><Name>Config
+func (f *protoFile) componentMessage(componentType collectorschema.ComponentType, componentName string, schema map[string]interface{}) (*message, error) {
+	root := newMessage(messageName(string(componentType)) + messageName(componentName) + "Config")
+	root.comment = fmt.Sprintf("%s is the configuration of the %s %s.", root.name, componentName, componentType)
+
+	c := &converter{
+		file:               f,
+		root:               root,
+		definitions:        make(map[string]interface{}),
+		definitionMessages: make(map[string]string),
+	}
+	if definitions, ok := schema["$defs"].(map[string]interface{}); ok {
+		for name, definition := range definitions {
+			c.definitions["#/$defs/"+name] = definition
+		}
+	}
+
+	if err := c.addFields(root, schema); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// newMessage creates an empty message
+func newMessage(name string) *message {
+	return &message{name: name, names: make(map[string]bool)}
+}
+
+// nestedName returns a unique name for a nested message of a message
+func (m *message) nestedName(name string) string {
+	unique := name
+	for i := 2; m.names[unique] || unique == m.name; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	m.names[unique] = true
+	return unique
+}
+
+// hasField returns whether a message has a field with the given name
+func (m *message) hasField(name string) bool {
+	for _, f := range m.fields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addFields adds a field for every property of an object schema to a message
+func (c *converter) addFields(m *message, schema map[string]interface{}) error {
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typeName, repeated, err := c.fieldType(m, name, property)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+
+		protoName := fieldName(name)
+		for i := 2; m.hasField(protoName); i++ {
+			protoName = fmt.Sprintf("%s_%d", fieldName(name), i)
+		}
+		jsonName := ""
+		if protoName != name {
+			jsonName = name
+		}
+		m.fields = append(m.fields, field{
+			name:       protoName,
+			jsonName:   jsonName,
+			typeName:   typeName,
+			repeated:   repeated,
+			comment:    fieldComment(property),
+			deprecated: property["deprecated"] == true,
+		})
+	}
+	return nil
+}
+
+// fieldType returns the proto type of a property, nested messages are added to the message of the property
+func (c *converter) fieldType(parent *message, name string, schema map[string]interface{}) (string, bool, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		typeName, err := c.definitionMessage(ref)
+		return typeName, false, err
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		return "string", false, nil
+	case "integer":
+		return "int64", false, nil
+	case "number":
+		return "double", false, nil
+	case "boolean":
+		return "bool", false, nil
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if items == nil {
+			return c.wellKnown(valueType), true, nil
+		}
+		if schemaType(items) == "array" {
+			return c.wellKnown(listValueType), true, nil
+		}
+		typeName, _, err := c.fieldType(parent, name+"_item", items)
+		if strings.HasPrefix(typeName, "map<") {
+			// Maps cannot be repeated
+			typeName = c.wellKnown(structType)
+		}
+		return typeName, true, err
+	case "object":
+		if properties, ok := schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+			nested := newMessage(parent.nestedName(messageName(name)))
+			if err := c.addFields(nested, schema); err != nil {
+				return "", false, err
+			}
+			parent.messages = append(parent.messages, nested)
+			return nested.name, false, nil
+		}
+		if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			valueTypeName, repeated, err := c.fieldType(parent, name+"_value", values)
+			if err != nil {
+				return "", false, err
+			}
+			// Map values cannot be repeated or maps
+			if repeated {
+				valueTypeName = c.wellKnown(listValueType)
+			} else if strings.HasPrefix(valueTypeName, "map<") {
+				valueTypeName = c.wellKnown(structType)
+			}
+			return fmt.Sprintf("map<string, %s>", valueTypeName), false, nil
+		}
+		return c.wellKnown(structType), false, nil
+	default:
+		// Unions like durations (string or integer) and untyped values
+		return c.wellKnown(valueType), false, nil
+	}
+}
+
+// definitionMessage returns the message of a definition, it is generated once per component in the root message
+// so recursive definitions reference their own message
+func (c *converter) definitionMessage(ref string) (string, error) {
+	if name, ok := c.definitionMessages[ref]; ok {
+		return name, nil
+	}
+	definition, ok := c.definitions[ref].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unresolvable $ref %q", ref)
+	}
+
+	if schemaType(definition) != "object" || definition["properties"] == nil {
+		typeName, repeated, err := c.fieldType(c.root, strings.TrimPrefix(ref, "#/$defs/"), definition)
+		if repeated {
+			typeName = c.wellKnown(listValueType)
+		}
+		c.definitionMessages[ref] = typeName
+		return typeName, err
+	}
+
+	nested := newMessage(c.root.nestedName(messageName(strings.TrimPrefix(ref, "#/$defs/"))))
+	nested.comment = fieldComment(definition)
+	// Definitions are referenced by their qualified name, nested messages of the referencing message may have the same name
+	qualifiedName := c.root.name + "." + nested.name
+	c.definitionMessages[ref] = qualifiedName
+	c.root.messages = append(c.root.messages, nested)
+	return qualifiedName, c.addFields(nested, definition)
+}
+
+// wellKnown returns a well-known type and records that the file imports it
+func (c *converter) wellKnown(typeName string) string {
+	c.file.usesStruct = true
+	return typeName
+}
+
+// schemaType returns the single type of a schema ignoring null, or an empty string for unions and untyped schemas
+func schemaType(schema map[string]interface{}) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []interface{}:
+		var types []string
+		for _, t := range value {
+			if s, ok := t.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		if len(types) == 1 {
+			return types[0]
+		}
+		return ""
+	default:
+		if schema["properties"] != nil || schema["additionalProperties"] != nil {
+			return "object"
+		}
+		return ""
+	}
+}
+
+// fieldComment returns the comment of a field from the description and the allowed values of its schema
+func fieldComment(schema map[string]interface{}) string {
+	comment, _ := schema["description"].(string)
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		allowed := make([]string, 0, len(values))
+		for _, value := range values {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+		comment = strings.TrimSpace(comment + "\nAllowed values: " + strings.Join(allowed, ", "))
+	}
+	return comment
+}
+
+// messageName returns an upper camel case message name, e.g. SendingQueue for sending_queue
+func messageName(name string) string {
+	var b strings.Builder
+	for _, part := range invalidIdentifierChars.Split(name, -1) {
+		for _, word := range strings.Split(part, "_") {
+			if word == "" {
+				continue
+			}
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			b.WriteString(string(runes))
+		}
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "M" + b.String()
+	}
+	return b.String()
+}
+
+// fieldName returns a valid proto field name, e.g. max_size for max-size
+func fieldName(name string) string {
+	sanitized := strings.Trim(invalidIdentifierChars.ReplaceAllString(name, "_"), "_")
+	if sanitized == "" || unicode.IsDigit(rune(sanitized[0])) {
+		return "f_" + sanitized
+	}
+	return sanitized
+}
+
+// render writes the .proto source of the file
+func (f *protoFile) render(packageName string, version string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated from the component schemas of collector %s. DO NOT EDIT.\n\n", version)
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", packageName)
+	if f.usesStruct {
+		fmt.Fprintf(&b, "\nimport \"%s\";\n", structImport)
+	}
+	for _, m := range f.messages {
+		b.WriteString("\n")
+		m.render(&b, "")
+	}
+	return []byte(b.String())
+}
+
+// render writes the .proto source of a message with the given indentation
+func (m *message) render(b *strings.Builder, indent string) {
+	writeComment(b, indent, m.comment)
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.name)
+	for i, f := range m.fields {
+		writeComment(b, indent+"  ", f.comment)
+		var options []string
+		if f.jsonName != "" {
+			options = append(options, fmt.Sprintf("json_name = %q", f.jsonName))
+		}
+		if f.deprecated {
+			options = append(options, "deprecated = true")
+		}
+		label := ""
+		if f.repeated {
+			label = "repeated "
+		}
+		fmt.Fprintf(b, "%s  %s%s %s = %d", indent, label, f.typeName, f.name, i+1)
+		if len(options) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(options, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	for _, nested := range m.messages {
+		b.WriteString("\n")
+		nested.render(b, indent+"  ")
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// writeComment writes a comment line per line of text
+func writeComment(b *strings.Builder, indent string, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight("// "+line, " "))
+	}
+}
@@ -0,0 +1,117 @@
+package protoschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+const inhouseSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "endpoint": {"type": "string", "description": "Endpoint to send data to"},
+    "timeout": {"type": ["string", "integer"], "description": "Duration string"},
+    "compression": {"type": "string", "enum": ["gzip", "none"]},
+    "legacy_mode": {"type": "boolean", "deprecated": true},
+    "ratio": {"type": "number"},
+    "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+    "attributes": {"type": "object"},
+    "rules": {"type": "array", "items": {"$ref": "#/$defs/rule"}},
+    "retry-on-failure": {
+      "type": "object",
+      "properties": {
+        "enabled": {"type": "boolean"},
+        "max_attempts": {"type": "integer"}
+      }
+    }
+  },
+  "$defs": {
+    "rule": {
+      "type": "object",
+      "description": "Rule routes matching data",
+      "properties": {
+        "condition": {"type": "string"},
+        "rules": {"type": "array", "items": {"$ref": "#/$defs/rule"}}
+      }
+    }
+  }
+}`
+
+const expectedProto = `// Code generated from the component schemas of collector 0.139.0. DO NOT EDIT.
+
+syntax = "proto3";
+
+package example.config;
+
+import "google/protobuf/struct.proto";
+
+// ExporterInhouseConfig is the configuration of the inhouse exporter.
+message ExporterInhouseConfig {
+  google.protobuf.Struct attributes = 1;
+  // Allowed values: gzip, none
+  string compression = 2;
+  // Endpoint to send data to
+  string endpoint = 3;
+  map<string, string> headers = 4;
+  bool legacy_mode = 5 [deprecated = true];
+  double ratio = 6;
+  RetryOnFailure retry_on_failure = 7 [json_name = "retry-on-failure"];
+  repeated ExporterInhouseConfig.Rule rules = 8;
+  // Duration string
+  google.protobuf.Value timeout = 9;
+
+  message RetryOnFailure {
+    bool enabled = 1;
+    int64 max_attempts = 2;
+  }
+
+  // Rule routes matching data
+  message Rule {
+    string condition = 1;
+    repeated ExporterInhouseConfig.Rule rules = 2;
+  }
+}
+`
+
+func TestGenerator_Generate(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(collectorschema.ComponentTypeExporter, "inhouse", "", []byte(inhouseSchema)))
+
+	proto, err := NewGenerator("example.config").Generate(manager, "0.139.0", map[collectorschema.ComponentType][]string{
+		collectorschema.ComponentTypeExporter: {"inhouse"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expectedProto, string(proto))
+}
+
+func TestGenerator_Generate_EmbeddedSchemas(t *testing.T) {
+	manager := collectorschema.NewSchemaManager()
+
+	proto, err := NewGenerator("").Generate(manager, "0.139.0", map[collectorschema.ComponentType][]string{
+		collectorschema.ComponentTypeReceiver:  {"otlp"},
+		collectorschema.ComponentTypeProcessor: {"batch"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(proto), "package otelcol.config.v0_139_0;")
+	assert.Contains(t, string(proto), "message ReceiverOtlpConfig {")
+	assert.Contains(t, string(proto), "message ProcessorBatchConfig {")
+	assert.Contains(t, string(proto), "  // Include propagates the incoming connection's metadata to downstream consumers.\n      bool include_metadata")
+
+	_, err = NewGenerator("").Generate(manager, "0.139.0", map[collectorschema.ComponentType][]string{
+		collectorschema.ComponentTypeReceiver: {"doesnotexist"},
+	})
+	assert.Error(t, err)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "SendingQueue", messageName("sending_queue"))
+	assert.Equal(t, "K8sAttributes", messageName("k8s.attributes"))
+	assert.Equal(t, "M0Value", messageName("0_value"))
+	assert.Equal(t, "max_size", fieldName("max-size"))
+	assert.Equal(t, "f_0", fieldName("0"))
+}
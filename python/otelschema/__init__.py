@@ -0,0 +1,165 @@
+"""Validate OpenTelemetry collector configurations against the component schemas.
+
+The package calls the Go library in process through the C shared library built from cmd/libotelschema (see
+``make libotelschema``). The library is looked up in the ``OTELSCHEMA_LIBRARY`` environment variable, next to this
+module and on the library path, in that order.
+
+    >>> import otelschema
+    >>> for error in otelschema.validate(open("config.yaml").read(), version="0.139.0"):
+    ...     print(error)
+    2:3: receivers.unknown: unknown receiver type "unknown"
+
+An empty version selects the latest embedded version.
+"""
+
+import ctypes
+import ctypes.util
+import json
+import os
+import sys
+from dataclasses import dataclass
+from typing import Any, Dict, List, Optional, Union
+
+__all__ = [
+    "Library",
+    "OtelSchemaError",
+    "ValidationError",
+    "component_schema",
+    "list_components",
+    "validate",
+    "versions",
+]
+
+_LIBRARY_NAME = "libotelschema.dylib" if sys.platform == "darwin" else "libotelschema.so"
+
+
+class OtelSchemaError(Exception):
+    """A call of the library failed, e.g. for an unknown component or a configuration that is not YAML."""
+
+
+@dataclass(frozen=True)
+class ValidationError:
+    """A problem of a collector configuration, see ConfigValidationError of the Go library."""
+
+    #: Dot separated location of the problem, e.g. "receivers.otlp.protocols"
+    path: str
+    message: str
+    #: Kind of problem for dedicated checks, e.g. "unknown-component", empty for schema violations
+    code: str = ""
+    #: Location of path in the configuration, 0 if the problem has no location
+    line: int = 0
+    column: int = 0
+
+    def __str__(self) -> str:
+        if self.line:
+            return f"{self.line}:{self.column}: {self.path}: {self.message}"
+        return f"{self.path}: {self.message}"
+
+
+class Library:
+    """The C shared library of the validator, it is safe to use from several threads."""
+
+    def __init__(self, path: Optional[str] = None):
+        self._lib = ctypes.CDLL(path or _find_library())
+        for name, argtypes in (
+            ("otelschema_validate_collector_config", [ctypes.c_char_p, ctypes.c_char_p]),
+            ("otelschema_get_component_schema", [ctypes.c_char_p, ctypes.c_char_p, ctypes.c_char_p]),
+            ("otelschema_list_components", [ctypes.c_char_p]),
+            ("otelschema_versions", []),
+        ):
+            function = getattr(self._lib, name)
+            function.argtypes = argtypes
+            # The response is released with otelschema_free, a c_char_p result would be copied and leaked
+            function.restype = ctypes.c_void_p
+        self._lib.otelschema_free.argtypes = [ctypes.c_void_p]
+        self._lib.otelschema_free.restype = None
+
+    def validate(self, config: Union[str, bytes, Dict[str, Any]], version: str = "") -> List[ValidationError]:
+        """Return the problems of a collector configuration in YAML or JSON, or of a parsed configuration."""
+        if isinstance(config, dict):
+            config = json.dumps(config)
+        if isinstance(config, str):
+            config = config.encode()
+        result = self._call("otelschema_validate_collector_config", config, version.encode())
+        errors = []
+        for error in result["errors"]:
+            position = error.get("position") or {}
+            errors.append(
+                ValidationError(
+                    path=error["path"],
+                    message=error["message"],
+                    code=error.get("code", ""),
+                    line=position.get("line", 0),
+                    column=position.get("column", 0),
+                )
+            )
+        return errors
+
+    def component_schema(self, component_type: str, component_name: str, version: str = "") -> Dict[str, Any]:
+        """Return the JSON schema of a component, e.g. component_schema("receiver", "otlp")."""
+        return self._call(
+            "otelschema_get_component_schema", component_type.encode(), component_name.encode(), version.encode()
+        )
+
+    def list_components(self, version: str = "") -> Dict[str, List[str]]:
+        """Return the names of the components of a version by type, e.g. {"receiver": ["otlp", ...], ...}."""
+        return self._call("otelschema_list_components", version.encode())
+
+    def versions(self) -> List[str]:
+        """Return the embedded versions, the latest version is last."""
+        return self._call("otelschema_versions")
+
+    def _call(self, name: str, *args: bytes) -> Any:
+        pointer = getattr(self._lib, name)(*args)
+        try:
+            response = json.loads(ctypes.string_at(pointer))
+        finally:
+            self._lib.otelschema_free(pointer)
+        if "error" in response:
+            raise OtelSchemaError(response["error"])
+        return response.get("result")
+
+
+def _find_library() -> str:
+    path = os.environ.get("OTELSCHEMA_LIBRARY")
+    if path:
+        return path
+    bundled = os.path.join(os.path.dirname(os.path.abspath(__file__)), _LIBRARY_NAME)
+    if os.path.exists(bundled):
+        return bundled
+    found = ctypes.util.find_library("otelschema")
+    if found:
+        return found
+    raise OtelSchemaError(
+        f"{_LIBRARY_NAME} not found, build it with make libotelschema and set OTELSCHEMA_LIBRARY to its path"
+    )
+
+
+_default: Optional[Library] = None
+
+
+def _library() -> Library:
+    global _default
+    if _default is None:
+        _default = Library()
+    return _default
+
+
+def validate(config: Union[str, bytes, Dict[str, Any]], version: str = "") -> List[ValidationError]:
+    """Return the problems of a collector configuration, see Library.validate."""
+    return _library().validate(config, version)
+
+
+def component_schema(component_type: str, component_name: str, version: str = "") -> Dict[str, Any]:
+    """Return the JSON schema of a component, see Library.component_schema."""
+    return _library().component_schema(component_type, component_name, version)
+
+
+def list_components(version: str = "") -> Dict[str, List[str]]:
+    """Return the names of the components of a version by type, see Library.list_components."""
+    return _library().list_components(version)
+
+
+def versions() -> List[str]:
+    """Return the embedded versions, see Library.versions."""
+    return _library().versions()
@@ -0,0 +1,53 @@
+"""Tests of the Python bindings, they run against the library built by make libotelschema."""
+
+import os
+import unittest
+
+import otelschema
+
+LIBRARY = os.environ.get("OTELSCHEMA_LIBRARY")
+
+
+@unittest.skipUnless(LIBRARY, "OTELSCHEMA_LIBRARY is not set, run make test-python")
+class OtelSchemaTest(unittest.TestCase):
+    def test_validate(self):
+        self.assertEqual(otelschema.validate("receivers:\n  otlp:\n", version="0.139.0"), [])
+        self.assertEqual(otelschema.validate({"receivers": {"otlp": None}}), [])
+
+        errors = otelschema.validate("receivers:\n  unknown:\n", version="0.139.0")
+        self.assertEqual(
+            errors,
+            [
+                otelschema.ValidationError(
+                    path="receivers.unknown",
+                    message='unknown receiver type "unknown"',
+                    code="unknown-component",
+                    line=2,
+                    column=3,
+                )
+            ],
+        )
+        self.assertEqual(str(errors[0]), '2:3: receivers.unknown: unknown receiver type "unknown"')
+
+        with self.assertRaises(otelschema.OtelSchemaError):
+            otelschema.validate("receivers: [", version="0.139.0")
+
+    def test_component_schema(self):
+        schema = otelschema.component_schema("receiver", "otlp", version="0.139.0")
+        self.assertIn("grpc", schema["properties"])
+
+        with self.assertRaisesRegex(otelschema.OtelSchemaError, "doesnotexist"):
+            otelschema.component_schema("receiver", "doesnotexist", version="0.139.0")
+
+    def test_list_components_and_versions(self):
+        components = otelschema.list_components("0.139.0")
+        self.assertIn("otlp", components["receiver"])
+        self.assertIn("batch", components["processor"])
+
+        versions = otelschema.versions()
+        self.assertIn("0.139.0", versions)
+        self.assertEqual(otelschema.list_components(), otelschema.list_components(versions[-1]))
+
+
+if __name__ == "__main__":
+    unittest.main()
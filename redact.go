@@ -0,0 +1,215 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces the values of secrets in redacted configurations
+const RedactedValue = "[REDACTED]"
+
+// RedactSecrets returns a collector configuration (YAML or JSON) with the values of secrets replaced by
+// RedactedValue, so support tooling can log effective configurations. Secrets are the fields marked writeOnly in
+// the component schemas, e.g. configopaque.String values, and, for schemas generated without the marker and
+// unknown components, fields and headers named like credentials, e.g. password or Authorization. Values read
+// from config providers, e.g. ${env:TOKEN}, are kept. Aliases are expanded so anchored values are redacted by the
+// keys they are used with, an anchored secret is redacted where it is anchored too. The configuration is returned as
+// YAML with its order and comments.
+func (sm *SchemaManager) RedactSecrets(config []byte, version string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse collector configuration: %w", err)
+	}
+	if document.Kind == 0 {
+		return config, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("collector configuration must be a map")
+	}
+	anchored := make(anchoredScalars)
+	expandAliases(root, anchored)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		section, body := root.Content[i].Value, root.Content[i+1]
+		componentType, isComponentSection := sectionComponentType(section)
+		if !isComponentSection || body.Kind != yaml.MappingNode {
+			// Service telemetry may hold secrets too, e.g. headers of the OTLP exporters of internal telemetry
+			redactNode(nil, nil, section, body)
+			continue
+		}
+
+		for j := 0; j+1 < len(body.Content); j += 2 {
+			id, component := body.Content[j].Value, body.Content[j+1]
+			var schema, definitions map[string]interface{}
+			if componentID, err := ParseComponentID(id); err == nil {
+				if componentSchema, err := sm.GetComponentSchema(componentType, componentID.Component, version); err == nil {
+					schema = componentSchema.Schema
+					definitions, _ = schema["$defs"].(map[string]interface{})
+				}
+			}
+			redactNode(schema, definitions, id, component)
+		}
+	}
+	anchored.redact()
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("failed to encode collector configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode collector configuration: %w", err)
+	}
+	return []byte(out.String()), nil
+}
+
+// redactNode redacts the secrets of a YAML node, schema is the schema of the node or nil and key is the
+// configuration key of the node
+func redactNode(schema map[string]interface{}, definitions map[string]interface{}, key string, node *yaml.Node) {
+	if ref, ok := schema["$ref"].(string); ok {
+		if definition, ok := definitions[strings.TrimPrefix(ref, definitionReferencePrefix)].(map[string]interface{}); ok {
+			schema = definition
+		}
+	}
+	// Unions, e.g. headers written as a map or as a list of name and value pairs, are resolved by the kind of the node
+	if alternative := unionAlternative(schema, definitions, nodeKindValue(node)); alternative != nil {
+		schema = alternative
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		writeOnly, _ := schema["writeOnly"].(bool)
+		if (writeOnly || isCredentialKey(key)) && node.Tag != "!!null" && !usesConfigProvider(node.Value) {
+			redactScalar(node)
+		}
+	case yaml.MappingNode:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		var headerName string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "name" {
+				headerName = node.Content[i+1].Value
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey, child := node.Content[i].Value, node.Content[i+1]
+			// Merge keys, e.g. <<: *defaults, add the keys of the merged maps to the map
+			if childKey == "<<" {
+				merged := []*yaml.Node{child}
+				if child.Kind == yaml.SequenceNode {
+					merged = child.Content
+				}
+				for _, m := range merged {
+					if m.Kind == yaml.AliasNode && m.Alias != nil {
+						m = m.Alias
+					}
+					redactNode(schema, definitions, key, m)
+				}
+				continue
+			}
+			propertySchema, ok := properties[childKey].(map[string]interface{})
+			if !ok {
+				propertySchema = additional
+			}
+			// Headers declared as lists of name and value pairs, e.g. in the otlphttp exporter
+			if childKey == "value" && isCredentialKey(headerName) {
+				childKey = headerName
+			}
+			redactNode(propertySchema, definitions, childKey, child)
+		}
+	case yaml.SequenceNode:
+		items, _ := schema["items"].(map[string]interface{})
+		for _, item := range node.Content {
+			redactNode(items, definitions, key, item)
+		}
+	}
+}
+
+// nodeKindValue returns an empty value of the kind of a YAML node, e.g. to select the alternative of a union
+func nodeKindValue(node *yaml.Node) interface{} {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return map[string]interface{}{}
+	case yaml.SequenceNode:
+		return []interface{}{}
+	case yaml.ScalarNode:
+		return node.Value
+	}
+	return nil
+}
+
+// redactScalar replaces the value of a scalar node, the value is quoted so it stays a string
+func redactScalar(node *yaml.Node) {
+	node.Value = RedactedValue
+	node.Tag = "!!str"
+	if node.Style != yaml.SingleQuotedStyle {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+}
+
+// anchoredScalars are the copies of anchored scalars made by expandAliases, by anchored scalar
+type anchoredScalars map[*yaml.Node][]*yaml.Node
+
+// expandAliases replaces the aliases of a node with copies of the anchored nodes, so the secrets they hold are
+// redacted by the keys they are used with. Merged maps, e.g. <<: *defaults, are redacted where they are anchored.
+func expandAliases(node *yaml.Node, anchored anchoredScalars) {
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 1 && node.Content[i-1].Value == "<<" {
+			// The encoder writes merge keys with an explicit !!merge tag unless their tag is resolved implicitly
+			node.Content[i-1].Tag = ""
+			continue
+		}
+		if child.Kind == yaml.AliasNode && child.Alias != nil {
+			node.Content[i] = copyAliased(child.Alias, anchored)
+		}
+		expandAliases(node.Content[i], anchored)
+	}
+}
+
+// copyAliased deep copies an anchored node without its anchor, nested aliases are expanded
+func copyAliased(node *yaml.Node, anchored anchoredScalars) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return copyAliased(node.Alias, anchored)
+	}
+	copied := *node
+	copied.Anchor = ""
+	if len(node.Content) > 0 {
+		copied.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			copied.Content[i] = copyAliased(child, anchored)
+		}
+	}
+	if node.Kind == yaml.ScalarNode {
+		anchored[node] = append(anchored[node], &copied)
+	}
+	return &copied
+}
+
+// redact redacts an anchored scalar and all its copies if one of them was redacted, a secret used through an
+// alias is not printed where it is anchored, e.g. under a key that is not a secret
+func (a anchoredScalars) redact() {
+	for scalar, copies := range a {
+		nodes := append([]*yaml.Node{scalar}, copies...)
+		if !slices.ContainsFunc(nodes, func(node *yaml.Node) bool { return node.Value == RedactedValue }) {
+			continue
+		}
+		for _, node := range nodes {
+			redactScalar(node)
+		}
+	}
+}
+
+// sectionComponentType returns the component type declared by a top-level configuration section
+func sectionComponentType(section string) (ComponentType, bool) {
+	for _, cs := range componentSections {
+		if cs.section == section {
+			return cs.componentType, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,163 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_RedactSecrets(t *testing.T) {
+	manager := NewSchemaManager()
+
+	redacted, err := manager.RedactSecrets([]byte(`# Gateway
+exporters:
+  otlphttp:
+    endpoint: https://backend:4318
+    headers:
+      - name: Authorization
+        value: Bearer abc # inline token
+      - name: X-Tenant
+        value: tenant
+  otlp:
+    headers:
+      api-key: 1234
+extensions:
+  basicauth/client:
+    client_auth:
+      username: collector
+      password: ${env:PASSWORD}
+  basicauth/server:
+    htpasswd:
+      file: /etc/collector/htpasswd
+service:
+  telemetry:
+    metrics:
+      readers:
+        - periodic:
+            exporter:
+              otlp:
+                headers:
+                  authorization: abc
+`), "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, `# Gateway
+exporters:
+  otlphttp:
+    endpoint: https://backend:4318
+    headers:
+      - name: Authorization
+        value: "[REDACTED]" # inline token
+      - name: X-Tenant
+        value: "[REDACTED]"
+  otlp:
+    headers:
+      api-key: "[REDACTED]"
+extensions:
+  basicauth/client:
+    client_auth:
+      username: collector
+      password: ${env:PASSWORD}
+  basicauth/server:
+    htpasswd:
+      file: /etc/collector/htpasswd
+service:
+  telemetry:
+    metrics:
+      readers:
+        - periodic:
+            exporter:
+              otlp:
+                headers:
+                  authorization: "[REDACTED]"
+`, string(redacted))
+}
+
+func TestSchemaManager_RedactSecrets_EmbeddedWriteOnly(t *testing.T) {
+	manager := NewSchemaManager()
+
+	// The Datadog API key is a configopaque.String, its key is not named like a credential
+	redacted, err := manager.RedactSecrets([]byte(`exporters:
+  datadog:
+    api:
+      key: 0123456789abcdef
+      site: datadoghq.eu
+    tls:
+      key_pem: abc
+`), "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, `exporters:
+  datadog:
+    api:
+      key: "[REDACTED]"
+      site: datadoghq.eu
+    tls:
+      key_pem: "[REDACTED]"
+`, string(redacted))
+}
+
+func TestSchemaManager_RedactSecrets_Aliases(t *testing.T) {
+	manager := NewSchemaManager()
+
+	redacted, err := manager.RedactSecrets([]byte(`x: &k "0123456789abcdef"
+defaults: &api
+  key: fedcba9876543210
+  site: datadoghq.eu
+exporters:
+  otlp:
+    headers:
+      api_key: *k
+  datadog:
+    api:
+      <<: *api
+`), "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, `x: &k "[REDACTED]"
+defaults: &api
+  key: "[REDACTED]"
+  site: datadoghq.eu
+exporters:
+  otlp:
+    headers:
+      api_key: "[REDACTED]"
+  datadog:
+    api:
+      <<: *api
+`, string(redacted))
+}
+
+func TestSchemaManager_RedactSecrets_WriteOnly(t *testing.T) {
+	manager := NewSchemaManager()
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "0.139.0", []byte(`{
+		"type": "object",
+		"properties": {
+			"endpoint": {"type": "string"},
+			"credentials": {"$ref": "#/$defs/credentials"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string", "writeOnly": true}}
+		},
+		"$defs": {
+			"credentials": {"type": "object", "properties": {"dsn": {"type": "string", "writeOnly": true}}}
+		}
+	}`)))
+
+	redacted, err := manager.RedactSecrets([]byte(`{"exporters": {"inhouse": {"endpoint": "db:5432", "credentials": {"dsn": "postgres://u:p@db"}, "labels": {"a": "b"}}}}`), "0.139.0")
+	require.NoError(t, err)
+	assert.YAMLEq(t, `
+exporters:
+  inhouse:
+    endpoint: db:5432
+    credentials:
+      dsn: "[REDACTED]"
+    labels:
+      a: "[REDACTED]"
+`, string(redacted))
+
+	redacted, err = manager.RedactSecrets(nil, "0.139.0")
+	require.NoError(t, err)
+	assert.Empty(t, redacted)
+
+	_, err = manager.RedactSecrets([]byte("- receivers"), "0.139.0")
+	require.Error(t, err)
+	_, err = manager.RedactSecrets([]byte("receivers: ["), "0.139.0")
+	require.Error(t, err)
+}
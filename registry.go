@@ -0,0 +1,269 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxRegistryFileSize limits the size of the files fetched from a registry, a bundle of all component schemas of a
+// version is a few megabytes
+const maxRegistryFileSize = 64 << 20
+
+// RegistryOption configures the remote schema registry of a SchemaManager, see WithRemoteRegistry
+type RegistryOption func(*registryOptions)
+
+// registryOptions holds the settings applied by RegistryOption
+type registryOptions struct {
+	cacheDir string
+	client   *http.Client
+}
+
+// WithRegistryCacheDir caches verified bundles in a directory, e.g. a subdirectory of os.UserCacheDir, so versions
+// are fetched only once. Cached bundles are verified again when they are read.
+func WithRegistryCacheDir(dir string) RegistryOption {
+	return func(options *registryOptions) {
+		options.cacheDir = dir
+	}
+}
+
+// WithRegistryHTTPClient sets the HTTP client that fetches bundles, e.g. with authentication for an internal
+// registry. The default is http.DefaultClient.
+func WithRegistryHTTPClient(client *http.Client) RegistryOption {
+	return func(options *registryOptions) {
+		options.client = client
+	}
+}
+
+// WithRemoteRegistry fetches the schemas of versions that are not embedded from a registry, e.g. GitHub release
+// assets or an internal server. The bundle of a version (see GetSchemaBundle) is fetched from
+// <base URL>/<version>/bundle.json the first time the version is used and verified with the schema manifest
+// <base URL>/<version>/checksums.json and its signature, see WithSignatureVerifier. The schemas are served by the
+// existing APIs like embedded schemas, registered schemas take precedence.
+func WithRemoteRegistry(baseURL string, opts ...RegistryOption) ManagerOption {
+	return func(options *managerOptions) {
+		registry := &registryOptions{client: http.DefaultClient}
+		for _, opt := range opts {
+			opt(registry)
+		}
+		options.registry = &remoteRegistry{
+			baseURL:  strings.TrimSuffix(baseURL, "/"),
+			options:  registry,
+			versions: make(map[string]*registryVersion),
+			fetches:  make(map[string]*registryFetch),
+		}
+	}
+}
+
+// remoteRegistry fetches and keeps the bundles of versions from a registry, it is safe for concurrent use
+type remoteRegistry struct {
+	baseURL      string
+	options      *registryOptions
+	verification schemaVerification
+
+	mu sync.Mutex
+	// versions holds the fetched versions, versions that failed to be fetched are fetched again on the next use
+	versions map[string]*registryVersion
+	// fetches holds the fetches in progress by version, concurrent callers of a version wait for the same fetch
+	fetches map[string]*registryFetch
+}
+
+// registryFetch is a fetch of a version in progress, done is closed when it completes
+type registryFetch struct {
+	done    chan struct{}
+	version *registryVersion
+	err     error
+}
+
+// registryVersion holds the component schemas of a fetched bundle by schema cache key
+type registryVersion struct {
+	schemas map[string]*ComponentSchema
+}
+
+// componentSchema returns the schema of a component of a version, the bundle of the version is fetched on first use
+func (r *remoteRegistry) componentSchema(ctx context.Context, componentType ComponentType, componentName string, version string) (*ComponentSchema, error) {
+	fetched, err := r.version(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	schema, ok := fetched.schemas[schemaCacheKey(componentType, componentName, version)]
+	if !ok {
+		return nil, fmt.Errorf("schema not found for component %s %s", componentType, componentName)
+	}
+	return schema, nil
+}
+
+// components returns the components of a version by type, the bundle of the version is fetched on first use
+func (r *remoteRegistry) components(ctx context.Context, version string) (map[ComponentType][]string, error) {
+	fetched, err := r.version(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	components := make(map[ComponentType][]string)
+	for _, schema := range fetched.schemas {
+		components[schema.Type] = append(components[schema.Type], schema.Name)
+	}
+	for _, names := range components {
+		sort.Strings(names)
+	}
+	return components, nil
+}
+
+// version returns the schemas of a version, fetching the bundle from the cache directory or the registry. The lock is
+// not held while the bundle is fetched, concurrent callers of a version wait for the same fetch until their context
+// is done. A fetch that fails because the context of its caller is done is retried by the waiting callers.
+func (r *remoteRegistry) version(ctx context.Context, version string) (*registryVersion, error) {
+	if version == "" || version == "." || version == ".." || strings.ContainsAny(version, `/\?#`) {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+
+	for {
+		r.mu.Lock()
+		if fetched, ok := r.versions[version]; ok {
+			r.mu.Unlock()
+			return fetched, nil
+		}
+		if fetch, ok := r.fetches[version]; ok {
+			r.mu.Unlock()
+			select {
+			case <-fetch.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if fetch.err != nil && (errors.Is(fetch.err, context.Canceled) || errors.Is(fetch.err, context.DeadlineExceeded)) && ctx.Err() == nil {
+				continue
+			}
+			return fetch.version, fetch.err
+		}
+
+		fetch := &registryFetch{done: make(chan struct{})}
+		r.fetches[version] = fetch
+		r.mu.Unlock()
+
+		fetch.version, fetch.err = r.load(ctx, version)
+
+		r.mu.Lock()
+		delete(r.fetches, version)
+		if fetch.err == nil {
+			r.versions[version] = fetch.version
+		}
+		r.mu.Unlock()
+		close(fetch.done)
+		return fetch.version, fetch.err
+	}
+}
+
+// load reads the bundle of a version from the cache directory or fetches it from the registry and parses it
+func (r *remoteRegistry) load(ctx context.Context, version string) (*registryVersion, error) {
+	data, err := r.cachedBundle(version)
+	if err != nil {
+		if data, err = r.fetchBundle(ctx, version); err != nil {
+			return nil, err
+		}
+	}
+
+	bundleVersion, componentSchemas, err := parseSchemaBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema bundle of version %s from %s: %w", version, r.baseURL, err)
+	}
+	if bundleVersion != version {
+		return nil, fmt.Errorf("schema bundle of version %s from %s is the bundle of version %s", version, r.baseURL, bundleVersion)
+	}
+
+	fetched := &registryVersion{schemas: make(map[string]*ComponentSchema, len(componentSchemas))}
+	for _, schema := range componentSchemas {
+		schema.Metadata = parseComponentMetadata(schema.Schema)
+		fetched.schemas[schemaCacheKey(schema.Type, schema.Name, version)] = schema
+	}
+	return fetched, nil
+}
+
+// fetchBundle fetches and verifies the bundle of a version and writes it with its manifest to the cache directory
+func (r *remoteRegistry) fetchBundle(ctx context.Context, version string) ([]byte, error) {
+	bundle, err := r.fetch(ctx, version, bundleFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{bundleFileName: bundle}
+	err = r.verification.verify(bundleFileName, bundle, true, func(name string) ([]byte, error) {
+		data, err := r.fetch(ctx, version, name)
+		if err == nil {
+			files[name] = data
+		}
+		return data, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schema bundle of version %s from %s: %w", version, r.baseURL, err)
+	}
+
+	if r.options.cacheDir != "" {
+		// The cache is an optimization, bundles that cannot be cached are fetched again by the next manager
+		dir := filepath.Join(r.options.cacheDir, version)
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			for name, data := range files {
+				_ = os.WriteFile(filepath.Join(dir, name), data, 0644)
+			}
+		}
+	}
+	return bundle, nil
+}
+
+// fetch fetches a file of a version from the registry
+func (r *remoteRegistry) fetch(ctx context.Context, version string, name string) ([]byte, error) {
+	url := r.baseURL + "/" + version + "/" + name
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	response, err := r.options.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound && name == bundleFileName {
+		return nil, fmt.Errorf("version %s is not available from the schema registry %s: %w", version, r.baseURL, fs.ErrNotExist)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, fs.ErrNotExist)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxRegistryFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	if len(data) > maxRegistryFileSize {
+		return nil, fmt.Errorf("failed to fetch %s: larger than %d bytes", url, maxRegistryFileSize)
+	}
+	return data, nil
+}
+
+// cachedBundle reads and verifies the bundle of a version from the cache directory
+func (r *remoteRegistry) cachedBundle(version string) ([]byte, error) {
+	if r.options.cacheDir == "" {
+		return nil, fs.ErrNotExist
+	}
+	dir := filepath.Join(r.options.cacheDir, version)
+	bundle, err := os.ReadFile(filepath.Join(dir, bundleFileName))
+	if err != nil {
+		return nil, err
+	}
+	err = r.verification.verify(bundleFileName, bundle, true, func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, name))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
@@ -0,0 +1,190 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registryBundle returns the bundle of 0.139.0 relabeled as a version that is not embedded
+func registryBundle(t *testing.T, version string) []byte {
+	bundle, err := NewSchemaManager().GetSchemaBundle("0.139.0")
+	require.NoError(t, err)
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	return []byte(strings.ReplaceAll(string(data), schemaIDBase+"0.139.0/", schemaIDBase+version+"/"))
+}
+
+// newRegistryServer serves files by path and counts the requests
+func newRegistryServer(t *testing.T, files map[string][]byte) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// manifestFile returns the checksums.json of a bundle
+func manifestFile(t *testing.T, bundle []byte) []byte {
+	data, err := json.Marshal(NewSchemaManifest("", map[string][]byte{bundleFileName: bundle}))
+	require.NoError(t, err)
+	return data
+}
+
+func TestRemoteRegistry(t *testing.T) {
+	bundle := registryBundle(t, "9.0.0")
+	server, requests := newRegistryServer(t, map[string][]byte{
+		"/schemas/9.0.0/bundle.json":    bundle,
+		"/schemas/9.0.0/checksums.json": manifestFile(t, bundle),
+	})
+	cacheDir := t.TempDir()
+
+	manager := NewSchemaManager(WithRemoteRegistry(server.URL+"/schemas/", WithRegistryCacheDir(cacheDir)))
+	schema, err := manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "9.0.0", schema.Version)
+	assert.Contains(t, schema.Schema["properties"], "endpoint")
+
+	components, err := manager.ListAvailableComponents("9.0.0")
+	require.NoError(t, err)
+	assert.Contains(t, components[ComponentTypeProcessor], "batch")
+
+	result, err := manager.ValidateCollectorConfig([]byte(layeredBase), "9.0.0")
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), result.Errors)
+	assert.Equal(t, int32(2), requests.Load())
+
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "nope", "9.0.0")
+	assert.EqualError(t, err, "schema not found for component exporter nope")
+
+	// Embedded versions are not fetched
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Another manager reads the verified bundle from the cache directory
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "9.0.0", "bundle.json"))
+	require.NoError(t, err)
+	assert.Equal(t, bundle, cached)
+	_, err = NewSchemaManager(WithRemoteRegistry(server.URL+"/schemas", WithRegistryCacheDir(cacheDir))).GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// A corrupted cache is fetched again
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "9.0.0", "bundle.json"), []byte("{}"), 0644))
+	_, err = NewSchemaManager(WithRemoteRegistry(server.URL+"/schemas", WithRegistryCacheDir(cacheDir))).GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), requests.Load())
+
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.1.0")
+	assert.ErrorContains(t, err, "version 9.1.0 is not available from the schema registry")
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "../9.0.0")
+	assert.EqualError(t, err, `invalid version "../9.0.0"`)
+}
+
+func TestRemoteRegistry_Verification(t *testing.T) {
+	bundle := registryBundle(t, "9.0.0")
+	manifest := manifestFile(t, bundle)
+	verifier, signature := newTestMinisignKey(t)
+	otherBundle := registryBundle(t, "9.1.0")
+
+	server, _ := newRegistryServer(t, map[string][]byte{
+		"/9.0.0/bundle.json":            bundle,
+		"/9.0.0/checksums.json":         manifest,
+		"/9.0.0/checksums.json.minisig": signature(manifest),
+		"/9.1.0/bundle.json":            otherBundle,
+		"/9.1.0/checksums.json":         manifest,
+		"/9.2.0/bundle.json":            bundle,
+		"/9.2.0/checksums.json":         manifest,
+		"/9.3.0/bundle.json":            bundle,
+	})
+
+	manager := NewSchemaManager(WithRemoteRegistry(server.URL), WithSignatureVerifier(verifier), RequireVerifiedSchemas())
+	_, err := manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.2.0")
+	assert.ErrorContains(t, err, "failed to read checksums.json.minisig")
+
+	otherVerifier, _ := newTestMinisignKey(t)
+	_, err = NewSchemaManager(WithRemoteRegistry(server.URL), WithSignatureVerifier(otherVerifier)).GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	assert.ErrorContains(t, err, "minisign signature was created with another key")
+	_, err = NewSchemaManager(WithRemoteRegistry(server.URL), RequireVerifiedSchemas()).GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	assert.ErrorContains(t, err, "no signature verifier is configured")
+
+	manager = NewSchemaManager(WithRemoteRegistry(server.URL))
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.1.0")
+	assert.ErrorContains(t, err, "checksum mismatch of bundle.json")
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.2.0")
+	assert.ErrorContains(t, err, "schema bundle of version 9.2.0 from "+server.URL+" is the bundle of version 9.0.0")
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.3.0")
+	assert.ErrorContains(t, err, "bundle.json is not verified: failed to read checksums.json")
+}
+
+func TestRemoteRegistry_ConcurrentFetch(t *testing.T) {
+	bundles := map[string][]byte{"9.0.0": registryBundle(t, "9.0.0"), "9.1.0": registryBundle(t, "9.1.0")}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var slowRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if version == "9.1.0" && name == bundleFileName {
+			if slowRequests.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+		}
+		switch name {
+		case bundleFileName:
+			_, _ = w.Write(bundles[version])
+		case "checksums.json":
+			_, _ = w.Write(manifestFile(t, bundles[version]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	manager := NewSchemaManager(WithRemoteRegistry(server.URL))
+	_, err := manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+
+	results := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.1.0")
+			results <- err
+		}()
+	}
+	<-started
+
+	// Fetched versions are served while another version is fetched, waiting callers are released by their context
+	_, err = manager.GetComponentSchema(ComponentTypeExporter, "otlp", "9.0.0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = manager.registry.version(ctx, "9.1.0")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	for range 2 {
+		assert.NoError(t, <-results)
+	}
+	assert.Equal(t, int32(1), slowRequests.Load(), "Concurrent callers share the fetch of a version")
+}
@@ -0,0 +1,52 @@
+package collectorconfigschema
+
+// RequiredComponents returns the components a collector configuration (YAML or JSON) needs by type, e.g. to verify
+// that a custom distribution contains everything before a configuration is deployed. Components are required when
+// they are declared, including connectors and components not used in a pipeline because the collector needs their
+// factories to load the configuration, enabled in the service or referenced from the configuration of another
+// component, e.g. an authenticator extension. Unknown components are returned as well. Names are sorted.
+func (sm *SchemaManager) RequiredComponents(config []byte, version string) (map[ComponentType][]string, error) {
+	if _, err := sm.ListAvailableComponents(version); err != nil {
+		return nil, err
+	}
+	configMap, err := parseCollectorConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	required := NewDistribution("", nil)
+	addComponent := func(componentType ComponentType, id string) {
+		if componentID, err := ParseComponentID(id); err == nil {
+			required.Add(componentType, componentID.Component)
+		}
+	}
+
+	for _, cs := range componentSections {
+		section, _ := configMap[cs.section].(map[string]interface{})
+		for _, id := range sortedKeys(section) {
+			addComponent(cs.componentType, id)
+
+			componentID, err := ParseComponentID(id)
+			if err != nil {
+				continue
+			}
+			componentSchema, err := sm.GetComponentSchema(cs.componentType, componentID.Component, version)
+			if err != nil {
+				continue
+			}
+			for _, reference := range collectComponentReferences(componentSchema.Schema, cs.section+"."+id, section[id]) {
+				addComponent(ComponentTypeExtension, reference.id)
+			}
+		}
+	}
+
+	service, _ := configMap[sectionService].(map[string]interface{})
+	enabled, _ := service["extensions"].([]interface{})
+	for _, extension := range enabled {
+		if id, ok := extension.(string); ok {
+			addComponent(ComponentTypeExtension, id)
+		}
+	}
+
+	return required.Components(), nil
+}
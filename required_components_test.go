@@ -0,0 +1,63 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_RequiredComponents(t *testing.T) {
+	manager := NewSchemaManager()
+
+	components, err := manager.RequiredComponents([]byte(`
+receivers:
+  otlp:
+  otlp/internal:
+  filelog:
+    storage: file_storage/checkpoints
+processors:
+  batch:
+exporters:
+  otlp:
+    auth:
+      authenticator: bearertokenauth
+  debug:
+  inhouse:
+connectors:
+  spanmetrics:
+extensions:
+  health_check:
+  file_storage/checkpoints:
+service:
+  extensions: [health_check, file_storage/checkpoints, pprof]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [spanmetrics]
+    metrics:
+      receivers: [spanmetrics]
+      exporters: [otlp]
+`), "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, map[ComponentType][]string{
+		ComponentTypeReceiver:  {"filelog", "otlp"},
+		ComponentTypeProcessor: {"batch"},
+		ComponentTypeExporter:  {"debug", "inhouse", "otlp"},
+		ComponentTypeConnector: {"spanmetrics"},
+		ComponentTypeExtension: {"bearertokenauth", "file_storage", "health_check", "pprof"},
+	}, components)
+}
+
+func TestSchemaManager_RequiredComponents_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	_, err := manager.RequiredComponents([]byte("receivers: ["), "0.139.0")
+	require.Error(t, err)
+	_, err = manager.RequiredComponents([]byte("receivers: {}"), "0.0.1")
+	require.Error(t, err)
+
+	components, err := manager.RequiredComponents(nil, "0.139.0")
+	require.NoError(t, err)
+	assert.Empty(t, components)
+}
@@ -0,0 +1,67 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+)
+
+// routingConnector is the connector that routes telemetry to the pipelines of its routing table
+const routingConnector = "routing"
+
+// routedPipeline is a pipeline ID used in the routing table of a routing connector
+type routedPipeline struct {
+	// path is the location of the reference, e.g. "connectors.routing.table[0].pipelines[1]"
+	path     string
+	pipeline string
+}
+
+// collectRoutedPipelines returns the pipelines of the routes and the default pipelines of a routing connector
+func collectRoutedPipelines(connectorID string, config interface{}) []routedPipeline {
+	fields, _ := config.(map[string]interface{})
+	path := sectionConnectors + "." + connectorID
+
+	var routed []routedPipeline
+	table, _ := fields["table"].([]interface{})
+	for i, item := range table {
+		route, _ := item.(map[string]interface{})
+		pipelines, _ := route["pipelines"].([]interface{})
+		for j, pipeline := range pipelines {
+			if id, ok := pipeline.(string); ok {
+				routed = append(routed, routedPipeline{path: fmt.Sprintf("%s.table[%d].pipelines[%d]", path, i, j), pipeline: id})
+			}
+		}
+	}
+	defaults, _ := fields["default_pipelines"].([]interface{})
+	for j, pipeline := range defaults {
+		if id, ok := pipeline.(string); ok {
+			routed = append(routed, routedPipeline{path: fmt.Sprintf("%s.default_pipelines[%d]", path, j), pipeline: id})
+		}
+	}
+	return routed
+}
+
+// validateRoutingPipelines checks that the pipelines routing connectors route to are defined in the service and
+// receive from the connector, the collector fails to build the connector otherwise
+func validateRoutingPipelines(connectors interface{}, service interface{}, result *ConfigValidationResult) {
+	section, _ := connectors.(map[string]interface{})
+	serviceConfig, _ := service.(map[string]interface{})
+	pipelines, _ := serviceConfig["pipelines"].(map[string]interface{})
+
+	for _, connectorID := range sortedKeys(section) {
+		id, err := ParseComponentID(connectorID)
+		if err != nil || id.Component != routingConnector {
+			continue
+		}
+		for _, routed := range collectRoutedPipelines(connectorID, section[connectorID]) {
+			pipeline, exists := pipelines[routed.pipeline]
+			if !exists {
+				result.addError(routed.path, "pipeline %q is not defined in the service", routed.pipeline)
+				continue
+			}
+			config, _ := pipeline.(map[string]interface{})
+			receivers, _ := config["receivers"].([]interface{})
+			if !containsValue(receivers, connectorID) {
+				result.addError(routed.path, "pipeline %q does not receive from connector %q", routed.pipeline, connectorID)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentRules_Routing(t *testing.T) {
+	manager := NewSchemaManager()
+
+	valid := []byte(`{
+		"default_pipelines": ["traces/default"],
+		"error_mode": "ignore",
+		"table": [
+			{"statement": "route() where attributes[\"tenant\"] == \"acme\"", "pipelines": ["traces/acme"]},
+			{"context": "span", "condition": "attributes[\"env\"] == \"prod\"", "pipelines": ["traces/prod"]}
+		]
+	}`)
+	result, err := manager.ValidateComponentJSON(ComponentTypeConnector, "routing", "0.139.0", valid)
+	require.NoError(t, err)
+	assert.True(t, result.Valid(), "Expected a valid routing table: %v", result.Errors())
+
+	invalid := []byte(`{
+		"table": [
+			{"statement": "route()", "condition": "true", "pipelines": ["traces/a"]},
+			{"pipelines": ["traces/b"]},
+			{"condition": "attributes[\"env\" == \"prod\""},
+			{"context": "trace", "condition": "true", "pipelines": ["traces/c"]}
+		]
+	}`)
+	result, err = manager.ValidateComponentJSON(ComponentTypeConnector, "routing", "0.139.0", invalid)
+	require.NoError(t, err)
+
+	var errors []string
+	for _, resultError := range result.Errors() {
+		errors = append(errors, resultError.Field()+": "+resultError.Type())
+	}
+	assert.ElementsMatch(t, []string{
+		"table.0: constraint",
+		"table.1: constraint",
+		"table.2.condition: ottl_syntax",
+		"table.2: constraint",
+		"table.3.context: enum",
+	}, errors)
+
+	result, err = manager.ValidateComponentJSON(ComponentTypeConnector, "routing", "0.139.0", []byte(`{"table": []}`))
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "the routing table must have at least one route", result.Errors()[0].Description())
+}
+
+func TestSchemaManager_ValidateCollectorConfig_RoutingPipelines(t *testing.T) {
+	manager := NewSchemaManager()
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+exporters:
+  debug:
+connectors:
+  routing:
+    default_pipelines: [traces/default]
+    table:
+      - condition: attributes["tenant"] == "acme"
+        pipelines: [traces/acme, traces/missing]
+      - condition: attributes["tenant"] == "other"
+        pipelines: [traces/other]
+service:
+  pipelines:
+    traces/in:
+      receivers: [otlp]
+      exporters: [routing]
+    traces/default:
+      receivers: [routing]
+      exporters: [debug]
+    traces/acme:
+      receivers: [routing]
+      exporters: [debug]
+    traces/other:
+      receivers: [otlp]
+      exporters: [debug]
+`)
+
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0")
+	require.NoError(t, err)
+
+	var errors []string
+	for _, configError := range result.Errors {
+		errors = append(errors, configError.String())
+	}
+	assert.Equal(t, []string{
+		`connectors.routing.table[0].pipelines[1]: pipeline "traces/missing" is not defined in the service`,
+		`connectors.routing.table[1].pipelines[0]: pipeline "traces/other" does not receive from connector "routing"`,
+	}, errors)
+	require.NotNil(t, result.Errors[0].Position)
+	assert.Equal(t, 13, result.Errors[0].Position.Line)
+
+	// Fragments route to pipelines outside of the fragment
+	result, err = manager.ValidateFragment("connectors", []byte(`
+routing:
+  table:
+    - condition: "true"
+      pipelines: [traces/elsewhere]
+`), "0.139.0")
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+}
@@ -0,0 +1,100 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteComponentSchema writes the JSON schema of a component to w, encoded like GetComponentSchemaJSON
+func (sm *SchemaManager) WriteComponentSchema(w io.Writer, componentType ComponentType, componentName string, version string) error {
+	schema, err := sm.GetComponentSchema(componentType, componentName, version)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(schema.Schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema of %s %s: %w", componentType, componentName, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteBundle writes the schema bundle of a version to w, encoded like json.MarshalIndent of GetSchemaBundle with
+// two spaces. The component schemas are encoded and written one at a time, the encoded bundle of several megabytes
+// is never held in memory, e.g. when it is served over HTTP or written by the CLI.
+func (sm *SchemaManager) WriteBundle(w io.Writer, version string) error {
+	return sm.WriteBundleContext(context.Background(), w, version)
+}
+
+// WriteBundleContext is WriteBundle with a context, writing stops with the error of the context when it is canceled
+func (sm *SchemaManager) WriteBundleContext(ctx context.Context, w io.Writer, version string) error {
+	properties, definitions, err := sm.bundleContent(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	// The keys are written in the order of json.Marshal, "$defs" sorts first
+	header := bundleHeader(version, properties)
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writer := &jsonStreamWriter{w: w}
+	writer.write("{\n  \"$defs\": {")
+	for i, name := range sortedKeys(definitions) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i > 0 {
+			writer.write(",")
+		}
+		writer.write("\n    ")
+		writer.value("", name)
+		writer.write(": ")
+		writer.value("    ", definitions[name])
+	}
+	if len(definitions) > 0 {
+		writer.write("\n  ")
+	}
+	writer.write("}")
+	for _, key := range keys {
+		writer.write(",\n  ")
+		writer.value("", key)
+		writer.write(": ")
+		writer.value("  ", header[key])
+	}
+	writer.write("\n}")
+	return writer.err
+}
+
+// jsonStreamWriter writes indented JSON in parts, the first error stops writing and is kept in err
+type jsonStreamWriter struct {
+	w   io.Writer
+	err error
+}
+
+// write writes JSON text as it is
+func (j *jsonStreamWriter) write(text string) {
+	if j.err == nil {
+		_, j.err = io.WriteString(j.w, text)
+	}
+}
+
+// value writes an indented JSON value nested at prefix
+func (j *jsonStreamWriter) value(prefix string, value interface{}) {
+	if j.err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(value, prefix, "  ")
+	if err != nil {
+		j.err = fmt.Errorf("failed to encode schema bundle: %w", err)
+		return
+	}
+	_, j.err = j.w.Write(data)
+}
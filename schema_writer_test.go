@@ -0,0 +1,67 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_WriteComponentSchema(t *testing.T) {
+	manager := NewSchemaManager()
+
+	var buf bytes.Buffer
+	require.NoError(t, manager.WriteComponentSchema(&buf, ComponentTypeReceiver, "otlp", "0.139.0"))
+	expected, err := manager.GetComponentSchemaJSON(ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String())
+
+	assert.Error(t, manager.WriteComponentSchema(&buf, ComponentTypeReceiver, "doesnotexist", "0.139.0"))
+}
+
+func TestSchemaManager_WriteBundle(t *testing.T) {
+	manager := NewSchemaManager()
+
+	var buf bytes.Buffer
+	require.NoError(t, manager.WriteBundle(&buf, "0.139.0"))
+
+	bundle, err := manager.GetSchemaBundle("0.139.0")
+	require.NoError(t, err)
+	expected, err := json.MarshalIndent(bundle, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String(), "The streamed bundle is encoded like the bundle")
+
+	// A bundle without component schemas is valid JSON
+	empty := NewSchemaManager()
+	require.NoError(t, empty.RegisterCustomSchema(ComponentTypeReceiver, "inhouse", "9.9.9", []byte(`{"type": "object"}`)))
+	buf.Reset()
+	require.NoError(t, empty.WriteBundle(&buf, "9.9.9"))
+	assert.True(t, json.Valid(buf.Bytes()), "Expected valid JSON: %s", buf.String())
+}
+
+// failingWriter fails after accepting limit bytes
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		return 0, errors.New("disk full")
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestSchemaManager_WriteBundle_Errors(t *testing.T) {
+	manager := NewSchemaManager()
+
+	assert.EqualError(t, manager.WriteBundle(&failingWriter{limit: 4096}, "0.139.0"), "disk full")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, manager.WriteBundleContext(ctx, &bytes.Buffer{}, "0.139.0"), context.Canceled)
+}
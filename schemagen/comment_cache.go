@@ -0,0 +1,65 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// commentCacheFormat is part of the cache file path, bump it when the extracted comments change
+const commentCacheFormat = "v2"
+
+// WithCommentCacheDir caches the type and field comments of packages in a directory, so repeated generator runs do not
+// re-parse the package sources. Entries are keyed by package path and module version, packages without a module
+// version, e.g. of the main module or set with WithPackageDir, are not cached because their sources can change.
+func WithCommentCacheDir(dir string) Option {
+	return func(g *Generator) {
+		g.cacheDir = dir
+	}
+}
+
+// commentCachePath returns the cache file of a package version, e.g. <dir>/v2/go.opentelemetry.io/collector/config/confighttp@v0.139.0.json
+func (g *Generator) commentCachePath(pkgPath string, moduleVersion string) string {
+	return filepath.Join(g.cacheDir, commentCacheFormat, filepath.FromSlash(pkgPath)+"@"+moduleVersion+".json")
+}
+
+// readCommentCache returns the cached comments of a package version, ok is false if they are not cached
+func (g *Generator) readCommentCache(pkgPath string, moduleVersion string) (map[string]string, bool) {
+	if g.cacheDir == "" || moduleVersion == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(g.commentCachePath(pkgPath, moduleVersion))
+	if err != nil {
+		return nil, false
+	}
+	var comments map[string]string
+	if err := json.Unmarshal(data, &comments); err != nil || comments == nil {
+		return nil, false
+	}
+	return comments, true
+}
+
+// writeCommentCache stores the comments of a package version, the cache is best effort and errors are ignored
+func (g *Generator) writeCommentCache(pkgPath string, moduleVersion string, comments map[string]string) {
+	if g.cacheDir == "" || moduleVersion == "" {
+		return
+	}
+
+	data, err := json.Marshal(comments)
+	if err != nil {
+		return
+	}
+	path := g.commentCachePath(pkgPath, moduleVersion)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	// Write to a temporary file first so concurrent runs never read a partial entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
@@ -0,0 +1,55 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentCache(t *testing.T) {
+	// A package of a versioned module dependency, packages of the main module are not cached
+	const pkgPath = "github.com/stretchr/testify/assert"
+	cacheDir := t.TempDir()
+
+	g := NewGenerator(WithCommentCacheDir(cacheDir))
+	require.NoError(t, g.loadCommentsForPackage(pkgPath))
+
+	source, err := g.findPackageSource(pkgPath)
+	require.NoError(t, err)
+	moduleVersion := source.moduleVersion
+	require.NotEmpty(t, moduleVersion)
+
+	cachePath := filepath.Join(cacheDir, commentCacheFormat, "github.com", "stretchr", "testify", "assert@"+moduleVersion+".json")
+	data, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	var cached map[string]string
+	require.NoError(t, json.Unmarshal(data, &cached))
+	assert.Equal(t, g.commentCache[pkgPath], cached)
+
+	// Cached comments are used instead of parsing the sources
+	require.NoError(t, os.WriteFile(cachePath, []byte(`{"Type.Field": "Cached comment"}`), 0644))
+	g = NewGenerator(WithCommentCacheDir(cacheDir))
+	require.NoError(t, g.loadCommentsForPackage(pkgPath))
+	assert.Equal(t, map[string]string{"Type.Field": "Cached comment"}, g.commentCache[pkgPath])
+
+	// Corrupt entries are ignored and rewritten
+	require.NoError(t, os.WriteFile(cachePath, []byte(`{`), 0644))
+	g = NewGenerator(WithCommentCacheDir(cacheDir))
+	require.NoError(t, g.loadCommentsForPackage(pkgPath))
+	assert.Equal(t, cached, g.commentCache[pkgPath])
+}
+
+func TestCommentCache_PackageDir(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	g := NewGenerator(WithCommentCacheDir(cacheDir), WithPackageDir("example.com/inhouse", t.TempDir()))
+	require.NoError(t, g.loadCommentsForPackage("example.com/inhouse"))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
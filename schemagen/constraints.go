@@ -0,0 +1,93 @@
+package schemagen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldConstraint sets the constraints of a field of a struct type, e.g. the bounds of a number.
+// It replaces the constraints derived from the name of the field, see WithConstraintHeuristics.
+type FieldConstraint struct {
+	// PkgPath and TypeName identify the Go struct type declaring the field
+	PkgPath  string
+	TypeName string
+	// Field is the Go name of the field
+	Field string
+	// Constraint holds the keywords merged into the property schema, an empty schema only disables the heuristics
+	Constraint *Schema
+}
+
+// WithConstraintHeuristics enables or disables constraints derived from the names of number fields: ports are
+// bounded to 0-65535, percentages to 0-100 and byte sizes must not be negative. Heuristics are enabled by default.
+func WithConstraintHeuristics(enabled bool) Option {
+	return func(g *Generator) {
+		g.heuristics = enabled
+	}
+}
+
+// WithFieldConstraint sets the constraints of a field, it takes precedence over the heuristics and constraints added before
+func WithFieldConstraint(constraint FieldConstraint) Option {
+	return func(g *Generator) {
+		g.fieldConstraints = append(g.fieldConstraints, constraint)
+	}
+}
+
+// lookupFieldConstraint returns the constraint of a field of a struct type or nil if none was set
+func (g *Generator) lookupFieldConstraint(parentType reflect.Type, field string) *Schema {
+	for i := len(g.fieldConstraints) - 1; i >= 0; i-- {
+		constraint := g.fieldConstraints[i]
+		if constraint.Field == field && constraint.TypeName == parentType.Name() && constraint.PkgPath == parentType.PkgPath() {
+			if constraint.Constraint == nil {
+				return &Schema{}
+			}
+			return constraint.Constraint
+		}
+	}
+	return nil
+}
+
+// constrainProperty merges the constraint set for a field, or derived from its property name, into its number schema
+func (g *Generator) constrainProperty(property *Schema, name string, parentType reflect.Type, fieldName string) {
+	if constraint := g.lookupFieldConstraint(parentType, fieldName); constraint != nil {
+		property.merge(constraint.clone())
+		return
+	}
+	if !g.heuristics || property.Minimum != nil || property.Maximum != nil || property.ExclusiveMinimum != nil {
+		return
+	}
+	if constraint := nameConstraint(name, property.Type); constraint != nil {
+		property.merge(constraint)
+	}
+}
+
+// nameConstraint returns the bounds of an integer or number property derived from its name or nil if the name
+// does not hint at a unit. Byte sizes are only constrained for integers, e.g. max_recv_msg_size_mib.
+func nameConstraint(name string, types Types) *Schema {
+	integer := types.Is("integer")
+	if !integer && !types.Is("number") {
+		return nil
+	}
+	name = strings.ToLower(name)
+	switch {
+	case integer && (name == "port" || strings.HasSuffix(name, "_port")):
+		return &Schema{Minimum: number(0), Maximum: number(65535)}
+	case strings.Contains(name, "percent") || strings.HasSuffix(name, "_pct"):
+		return &Schema{Minimum: number(0), Maximum: number(100)}
+	case integer && isByteSizeName(name):
+		return &Schema{Minimum: number(0)}
+	}
+	return nil
+}
+
+// byteSizeSuffixes are the name suffixes of fields holding sizes in bytes or in binary units
+var byteSizeSuffixes = []string{"_bytes", "_kib", "_mib", "_gib", "_size"}
+
+// isByteSizeName returns whether a property name hints at a size, e.g. limit_mib, max_recv_msg_size or buffer_size
+func isByteSizeName(name string) bool {
+	for _, suffix := range byteSizeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// boundedConfig is a test configuration with number fields whose names hint at their bounds
+type boundedConfig struct {
+	Port               int     `mapstructure:"port"`
+	MetricsPort        uint16  `mapstructure:"metrics_port"`
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+	LimitPercentage    uint32  `mapstructure:"limit_percentage"`
+	LimitMiB           uint32  `mapstructure:"limit_mib"`
+	MaxRecvMsgSize     int     `mapstructure:"max_recv_msg_size"`
+	Ratio              float64 `mapstructure:"ratio_size"`
+	Ports              []int   `mapstructure:"ports"`
+	Transport          string  `mapstructure:"transport_port"`
+	Workers            int     `mapstructure:"workers"`
+}
+
+func TestGenerateSchema_ConstraintHeuristics(t *testing.T) {
+	schema, err := GenerateSchema(boundedConfig{}, WithComments(false), WithDefaults(false))
+	require.NoError(t, err)
+
+	port := map[string]interface{}{"type": "integer", "minimum": float64(0), "maximum": float64(65535)}
+	percentage := map[string]interface{}{"minimum": float64(0), "maximum": float64(100)}
+	size := map[string]interface{}{"type": "integer", "minimum": float64(0)}
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, port, properties["port"])
+	assert.Equal(t, port, properties["metrics_port"])
+	assert.Equal(t, merged(percentage, "type", "number"), properties["sampling_percentage"])
+	assert.Equal(t, merged(percentage, "type", "integer"), properties["limit_percentage"])
+	assert.Equal(t, size, properties["limit_mib"])
+	assert.Equal(t, size, properties["max_recv_msg_size"])
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["ratio_size"], "Byte sizes are integers")
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["ports"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["transport_port"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["workers"])
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		config map[string]interface{}
+		valid  bool
+	}{
+		{config: map[string]interface{}{"port": 4317, "sampling_percentage": 12.5, "limit_mib": 512}, valid: true},
+		{config: map[string]interface{}{"port": 99999}, valid: false},
+		{config: map[string]interface{}{"sampling_percentage": 150}, valid: false},
+		{config: map[string]interface{}{"max_recv_msg_size": -1}, valid: false},
+	}
+	for _, test := range tests {
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(test.config))
+		require.NoError(t, err)
+		assert.Equal(t, test.valid, result.Valid(), "%v: %v", test.config, result.Errors())
+	}
+
+	schema, err = GenerateSchema(boundedConfig{}, WithComments(false), WithDefaults(false), WithConstraintHeuristics(false))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, schema["properties"].(map[string]interface{})["port"])
+}
+
+func TestGenerateSchema_FieldConstraint(t *testing.T) {
+	pkgPath := reflect.TypeOf(boundedConfig{}).PkgPath()
+	schema, err := GenerateSchema(boundedConfig{}, WithComments(false), WithDefaults(false),
+		WithFieldConstraint(FieldConstraint{PkgPath: pkgPath, TypeName: "boundedConfig", Field: "Port", Constraint: &Schema{Minimum: number(1024)}}),
+		WithFieldConstraint(FieldConstraint{PkgPath: pkgPath, TypeName: "boundedConfig", Field: "Workers", Constraint: &Schema{Minimum: number(1)}}),
+		WithFieldConstraint(FieldConstraint{PkgPath: pkgPath, TypeName: "boundedConfig", Field: "LimitMiB"}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer", "minimum": float64(1024)}, properties["port"],
+		"Field constraints replace the heuristics")
+	assert.Equal(t, map[string]interface{}{"type": "integer", "minimum": float64(1)}, properties["workers"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["limit_mib"], "Empty constraints disable the heuristics")
+}
+
+// merged returns a copy of a map with an additional key
+func merged(m map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := map[string]interface{}{key: value}
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
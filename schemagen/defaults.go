@@ -0,0 +1,189 @@
+package schemagen
+
+import (
+	"encoding"
+	"reflect"
+	"time"
+)
+
+// WithDefaults enables or disables default values in generated schemas. The values of the configuration passed
+// to GenerateSchema are recorded as the "default" of the schema, optional sections that are disabled by default,
+// e.g. a protocol of the otlp receiver, record their defaults on their own property so they only apply when the
+// section is enabled. Defaults are enabled by default.
+func WithDefaults(enabled bool) Option {
+	return func(g *Generator) {
+		g.defaults = enabled
+	}
+}
+
+// durationType is the type of time.Duration, durations are recorded in their string form
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// textMarshalerType is the type of encoding.TextMarshaler, e.g. component.ID
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// addDefaults records the default values of a configuration in its schema. A configuration without non-zero values
+// records an empty default, the root default tells that the schema was generated from the default configuration.
+func addDefaults(schema *Schema, cfg reflect.Value) {
+	defaults, ok := encodeDefault(cfg)
+	if !ok {
+		defaults = map[string]interface{}{}
+	}
+	schema.Default = defaults
+	annotateOptionalDefaults(schema, cfg)
+}
+
+// encodeDefault encodes a configuration value in the form it is written in a collector configuration.
+// Zero struct fields, empty maps and lists and disabled optional sections are omitted, ok is false if nothing is left.
+func encodeDefault(v reflect.Value) (interface{}, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), true
+	}
+	if v.CanInterface() && v.Kind() != reflect.String && v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil || len(text) == 0 {
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return v.String(), true
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return nil, false
+		}
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, _ := encodeDefault(v.Index(i))
+			items = append(items, item)
+		}
+		return items, true
+	case reflect.Map:
+		if v.Len() == 0 || v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entry, _ := encodeDefault(iter.Value())
+			entries[iter.Key().String()] = entry
+		}
+		return entries, true
+	case reflect.Struct:
+		if inner, enabled, ok := optionalValue(v); ok {
+			if !enabled {
+				return nil, false
+			}
+			return encodeDefault(inner)
+		}
+
+		fields := make(map[string]interface{})
+		encodeStructDefaults(v, fields)
+		return fields, len(fields) > 0
+	default:
+		return nil, false
+	}
+}
+
+// encodeStructDefaults encodes the non-zero fields of a struct, squashed structs and structs held by squashed
+// interfaces are flattened like mapstructure does
+func encodeStructDefaults(v reflect.Value, fields map[string]interface{}) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if isSquashed(field) {
+			for (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil() {
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				encodeStructDefaults(value, fields)
+			}
+			continue
+		}
+
+		name := getFieldName(field)
+		if name == "" || name == "-" || value.IsZero() {
+			continue
+		}
+		if encoded, ok := encodeDefault(value); ok {
+			fields[name] = encoded
+		}
+	}
+}
+
+// annotateOptionalDefaults records the defaults of optional sections that are disabled by default on their property
+// schemas. Shared definitions are not annotated, their defaults differ between components.
+func annotateOptionalDefaults(schema *Schema, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if isSquashed(field) {
+			annotateOptionalDefaults(schema, value)
+			continue
+		}
+
+		property := schema.Properties.Get(getFieldName(field))
+		if property == nil {
+			continue
+		}
+		if inner, enabled, ok := optionalValue(value); ok {
+			if defaults, ok := encodeDefault(inner); ok && !enabled {
+				property.Default = defaults
+			}
+			value = inner
+		}
+		annotateOptionalDefaults(property, value)
+	}
+}
+
+// optionalValue returns the wrapped value of an optional type like configoptional.Optional[T] and whether it is
+// enabled. Optional types are structs with a HasValue method and an unexported value field, ok is false for other values.
+func optionalValue(v reflect.Value) (inner reflect.Value, enabled bool, ok bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false, false
+	}
+	hasValue, exists := v.Type().MethodByName("HasValue")
+	inner = v.FieldByName("value")
+	if !exists || !inner.IsValid() || hasValue.Type.NumIn() != 1 || hasValue.Type.NumOut() != 1 ||
+		hasValue.Type.Out(0).Kind() != reflect.Bool || !v.CanInterface() {
+		return reflect.Value{}, false, false
+	}
+
+	enabled = v.Method(hasValue.Index).Call(nil)[0].Bool()
+	return inner, enabled, true
+}
@@ -0,0 +1,93 @@
+package schemagen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOptional is an optional section like configoptional.Optional
+type testOptional[T any] struct {
+	value   T
+	enabled bool
+}
+
+// HasValue returns true if the section is enabled
+func (o testOptional[T]) HasValue() bool {
+	return o.enabled
+}
+
+// serverSettings is an optional test section
+type serverSettings struct {
+	Endpoint string        `mapstructure:"endpoint"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// CommonSettings is squashed into defaultsConfig
+type CommonSettings struct {
+	Name string `mapstructure:"name"`
+}
+
+// defaultsConfig is a test configuration with default values
+type defaultsConfig struct {
+	CommonSettings `mapstructure:",squash"`
+
+	GRPC     testOptional[serverSettings] `mapstructure:"grpc"`
+	HTTP     testOptional[serverSettings] `mapstructure:"http"`
+	Interval time.Duration                `mapstructure:"interval"`
+	Tags     []string                     `mapstructure:"tags"`
+	Labels   map[string]string            `mapstructure:"labels"`
+	Enabled  bool                         `mapstructure:"enabled"`
+	Unset    string                       `mapstructure:"unset"`
+	Server   *serverSettings              `mapstructure:"server"`
+}
+
+func TestGenerateSchema_Defaults(t *testing.T) {
+	cfg := &defaultsConfig{
+		CommonSettings: CommonSettings{Name: "default"},
+		GRPC:           testOptional[serverSettings]{value: serverSettings{Endpoint: "localhost:4317"}, enabled: true},
+		HTTP:           testOptional[serverSettings]{value: serverSettings{Endpoint: "localhost:4318", Timeout: 5 * time.Second}},
+		Interval:       90 * time.Second,
+		Tags:           []string{"a", "b"},
+		Labels:         map[string]string{"env": "prod"},
+		Enabled:        true,
+	}
+
+	optional := WithTypeMapping(TypeMapping{
+		PkgPath:  "github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen",
+		TypeName: "testOptional",
+		Mapper:   unwrapOptional,
+		Wrapper:  true,
+	})
+	schema, err := GenerateSchema(cfg, WithComments(false), optional)
+	require.NoError(t, err)
+
+	// Disabled optional sections are not part of the defaults of the configuration
+	assert.Equal(t, map[string]interface{}{
+		"name":     "default",
+		"grpc":     map[string]interface{}{"endpoint": "localhost:4317"},
+		"interval": "1m30s",
+		"tags":     []interface{}{"a", "b"},
+		"labels":   map[string]interface{}{"env": "prod"},
+		"enabled":  true,
+	}, schema["default"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"endpoint": "localhost:4318", "timeout": "5s"}, properties["http"].(map[string]interface{})["default"])
+	assert.NotContains(t, properties["grpc"], "default")
+	assert.Equal(t, []interface{}{"object", "null"}, properties["grpc"].(map[string]interface{})["type"], "Optional sections are enabled by an empty value")
+
+	schema, err = GenerateSchema(cfg, WithComments(false), optional, WithDefaults(false))
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "default")
+	assert.NotContains(t, schema["properties"].(map[string]interface{})["http"], "default")
+}
+
+func TestGenerateSchema_ZeroDefaults(t *testing.T) {
+	schema, err := GenerateSchema(defaultsConfig{}, WithComments(false))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, schema["default"], "An empty default tells the schema was generated from the defaults")
+	assert.NotContains(t, schema["properties"].(map[string]interface{})["http"], "default")
+}
@@ -0,0 +1,165 @@
+package schemagen
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// sharedDefinition is a configuration block used by many components that is generated once into the
+// $defs of a schema and referenced wherever it is used, so its constraints cannot drift between components
+type sharedDefinition struct {
+	// name is the key of the definition in $defs
+	name string
+	// pkgPathPrefix and typeName identify the Go struct type of the block
+	pkgPathPrefix string
+	typeName      string
+	// field limits the definition to a single field of the struct type, e.g. TimeoutConfig.Timeout
+	field string
+	// constraints are keywords merged into the generated schema by dot separated property path, "" is the block itself
+	constraints map[string]*Schema
+}
+
+// sizerSchema is the schema of the exporterhelper queue and batch sizer
+var sizerSchema = &Schema{
+	Type: Types{"string"},
+	Enum: []interface{}{"requests", "items", "bytes"},
+}
+
+// booleanSchema is the schema of boolean fields
+var booleanSchema = &Schema{Type: Types{"boolean"}}
+
+// number returns a pointer to a number keyword value, e.g. a minimum
+func number(value float64) *float64 {
+	return &value
+}
+
+// sharedDefinitions lists the exporterhelper blocks shared by exporters
+var sharedDefinitions = []sharedDefinition{
+	{
+		name:          "sending_queue",
+		pkgPathPrefix: "go.opentelemetry.io/collector/exporter/exporterhelper/internal/queuebatch",
+		typeName:      "Config",
+		constraints: map[string]*Schema{
+			"enabled":         booleanSchema,
+			"queue_size":      {Minimum: number(0)},
+			"num_consumers":   {Minimum: number(1)},
+			"sizer":           sizerSchema,
+			"batch.min_size":  {Minimum: number(0)},
+			"batch.max_size":  {Minimum: number(0)},
+			"batch.sizer":     sizerSchema,
+			"wait_for_result": booleanSchema,
+		},
+	},
+	{
+		name:          "retry_on_failure",
+		pkgPathPrefix: "go.opentelemetry.io/collector/config/configretry",
+		typeName:      "BackOffConfig",
+		constraints: map[string]*Schema{
+			"enabled":              booleanSchema,
+			"randomization_factor": {Minimum: number(0), Maximum: number(1)},
+			"multiplier":           {ExclusiveMinimum: number(0)},
+		},
+	},
+	{
+		name:          "timeout",
+		pkgPathPrefix: "go.opentelemetry.io/collector/exporter/exporterhelper",
+		typeName:      "TimeoutConfig",
+		field:         "Timeout",
+	},
+}
+
+// lookupSharedDefinition returns the shared definition of a struct type or of a field of a struct type
+func lookupSharedDefinition(t reflect.Type, field string) *sharedDefinition {
+	for i := range sharedDefinitions {
+		definition := &sharedDefinitions[i]
+		if definition.field == field && definition.typeName == t.Name() && strings.HasPrefix(t.PkgPath(), definition.pkgPathPrefix) {
+			return definition
+		}
+	}
+	return nil
+}
+
+// reference generates the schema of a shared definition into the $defs of the current schema and returns a reference to it
+func (g *Generator) reference(definition *sharedDefinition, t reflect.Type) (*Schema, error) {
+	if g.definitions == nil {
+		g.definitions = make(map[string]*Schema)
+	}
+	if _, exists := g.definitions[definition.name]; !exists {
+		// Struct blocks are generated from their fields, generateTypeSchema would resolve them to this reference again
+		var schema *Schema
+		if definition.field == "" {
+			schema = g.generateStructSchema(t)
+		} else {
+			var err error
+			if schema, err = g.generateTypeSchema(t); err != nil {
+				return nil, err
+			}
+		}
+		applyConstraints(schema, definition.constraints)
+		g.definitions[definition.name] = schema
+	}
+
+	return &Schema{Ref: "#/$defs/" + definition.name}, nil
+}
+
+// applyConstraints merges keywords into the properties of a schema, paths that do not exist are skipped
+func applyConstraints(schema *Schema, constraints map[string]*Schema) {
+	for path, keywords := range constraints {
+		target := schema
+		if path != "" {
+			for _, name := range strings.Split(path, ".") {
+				target = target.Properties.Get(name)
+				if target == nil {
+					break
+				}
+			}
+		}
+		if target == nil {
+			continue
+		}
+
+		target.merge(keywords)
+	}
+}
+
+// expandStruct generates the schema of a struct type with build unless the type is already being generated.
+// Self-referential types, e.g. nested routing rules, reference their definition instead of recursing forever:
+// the schema of a recursive type is moved into $defs and every occurrence is a $ref to it.
+func (g *Generator) expandStruct(t reflect.Type, build func() (*Schema, error)) (*Schema, error) {
+	if g.expanding[t] {
+		return g.recursiveReference(t), nil
+	}
+	if name, ok := g.recursive[t]; ok && g.definitions[name] != nil {
+		return g.recursiveReference(t), nil
+	}
+
+	if g.expanding == nil {
+		g.expanding = make(map[reflect.Type]bool)
+	}
+	g.expanding[t] = true
+	schema, err := build()
+	delete(g.expanding, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if name, ok := g.recursive[t]; ok {
+		g.definitions[name] = schema
+		return g.recursiveReference(t), nil
+	}
+	return schema, nil
+}
+
+// recursiveReference returns a reference to the definition of a recursive struct type, e.g. "#/$defs/routingprocessor.RoutingTableItem"
+func (g *Generator) recursiveReference(t reflect.Type) *Schema {
+	if g.recursive == nil {
+		g.recursive = make(map[reflect.Type]string)
+	}
+	name, ok := g.recursive[t]
+	if !ok {
+		name = path.Base(t.PkgPath()) + "." + t.Name()
+		g.recursive[t] = name
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
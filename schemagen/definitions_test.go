@@ -0,0 +1,161 @@
+package schemagen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// QueueConfig is a test configuration block shared by several configurations
+type QueueConfig struct {
+	Enabled   bool `mapstructure:"enabled"`
+	QueueSize int  `mapstructure:"queue_size"`
+}
+
+// TimeoutConfig is a test configuration block with a shared field
+type TimeoutConfig struct {
+	Timeout int `mapstructure:"timeout"`
+}
+
+// exporterConfig uses the shared blocks several times
+type exporterConfig struct {
+	TimeoutConfig `mapstructure:",squash"`
+
+	Queue      QueueConfig            `mapstructure:"sending_queue" description:"Queue settings"`
+	Queues     []QueueConfig          `mapstructure:"queues"`
+	NamedQueue map[string]QueueConfig `mapstructure:"named_queues"`
+}
+
+// withTestDefinitions registers shared definitions for the test types for the duration of a test
+func withTestDefinitions(t *testing.T) {
+	previous := sharedDefinitions
+	t.Cleanup(func() { sharedDefinitions = previous })
+
+	pkgPath := "github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	sharedDefinitions = []sharedDefinition{
+		{
+			name:          "sending_queue",
+			pkgPathPrefix: pkgPath,
+			typeName:      "QueueConfig",
+			constraints: map[string]*Schema{
+				"queue_size": {Minimum: number(0)},
+				"missing":    {Minimum: number(0)},
+			},
+		},
+		{
+			name:          "timeout",
+			pkgPathPrefix: pkgPath,
+			typeName:      "TimeoutConfig",
+			field:         "Timeout",
+			constraints: map[string]*Schema{
+				"": {Minimum: number(0)},
+			},
+		},
+	}
+}
+
+func TestGenerateSchema_SharedDefinitions(t *testing.T) {
+	withTestDefinitions(t)
+
+	schema, err := GenerateSchema(exporterConfig{}, WithComments(false))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/sending_queue", "description": "Queue settings"}, properties["sending_queue"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/sending_queue"}, properties["queues"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/sending_queue"}, properties["named_queues"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/timeout"}, properties["timeout"])
+
+	definitions := schema["$defs"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"enabled":    map[string]interface{}{"type": "boolean"},
+			"queue_size": map[string]interface{}{"type": "integer", "minimum": float64(0)},
+		},
+	}, definitions["sending_queue"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "minimum": float64(0)}, definitions["timeout"])
+
+	// Definitions are collected per schema
+	schema, err = GenerateSchema(testConfig{}, WithComments(false))
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "$defs")
+}
+
+func TestApplyConstraints(t *testing.T) {
+	batchProperties := NewProperties()
+	batchProperties.Set("sizer", &Schema{Type: Types{"object"}, Description: "Sizer of the batch"})
+	properties := NewProperties()
+	properties.Set("batch", &Schema{Type: Types{"object"}, Properties: batchProperties})
+	schema := &Schema{Type: Types{"object"}, Properties: properties}
+
+	applyConstraints(schema, map[string]*Schema{
+		"batch.sizer":   sizerSchema,
+		"batch.missing": {Minimum: number(0)},
+		"":              {Description: "Batch settings"},
+	})
+
+	assert.Equal(t, "Batch settings", schema.Description)
+	assert.Equal(t, &Schema{Type: Types{"string"}, Enum: sizerSchema.Enum, Description: "Sizer of the batch"}, batchProperties.Get("sizer"))
+	assert.Nil(t, batchProperties.Get("missing"))
+}
+
+// routingRule is a self-referential test configuration like nested routing rules
+type routingRule struct {
+	Condition string         `mapstructure:"condition"`
+	Rules     []routingRule  `mapstructure:"rules"`
+	Fallback  *routingRule   `mapstructure:"fallback"`
+	Filter    *routingFilter `mapstructure:"filter"`
+}
+
+// routingFilter and routingRule reference each other
+type routingFilter struct {
+	Rule *routingRule `mapstructure:"rule"`
+}
+
+// routingConfig uses the recursive rules and references itself
+type routingConfig struct {
+	Table  []routingRule  `mapstructure:"table"`
+	Parent *routingConfig `mapstructure:"parent"`
+}
+
+func TestGenerateSchema_RecursiveTypes(t *testing.T) {
+	schema, err := GenerateSchema(routingConfig{}, WithComments(false))
+	require.NoError(t, err)
+
+	ruleReference := map[string]interface{}{"$ref": "#/$defs/schemagen.routingRule"}
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, ruleReference, properties["table"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/schemagen.routingConfig"}, properties["parent"])
+
+	definitions := schema["$defs"].(map[string]interface{})
+	require.Len(t, definitions, 2)
+	assert.Equal(t, map[string]interface{}{"type": "object", "properties": properties}, definitions["schemagen.routingConfig"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"condition": map[string]interface{}{"type": "string"},
+			"rules":     map[string]interface{}{"type": "array", "items": ruleReference},
+			"fallback":  ruleReference,
+			"filter": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"rule": ruleReference},
+			},
+		},
+	}, definitions["schemagen.routingRule"])
+
+	// The references resolve, nested rules are validated at any depth
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(map[string]interface{}{
+		"table": []interface{}{map[string]interface{}{
+			"rules": []interface{}{map[string]interface{}{"fallback": map[string]interface{}{"condition": 1}}},
+		}},
+		"parent": map[string]interface{}{"table": []interface{}{map[string]interface{}{"condition": "true"}}},
+	}))
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "table.0.rules.0.fallback.condition", result.Errors()[0].Field())
+}
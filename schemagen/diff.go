@@ -0,0 +1,138 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DifferenceKind is the kind of a difference between two serialized schemas
+type DifferenceKind string
+
+// Kinds of differences
+const (
+	DifferenceAdded   DifferenceKind = "added"
+	DifferenceRemoved DifferenceKind = "removed"
+	DifferenceChanged DifferenceKind = "changed"
+)
+
+// SchemaDifference is a value that differs between two serialized schemas, see DiffSchemas
+type SchemaDifference struct {
+	// Path is the JSON pointer (RFC 6901) of the value, e.g. /properties/timeout/type
+	Path string
+	Kind DifferenceKind
+	// Expected is the value in the expected schema, nil for added values
+	Expected interface{}
+	// Actual is the value in the actual schema, nil for removed values
+	Actual interface{}
+}
+
+// String returns the difference in "path: kind" form with the differing values, e.g.
+// /properties/timeout/type: changed from "string" to "integer"
+func (d SchemaDifference) String() string {
+	switch d.Kind {
+	case DifferenceAdded:
+		return fmt.Sprintf("%s: added %s", d.Path, formatJSON(d.Actual))
+	case DifferenceRemoved:
+		return fmt.Sprintf("%s: removed %s", d.Path, formatJSON(d.Expected))
+	default:
+		return fmt.Sprintf("%s: changed from %s to %s", d.Path, formatJSON(d.Expected), formatJSON(d.Actual))
+	}
+}
+
+// DiffSchemas compares two serialized schemas structurally and returns the values that differ sorted by path, e.g.
+// to compare generated schemas with golden files. The order of object members is ignored, list items are compared
+// by index and numbers by value, e.g. 1 and 1.0 are equal. Values of the same kind are compared recursively, the
+// paths of the differences point to the innermost value that differs.
+func DiffSchemas(expected []byte, actual []byte) ([]SchemaDifference, error) {
+	expectedValue, err := decodeJSON(expected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expected schema: %w", err)
+	}
+	actualValue, err := decodeJSON(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse actual schema: %w", err)
+	}
+
+	var differences []SchemaDifference
+	diffValues("", expectedValue, actualValue, &differences)
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences, nil
+}
+
+// diffValues appends the differences between two decoded JSON values at a JSON pointer
+func diffValues(pointer string, expected interface{}, actual interface{}, differences *[]SchemaDifference) {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, value := range expectedValue {
+			path := pointer + "/" + escapePointerToken(key)
+			if actualMember, ok := actualValue[key]; ok {
+				diffValues(path, value, actualMember, differences)
+			} else {
+				*differences = append(*differences, SchemaDifference{Path: path, Kind: DifferenceRemoved, Expected: value})
+			}
+		}
+		for key, value := range actualValue {
+			if _, ok := expectedValue[key]; !ok {
+				*differences = append(*differences, SchemaDifference{Path: pointer + "/" + escapePointerToken(key), Kind: DifferenceAdded, Actual: value})
+			}
+		}
+		return
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(expectedValue) || i < len(actualValue); i++ {
+			path := pointer + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(actualValue):
+				*differences = append(*differences, SchemaDifference{Path: path, Kind: DifferenceRemoved, Expected: expectedValue[i]})
+			case i >= len(expectedValue):
+				*differences = append(*differences, SchemaDifference{Path: path, Kind: DifferenceAdded, Actual: actualValue[i]})
+			default:
+				diffValues(path, expectedValue[i], actualValue[i], differences)
+			}
+		}
+		return
+	case json.Number:
+		if actualValue, ok := actual.(json.Number); ok && equalNumbers(expectedValue, actualValue) {
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*differences = append(*differences, SchemaDifference{Path: pointer, Kind: DifferenceChanged, Expected: expected, Actual: actual})
+	}
+}
+
+// equalNumbers returns whether two JSON numbers have the same value, e.g. 1 and 1.0
+func equalNumbers(a json.Number, b json.Number) bool {
+	x, okX := new(big.Rat).SetString(a.String())
+	y, okY := new(big.Rat).SetString(b.String())
+	return okX && okY && x.Cmp(y) == 0
+}
+
+// escapePointerToken escapes a reference token of a JSON pointer, see parsePointer
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// formatJSON returns the compact JSON of a decoded value for messages
+func formatJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,84 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	expected := []byte(`{
+		"type": "object",
+		"properties": {
+			"timeout": {"type": "string", "default": "200ms"},
+			"send_batch_size": {"type": "integer", "minimum": 0},
+			"metadata_keys": {"type": "array", "items": {"type": "string"}},
+			"a/b": {"type": "string"}
+		},
+		"required": ["timeout", "send_batch_size"]
+	}`)
+	actual := []byte(`{
+		"properties": {
+			"send_batch_size": {"type": "integer", "minimum": 0},
+			"timeout": {"type": "integer", "default": "200ms"},
+			"metadata_keys": {"type": "array", "items": {"type": "string"}},
+			"metadata_cardinality_limit": {"type": "integer"}
+		},
+		"required": ["timeout"],
+		"type": "object"
+	}`)
+
+	differences, err := DiffSchemas(expected, actual)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaDifference{
+		{Path: "/properties/a~1b", Kind: DifferenceRemoved, Expected: map[string]interface{}{"type": "string"}},
+		{Path: "/properties/metadata_cardinality_limit", Kind: DifferenceAdded, Actual: map[string]interface{}{"type": "integer"}},
+		{Path: "/properties/timeout/type", Kind: DifferenceChanged, Expected: "string", Actual: "integer"},
+		{Path: "/required/1", Kind: DifferenceRemoved, Expected: "send_batch_size"},
+	}, differences)
+
+	assert.Equal(t, []string{
+		`/properties/a~1b: removed {"type":"string"}`,
+		`/properties/metadata_cardinality_limit: added {"type":"integer"}`,
+		`/properties/timeout/type: changed from "string" to "integer"`,
+		`/required/1: removed "send_batch_size"`,
+	}, differenceStrings(differences))
+}
+
+func TestDiffSchemas_Equal(t *testing.T) {
+	schema := patchTarget()
+	data, err := json.MarshalIndent(schema, "", "  ")
+	require.NoError(t, err)
+	compact, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	differences, err := DiffSchemas(data, compact)
+	require.NoError(t, err)
+	assert.Empty(t, differences)
+}
+
+func TestDiffSchemas_Kinds(t *testing.T) {
+	differences, err := DiffSchemas(
+		[]byte(`{"default": {"port": 4317}, "minimum": 1.0, "maximum": 1e3, "multipleOf": 0.5}`),
+		[]byte(`{"default": [4317], "minimum": 1, "maximum": 1000, "multipleOf": 0.25}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`/default: changed from {"port":4317} to [4317]`,
+		`/multipleOf: changed from 0.5 to 0.25`,
+	}, differenceStrings(differences), "Numbers with the same value are equal")
+
+	_, err = DiffSchemas([]byte(`{`), []byte(`{}`))
+	assert.ErrorContains(t, err, "failed to parse expected schema")
+}
+
+// differenceStrings returns the messages of differences
+func differenceStrings(differences []SchemaDifference) []string {
+	messages := make([]string, 0, len(differences))
+	for _, difference := range differences {
+		messages = append(messages, difference.String())
+	}
+	return messages
+}
@@ -0,0 +1,82 @@
+package schemagen
+
+import "strings"
+
+// Draft07Version is the JSON schema dialect of schemas converted with Draft07
+const Draft07Version = "http://json-schema.org/draft-07/schema#"
+
+// draft07DefinitionsPrefix is the prefix of references to definitions in draft-07 schemas
+const draft07DefinitionsPrefix = "#/definitions/"
+
+// Draft07 returns a copy of a schema for tools that only support JSON schema draft-07, e.g. older IDE plugins.
+// $defs become definitions and references to them are rewritten, keywords next to $ref, which draft-07 ignores,
+// are moved into an allOf with the reference, and the deprecated keyword, added in 2019-09, is dropped.
+func Draft07(schema *Schema) *Schema {
+	converted := draft07(schema)
+	if converted.Schema != "" {
+		converted.Schema = Draft07Version
+	}
+	return converted
+}
+
+// draft07 converts a schema and its subschemas to draft-07
+func draft07(schema *Schema) *Schema {
+	if schema == nil || schema.boolean != nil {
+		return schema
+	}
+
+	converted := schema.clone()
+	converted.Deprecated = false
+	if schema.Properties != nil {
+		converted.Properties = NewProperties()
+		for _, name := range schema.Properties.Names() {
+			converted.Properties.Set(name, draft07(schema.Properties.Get(name)))
+		}
+	}
+	converted.AdditionalProperties = draft07(schema.AdditionalProperties)
+	converted.PropertyNames = draft07(schema.PropertyNames)
+	converted.Items = draft07(schema.Items)
+	converted.OneOf = draft07All(schema.OneOf)
+	converted.AllOf = draft07All(schema.AllOf)
+
+	// Definitions are serialized under the draft-07 definitions keyword
+	converted.Defs = nil
+	if len(schema.Defs) > 0 {
+		definitions := make(map[string]*Schema, len(schema.Defs))
+		for name, definition := range schema.Defs {
+			definitions[name] = draft07(definition)
+		}
+		converted.Extensions = make(map[string]interface{}, len(schema.Extensions)+1)
+		for key, value := range schema.Extensions {
+			converted.Extensions[key] = value
+		}
+		converted.Extensions["definitions"] = definitions
+	}
+
+	if schema.Ref == "" {
+		return converted
+	}
+	if name, ok := strings.CutPrefix(schema.Ref, "#/$defs/"); ok {
+		converted.Ref = draft07DefinitionsPrefix + name
+	}
+
+	// Draft-07 ignores keywords next to $ref, constraints are kept in an allOf with the reference
+	constraints := converted.clone()
+	constraints.Schema, constraints.Ref, constraints.Description = "", "", ""
+	if len(constraints.keywords()) == 0 {
+		return converted
+	}
+	return &Schema{Schema: converted.Schema, Description: converted.Description, AllOf: []*Schema{{Ref: converted.Ref}, constraints}}
+}
+
+// draft07All converts a list of schemas to draft-07
+func draft07All(schemas []*Schema) []*Schema {
+	if schemas == nil {
+		return nil
+	}
+	converted := make([]*Schema, 0, len(schemas))
+	for _, schema := range schemas {
+		converted = append(converted, draft07(schema))
+	}
+	return converted
+}
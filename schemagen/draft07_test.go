@@ -0,0 +1,54 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestDraft07(t *testing.T) {
+	withTestDefinitions(t)
+
+	schema, err := Generate(exporterConfig{}, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+	schema.Properties.Get("timeout").Deprecated = true
+
+	converted := Draft07(schema).Map()
+	assert.Equal(t, Draft07Version, converted["$schema"])
+	assert.NotContains(t, converted, "$defs")
+	assert.Contains(t, converted["definitions"], "sending_queue")
+
+	properties := converted["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/sending_queue", "description": "Queue settings"}, properties["sending_queue"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/timeout"}, properties["timeout"], "The deprecated keyword is dropped")
+
+	// The original schema is not modified
+	assert.Equal(t, "#/$defs/sending_queue", schema.Properties.Get("sending_queue").Ref)
+	assert.True(t, schema.Properties.Get("timeout").Deprecated)
+
+	data, err := json.Marshal(Draft07(schema))
+	require.NoError(t, err)
+	assert.NoError(t, VerifySchema(data))
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+	require.NoError(t, err)
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(map[string]interface{}{
+		"sending_queue": map[string]interface{}{"queue_size": -1},
+	}))
+	require.NoError(t, err)
+	require.Len(t, result.Errors(), 1)
+	assert.Equal(t, "sending_queue.queue_size", result.Errors()[0].Field())
+}
+
+func TestDraft07_ReferenceConstraints(t *testing.T) {
+	schema := &Schema{Ref: "#/$defs/timeout", Description: "Timeout of requests", Minimum: number(1)}
+
+	assert.Equal(t, &Schema{
+		Description: "Timeout of requests",
+		AllOf:       []*Schema{{Ref: "#/definitions/timeout"}, {Minimum: number(1)}},
+	}, Draft07(schema), "Keywords next to $ref are ignored by draft-07 and moved into an allOf")
+	assert.Equal(t, BoolSchema(false), Draft07(BoolSchema(false)))
+}
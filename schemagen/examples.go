@@ -0,0 +1,55 @@
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// maxExamples is the maximum number of examples recorded on a schema
+const maxExamples = 5
+
+// AddExamples records an example configuration, e.g. a component configuration from its testdata/config.yaml,
+// under the "examples" keyword: the whole configuration on the schema and the value of every field on its
+// property. Values equal to the default or to a recorded example are skipped, at most maxExamples are recorded
+// per schema. Values must be JSON values, i.e. maps with string keys.
+func AddExamples(schema *Schema, example map[string]interface{}) {
+	addExample(schema, example, schema.Default)
+	defaults, _ := schema.Default.(map[string]interface{})
+	addFieldExamples(schema, example, defaults)
+}
+
+// addFieldExamples records the values of an example configuration on the properties of an object schema.
+// Nested configuration blocks record the values of their fields, other values are recorded as a whole.
+// Defaults are the default values of the fields, they are recorded on the root schema.
+func addFieldExamples(schema *Schema, example map[string]interface{}, defaults map[string]interface{}) {
+	for name, value := range example {
+		property := schema.Properties.Get(name)
+		if property == nil {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && property.Properties.Len() > 0 {
+			nestedDefaults, _ := defaults[name].(map[string]interface{})
+			addFieldExamples(property, nested, nestedDefaults)
+			continue
+		}
+		addExample(property, value, defaults[name])
+	}
+}
+
+// addExample appends a value to the examples of a schema unless it is the default value or already recorded
+func addExample(schema *Schema, value interface{}, defaultValue interface{}) {
+	if value == nil || len(schema.Examples) >= maxExamples {
+		return
+	}
+	// Values are compared in their JSON form, defaults are Go values while examples are usually decoded from YAML
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	for _, other := range append([]interface{}{defaultValue}, schema.Examples...) {
+		if encodedOther, err := json.Marshal(other); err == nil && bytes.Equal(encoded, encodedOther) {
+			return
+		}
+	}
+	schema.Examples = append(schema.Examples, value)
+}
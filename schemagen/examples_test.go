@@ -0,0 +1,49 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddExamples(t *testing.T) {
+	schema, err := Generate(&testConfig{Ratio: 0.5}, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	AddExamples(schema, map[string]interface{}{
+		"retries": 3,
+		"server":  map[string]interface{}{"endpoint": "0.0.0.0:4317", "read_timeout": "5s"},
+		"ratio":   0.5,
+		"headers": map[string]interface{}{"x-tenant": "acme"},
+		"unknown": "ignored",
+	})
+	AddExamples(schema, map[string]interface{}{
+		"retries": 3,
+		"server":  map[string]interface{}{"endpoint": "localhost:4317"},
+	})
+
+	assert.Len(t, schema.Examples, 2, "Both configurations are recorded on the schema")
+	assert.Equal(t, []interface{}{3}, schema.Properties.Get("retries").Examples, "Repeated values are recorded once")
+	assert.Empty(t, schema.Properties.Get("ratio").Examples, "Default values are not recorded")
+	assert.Equal(t, []interface{}{map[string]interface{}{"x-tenant": "acme"}}, schema.Properties.Get("headers").Examples)
+
+	server := schema.Properties.Get("server")
+	assert.Empty(t, server.Examples, "Configuration blocks record the values of their fields")
+	assert.Equal(t, []interface{}{"0.0.0.0:4317", "localhost:4317"}, server.Properties.Get("endpoint").Examples)
+	assert.Equal(t, []interface{}{"5s"}, server.Properties.Get("read_timeout").Examples)
+	assert.Nil(t, schema.Properties.Get("unknown"))
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.NoError(t, VerifySchema(data))
+}
+
+func TestAddExamples_Limit(t *testing.T) {
+	schema := &Schema{Type: Types{"string"}}
+	for _, value := range []string{"a", "b", "c", "d", "e", "f"} {
+		addExample(schema, value, nil)
+	}
+	assert.Equal(t, []interface{}{"a", "b", "c", "d", "e"}, schema.Examples)
+}
@@ -0,0 +1,91 @@
+package schemagen
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Implementation is a known implementation of an interface type, see WithImplementations
+type Implementation struct {
+	// Name is the value of the discriminator key selecting the implementation, it is unused without a discriminator
+	Name string
+	// Type is the implementation type, pointer types are generated from the type they point to
+	Type reflect.Type
+}
+
+// WithImplementations renders fields of an interface type as a oneOf of the schemas of its known implementations,
+// instead of an object accepting arbitrary keys. With a discriminator key, e.g. "type", every implementation requires
+// the key to be set to its name. Without one the implementations must be distinguishable by their fields, e.g. in strict mode.
+func WithImplementations(iface reflect.Type, discriminator string, implementations ...Implementation) Option {
+	return WithTypeMapping(TypeMapping{
+		PkgPath:  iface.PkgPath(),
+		TypeName: iface.Name(),
+		Mapper:   implementationsSchema(iface, discriminator, implementations),
+	})
+}
+
+// implementationsSchema returns a mapper that maps an interface type to a oneOf of the schemas of its implementations
+func implementationsSchema(iface reflect.Type, discriminator string, implementations []Implementation) TypeMapper {
+	return func(g *Generator, t reflect.Type) (*Schema, error) {
+		for _, implementation := range implementations {
+			if !implementation.Type.Implements(iface) && !reflect.PointerTo(implementation.Type).Implements(iface) {
+				return nil, fmt.Errorf("type %s does not implement %s", implementation.Type, iface)
+			}
+		}
+		return g.variantsSchema(discriminator, implementations)
+	}
+}
+
+// Variants returns a mapper for types that wrap one of several configurations selected by a discriminator key, e.g.
+// the operator.Config of the stanza package that decodes the operator builder registered for its "type". The
+// variants are rendered as a oneOf like WithImplementations, the mapped type does not have to be an interface.
+func Variants(discriminator string, variants ...Implementation) TypeMapper {
+	return func(g *Generator, _ reflect.Type) (*Schema, error) {
+		return g.variantsSchema(discriminator, variants)
+	}
+}
+
+// variantsSchema returns a oneOf of the schemas of the variants of a type, an object accepting arbitrary keys if
+// there are no variants
+func (g *Generator) variantsSchema(discriminator string, variants []Implementation) (*Schema, error) {
+	var options []*Schema
+	for _, variant := range variants {
+		option, err := g.generateTypeSchema(variant.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate schema of implementation %s: %w", variant.Type, err)
+		}
+		if discriminator != "" {
+			option = discriminate(option, discriminator, variant.Name)
+		}
+		options = append(options, option)
+	}
+
+	if len(options) == 0 {
+		return &Schema{Type: Types{"object"}, AdditionalProperties: BoolSchema(true)}, nil
+	}
+	return &Schema{OneOf: options}, nil
+}
+
+// discriminate requires the discriminator key of an implementation schema to be set to the implementation name
+func discriminate(schema *Schema, discriminator string, name string) *Schema {
+	key := &Schema{Const: name}
+
+	// Referenced definitions are shared, the key is added next to the reference. In strict mode the closed definition
+	// rejects the key, recursive implementations should declare it as a field instead.
+	if schema.Ref != "" {
+		properties := NewProperties()
+		properties.Set(discriminator, key)
+		return &Schema{
+			AllOf:      []*Schema{schema},
+			Properties: properties,
+			Required:   []string{discriminator},
+		}
+	}
+
+	if schema.Properties == nil {
+		schema.Properties = NewProperties()
+	}
+	schema.Properties.Set(discriminator, key)
+	schema.Required = append(schema.Required, discriminator)
+	return schema
+}
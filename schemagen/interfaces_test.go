@@ -0,0 +1,155 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// testEncoding is a test interface with several implementations
+type testEncoding interface {
+	Extension() string
+}
+
+// jsonEncoding implements testEncoding
+type jsonEncoding struct {
+	Pretty bool `mapstructure:"pretty"`
+}
+
+func (jsonEncoding) Extension() string { return "json" }
+
+// protoEncoding implements testEncoding with a pointer receiver
+type protoEncoding struct {
+	Compression string `mapstructure:"compression"`
+}
+
+func (*protoEncoding) Extension() string { return "pb" }
+
+// encodingConfig is a test configuration with interface fields
+type encodingConfig struct {
+	Encoding  testEncoding   `mapstructure:"encoding"`
+	Fallbacks []testEncoding `mapstructure:"fallbacks"`
+	Extra     interface{}    `mapstructure:"extra"`
+}
+
+var (
+	testEncodingType    = reflect.TypeOf((*testEncoding)(nil)).Elem()
+	testImplementations = []Implementation{
+		{Name: "json", Type: reflect.TypeOf(jsonEncoding{})},
+		{Name: "proto", Type: reflect.TypeOf(&protoEncoding{})},
+	}
+)
+
+func TestGenerateSchema_Implementations(t *testing.T) {
+	schema, err := GenerateSchema(encodingConfig{}, WithComments(false), WithStrict(true),
+		WithImplementations(testEncodingType, "", testImplementations...))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	options := []interface{}{
+		map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"pretty": map[string]interface{}{"type": "boolean"}},
+			"additionalProperties": false,
+		},
+		map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"compression": map[string]interface{}{"type": "string"}},
+			"additionalProperties": false,
+		},
+	}
+	assert.Equal(t, map[string]interface{}{"oneOf": options}, properties["encoding"])
+	assert.Equal(t, map[string]interface{}{"oneOf": options}, properties["fallbacks"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": true}, properties["extra"], "Interfaces without implementations accept arbitrary objects")
+
+	valid := map[string]interface{}{"encoding": map[string]interface{}{"pretty": true}}
+	assert.True(t, validateSchema(t, schema, valid))
+	invalid := map[string]interface{}{"encoding": map[string]interface{}{"pretty": true, "compression": "gzip"}}
+	assert.False(t, validateSchema(t, schema, invalid))
+}
+
+func TestGenerateSchema_DiscriminatedImplementations(t *testing.T) {
+	schema, err := GenerateSchema(encodingConfig{}, WithComments(false),
+		WithImplementations(testEncodingType, "type", testImplementations...))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"oneOf": []interface{}{
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":   map[string]interface{}{"const": "json"},
+				"pretty": map[string]interface{}{"type": "boolean"},
+			},
+			"required": []interface{}{"type"},
+		},
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":        map[string]interface{}{"const": "proto"},
+				"compression": map[string]interface{}{"type": "string"},
+			},
+			"required": []interface{}{"type"},
+		},
+	}}, properties["encoding"])
+
+	assert.True(t, validateSchema(t, schema, map[string]interface{}{"encoding": map[string]interface{}{"type": "proto", "compression": "gzip"}}))
+	assert.False(t, validateSchema(t, schema, map[string]interface{}{"encoding": map[string]interface{}{"type": "avro"}}))
+	assert.False(t, validateSchema(t, schema, map[string]interface{}{"encoding": map[string]interface{}{"compression": "gzip"}}))
+}
+
+// testOperator wraps the configuration selected by its type, like the operator.Config of stanza
+type testOperator struct {
+	testEncoding
+}
+
+// operatorsConfig is a test configuration with a list of wrapped configurations
+type operatorsConfig struct {
+	Operators []testOperator `mapstructure:"operators"`
+}
+
+func TestGenerateSchema_Variants(t *testing.T) {
+	schema, err := GenerateSchema(operatorsConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(testOperator{}).PkgPath(),
+		TypeName: "testOperator",
+		Mapper:   Variants("type", testImplementations...),
+	}))
+	require.NoError(t, err)
+
+	items := schema["properties"].(map[string]interface{})["operators"].(map[string]interface{})["items"].(map[string]interface{})
+	require.Len(t, items["oneOf"], 2)
+
+	valid := map[string]interface{}{"operators": []interface{}{
+		map[string]interface{}{"type": "json", "pretty": true},
+		map[string]interface{}{"type": "proto", "compression": "gzip"},
+	}}
+	assert.True(t, validateSchema(t, schema, valid))
+	assert.False(t, validateSchema(t, schema, map[string]interface{}{"operators": []interface{}{map[string]interface{}{"type": "avro"}}}))
+
+	empty, err := GenerateSchema(operatorsConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(testOperator{}).PkgPath(),
+		TypeName: "testOperator",
+		Mapper:   Variants("type"),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": true},
+		empty["properties"].(map[string]interface{})["operators"].(map[string]interface{})["items"], "Types without variants accept arbitrary objects")
+}
+
+func TestGenerateSchema_InvalidImplementation(t *testing.T) {
+	_, err := GenerateSchema(encodingConfig{}, WithImplementations(testEncodingType, "type", Implementation{Name: "server", Type: reflect.TypeOf(testServerConfig{})}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not implement")
+}
+
+// validateSchema returns whether a value is valid against a generated schema
+func validateSchema(t *testing.T, schema map[string]interface{}, value interface{}) bool {
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(value))
+	require.NoError(t, err)
+	return result.Valid()
+}
@@ -0,0 +1,153 @@
+package schemagen
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// packageLoadMode loads the files and module of packages, comments are parsed from the files by the generator
+// so packages found in the comment cache are never parsed
+const packageLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedModule
+
+// WithBuildTags sets the build tags used to select the source files of packages, e.g. the build_tags of
+// a collector builder manifest, so comments are read from the files the component is built from
+func WithBuildTags(tags ...string) Option {
+	return func(g *Generator) {
+		g.buildTags = append(g.buildTags, tags...)
+	}
+}
+
+// packageSource holds the source files of a package and the version of its module
+type packageSource struct {
+	files []string
+	// moduleVersion is empty for packages of the main module, replaced modules and directories set with WithPackageDir
+	moduleVersion string
+	err           error
+}
+
+// findPackageSource returns the source files of a package, loading it with go/packages if it was not preloaded
+func (g *Generator) findPackageSource(pkgPath string) (*packageSource, error) {
+	if dir, ok := g.packageDirs[pkgPath]; ok {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sources of package %s: %w", pkgPath, err)
+		}
+		return &packageSource{files: files}, nil
+	}
+
+	// For standard library packages, we can't easily access source
+	if !strings.Contains(pkgPath, ".") {
+		return nil, fmt.Errorf("cannot access source for standard library package: %s", pkgPath)
+	}
+
+	if _, loaded := g.packageSources[pkgPath]; !loaded {
+		g.loadPackageSources([]string{pkgPath})
+	}
+	source := g.packageSources[pkgPath]
+	if source.err != nil {
+		return nil, source.err
+	}
+	return source, nil
+}
+
+// loadPackageSources loads the source files of packages with a single go/packages query
+func (g *Generator) loadPackageSources(pkgPaths []string) {
+	var patterns []string
+	for _, pkgPath := range pkgPaths {
+		_, loaded := g.packageSources[pkgPath]
+		_, configured := g.packageDirs[pkgPath]
+		if !loaded && !configured && strings.Contains(pkgPath, ".") {
+			patterns = append(patterns, pkgPath)
+		}
+	}
+	if len(patterns) == 0 {
+		return
+	}
+
+	// Test variants are loaded too, configuration types may be declared in test files like the test component of the build tool
+	config := &packages.Config{Mode: packageLoadMode, Tests: true}
+	if len(g.buildTags) > 0 {
+		config.BuildFlags = []string{"-tags=" + strings.Join(g.buildTags, ",")}
+	}
+	loaded, err := packages.Load(config, patterns...)
+	if err != nil {
+		for _, pkgPath := range patterns {
+			g.packageSources[pkgPath] = &packageSource{err: fmt.Errorf("failed to load package %s: %w", pkgPath, err)}
+		}
+		return
+	}
+
+	files := make(map[string]map[string]bool)
+	for _, pkg := range loaded {
+		source, ok := g.packageSources[pkg.PkgPath]
+		if !ok {
+			source = &packageSource{}
+			if pkg.Module != nil && pkg.Module.Replace == nil {
+				source.moduleVersion = pkg.Module.Version
+			}
+			g.packageSources[pkg.PkgPath] = source
+			files[pkg.PkgPath] = make(map[string]bool)
+		}
+		// The test variant of a package repeats its files
+		for _, file := range pkg.GoFiles {
+			if seen, loading := files[pkg.PkgPath]; loading && !seen[file] {
+				seen[file] = true
+				source.files = append(source.files, file)
+			}
+		}
+		if len(pkg.GoFiles) == 0 && len(pkg.Errors) > 0 && source.err == nil {
+			source.err = fmt.Errorf("failed to load package %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+	}
+	for _, pkgPath := range patterns {
+		source, ok := g.packageSources[pkgPath]
+		switch {
+		case !ok:
+			g.packageSources[pkgPath] = &packageSource{err: fmt.Errorf("package %s not found", pkgPath)}
+		case len(source.files) > 0:
+			source.err = nil
+		case source.err == nil:
+			source.err = fmt.Errorf("no source files found for package %s", pkgPath)
+		}
+	}
+}
+
+// preloadPackages loads the sources of all packages declaring struct types of a configuration at once,
+// instead of querying the build system for every package while the schema is generated
+func (g *Generator) preloadPackages(configType reflect.Type) {
+	pkgPaths := make(map[string]bool)
+	collectPackages(configType, make(map[reflect.Type]bool), pkgPaths)
+
+	var paths []string
+	for pkgPath := range pkgPaths {
+		paths = append(paths, pkgPath)
+	}
+	g.loadPackageSources(paths)
+}
+
+// collectPackages collects the packages of the struct types reachable from a type
+func collectPackages(t reflect.Type, seen map[reflect.Type]bool, pkgPaths map[string]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		collectPackages(t.Elem(), seen, pkgPaths)
+	case reflect.Map:
+		collectPackages(t.Key(), seen, pkgPaths)
+		collectPackages(t.Elem(), seen, pkgPaths)
+	case reflect.Struct:
+		if t.PkgPath() != "" {
+			pkgPaths[t.PkgPath()] = true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			collectPackages(t.Field(i).Type, seen, pkgPaths)
+		}
+	}
+}
@@ -0,0 +1,541 @@
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7396) to a schema and returns the patched schema, e.g. to add an
+// enum or a description reflection cannot derive. Properties keep their order, added properties are appended.
+func ApplyMergePatch(schema *Schema, patch []byte) (*Schema, error) {
+	value, err := decodeJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	document, err := schemaDocument(schema)
+	if err != nil {
+		return nil, err
+	}
+	return patchedSchema(schema, mergePatch(document, value))
+}
+
+// mergePatch merges a merge patch into a document, null members of the patch remove members of the document
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// patchOperation is an operation of a JSON Patch
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies a JSON Patch (RFC 6902) to a schema and returns the patched schema, e.g. to replace the
+// items of a oneOf or to remove a property. All operations are supported, a failing test operation fails the patch.
+func ApplyJSONPatch(schema *Schema, patch []byte) (*Schema, error) {
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	document, err := schemaDocument(schema)
+	if err != nil {
+		return nil, err
+	}
+	for i, operation := range operations {
+		if document, err = operation.apply(document); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s) failed: %w", i, operation.Op, operation.Path, err)
+		}
+	}
+	return patchedSchema(schema, document)
+}
+
+// apply applies the operation to a document and returns the updated document
+func (o patchOperation) apply(document interface{}) (interface{}, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch o.Op {
+	case "add", "replace", "test":
+		if len(o.Value) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		if value, err = decodeJSON(o.Value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	case "move", "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = pointerValue(document, from); err != nil {
+			return nil, err
+		}
+		if o.Op == "move" {
+			if document, err = updatePointer(document, from, removeMember); err != nil {
+				return nil, err
+			}
+		} else if value, err = deepCopyJSON(value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch o.Op {
+	case "add", "move", "copy":
+		return updatePointer(document, path, addMember(value))
+	case "remove":
+		return updatePointer(document, path, removeMember)
+	case "replace":
+		return updatePointer(document, path, replaceMember(value))
+	case "test":
+		actual, err := pointerValue(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, fmt.Errorf("value does not match")
+		}
+		return document, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", o.Op)
+	}
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens, "" is the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerValue returns the value at a JSON pointer
+func pointerValue(document interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		child, err := childValue(document, token)
+		if err != nil {
+			return nil, err
+		}
+		document = child
+	}
+	return document, nil
+}
+
+// childValue returns the member of an object or the item of an array referenced by a token
+func childValue(container interface{}, token string) (interface{}, error) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		value, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q does not exist", token)
+		}
+		return value, nil
+	case []interface{}:
+		index, err := arrayIndex(c, token, false)
+		if err != nil {
+			return nil, err
+		}
+		return c[index], nil
+	default:
+		return nil, fmt.Errorf("cannot reference %q in a value that is not an object or an array", token)
+	}
+}
+
+// arrayIndex parses the index of an array item, with insert the index after the last item is valid
+func arrayIndex(array []interface{}, token string, insert bool) (int, error) {
+	limit := len(array)
+	if insert {
+		limit++
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index >= limit || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+// memberUpdate updates the member of a container referenced by a token and returns the updated container
+type memberUpdate func(container interface{}, token string) (interface{}, error)
+
+// updatePointer applies an update to the parent of the value at a JSON pointer and returns the updated document.
+// Updates of the whole document replace it.
+func updatePointer(document interface{}, tokens []string, update memberUpdate) (interface{}, error) {
+	if len(tokens) == 0 {
+		return update(nil, "")
+	}
+	if len(tokens) == 1 {
+		return update(document, tokens[0])
+	}
+
+	child, err := childValue(document, tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = updatePointer(child, tokens[1:], update); err != nil {
+		return nil, err
+	}
+	return replaceMember(child)(document, tokens[0])
+}
+
+// addMember returns an update that adds a member to an object or inserts an item into an array, "-" appends
+func addMember(value interface{}) memberUpdate {
+	return func(container interface{}, token string) (interface{}, error) {
+		switch c := container.(type) {
+		case nil:
+			return value, nil
+		case map[string]interface{}:
+			c[token] = value
+			return c, nil
+		case []interface{}:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			index, err := arrayIndex(c, token, true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[index+1:], c[index:])
+			c[index] = value
+			return c, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to a value that is not an object or an array", token)
+		}
+	}
+}
+
+// replaceMember returns an update that replaces an existing member of an object or item of an array
+func replaceMember(value interface{}) memberUpdate {
+	return func(container interface{}, token string) (interface{}, error) {
+		if container == nil {
+			return value, nil
+		}
+		if _, err := childValue(container, token); err != nil {
+			return nil, err
+		}
+		if c, ok := container.([]interface{}); ok {
+			index, _ := strconv.Atoi(token)
+			c[index] = value
+			return c, nil
+		}
+		container.(map[string]interface{})[token] = value
+		return container, nil
+	}
+}
+
+// removeMember removes an existing member of an object or item of an array
+func removeMember(container interface{}, token string) (interface{}, error) {
+	if container == nil {
+		return nil, fmt.Errorf("cannot remove the whole document")
+	}
+	if _, err := childValue(container, token); err != nil {
+		return nil, err
+	}
+	if c, ok := container.([]interface{}); ok {
+		index, _ := strconv.Atoi(token)
+		return append(c[:index], c[index+1:]...), nil
+	}
+	delete(container.(map[string]interface{}), token)
+	return container, nil
+}
+
+// decodeJSON decodes a JSON value, numbers keep their literal form
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// deepCopyJSON copies a decoded JSON value
+func deepCopyJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// schemaDocument returns the JSON document of a schema, patches modify the document instead of the schema
+func schemaDocument(schema *Schema) (interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return decodeJSON(data)
+}
+
+// patchedSchema converts a patched document back into a schema, properties keep their order in the original schema
+func patchedSchema(original *Schema, document interface{}) (*Schema, error) {
+	schema, err := schemaFromValue(document)
+	if err != nil {
+		return nil, fmt.Errorf("patched schema is invalid: %w", err)
+	}
+	keepPropertyOrder(schema, original)
+	return schema, nil
+}
+
+// schemaFromValue converts a decoded JSON schema into a schema, keywords that are not modelled become extensions.
+// Properties and definitions are sorted by name.
+func schemaFromValue(value interface{}) (*Schema, error) {
+	if boolean, ok := value.(bool); ok {
+		return BoolSchema(boolean), nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema must be an object or a boolean")
+	}
+
+	schema := &Schema{}
+	for _, key := range sortedNames(object) {
+		var err error
+		member := object[key]
+		switch key {
+		case "$schema":
+			schema.Schema, err = stringKeyword(key, member)
+		case "$ref":
+			schema.Ref, err = stringKeyword(key, member)
+		case "description":
+			schema.Description, err = stringKeyword(key, member)
+		case "format":
+			schema.Format, err = stringKeyword(key, member)
+		case "pattern":
+			schema.Pattern, err = stringKeyword(key, member)
+		case "type":
+			schema.Type, err = typesKeyword(member)
+		case "deprecated":
+			schema.Deprecated, err = boolKeyword(key, member)
+		case "writeOnly":
+			schema.WriteOnly, err = boolKeyword(key, member)
+		case "enum":
+			schema.Enum, err = arrayKeyword(key, member)
+		case "examples":
+			schema.Examples, err = arrayKeyword(key, member)
+		case "const":
+			schema.Const = member
+		case "default":
+			schema.Default = member
+		case "minimum":
+			schema.Minimum, err = numberKeyword(key, member)
+		case "maximum":
+			schema.Maximum, err = numberKeyword(key, member)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = numberKeyword(key, member)
+		case "required":
+			schema.Required, err = stringsKeyword(key, member)
+		case "additionalProperties":
+			schema.AdditionalProperties, err = schemaFromValue(member)
+		case "propertyNames":
+			schema.PropertyNames, err = schemaFromValue(member)
+		case "items":
+			schema.Items, err = schemaFromValue(member)
+		case "oneOf":
+			schema.OneOf, err = schemasKeyword(key, member)
+		case "allOf":
+			schema.AllOf, err = schemasKeyword(key, member)
+		case "properties":
+			var schemas map[string]*Schema
+			if schemas, err = schemaMapKeyword(key, member); err == nil {
+				schema.Properties = NewProperties()
+				for _, name := range sortedNames(schemas) {
+					schema.Properties.Set(name, schemas[name])
+				}
+			}
+		case "$defs":
+			schema.Defs, err = schemaMapKeyword(key, member)
+		default:
+			if schema.Extensions == nil {
+				schema.Extensions = make(map[string]interface{})
+			}
+			schema.Extensions[key] = member
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+// stringKeyword returns the value of a string keyword
+func stringKeyword(key string, value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return s, nil
+}
+
+// boolKeyword returns the value of a boolean keyword
+func boolKeyword(key string, value interface{}) (bool, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return b, nil
+}
+
+// numberKeyword returns the value of a number keyword
+func numberKeyword(key string, value interface{}) (*float64, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number: %w", key, err)
+	}
+	return &f, nil
+}
+
+// arrayKeyword returns the value of an array keyword
+func arrayKeyword(key string, value interface{}) ([]interface{}, error) {
+	a, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", key)
+	}
+	return a, nil
+}
+
+// stringsKeyword returns the value of a keyword holding an array of strings
+func stringsKeyword(key string, value interface{}) ([]string, error) {
+	items, err := arrayKeyword(key, value)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// typesKeyword returns the value of the type keyword, a type name or an array of type names
+func typesKeyword(value interface{}) (Types, error) {
+	if name, ok := value.(string); ok {
+		return Types{name}, nil
+	}
+	names, err := stringsKeyword("type", value)
+	if err != nil {
+		return nil, fmt.Errorf("type must be a string or an array of strings")
+	}
+	return Types(names), nil
+}
+
+// schemasKeyword returns the value of a keyword holding an array of schemas
+func schemasKeyword(key string, value interface{}) ([]*Schema, error) {
+	items, err := arrayKeyword(key, value)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]*Schema, 0, len(items))
+	for i, item := range items {
+		schema, err := schemaFromValue(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%d: %w", key, i, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// schemaMapKeyword returns the value of a keyword holding schemas by name
+func schemaMapKeyword(key string, value interface{}) (map[string]*Schema, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", key)
+	}
+	schemas := make(map[string]*Schema, len(object))
+	for name, member := range object {
+		schema, err := schemaFromValue(member)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", key, name, err)
+		}
+		schemas[name] = schema
+	}
+	return schemas, nil
+}
+
+// sortedNames returns the keys of a map in sorted order
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keepPropertyOrder orders the properties of a patched schema and its subschemas like the properties of the original
+// schema, properties the original does not have are appended
+func keepPropertyOrder(schema *Schema, original *Schema) {
+	if schema == nil || original == nil {
+		return
+	}
+
+	if schema.Properties != nil && original.Properties != nil {
+		ordered := NewProperties()
+		for _, name := range original.Properties.Names() {
+			if property := schema.Properties.Get(name); property != nil {
+				ordered.Set(name, property)
+				keepPropertyOrder(property, original.Properties.Get(name))
+			}
+		}
+		for _, name := range schema.Properties.Names() {
+			if ordered.Get(name) == nil {
+				ordered.Set(name, schema.Properties.Get(name))
+			}
+		}
+		schema.Properties = ordered
+	}
+
+	keepPropertyOrder(schema.AdditionalProperties, original.AdditionalProperties)
+	keepPropertyOrder(schema.PropertyNames, original.PropertyNames)
+	keepPropertyOrder(schema.Items, original.Items)
+	for i := 0; i < len(schema.OneOf) && i < len(original.OneOf); i++ {
+		keepPropertyOrder(schema.OneOf[i], original.OneOf[i])
+	}
+	for i := 0; i < len(schema.AllOf) && i < len(original.AllOf); i++ {
+		keepPropertyOrder(schema.AllOf[i], original.AllOf[i])
+	}
+	for name, definition := range schema.Defs {
+		keepPropertyOrder(definition, original.Defs[name])
+	}
+}
@@ -0,0 +1,100 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// patchTarget returns a schema with properties that are not in alphabetical order
+func patchTarget() *Schema {
+	protocols := NewProperties()
+	protocols.Set("http", &Schema{Type: Types{"object"}})
+	protocols.Set("grpc", &Schema{Type: Types{"object"}})
+
+	properties := NewProperties()
+	properties.Set("transport", &Schema{Type: Types{"string"}})
+	properties.Set("port", &Schema{Type: Types{"integer"}, Minimum: number(0)})
+	properties.Set("protocols", &Schema{Type: Types{"object"}, Properties: protocols, AdditionalProperties: BoolSchema(false)})
+	return &Schema{
+		Schema:     SchemaVersion,
+		Type:       Types{"object"},
+		Properties: properties,
+		Default:    map[string]interface{}{"port": int64(4317)},
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	original := patchTarget()
+	before, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	patched, err := ApplyMergePatch(original, []byte(`{
+		"properties": {
+			"transport": {"enum": ["tcp", "udp"], "description": "Transport protocol"},
+			"port": {"minimum": null, "maximum": 65535},
+			"protocols": {"properties": {"http": null, "arrow": {"type": "object"}}},
+			"endpoint": {"type": "string"}
+		},
+		"x-reviewed": true
+	}`))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(patched)
+	require.NoError(t, err)
+	assert.Equal(t, `{"$schema":"`+SchemaVersion+`","type":"object","properties":{`+
+		`"transport":{"type":"string","description":"Transport protocol","enum":["tcp","udp"]},`+
+		`"port":{"type":"integer","maximum":65535},`+
+		`"protocols":{"type":"object","properties":{"grpc":{"type":"object"},"arrow":{"type":"object"}},"additionalProperties":false},`+
+		`"endpoint":{"type":"string"}},"default":{"port":4317},"x-reviewed":true}`, string(data),
+		"Properties keep their order, added properties are appended")
+
+	after, err := json.Marshal(original)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "The original schema is not modified")
+
+	_, err = ApplyMergePatch(original, []byte(`{"properties": {"port": {"type": 1}}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "type must be a string or an array of strings")
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	patched, err := ApplyJSONPatch(patchTarget(), []byte(`[
+		{"op": "test", "path": "/default/port", "value": 4317},
+		{"op": "add", "path": "/properties/transport/oneOf", "value": [{"const": "tcp"}]},
+		{"op": "add", "path": "/properties/transport/oneOf/0", "value": {"const": "udp"}},
+		{"op": "add", "path": "/properties/transport/oneOf/-", "value": {"const": "unix"}},
+		{"op": "replace", "path": "/properties/port/minimum", "value": 1},
+		{"op": "copy", "from": "/properties/port", "path": "/properties/metrics_port"},
+		{"op": "move", "from": "/properties/protocols/properties/http", "path": "/properties/protocols/properties/http~1json"},
+		{"op": "remove", "path": "/default"}
+	]`))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(patched)
+	require.NoError(t, err)
+	assert.Equal(t, `{"$schema":"`+SchemaVersion+`","type":"object","properties":{`+
+		`"transport":{"type":"string","oneOf":[{"const":"udp"},{"const":"tcp"},{"const":"unix"}]},`+
+		`"port":{"type":"integer","minimum":1},`+
+		`"protocols":{"type":"object","properties":{"grpc":{"type":"object"},"http/json":{"type":"object"}},"additionalProperties":false},`+
+		`"metrics_port":{"type":"integer","minimum":1}}}`, string(data))
+
+	tests := map[string]string{
+		`[{"op": "test", "path": "/default/port", "value": 4318}]`:        "value does not match",
+		`[{"op": "remove", "path": "/properties/endpoint"}]`:              `member "endpoint" does not exist`,
+		`[{"op": "replace", "path": "/properties/missing", "value": {}}]`: `member "missing" does not exist`,
+		`[{"op": "add", "path": "/required/1", "value": "port"}]`:         `member "required" does not exist`,
+		`[{"op": "add", "path": "properties", "value": {}}]`:              `invalid JSON pointer "properties"`,
+		`[{"op": "add", "path": "/properties/port"}]`:                     "missing value",
+		`[{"op": "rename", "path": "/properties/port"}]`:                  `unknown operation "rename"`,
+		`[{"op": "remove", "path": ""}]`:                                  "cannot remove the whole document",
+		`{"op": "remove"}`:                                                "invalid JSON patch",
+	}
+	for patch, expected := range tests {
+		_, err := ApplyJSONPatch(patchTarget(), []byte(patch))
+		require.Error(t, err, patch)
+		assert.Contains(t, err.Error(), expected, patch)
+	}
+}
@@ -0,0 +1,63 @@
+package schemagen
+
+import (
+	"reflect"
+)
+
+// Formats of string fields that are compiled into patterns by components
+const (
+	// FormatRegex marks strings holding Go regular expressions
+	FormatRegex = "regex"
+	// FormatGlob marks strings holding file path glob patterns, e.g. "/var/log/**/*.log"
+	FormatGlob = "glob"
+)
+
+// patternField is a string or string list field of a struct type that holds patterns
+type patternField struct {
+	// pkgPath and typeName identify the Go struct type declaring the field
+	pkgPath  string
+	typeName string
+	// field is the Go name of the field
+	field  string
+	format string
+}
+
+// patternFields lists the configuration fields that components compile into regular expressions or globs.
+// Fields that only hold patterns for some match types, e.g. filterset match properties, are not listed.
+var patternFields = []patternField{
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/attraction", typeName: "ActionKeyValue", field: "RegexPattern", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sattributesprocessor", typeName: "FieldExtractConfig", field: "KeyRegex", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor", typeName: "Config", field: "AllowedValues", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor", typeName: "Config", field: "BlockedValues", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor", typeName: "Config", field: "BlockedKeyPatterns", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal/aws/ec2", typeName: "Config", field: "Tags", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal/azure", typeName: "Config", field: "Tags", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher", typeName: "Criteria", field: "Include", format: FormatGlob},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher", typeName: "Criteria", field: "Exclude", format: FormatGlob},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/matcher", typeName: "OrderingCriteria", field: "Regex", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer", typeName: "HeaderConfig", field: "Pattern", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split", typeName: "Config", field: "LineStartPattern", format: FormatRegex},
+	{pkgPath: "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/split", typeName: "Config", field: "LineEndPattern", format: FormatRegex},
+}
+
+// lookupPatternFormat returns the pattern format of a field of a struct type or "" if the field does not hold patterns
+func lookupPatternFormat(parentType reflect.Type, field string) string {
+	for _, pattern := range patternFields {
+		if pattern.field == field && pattern.typeName == parentType.Name() && pattern.pkgPath == parentType.PkgPath() {
+			return pattern.format
+		}
+	}
+	return ""
+}
+
+// annotatePattern sets the format of a string property or of the string items of an array property
+func annotatePattern(property *Schema, format string) {
+	switch {
+	case property.Type.Is("string"):
+		property.Format = format
+	case property.Type.Is("array"):
+		if property.Items != nil && property.Items.Type.Is("string") {
+			property.Items.Format = format
+		}
+	}
+}
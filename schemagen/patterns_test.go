@@ -0,0 +1,53 @@
+package schemagen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matchConfig holds regular expressions and globs
+type matchConfig struct {
+	Pattern  string   `mapstructure:"pattern"`
+	Include  []string `mapstructure:"include"`
+	Limit    int      `mapstructure:"limit"`
+	Name     string   `mapstructure:"name"`
+	Patterns []int    `mapstructure:"patterns"`
+}
+
+// withTestPatterns registers pattern fields of the test types for the duration of a test
+func withTestPatterns(t *testing.T) {
+	previous := patternFields
+	t.Cleanup(func() { patternFields = previous })
+
+	pkgPath := "github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen"
+	patternFields = []patternField{
+		{pkgPath: pkgPath, typeName: "matchConfig", field: "Pattern", format: FormatRegex},
+		{pkgPath: pkgPath, typeName: "matchConfig", field: "Include", format: FormatGlob},
+		{pkgPath: pkgPath, typeName: "matchConfig", field: "Limit", format: FormatRegex},
+		{pkgPath: pkgPath, typeName: "matchConfig", field: "Patterns", format: FormatRegex},
+	}
+}
+
+func TestGenerateSchema_PatternFields(t *testing.T) {
+	withTestPatterns(t)
+
+	schema, err := GenerateSchema(matchConfig{}, WithComments(false))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": FormatRegex}, properties["pattern"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string", "format": FormatGlob},
+	}, properties["include"])
+
+	// Only strings and string lists are annotated
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["limit"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["name"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "integer"},
+	}, properties["patterns"])
+}
@@ -0,0 +1,294 @@
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Schema is a node of a generated JSON schema. Schemas are serialized with a fixed keyword order and object
+// properties in the order they were added, i.e. in the declaration order of struct fields.
+type Schema struct {
+	// Schema is the JSON schema dialect, it is set on root schemas
+	Schema      string
+	Ref         string
+	Type        Types
+	Description string
+	Deprecated  bool
+	// WriteOnly marks secrets, e.g. configopaque.String values, tools must not echo their values
+	WriteOnly bool
+	Format    string
+	Pattern   string
+	Enum      []interface{}
+	Const     interface{}
+
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+
+	Properties *Properties
+	// AdditionalProperties is the schema of keys not listed in Properties, see BoolSchema
+	AdditionalProperties *Schema
+	// PropertyNames is the schema of the keys of an object, e.g. the pattern of maps keyed by component IDs
+	PropertyNames *Schema
+	Required      []string
+	Items         *Schema
+	OneOf         []*Schema
+	AllOf         []*Schema
+	Default       interface{}
+	// Examples are example values, e.g. from the testdata/config.yaml of a component, see AddExamples
+	Examples []interface{}
+
+	// Extensions are keywords that are not modelled by Schema, e.g. x-component-reference
+	Extensions map[string]interface{}
+	Defs       map[string]*Schema
+
+	// boolean is set for the true and false schemas
+	boolean *bool
+}
+
+// Types are the types of a schema, a single type is serialized as a string
+type Types []string
+
+// Is returns whether the types are exactly the given type
+func (t Types) Is(name string) bool {
+	return len(t) == 1 && t[0] == name
+}
+
+// BoolSchema returns the schema accepting every value (true) or no value (false), e.g. for AdditionalProperties
+func BoolSchema(value bool) *Schema {
+	return &Schema{boolean: &value}
+}
+
+// IsBool returns whether the schema is the true or false schema and its value
+func (s *Schema) IsBool() (value bool, ok bool) {
+	if s.boolean == nil {
+		return false, false
+	}
+	return *s.boolean, true
+}
+
+// Properties are the properties of an object schema in the order they were added
+type Properties struct {
+	names   []string
+	schemas map[string]*Schema
+}
+
+// NewProperties creates empty properties
+func NewProperties() *Properties {
+	return &Properties{schemas: make(map[string]*Schema)}
+}
+
+// Set sets the schema of a property, replacing a property keeps its position
+func (p *Properties) Set(name string, schema *Schema) {
+	if _, exists := p.schemas[name]; !exists {
+		p.names = append(p.names, name)
+	}
+	p.schemas[name] = schema
+}
+
+// Get returns the schema of a property or nil if it does not exist
+func (p *Properties) Get(name string) *Schema {
+	if p == nil {
+		return nil
+	}
+	return p.schemas[name]
+}
+
+// Names returns the property names in order
+func (p *Properties) Names() []string {
+	if p == nil {
+		return nil
+	}
+	return append([]string(nil), p.names...)
+}
+
+// Len returns the number of properties
+func (p *Properties) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.names)
+}
+
+// MarshalJSON serializes the properties in order
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range p.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeMember(&buf, name, p.schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON serializes the schema keywords in a fixed order
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	member := func(key string, value interface{}) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		return writeMember(&buf, key, value)
+	}
+
+	for _, keyword := range s.keywords() {
+		if err := member(keyword.key, keyword.value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// keyword is a keyword of a schema and its value
+type keyword struct {
+	key   string
+	value interface{}
+}
+
+// keywords returns the keywords that are set on the schema in serialization order
+func (s *Schema) keywords() []keyword {
+	var keywords []keyword
+	add := func(key string, value interface{}, set bool) {
+		if set {
+			keywords = append(keywords, keyword{key, value})
+		}
+	}
+
+	add("$schema", s.Schema, s.Schema != "")
+	add("$ref", s.Ref, s.Ref != "")
+	if len(s.Type) == 1 {
+		add("type", s.Type[0], true)
+	} else {
+		add("type", []string(s.Type), len(s.Type) > 1)
+	}
+	add("description", s.Description, s.Description != "")
+	add("deprecated", s.Deprecated, s.Deprecated)
+	add("writeOnly", s.WriteOnly, s.WriteOnly)
+	add("format", s.Format, s.Format != "")
+	add("pattern", s.Pattern, s.Pattern != "")
+	add("enum", s.Enum, s.Enum != nil)
+	add("const", s.Const, s.Const != nil)
+	add("minimum", s.Minimum, s.Minimum != nil)
+	add("maximum", s.Maximum, s.Maximum != nil)
+	add("exclusiveMinimum", s.ExclusiveMinimum, s.ExclusiveMinimum != nil)
+	add("properties", s.Properties, s.Properties != nil)
+	add("additionalProperties", s.AdditionalProperties, s.AdditionalProperties != nil)
+	add("propertyNames", s.PropertyNames, s.PropertyNames != nil)
+	add("required", s.Required, len(s.Required) > 0)
+	add("items", s.Items, s.Items != nil)
+	add("oneOf", s.OneOf, len(s.OneOf) > 0)
+	add("allOf", s.AllOf, len(s.AllOf) > 0)
+	add("default", s.Default, s.Default != nil)
+	add("examples", s.Examples, len(s.Examples) > 0)
+
+	extensions := make([]string, 0, len(s.Extensions))
+	for key := range s.Extensions {
+		extensions = append(extensions, key)
+	}
+	sort.Strings(extensions)
+	for _, key := range extensions {
+		add(key, s.Extensions[key], true)
+	}
+
+	add("$defs", s.Defs, len(s.Defs) > 0)
+	return keywords
+}
+
+// writeMember writes a "key":value object member
+func writeMember(buf *bytes.Buffer, key string, value interface{}) error {
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(encodedKey)
+	buf.WriteByte(':')
+	buf.Write(encodedValue)
+	return nil
+}
+
+// Map converts the schema into nested maps, e.g. to validate with a JSON schema library.
+// Boolean schemas are only supported below the root and become true or false.
+func (s *Schema) Map() map[string]interface{} {
+	value, _ := s.value().(map[string]interface{})
+	return value
+}
+
+// value converts the schema into a map or, for boolean schemas, a bool
+func (s *Schema) value() interface{} {
+	if s.boolean != nil {
+		return *s.boolean
+	}
+
+	result := make(map[string]interface{})
+	for _, keyword := range s.keywords() {
+		switch value := keyword.value.(type) {
+		case *Schema:
+			result[keyword.key] = value.value()
+		case []*Schema:
+			schemas := make([]interface{}, 0, len(value))
+			for _, schema := range value {
+				schemas = append(schemas, schema.value())
+			}
+			result[keyword.key] = schemas
+		case *Properties:
+			properties := make(map[string]interface{}, value.Len())
+			for _, name := range value.names {
+				properties[name] = value.schemas[name].value()
+			}
+			result[keyword.key] = properties
+		case map[string]*Schema:
+			definitions := make(map[string]interface{}, len(value))
+			for name, schema := range value {
+				definitions[name] = schema.value()
+			}
+			result[keyword.key] = definitions
+		case []string:
+			items := make([]interface{}, 0, len(value))
+			for _, item := range value {
+				items = append(items, item)
+			}
+			result[keyword.key] = items
+		case *float64:
+			result[keyword.key] = *value
+		default:
+			result[keyword.key] = value
+		}
+	}
+	return result
+}
+
+// clone returns a shallow copy of the schema
+func (s *Schema) clone() *Schema {
+	copied := *s
+	return &copied
+}
+
+// merge sets the keywords that are set on other, e.g. constraints of configuration blocks
+func (s *Schema) merge(other *Schema) {
+	target := reflect.ValueOf(s).Elem()
+	source := reflect.ValueOf(other).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if target.Type().Field(i).IsExported() && !source.Field(i).IsZero() {
+			target.Field(i).Set(source.Field(i))
+		}
+	}
+}
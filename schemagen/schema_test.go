@@ -0,0 +1,97 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	schema, err := Generate(testConfig{}, WithComments(false), WithStrict(true), WithDefaults(false))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"retries", "server", "ratio", "headers", "endpoints", "legacy", "tagged", "untagged"}, schema.Properties.Names(),
+		"Properties keep the declaration order of struct fields")
+
+	data, err := json.Marshal(schema.Properties.Get("server"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"object","properties":{"endpoint":{"type":"string"},"read_timeout":{"type":["string","integer"],`+
+		`"description":"Duration string (e.g., '1s', '1h30m', '1.5s') or integer nanoseconds","pattern":"^[-+]?(0|(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$"}},`+
+		`"additionalProperties":false}`, string(data))
+
+	// Serialization is deterministic
+	first, err := json.Marshal(schema)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := json.Marshal(schema)
+		require.NoError(t, err)
+		assert.Equal(t, string(first), string(again))
+	}
+}
+
+func TestSchema_Keywords(t *testing.T) {
+	properties := NewProperties()
+	properties.Set("b", &Schema{Type: Types{"string"}, Const: "b"})
+	properties.Set("a", BoolSchema(false))
+	properties.Set("b", &Schema{Type: Types{"integer"}, Minimum: number(0), Maximum: number(10)})
+
+	schema := &Schema{
+		Schema:               SchemaVersion,
+		Type:                 Types{"object"},
+		Description:          "Test schema",
+		Deprecated:           true,
+		Properties:           properties,
+		AdditionalProperties: BoolSchema(true),
+		Required:             []string{"b"},
+		Default:              map[string]interface{}{"b": 1},
+		Extensions:           map[string]interface{}{ComponentReferenceKeyword: "extension"},
+		Defs:                 map[string]*Schema{"timeout": durationSchema()},
+	}
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []string{"b", "a"}, properties.Names(), "Replaced properties keep their position")
+	assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","description":"Test schema","deprecated":true,`+
+		`"properties":{"b":{"type":"integer","minimum":0,"maximum":10},"a":false},"additionalProperties":true,"required":["b"],`+
+		`"default":{"b":1},"x-component-reference":"extension","$defs":{"timeout":`+mustMarshal(t, durationSchema())+`}}`, string(data))
+
+	assert.Equal(t, map[string]interface{}{
+		"$schema":     SchemaVersion,
+		"type":        "object",
+		"description": "Test schema",
+		"deprecated":  true,
+		"properties": map[string]interface{}{
+			"b": map[string]interface{}{"type": "integer", "minimum": float64(0), "maximum": float64(10)},
+			"a": false,
+		},
+		"additionalProperties":    true,
+		"required":                []interface{}{"b"},
+		"default":                 map[string]interface{}{"b": 1},
+		ComponentReferenceKeyword: "extension",
+		"$defs":                   map[string]interface{}{"timeout": durationSchema().Map()},
+	}, schema.Map())
+}
+
+func TestBoolSchema(t *testing.T) {
+	value, ok := BoolSchema(false).IsBool()
+	assert.True(t, ok)
+	assert.False(t, value)
+
+	_, ok = (&Schema{}).IsBool()
+	assert.False(t, ok)
+
+	data, err := json.Marshal(BoolSchema(true))
+	require.NoError(t, err)
+	assert.Equal(t, "true", string(data))
+}
+
+// mustMarshal serializes a value to JSON
+func mustMarshal(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return string(data)
+}
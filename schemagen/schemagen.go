@@ -0,0 +1,716 @@
+// Package schemagen generates JSON schemas for OpenTelemetry collector component configurations using reflection.
+// It can be used at runtime to generate schemas for components that are not part of the embedded schemas,
+// e.g. from the default config of a private component factory.
+package schemagen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// SchemaVersion is the JSON schema dialect of the generated schemas
+const SchemaVersion = "https://json-schema.org/draft/2020-12/schema"
+
+// Option configures a Generator
+type Option func(*Generator)
+
+// WithComments enables or disables field descriptions extracted from Go source comments.
+// Comments are read from the package sources located with golang.org/x/tools/go/packages, which requires
+// the Go toolchain (or a GOPACKAGESDRIVER) and the module sources to be available. Comments are enabled by default.
+func WithComments(enabled bool) Option {
+	return func(g *Generator) {
+		g.comments = enabled
+	}
+}
+
+// WithStrict closes generated struct objects with additionalProperties:false so unknown keys are rejected.
+// Maps and interface fields keep accepting arbitrary keys.
+func WithStrict(enabled bool) Option {
+	return func(g *Generator) {
+		g.strict = enabled
+	}
+}
+
+// WithPackageDir sets the source directory of a package instead of locating it with go/packages
+func WithPackageDir(pkgPath string, dir string) Option {
+	return func(g *Generator) {
+		g.packageDirs[pkgPath] = dir
+	}
+}
+
+// Generator generates JSON schemas from Go configuration structs
+type Generator struct {
+	comments         bool
+	strict           bool
+	defaults         bool
+	heuristics       bool                         // derive number bounds from field names, see WithConstraintHeuristics
+	cacheDir         string                       // directory of the on-disk comment cache, empty if disabled
+	buildTags        []string                     // build tags used to select the source files of packages
+	packageDirs      map[string]string            // packagePath -> source directory
+	packageSources   map[string]*packageSource    // packagePath -> source files loaded with go/packages
+	commentCache     map[string]map[string]string // packagePath -> typeName.fieldName or typeName -> comment
+	definitions      map[string]*Schema           // shared definitions used by the schema being generated
+	expanding        map[reflect.Type]bool        // struct types whose schemas are being generated, to detect recursion
+	recursive        map[reflect.Type]string      // recursive struct types -> name of their definition in $defs
+	open             map[reflect.Type]bool        // struct types squashing an interface, their keys are not known statically
+	typeMappings     []TypeMapping
+	fieldConstraints []FieldConstraint
+}
+
+// NewGenerator creates a new schema generator
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{
+		comments:       true,
+		defaults:       true,
+		heuristics:     true,
+		packageDirs:    make(map[string]string),
+		packageSources: make(map[string]*packageSource),
+		commentCache:   make(map[string]map[string]string),
+		typeMappings:   append([]TypeMapping(nil), defaultTypeMappings...),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// GenerateSchema generates a JSON schema for a component configuration, e.g. the default config of a component factory.
+// The schema is returned as nested maps, use Generate for the typed schema.
+func GenerateSchema(cfg interface{}, opts ...Option) (map[string]interface{}, error) {
+	return NewGenerator(opts...).GenerateSchema(cfg)
+}
+
+// Generate generates the typed JSON schema of a component configuration
+func Generate(cfg interface{}, opts ...Option) (*Schema, error) {
+	return NewGenerator(opts...).Generate(cfg)
+}
+
+// GenerateSchema generates a JSON schema for a component configuration as nested maps.
+// The configuration must be a struct or a pointer to a struct.
+func (g *Generator) GenerateSchema(cfg interface{}) (map[string]interface{}, error) {
+	schema, err := g.Generate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Map(), nil
+}
+
+// Generate generates the typed JSON schema of a component configuration.
+// The configuration must be a struct or a pointer to a struct.
+func (g *Generator) Generate(cfg interface{}) (*Schema, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config must not be nil")
+	}
+
+	configType := reflect.TypeOf(cfg)
+	if configType.Kind() == reflect.Ptr {
+		configType = configType.Elem()
+	}
+	if configType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct, got %s", configType.Kind())
+	}
+
+	if g.comments {
+		g.preloadPackages(configType)
+	}
+
+	schema := &Schema{
+		Schema:      SchemaVersion,
+		Type:        Types{"object"},
+		Description: g.extractTypeComment(configType),
+		Properties:  NewProperties(),
+	}
+
+	g.definitions = make(map[string]*Schema)
+	g.expanding = map[reflect.Type]bool{configType: true}
+	g.recursive = make(map[reflect.Type]string)
+	g.open = make(map[reflect.Type]bool)
+	if err := g.analyzeStructFields(configType, schema.Properties); err != nil {
+		return nil, err
+	}
+	g.closeObject(schema, configType)
+
+	// A configuration type referencing itself is also a definition, references cannot point to the root
+	// because component schemas are embedded into the schema of the collector configuration
+	if name, ok := g.recursive[configType]; ok {
+		definition := &Schema{Type: Types{"object"}, Properties: schema.Properties}
+		g.closeObject(definition, configType)
+		g.definitions[name] = definition
+	}
+
+	if g.defaults {
+		addDefaults(schema, reflect.ValueOf(cfg))
+	}
+
+	if len(g.definitions) > 0 {
+		schema.Defs = g.definitions
+	}
+
+	return schema, nil
+}
+
+// analyzeStructFields recursively analyzes struct fields to build JSON schema properties
+func (g *Generator) analyzeStructFields(structType reflect.Type, properties *Properties) error {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		// Skip unexported fields
+		if !field.IsExported() {
+			continue
+		}
+
+		// Handle embedded and squashed fields by flattening them
+		if isSquashed(field) {
+			if err := g.handleEmbeddedField(field, structType, properties); err != nil {
+				return fmt.Errorf("failed to handle squashed field %s: %w", field.Name, err)
+			}
+			continue
+		}
+
+		fieldName := getFieldName(field)
+		if fieldName == "" || fieldName == "-" {
+			continue
+		}
+
+		property, err := g.generatePropertySchema(field, structType)
+		if err != nil {
+			return fmt.Errorf("failed to generate property schema for field %s: %w", field.Name, err)
+		}
+
+		properties.Set(fieldName, property)
+	}
+
+	return nil
+}
+
+// handleEmbeddedField handles embedded and squashed struct fields by flattening their properties
+func (g *Generator) handleEmbeddedField(field reflect.StructField, parentType reflect.Type, properties *Properties) error {
+	fieldType := field.Type
+
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+	case reflect.Interface:
+		return g.handleSquashedInterface(fieldType, parentType, properties)
+	default:
+		return fmt.Errorf("mapstructure cannot squash fields of kind %s", fieldType.Kind())
+	}
+
+	// A struct embedding itself through a pointer cannot be flattened
+	if g.expanding[fieldType] {
+		return nil
+	}
+
+	// Mapped object types contribute their properties, other mappings cannot be flattened
+	if flattened, err := g.flattenMapping(fieldType, properties); flattened || err != nil {
+		return err
+	}
+
+	if err := g.analyzeStructFields(fieldType, properties); err != nil {
+		return err
+	}
+	if g.open[fieldType] {
+		g.open[parentType] = true
+	}
+	return nil
+}
+
+// handleSquashedInterface handles interface fields tagged with ",squash", mapstructure decodes the fields of the
+// struct the interface holds from the parent. Mapped interfaces with an object schema contribute its properties,
+// the keys of other interfaces are only known at runtime so the parent object is not closed in strict mode.
+func (g *Generator) handleSquashedInterface(fieldType reflect.Type, parentType reflect.Type, properties *Properties) error {
+	if flattened, err := g.flattenMapping(fieldType, properties); flattened || err != nil {
+		return err
+	}
+	g.open[parentType] = true
+	return nil
+}
+
+// flattenMapping adds the properties of the mapped object schema of a type, flattened is false if the type is not
+// mapped or its schema has no properties, e.g. a oneOf of implementations
+func (g *Generator) flattenMapping(t reflect.Type, properties *Properties) (flattened bool, err error) {
+	mapping := g.lookupTypeMapping(t)
+	if mapping == nil {
+		return false, nil
+	}
+	schema, err := mapping.Mapper(g, t)
+	if err != nil {
+		return false, err
+	}
+	if schema.Properties == nil {
+		return false, nil
+	}
+	for _, name := range schema.Properties.Names() {
+		properties.Set(name, schema.Properties.Get(name))
+	}
+	return true, nil
+}
+
+// isSquashed returns whether mapstructure decodes the fields of a struct field from its parent, i.e. fields tagged
+// with ",squash" whether they are named or embedded. Embedded structs without a mapstructure name are flattened too,
+// other embedded types like named booleans or interfaces are decoded like named fields under their type name.
+func isSquashed(field reflect.StructField) bool {
+	parts := strings.Split(field.Tag.Get("mapstructure"), ",")
+	for _, option := range parts[1:] {
+		if option == "squash" {
+			return true
+		}
+	}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return field.Anonymous && parts[0] == "" && fieldType.Kind() == reflect.Struct
+}
+
+// getFieldName gets the field name for JSON, preferring mapstructure tag
+func getFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("mapstructure"); tag != "" {
+		parts := strings.Split(tag, ",")
+		if len(parts) > 0 && parts[0] != "" {
+			return parts[0]
+		}
+	}
+
+	if tag := field.Tag.Get("json"); tag != "" {
+		parts := strings.Split(tag, ",")
+		if len(parts) > 0 && parts[0] != "" && parts[0] != "-" {
+			return parts[0]
+		}
+	}
+
+	return strings.ToLower(field.Name)
+}
+
+// generatePropertySchema generates a JSON schema property for a struct field
+func (g *Generator) generatePropertySchema(field reflect.StructField, parentType reflect.Type) (*Schema, error) {
+	fieldType := field.Type
+
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	// Shared blocks like the exporterhelper queue are referenced from $defs
+	definition := lookupSharedDefinition(parentType, field.Name)
+	if definition == nil {
+		definition = lookupSharedDefinition(fieldType, "")
+	}
+	if definition != nil {
+		reference, err := g.reference(definition, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		return g.describeProperty(reference, field, parentType), nil
+	}
+
+	if mapping := g.lookupTypeMapping(fieldType); mapping != nil {
+		mapped, err := mapping.Mapper(g, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Wrapper {
+			return mapped, nil
+		}
+		return g.describeProperty(mapped, field, parentType), nil
+	}
+
+	var property *Schema
+	switch fieldType.Kind() {
+	case reflect.String:
+		property = &Schema{Type: Types{"string"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		property = &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		property = &Schema{Type: Types{"number"}}
+	case reflect.Bool:
+		property = &Schema{Type: Types{"boolean"}}
+	case reflect.Slice, reflect.Array:
+		itemSchema, err := g.generateTypeSchema(fieldType.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate array item schema: %w", err)
+		}
+		property = &Schema{Type: Types{"array"}, Items: itemSchema}
+	case reflect.Map:
+		property = g.generateMapSchema(fieldType)
+	case reflect.Struct:
+		var err error
+		property, err = g.expandStruct(fieldType, func() (*Schema, error) {
+			nested := &Schema{Type: Types{"object"}, Description: g.extractTypeComment(fieldType)}
+			nestedProperties := NewProperties()
+			if err := g.analyzeStructFields(fieldType, nestedProperties); err != nil {
+				return nil, fmt.Errorf("failed to analyze struct fields: %w", err)
+			}
+			if nestedProperties.Len() > 0 {
+				nested.Properties = nestedProperties
+			}
+			g.closeObject(nested, fieldType)
+			return nested, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case reflect.Interface:
+		property = &Schema{Type: Types{"object"}, AdditionalProperties: BoolSchema(true)}
+	default:
+		property = &Schema{Type: Types{"object"}}
+	}
+
+	property = customUnmarshalerSchema(fieldType, property)
+	g.constrainProperty(property, getFieldName(field), parentType, field.Name)
+
+	// Regular expressions and globs are annotated so they can be compiled during validation
+	if format := lookupPatternFormat(parentType, field.Name); format != "" {
+		annotatePattern(property, format)
+	}
+
+	return g.describeProperty(property, field, parentType), nil
+}
+
+// describeProperty adds the description and deprecation of a struct field to its property schema
+func (g *Generator) describeProperty(property *Schema, field reflect.StructField, parentType reflect.Type) *Schema {
+	// Description from source code comments, the description tag or a plain yaml tag
+	var description string
+	if comment := g.extractFieldComment(parentType, field.Name); comment != "" {
+		description = comment
+	} else if desc := field.Tag.Get("description"); desc != "" {
+		description = desc
+	} else if desc := field.Tag.Get("yaml"); desc != "" && !strings.Contains(desc, ",") {
+		description = desc
+	}
+	// Mapped schemas like durations keep their own description, the doc comment of a struct type is replaced
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if description != "" && (property.Description == "" || property.Description == g.extractTypeComment(fieldType)) {
+		property.Description = description
+	}
+
+	if isFieldDeprecated(field, description) {
+		property.Deprecated = true
+	}
+
+	return property
+}
+
+// generateTypeSchema generates a schema for a specific reflect.Type
+func (g *Generator) generateTypeSchema(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if definition := lookupSharedDefinition(t, ""); definition != nil {
+		return g.reference(definition, t)
+	}
+
+	if mapping := g.lookupTypeMapping(t); mapping != nil {
+		return mapping.Mapper(g, t)
+	}
+
+	return customUnmarshalerSchema(t, g.generateLayoutSchema(t)), nil
+}
+
+// generateLayoutSchema generates the schema of a type from its Go kind and fields
+func (g *Generator) generateLayoutSchema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Slice, reflect.Array:
+		schema := &Schema{Type: Types{"array"}}
+		if itemSchema, err := g.generateTypeSchema(t.Elem()); err == nil {
+			schema.Items = itemSchema
+		}
+		return schema
+	case reflect.Map:
+		return g.generateMapSchema(t)
+	case reflect.Interface:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: BoolSchema(true)}
+	case reflect.Struct:
+		return g.generateStructSchema(t)
+	default:
+		return &Schema{Type: Types{"object"}}
+	}
+}
+
+// generateMapSchema generates an object schema from a map type. Values are described by additionalProperties,
+// mapstructure decodes keys that are not strings from their YAML string, e.g. component.ID with UnmarshalText or
+// integers, so their format is described by propertyNames. Values of maps of empty interfaces, e.g.
+// map[string]any, can be any YAML value.
+func (g *Generator) generateMapSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: Types{"object"}, AdditionalProperties: BoolSchema(true)}
+	if elem := t.Elem(); elem.Kind() != reflect.Interface || elem.NumMethod() > 0 {
+		if valueSchema, err := g.generateTypeSchema(elem); err == nil && valueSchema != nil {
+			schema.AdditionalProperties = valueSchema
+		}
+	}
+	if pattern := g.mapKeyPattern(t.Key()); pattern != "" {
+		schema.PropertyNames = &Schema{Pattern: pattern}
+	}
+	return schema
+}
+
+// mapKeyPattern returns the pattern of the keys of a map with a key type, mapped types like component.ID use the
+// pattern of their schema. Plain string keys and keys of unknown formats are not constrained.
+func (g *Generator) mapKeyPattern(key reflect.Type) string {
+	if mapping := g.lookupTypeMapping(key); mapping != nil {
+		if schema, err := mapping.Mapper(g, key); err == nil && schema != nil {
+			return schema.Pattern
+		}
+		return ""
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return `^[-+]?[0-9]+$`
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return `^\+?[0-9]+$`
+	case reflect.Float32, reflect.Float64:
+		return `^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	case reflect.Bool:
+		return `^(true|false|True|False|TRUE|FALSE|1|0|t|f|T|F)$`
+	}
+	return ""
+}
+
+// generateStructSchema generates an object schema from the fields of a struct type
+func (g *Generator) generateStructSchema(t reflect.Type) *Schema {
+	schema, _ := g.expandStruct(t, func() (*Schema, error) {
+		schema := &Schema{Type: Types{"object"}, Description: g.extractTypeComment(t)}
+		properties := NewProperties()
+		if err := g.analyzeStructFields(t, properties); err == nil && properties.Len() > 0 {
+			schema.Properties = properties
+		}
+		g.closeObject(schema, t)
+		return schema, nil
+	})
+	return schema
+}
+
+// unwrapOptionalType unwraps configoptional.Optional[T] and similar wrapper types
+func (g *Generator) unwrapOptionalType(optionalType reflect.Type) (*Schema, error) {
+	// configoptional.Optional[T] has a field named "value" that contains the actual T value
+	if field, ok := optionalType.FieldByName("value"); ok {
+		return g.generateTypeSchema(field.Type)
+	}
+
+	// Fall back to any exported struct field that might contain the wrapped type
+	for i := 0; i < optionalType.NumField(); i++ {
+		field := optionalType.Field(i)
+		if !field.IsExported() || field.Name == "_" || field.Name == "flavor" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType.NumField() > 0 {
+			return g.generateTypeSchema(fieldType)
+		}
+	}
+
+	return &Schema{Type: Types{"object"}}, nil
+}
+
+// closeObject rejects unknown keys of the object schema of a struct type in strict mode. Structs implementing
+// confmap.Unmarshaler are flagged instead, their Unmarshal method may accept keys their fields do not declare.
+func (g *Generator) closeObject(schema *Schema, t reflect.Type) {
+	if isConfmapUnmarshaler(t) {
+		if schema.Extensions == nil {
+			schema.Extensions = make(map[string]interface{})
+		}
+		schema.Extensions[CustomUnmarshalerKeyword] = UnmarshalerConfmap
+		return
+	}
+	if g.strict && !g.open[t] {
+		schema.AdditionalProperties = BoolSchema(false)
+	}
+}
+
+// durationPattern matches the time.ParseDuration grammar: an optionally signed sequence of decimal numbers
+// with a unit suffix, e.g. "300ms", "-1.5h" or "2h45m", or a plain "0"
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// durationSchema returns the schema of a duration, the collector decodes both duration strings and integer nanoseconds
+func durationSchema() *Schema {
+	return &Schema{
+		Type:        Types{"string", "integer"},
+		Pattern:     durationPattern,
+		Description: "Duration string (e.g., '1s', '1h30m', '1.5s') or integer nanoseconds",
+	}
+}
+
+// extractFieldComment extracts comments for a struct field from source code
+func (g *Generator) extractFieldComment(parentType reflect.Type, fieldName string) string {
+	if !g.comments || parentType.PkgPath() == "" || parentType.Kind() != reflect.Struct {
+		return ""
+	}
+
+	pkgPath := parentType.PkgPath()
+	if err := g.loadCommentsForPackage(pkgPath); err != nil {
+		return ""
+	}
+
+	return g.commentCache[pkgPath][fmt.Sprintf("%s.%s", parentType.Name(), fieldName)]
+}
+
+// extractTypeComment extracts the doc comment of a struct type from source code
+func (g *Generator) extractTypeComment(t reflect.Type) string {
+	if !g.comments || t.PkgPath() == "" || t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	pkgPath := t.PkgPath()
+	if err := g.loadCommentsForPackage(pkgPath); err != nil {
+		return ""
+	}
+
+	return g.commentCache[pkgPath][t.Name()]
+}
+
+// loadCommentsForPackage loads comments for all structs in a Go package
+func (g *Generator) loadCommentsForPackage(pkgPath string) error {
+	if _, exists := g.commentCache[pkgPath]; exists {
+		return nil
+	}
+	g.commentCache[pkgPath] = make(map[string]string)
+
+	source, err := g.findPackageSource(pkgPath)
+	if err != nil {
+		return err
+	}
+	if comments, ok := g.readCommentCache(pkgPath, source.moduleVersion); ok {
+		g.commentCache[pkgPath] = comments
+		return nil
+	}
+
+	fset := token.NewFileSet()
+	for _, filename := range source.files {
+		file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("failed to parse package %s: %w", pkgPath, err)
+		}
+		g.extractCommentsFromFile(file, pkgPath)
+	}
+	g.writeCommentCache(pkgPath, source.moduleVersion, g.commentCache[pkgPath])
+
+	return nil
+}
+
+// extractCommentsFromFile extracts struct type and field comments from a single Go file
+func (g *Generator) extractCommentsFromFile(file *ast.File, pkgPath string) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		// The doc comment of a single type declaration belongs to the declaration, not to its type spec
+		if decl, ok := n.(*ast.GenDecl); ok && decl.Tok == token.TYPE && !decl.Lparen.IsValid() && decl.Doc != nil {
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Doc == nil {
+					typeSpec.Doc = decl.Doc
+				}
+			}
+			return true
+		}
+
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		if typeSpec.Doc != nil {
+			if comment := cleanComment(typeSpec.Doc.Text()); comment != "" {
+				g.commentCache[pkgPath][typeSpec.Name.Name] = comment
+			}
+		}
+
+		for _, field := range structType.Fields.List {
+			// Prefer the doc comment over the trailing line comment
+			var comment string
+			if field.Doc != nil {
+				comment = cleanComment(field.Doc.Text())
+			} else if field.Comment != nil {
+				comment = cleanComment(field.Comment.Text())
+			}
+			if comment == "" {
+				continue
+			}
+
+			for _, name := range field.Names {
+				g.commentCache[pkgPath][fmt.Sprintf("%s.%s", typeSpec.Name.Name, name.Name)] = comment
+			}
+		}
+		return true
+	})
+}
+
+// cleanComment removes comment markers and joins the comment lines
+func cleanComment(comment string) string {
+	var cleanedLines []string
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "//")
+		line = strings.TrimPrefix(line, "/*")
+		line = strings.TrimSuffix(line, "*/")
+		line = strings.TrimSpace(line)
+
+		if line != "" {
+			cleanedLines = append(cleanedLines, line)
+		}
+	}
+
+	return strings.Join(cleanedLines, " ")
+}
+
+// deprecatedKeywords are phrases in field comments that mark a field as deprecated
+var deprecatedKeywords = []string{
+	"deprecated",
+	"deprecation",
+	"no longer",
+	"obsolete",
+	"legacy",
+	"do not use",
+	"will be removed",
+	"use instead",
+	"replaced by",
+}
+
+// isFieldDeprecated checks if a field is deprecated based on its tags and description
+func isFieldDeprecated(field reflect.StructField, description string) bool {
+	if tag := field.Tag.Get("deprecated"); tag != "" {
+		return true
+	}
+
+	for _, tagName := range []string{"mapstructure", "json"} {
+		for _, part := range strings.Split(field.Tag.Get(tagName), ",") {
+			if strings.TrimSpace(part) == "deprecated" {
+				return true
+			}
+		}
+	}
+
+	lowerDesc := strings.ToLower(description)
+	for _, keyword := range deprecatedKeywords {
+		if description != "" && strings.Contains(lowerDesc, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
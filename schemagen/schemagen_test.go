@@ -0,0 +1,356 @@
+package schemagen
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// testServerConfig is a nested test configuration
+type testServerConfig struct {
+	// Endpoint is the address the server listens on
+	Endpoint string `mapstructure:"endpoint"`
+	// ReadTimeout limits the time to read a request
+	ReadTimeout time.Duration `mapstructure:"read_timeout"`
+}
+
+// EmbeddedSettings is embedded into the test configuration
+type EmbeddedSettings struct {
+	// Retries is the number of retries
+	Retries int `mapstructure:"retries"`
+}
+
+// testConfig is a test component configuration
+type testConfig struct {
+	EmbeddedSettings `mapstructure:",squash"`
+
+	// Server contains the server configuration
+	Server testServerConfig `mapstructure:"server"`
+	// Ratio is the sampling ratio
+	Ratio float64 `mapstructure:"ratio"`
+	// Headers are added to every request
+	Headers map[string]string `mapstructure:"headers"`
+	// Endpoints lists additional servers
+	Endpoints []*testServerConfig `mapstructure:"endpoints"`
+	// Legacy is deprecated, use Server instead
+	Legacy   bool   `mapstructure:"legacy"`
+	Tagged   string `mapstructure:"tagged" description:"Tagged has a description tag"`
+	Skipped  string `mapstructure:"-"`
+	Untagged bool
+
+	unexported string
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(&testConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaVersion, schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"retries", "server", "ratio", "headers", "endpoints", "legacy", "tagged", "untagged"}, keys(properties))
+
+	assert.Equal(t, map[string]interface{}{
+		"type":        "integer",
+		"description": "Retries is the number of retries",
+	}, properties["retries"], "Embedded struct fields should be flattened")
+	assert.Equal(t, map[string]interface{}{
+		"type":        "number",
+		"description": "Ratio is the sampling ratio",
+	}, properties["ratio"])
+	assert.Equal(t, map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "string"},
+		"description":          "Headers are added to every request",
+	}, properties["headers"])
+	assert.Equal(t, map[string]interface{}{
+		"type":        "boolean",
+		"description": "Legacy is deprecated, use Server instead",
+		"deprecated":  true,
+	}, properties["legacy"])
+	assert.Equal(t, "Tagged has a description tag", properties["tagged"].(map[string]interface{})["description"])
+
+	server := properties["server"].(map[string]interface{})
+	assert.Equal(t, "object", server["type"])
+	assert.Equal(t, "Server contains the server configuration", server["description"])
+	serverProperties := server["properties"].(map[string]interface{})
+	assert.Equal(t, "Endpoint is the address the server listens on", serverProperties["endpoint"].(map[string]interface{})["description"])
+	assert.Equal(t, durationSchema().Map(), serverProperties["read_timeout"])
+
+	endpoints := properties["endpoints"].(map[string]interface{})
+	assert.Equal(t, "array", endpoints["type"])
+	assert.Equal(t, "object", endpoints["items"].(map[string]interface{})["type"])
+}
+
+type (
+	// groupedConfig is declared in a type group
+	groupedConfig struct {
+		// Server has a field comment that takes precedence over the type comment
+		Server   testServerConfig   `mapstructure:"server"`
+		Fallback *testServerConfig  `mapstructure:"fallback"`
+		Servers  []testServerConfig `mapstructure:"servers"`
+	}
+)
+
+func TestGenerateSchema_TypeComments(t *testing.T) {
+	schema, err := GenerateSchema(groupedConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "groupedConfig is declared in a type group", schema["description"])
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, "Server has a field comment that takes precedence over the type comment", properties["server"].(map[string]interface{})["description"])
+	assert.Equal(t, "testServerConfig is a nested test configuration", properties["fallback"].(map[string]interface{})["description"],
+		"Struct fields without a comment are described by their type")
+	assert.Equal(t, "testServerConfig is a nested test configuration", properties["servers"].(map[string]interface{})["items"].(map[string]interface{})["description"])
+
+	schema, err = GenerateSchema(testConfig{}, WithComments(false))
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "description")
+}
+
+func TestGenerateSchema_WithoutComments(t *testing.T) {
+	schema, err := GenerateSchema(testConfig{}, WithComments(false))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["retries"])
+	assert.Equal(t, "Tagged has a description tag", properties["tagged"].(map[string]interface{})["description"])
+}
+
+func TestGenerateSchema_WithPackageDir(t *testing.T) {
+	// The configured package directory is empty, so no comments are found
+	schema, err := GenerateSchema(testConfig{}, WithPackageDir("github.com/pavolloffay/opentelemetry-collector-config-schema/schemagen", t.TempDir()))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["retries"])
+}
+
+func TestGenerateSchema_InvalidConfig(t *testing.T) {
+	_, err := GenerateSchema(nil)
+	require.Error(t, err)
+
+	_, err = GenerateSchema("config")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config must be a struct, got string")
+}
+
+// squashedTimeout is squashed into squashedConfig through a named field
+type squashedTimeout struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// squashedQueue is squashed into squashedConfig through a pointer
+type squashedQueue struct {
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// NamedEmbedded is embedded into squashedConfig with a name, so it is not squashed
+type NamedEmbedded struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// squashedConfig is a test configuration with named squashed fields
+type squashedConfig struct {
+	TimeoutConfig squashedTimeout `mapstructure:",squash"`
+	Queue         *squashedQueue  `mapstructure:",squash"`
+	NamedEmbedded `mapstructure:"named"`
+	Endpoint      string `mapstructure:"endpoint"`
+}
+
+func TestGenerateSchema_Squash(t *testing.T) {
+	cfg := &squashedConfig{
+		TimeoutConfig: squashedTimeout{Timeout: 5 * time.Second},
+		Queue:         &squashedQueue{QueueSize: 100},
+		NamedEmbedded: NamedEmbedded{Enabled: true},
+	}
+	schema, err := GenerateSchema(cfg, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"timeout", "queue_size", "named", "endpoint"}, keys(properties))
+	assert.Equal(t, map[string]interface{}{
+		"timeout":    "5s",
+		"queue_size": int64(100),
+		"named":      map[string]interface{}{"enabled": true},
+	}, schema["default"])
+
+	// The default configuration is encoded with the same keys by mapstructure
+	var encoded map[string]interface{}
+	require.NoError(t, mapstructure.Decode(cfg, &encoded))
+	delete(encoded, "endpoint")
+	assert.ElementsMatch(t, keys(encoded), keys(schema["default"].(map[string]interface{})))
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+
+	tests := []map[string]interface{}{
+		{"endpoint": "localhost:4317", "timeout": "5s", "queue_size": 10, "named": map[string]interface{}{"enabled": false}},
+		{"timeoutconfig": map[string]interface{}{"timeout": "5s"}},
+		{"queue": map[string]interface{}{"queue_size": 10}},
+		{"enabled": true},
+	}
+	for _, config := range tests {
+		// The schema accepts exactly the configurations mapstructure decodes without unused keys
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:  mapstructure.StringToTimeDurationHookFunc(),
+			ErrorUnused: true,
+			Result:      &squashedConfig{Queue: &squashedQueue{}},
+		})
+		require.NoError(t, err)
+		decodeErr := decoder.Decode(config)
+
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(config))
+		require.NoError(t, err)
+		assert.Equal(t, decodeErr == nil, result.Valid(), "%v: decode error %v, schema errors %v", config, decodeErr, result.Errors())
+	}
+}
+
+// Verbose is a test flag embedded into embeddedKindsConfig
+type Verbose bool
+
+// Labels is a test list embedded into embeddedKindsConfig
+type Labels []string
+
+// Settings is a test interface embedded into configurations, like component.Config
+type Settings interface{}
+
+// embeddedKindsConfig is a test configuration embedding types that are not structs
+type embeddedKindsConfig struct {
+	Verbose
+	Labels
+	Settings
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func TestGenerateSchema_EmbeddedKinds(t *testing.T) {
+	cfg := &embeddedKindsConfig{Verbose: true, Labels: Labels{"a"}}
+	schema, err := GenerateSchema(cfg, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["verbose"], "Embedded basic types are fields named after their type")
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, properties["labels"])
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": true}, properties["settings"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Equal(t, map[string]interface{}{"verbose": true, "labels": []interface{}{"a"}}, schema["default"])
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+
+	tests := []map[string]interface{}{
+		{"verbose": true, "labels": []interface{}{"a", "b"}, "settings": map[string]interface{}{"key": "value"}, "endpoint": "localhost:4317"},
+		{"verbose": "yes"},
+		{"labels": "a"},
+		{"key": "value"},
+	}
+	for _, config := range tests {
+		// The schema accepts exactly the configurations mapstructure decodes without unused keys
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			ErrorUnused: true,
+			Result:      &embeddedKindsConfig{},
+		})
+		require.NoError(t, err)
+		decodeErr := decoder.Decode(config)
+
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(config))
+		require.NoError(t, err)
+		assert.Equal(t, decodeErr == nil, result.Valid(), "%v: decode error %v, schema errors %v", config, decodeErr, result.Errors())
+	}
+}
+
+// squashedSettingsConfig squashes an interface holding the settings of an implementation
+type squashedSettingsConfig struct {
+	Settings `mapstructure:",squash"`
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// squashedNestedConfig squashes a struct squashing an interface
+type squashedNestedConfig struct {
+	Inner squashedSettingsConfig `mapstructure:",squash"`
+}
+
+func TestGenerateSchema_SquashedInterface(t *testing.T) {
+	cfg := &squashedSettingsConfig{Settings: &squashedQueue{QueueSize: 100}, Endpoint: "localhost:4317"}
+	schema, err := GenerateSchema(cfg, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"endpoint"}, keys(schema["properties"].(map[string]interface{})))
+	assert.NotContains(t, schema, "additionalProperties", "Keys of squashed interfaces are only known at runtime")
+	assert.Equal(t, map[string]interface{}{"endpoint": "localhost:4317", "queue_size": int64(100)}, schema["default"],
+		"Defaults of the struct held by the interface are flattened")
+
+	schema, err = GenerateSchema(squashedNestedConfig{}, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "additionalProperties", "Structs squashing open structs are open")
+
+	mapped := NewProperties()
+	mapped.Set("queue_size", &Schema{Type: Types{"integer"}})
+	schema, err = GenerateSchema(squashedSettingsConfig{}, WithComments(false), WithStrict(true),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  reflect.TypeOf((*Settings)(nil)).Elem().PkgPath(),
+			TypeName: "Settings",
+			Mapper:   StaticSchema(&Schema{Type: Types{"object"}, Properties: mapped}),
+		}))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"queue_size", "endpoint"}, keys(schema["properties"].(map[string]interface{})),
+		"Mapped interfaces contribute their properties")
+	assert.Equal(t, false, schema["additionalProperties"])
+}
+
+// squashedFlagConfig squashes a basic type, mapstructure rejects such configurations
+type squashedFlagConfig struct {
+	Verbose `mapstructure:",squash"`
+}
+
+func TestGenerateSchema_SquashedBasicType(t *testing.T) {
+	_, err := GenerateSchema(squashedFlagConfig{}, WithComments(false))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mapstructure cannot squash fields of kind bool")
+}
+
+// keys returns the keys of a map
+func keys(m map[string]interface{}) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
+
+func TestGenerateSchema_WithStrict(t *testing.T) {
+	schema, err := GenerateSchema(testConfig{}, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, false, properties["server"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, false, properties["endpoints"].(map[string]interface{})["items"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["headers"].(map[string]interface{})["additionalProperties"], "Maps should accept arbitrary keys")
+
+	schema, err = GenerateSchema(testConfig{}, WithComments(false))
+	require.NoError(t, err)
+	assert.NotContains(t, schema, "additionalProperties")
+}
+
+func TestDurationPattern(t *testing.T) {
+	pattern := regexp.MustCompile(durationPattern)
+
+	tests := []string{
+		"0", "1s", "300ms", "1h30m", "2h45m30.5s", "1.5s", ".5s", "1.s", "-1s", "+10m", "-0", "1µs", "1μs", "1us", "10ns",
+		"", "1", "s", "1d", "1 s", "5 seconds", "1s-1s", ".s", "1.5.5s", "--1s",
+	}
+
+	for _, value := range tests {
+		_, err := time.ParseDuration(value)
+		assert.Equal(t, err == nil, pattern.MatchString(value), value)
+	}
+}
@@ -0,0 +1,203 @@
+package schemagen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TypeMapper returns the schema of a Go type. Mappers can generate the schemas of other types,
+// e.g. of the value wrapped by a generic type, with Generator.TypeSchema.
+type TypeMapper func(g *Generator, t reflect.Type) (*Schema, error)
+
+// TypeMapping maps the Go types with a name in a package to a schema instead of reflecting over their fields
+type TypeMapping struct {
+	// PkgPath is the import path of the package declaring the type
+	PkgPath string
+	// TypeName is the name of the type, generic types match all instantiations, e.g. "Optional" matches "Optional[T]"
+	TypeName string
+	// Mapper returns the schema of the type
+	Mapper TypeMapper
+	// Wrapper marks types whose schema is the schema of the wrapped value, field descriptions are not added to it
+	Wrapper bool
+}
+
+// WithTypeMapping adds a type mapping, it takes precedence over the default mappings and mappings added before
+func WithTypeMapping(mapping TypeMapping) Option {
+	return func(g *Generator) {
+		g.typeMappings = append(g.typeMappings, mapping)
+	}
+}
+
+// StaticSchema returns a mapper that maps a type to a copy of a fixed schema
+func StaticSchema(schema *Schema) TypeMapper {
+	return func(_ *Generator, _ reflect.Type) (*Schema, error) {
+		return schema.clone(), nil
+	}
+}
+
+// StringOrObject returns a mapper for types that accept a string shorthand or their expanded form, e.g. an endpoint
+// given as "host:port" or as an object with host and port. The expanded form is generated from the Go type, shorthand
+// is the schema of the string form, nil accepts any string.
+func StringOrObject(shorthand *Schema) TypeMapper {
+	return func(g *Generator, t reflect.Type) (*Schema, error) {
+		return stringOrExpanded(shorthand, g.generateLayoutSchema(t)), nil
+	}
+}
+
+// stringOrExpanded returns a oneOf of the string shorthand of a value and its expanded form
+func stringOrExpanded(shorthand *Schema, expanded *Schema) *Schema {
+	if shorthand == nil {
+		shorthand = stringSchema
+	}
+	return &Schema{OneOf: []*Schema{shorthand.clone(), expanded}}
+}
+
+// constrainedStruct returns a mapper that reflects over the fields of a struct and merges constraints into the
+// generated schema by dot separated property path, see applyConstraints
+func constrainedStruct(constraints map[string]*Schema) TypeMapper {
+	return func(g *Generator, t reflect.Type) (*Schema, error) {
+		schema := g.generateStructSchema(t)
+		applyConstraints(schema, constraints)
+		return schema, nil
+	}
+}
+
+// stringSchema is the schema of types that are decoded from strings
+var stringSchema = &Schema{Type: Types{"string"}}
+
+// defaultTypeMappings maps collector config primitives whose Go representation does not match their YAML representation
+var defaultTypeMappings = []TypeMapping{
+	{PkgPath: "time", TypeName: "Duration", Mapper: func(_ *Generator, _ reflect.Type) (*Schema, error) {
+		return durationSchema(), nil
+	}},
+	{PkgPath: "time", TypeName: "Time", Mapper: StaticSchema(&Schema{
+		Type:   Types{"string"},
+		Format: "date-time",
+	})},
+	{PkgPath: "go.opentelemetry.io/collector/config/configoptional", TypeName: "Optional", Mapper: unwrapOptional, Wrapper: true},
+	{PkgPath: "go.opentelemetry.io/collector/component", TypeName: "ID", Mapper: StaticSchema(componentIDSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/component", TypeName: "Type", Mapper: StaticSchema(componentTypeSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/pipeline", TypeName: "ID", Mapper: StaticSchema(pipelineIDSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/confmap", TypeName: "Conf", Mapper: StaticSchema(&Schema{
+		Type:                 Types{"object"},
+		AdditionalProperties: BoolSchema(true),
+	})},
+	{PkgPath: "go.opentelemetry.io/collector/config/configtls", TypeName: "Config", Mapper: constrainedStruct(map[string]*Schema{
+		"min_version": {Enum: tlsVersions},
+		"max_version": {Enum: tlsVersions},
+	})},
+	{PkgPath: "go.opentelemetry.io/collector/config/confignet", TypeName: "AddrConfig", Mapper: constrainedStruct(map[string]*Schema{
+		"transport": {Enum: transportTypes},
+	})},
+	{PkgPath: "net/url", TypeName: "URL", Mapper: StaticSchema(uriSchema)},
+	{PkgPath: "github.com/prometheus/common/config", TypeName: "URL", Mapper: StaticSchema(uriSchema)},
+	{PkgPath: "regexp", TypeName: "Regexp", Mapper: StaticSchema(regexSchema)},
+	{PkgPath: "github.com/prometheus/prometheus/model/relabel", TypeName: "Regexp", Mapper: StaticSchema(regexSchema)},
+	{PkgPath: "net", TypeName: "IP", Mapper: StaticSchema(stringSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/config/configopaque", TypeName: "String", Mapper: StaticSchema(secretSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/config/configopaque", TypeName: "MapList", Mapper: StaticSchema(headersSchema)},
+	{PkgPath: "go.opentelemetry.io/collector/config/configtelemetry", TypeName: "Level", Mapper: StaticSchema(telemetryLevelSchema)},
+}
+
+// ComponentReferenceKeyword marks component.ID fields, its value is the kind of the referenced component
+const ComponentReferenceKeyword = "x-component-reference"
+
+// componentIDPattern matches component IDs in the type[/name] format
+const componentIDPattern = `^[a-zA-Z][0-9a-zA-Z_]*(/[^\s]+)?$`
+
+// componentIDSchema is the schema of component.ID fields, collector configs reference extensions by ID
+var componentIDSchema = &Schema{
+	Type:       Types{"string"},
+	Pattern:    componentIDPattern,
+	Extensions: map[string]interface{}{ComponentReferenceKeyword: "extension"},
+}
+
+// componentTypeSchema is the schema of component.Type values, e.g. the keys of maps of component factories
+var componentTypeSchema = &Schema{
+	Type:    Types{"string"},
+	Pattern: `^[a-zA-Z][0-9a-zA-Z_]*$`,
+}
+
+// pipelineIDSchema is the schema of pipeline IDs in the signal[/name] format, e.g. the pipelines of the routing
+// and failover connectors
+var pipelineIDSchema = &Schema{
+	Type:    Types{"string"},
+	Pattern: `^(traces|metrics|logs|profiles)(/[^\s]+)?$`,
+}
+
+// secretSchema is the schema of opaque strings holding secrets, e.g. passwords, tokens and header values
+var secretSchema = &Schema{
+	Type:      Types{"string"},
+	WriteOnly: true,
+}
+
+// headersSchema is the schema of configopaque.MapList values, e.g. the headers of confighttp and configgrpc clients.
+// They are configured as a map of header names to opaque values or as a list of name and value pairs, values that
+// are not strings are rejected.
+var headersSchema = &Schema{
+	OneOf: []*Schema{
+		{Type: Types{"object"}, AdditionalProperties: secretSchema},
+		{Type: Types{"array"}, Items: &Schema{Type: Types{"object"}, Properties: headerPairProperties()}},
+	},
+}
+
+// headerPairProperties returns the properties of a header in the list form of configopaque.MapList
+func headerPairProperties() *Properties {
+	properties := NewProperties()
+	properties.Set("name", stringSchema)
+	properties.Set("value", secretSchema)
+	return properties
+}
+
+// uriSchema is the schema of URLs
+var uriSchema = &Schema{
+	Type:   Types{"string"},
+	Format: "uri",
+}
+
+// regexSchema is the schema of regular expressions
+var regexSchema = &Schema{
+	Type:   Types{"string"},
+	Format: FormatRegex,
+}
+
+// tlsVersions are the TLS versions accepted by configtls
+var tlsVersions = []interface{}{"1.0", "1.1", "1.2", "1.3"}
+
+// telemetryLevelSchema is the schema of configtelemetry.Level, an integer written by its name, e.g. the verbosity of
+// the debug exporter
+var telemetryLevelSchema = &Schema{
+	Type: Types{"string"},
+	Enum: []interface{}{"none", "basic", "normal", "detailed"},
+}
+
+// transportTypes are the transports accepted by confignet
+var transportTypes = []interface{}{"tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "ip", "ip4", "ip6", "unix", "unixgram", "unixpacket"}
+
+// unwrapOptional maps configoptional.Optional[T] to the schema of T. Optional sections are enabled with their defaults
+// by an empty value, e.g. "grpc:" in the protocols of the otlp receiver, so object schemas also accept null.
+func unwrapOptional(g *Generator, t reflect.Type) (*Schema, error) {
+	schema, err := g.unwrapOptionalType(t)
+	if err != nil || !schema.Type.Is("object") {
+		return schema, err
+	}
+	nullable := schema.clone()
+	nullable.Type = Types{"object", "null"}
+	return nullable, nil
+}
+
+// lookupTypeMapping returns the mapping of a type, mappings added last take precedence
+func (g *Generator) lookupTypeMapping(t reflect.Type) *TypeMapping {
+	for i := len(g.typeMappings) - 1; i >= 0; i-- {
+		mapping := &g.typeMappings[i]
+		if mapping.PkgPath == t.PkgPath() && (t.Name() == mapping.TypeName || strings.HasPrefix(t.Name(), mapping.TypeName+"[")) {
+			return mapping
+		}
+	}
+	return nil
+}
+
+// TypeSchema generates the schema of a Go type, applying type mappings and shared definitions
+func (g *Generator) TypeSchema(t reflect.Type) (*Schema, error) {
+	return g.generateTypeSchema(t)
+}
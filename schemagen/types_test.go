@@ -0,0 +1,321 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// AddrSettings is a test configuration block embedded into other configurations
+type AddrSettings struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Transport string `mapstructure:"transport"`
+}
+
+// Wrapped is a test generic wrapper type
+type Wrapped[T any] struct {
+	value T
+}
+
+// Level is a test type decoded from a string
+type Level struct {
+	level int
+}
+
+// mappedConfig uses types with default and custom type mappings
+type mappedConfig struct {
+	AddrSettings `mapstructure:",squash"`
+
+	Interval time.Duration             `mapstructure:"interval" description:"Interval between runs"`
+	Started  time.Time                 `mapstructure:"started"`
+	Proxy    *url.URL                  `mapstructure:"proxy"`
+	Include  *regexp.Regexp            `mapstructure:"include"`
+	Address  net.IP                    `mapstructure:"address"`
+	Level    Level                     `mapstructure:"level" description:"Log level"`
+	Levels   []Level                   `mapstructure:"levels"`
+	Server   Wrapped[testServerConfig] `mapstructure:"server" description:"Wrapped server"`
+}
+
+func TestGenerateSchema_DefaultTypeMappings(t *testing.T) {
+	schema, err := GenerateSchema(mappedConfig{}, WithComments(false))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, durationSchema().Map(), properties["interval"], "Mapped schemas keep their own description")
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["started"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "uri"}, properties["proxy"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "regex"}, properties["include"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["address"])
+	assert.Equal(t, map[string]interface{}{"type": "object", "description": "Log level"}, properties["level"], "Unmapped structs without exported fields are opaque objects")
+}
+
+func TestGenerateSchema_WithTypeMapping(t *testing.T) {
+	pkgPath := reflect.TypeOf(Level{}).PkgPath()
+
+	schema, err := GenerateSchema(mappedConfig{}, WithComments(false),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  pkgPath,
+			TypeName: "Level",
+			Mapper:   StaticSchema(&Schema{Type: Types{"string"}, Enum: []interface{}{"info", "debug"}}),
+		}),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  pkgPath,
+			TypeName: "Wrapped",
+			Mapper: func(g *Generator, t reflect.Type) (*Schema, error) {
+				return g.TypeSchema(t.Field(0).Type)
+			},
+			Wrapper: true,
+		}),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  pkgPath,
+			TypeName: "AddrSettings",
+			Mapper: constrainedStruct(map[string]*Schema{
+				"transport": {Enum: []interface{}{"tcp", "udp"}},
+			}),
+		}),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  "time",
+			TypeName: "Duration",
+			Mapper:   StaticSchema(&Schema{Type: Types{"integer"}}),
+		}),
+	)
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"info", "debug"}, "description": "Log level"}, properties["level"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"info", "debug"}}, properties["levels"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "description": "Interval between runs"}, properties["interval"], "Added mappings take precedence over the defaults")
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"tcp", "udp"}}, properties["transport"], "Embedded mapped objects are flattened")
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["endpoint"])
+
+	server := properties["server"].(map[string]interface{})
+	assert.NotContains(t, server, "description", "Wrapper schemas are not described")
+	assert.ElementsMatch(t, []string{"endpoint", "read_timeout"}, keys(server["properties"].(map[string]interface{})))
+}
+
+func TestGenerateSchema_SecretMapping(t *testing.T) {
+	// configopaque.String is mapped by package path, the mapping is applied to a test type the same way
+	schema, err := GenerateSchema(mappedConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(Level{}).PkgPath(),
+		TypeName: "Level",
+		Mapper:   StaticSchema(secretSchema),
+	}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "writeOnly": true, "description": "Log level"}, properties["level"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "writeOnly": true}, properties["levels"].(map[string]interface{})["items"])
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.NoError(t, VerifySchema(data))
+}
+
+func TestGenerateSchema_TelemetryLevelMapping(t *testing.T) {
+	// configtelemetry.Level is mapped by package path, the mapping is applied to a test type the same way
+	schema, err := GenerateSchema(mappedConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(Level{}).PkgPath(),
+		TypeName: "Level",
+		Mapper:   StaticSchema(telemetryLevelSchema),
+	}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type":        "string",
+		"enum":        []interface{}{"none", "basic", "normal", "detailed"},
+		"description": "Log level",
+	}, properties["level"])
+}
+
+// testHeaderPair is a header of a testHeaders list, like configopaque.Pair
+type testHeaderPair struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+// testHeaders stands in for configopaque.MapList in tests
+type testHeaders []testHeaderPair
+
+// clientConfig is a test configuration with headers
+type clientConfig struct {
+	Headers testHeaders `mapstructure:"headers"`
+}
+
+func TestGenerateSchema_HeadersMapping(t *testing.T) {
+	// configopaque.MapList is mapped by package path, the mapping is applied to a test type the same way
+	schema, err := GenerateSchema(clientConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(testHeaders{}).PkgPath(),
+		TypeName: "testHeaders",
+		Mapper:   StaticSchema(headersSchema),
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string", "writeOnly": true},
+			},
+			map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":  map[string]interface{}{"type": "string"},
+						"value": map[string]interface{}{"type": "string", "writeOnly": true},
+					},
+				},
+			},
+		},
+	}, schema["properties"].(map[string]interface{})["headers"])
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		headers interface{}
+		valid   bool
+	}{
+		{headers: map[string]interface{}{"Authorization": "Bearer token", "X-Scope-OrgID": "tenant"}, valid: true},
+		{headers: map[string]interface{}{"X-Retries": 3}, valid: false},
+		{headers: map[string]interface{}{"X-Tenant": map[string]interface{}{"id": "tenant"}}, valid: false},
+		{headers: "Authorization: Bearer token", valid: false},
+		{headers: []interface{}{map[string]interface{}{"name": "Authorization", "value": "Bearer token"}}, valid: true},
+		{headers: []interface{}{map[string]interface{}{"name": "X-Retries", "value": 3}}, valid: false},
+		{headers: []interface{}{"Authorization: Bearer token"}, valid: false},
+	}
+	for _, test := range tests {
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"headers": test.headers}))
+		require.NoError(t, err)
+		assert.Equal(t, test.valid, result.Valid(), "%v: %v", test.headers, result.Errors())
+	}
+}
+
+// dualFormConfig has fields accepting a string shorthand or an object
+type dualFormConfig struct {
+	Addr  AddrSettings     `mapstructure:"addr"`
+	Addrs []AddrSettings   `mapstructure:"addrs"`
+	Proxy testServerConfig `mapstructure:"proxy"`
+}
+
+func TestGenerateSchema_StringOrObject(t *testing.T) {
+	pkgPath := reflect.TypeOf(AddrSettings{}).PkgPath()
+	schema, err := GenerateSchema(dualFormConfig{}, WithComments(false), WithStrict(true),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  pkgPath,
+			TypeName: "AddrSettings",
+			Mapper:   StringOrObject(&Schema{Type: Types{"string"}, Pattern: `^[^:]+:[0-9]+$`}),
+		}),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  pkgPath,
+			TypeName: "testServerConfig",
+			Mapper:   StringOrObject(nil),
+		}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	addr := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string", "pattern": `^[^:]+:[0-9]+$`},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"endpoint":  map[string]interface{}{"type": "string"},
+					"transport": map[string]interface{}{"type": "string"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+	assert.Equal(t, addr, properties["addr"])
+	assert.Equal(t, addr, properties["addrs"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["proxy"].(map[string]interface{})["oneOf"].([]interface{})[0],
+		"Shorthands accept any string by default")
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+	for document, valid := range map[string]bool{
+		`{"addr": "localhost:4317", "proxy": "proxy"}`:                             true,
+		`{"addr": {"endpoint": "localhost:4317"}, "proxy": {"endpoint": "proxy"}}`: true,
+		`{"addrs": ["localhost:4317", {"transport": "tcp"}]}`:                      true,
+		`{"addr": "localhost"}`:                                                    false,
+		`{"addr": {"host": "localhost"}}`:                                          false,
+		`{"addr": 4317}`:                                                           false,
+	} {
+		result, err := compiled.Validate(gojsonschema.NewStringLoader(document))
+		require.NoError(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+}
+
+// componentID is a test map key type decoded from a type[/name] string
+type componentID struct {
+	typ  string
+	name string
+}
+
+// keyedConfig uses maps with non-string keys
+type keyedConfig struct {
+	Routes     map[componentID][]string      `mapstructure:"routes"`
+	Priorities map[int]testServerConfig      `mapstructure:"priorities"`
+	Weights    map[uint8]float64             `mapstructure:"weights"`
+	Nested     []map[componentID]componentID `mapstructure:"nested"`
+	Attributes map[string]interface{}        `mapstructure:"attributes"`
+	Opaque     map[Level]string              `mapstructure:"opaque"`
+}
+
+func TestGenerateSchema_MapKeys(t *testing.T) {
+	schema, err := GenerateSchema(keyedConfig{}, WithComments(false), WithTypeMapping(TypeMapping{
+		PkgPath:  reflect.TypeOf(componentID{}).PkgPath(),
+		TypeName: "componentID",
+		Mapper:   StaticSchema(componentIDSchema),
+	}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	routes := properties["routes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"pattern": componentIDPattern}, routes["propertyNames"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, routes["additionalProperties"])
+
+	priorities := properties["priorities"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"pattern": `^[-+]?[0-9]+$`}, priorities["propertyNames"])
+	assert.ElementsMatch(t, []string{"endpoint", "read_timeout"}, keys(priorities["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})), "Values of non-string keys have a schema")
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["weights"].(map[string]interface{})["additionalProperties"])
+
+	nested := properties["nested"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"pattern": componentIDPattern}, nested["propertyNames"])
+	assert.Equal(t, componentIDPattern, nested["additionalProperties"].(map[string]interface{})["pattern"])
+
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": true}, properties["attributes"], "Values of empty interfaces can be any YAML value")
+	assert.NotContains(t, properties["opaque"], "propertyNames", "Keys of unknown formats are not constrained")
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+	for document, valid := range map[string]bool{
+		`{"routes": {"otlp/2": ["a"]}, "priorities": {"1": {}}, "weights": {"3": 0.5}}`: true,
+		`{"routes": {"otlp 2": ["a"]}}`: false,
+		`{"priorities": {"first": {}}}`: false,
+		`{"weights": {"-1": 0.5}}`:      false,
+		`{"attributes": {"a": "b"}}`:    true,
+	} {
+		result, err := compiled.Validate(gojsonschema.NewStringLoader(document))
+		require.NoError(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+}
+
+func TestGenerateSchema_PipelineIDs(t *testing.T) {
+	pattern := regexp.MustCompile(pipelineIDSchema.Pattern)
+	for value, valid := range map[string]bool{"traces": true, "logs/2": true, "profiles/a_b": true, "trace": false, "metrics/": false, "logs/a b": false} {
+		assert.Equal(t, valid, pattern.MatchString(value), value)
+	}
+}
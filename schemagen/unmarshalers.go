@@ -0,0 +1,54 @@
+package schemagen
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// CustomUnmarshalerKeyword marks schemas of types with a custom unmarshaler, its value is the kind of the unmarshaler
+const CustomUnmarshalerKeyword = "x-custom-unmarshaler"
+
+// Kinds of custom unmarshalers recorded under CustomUnmarshalerKeyword
+const (
+	// UnmarshalerText marks types implementing encoding.TextUnmarshaler, they are also decoded from strings
+	UnmarshalerText = "text"
+	// UnmarshalerConfmap marks types implementing confmap.Unmarshaler, they may decode keys their fields do not declare
+	UnmarshalerConfmap = "confmap"
+)
+
+// confmapPkgPath is the import path of the collector confmap package, it is a variable so tests can use their own Conf
+var confmapPkgPath = "go.opentelemetry.io/collector/confmap"
+
+// textUnmarshalerType is the type of encoding.TextUnmarshaler
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isTextUnmarshaler returns whether a type or a pointer to it implements encoding.TextUnmarshaler.
+// The collector decodes strings into such types with UnmarshalText.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isConfmapUnmarshaler returns whether a pointer to a type implements confmap.Unmarshaler, i.e. has an
+// Unmarshal(*confmap.Conf) error method. The method is matched by its signature, schemagen does not depend on confmap.
+func isConfmapUnmarshaler(t reflect.Type) bool {
+	method, ok := reflect.PointerTo(t).MethodByName("Unmarshal")
+	if !ok || method.Type.NumIn() != 2 || method.Type.NumOut() != 1 {
+		return false
+	}
+	conf := method.Type.In(1)
+	return conf.Kind() == reflect.Ptr && conf.Elem().Name() == "Conf" && conf.Elem().PkgPath() == confmapPkgPath &&
+		method.Type.Out(0) == reflect.TypeOf((*error)(nil)).Elem()
+}
+
+// customUnmarshalerSchema adapts the schema generated from the layout of a type with a custom unmarshaler.
+// Types decoded from strings with UnmarshalText accept a string or their layout, string and interface types are
+// returned unchanged. Types implementing confmap.Unmarshaler are flagged, closeObject keeps their objects open.
+// Register a type mapping to replace the schema of a type whose unmarshaler accepts other shapes.
+func customUnmarshalerSchema(t reflect.Type, layout *Schema) *Schema {
+	if t.Kind() == reflect.String || t.Kind() == reflect.Interface || !isTextUnmarshaler(t) {
+		return layout
+	}
+	schema := stringOrExpanded(nil, layout)
+	schema.Extensions = map[string]interface{}{CustomUnmarshalerKeyword: UnmarshalerText}
+	return schema
+}
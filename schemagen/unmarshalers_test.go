@@ -0,0 +1,155 @@
+package schemagen
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// testVerbosity is a test level decoded from its name or its number
+type testVerbosity int8
+
+func (v *testVerbosity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "basic":
+		*v = 0
+	case "detailed":
+		*v = 1
+	default:
+		return fmt.Errorf("unknown verbosity %q", text)
+	}
+	return nil
+}
+
+// testEndpoint is decoded from a "host:port" string or from its fields
+type testEndpoint struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+func (e *testEndpoint) UnmarshalText(text []byte) error {
+	host, port, err := net.SplitHostPort(string(text))
+	if err != nil {
+		return err
+	}
+	e.Host = host
+	e.Port, err = strconv.Atoi(port)
+	return err
+}
+
+// testCompression is a string type validated by UnmarshalText
+type testCompression string
+
+func (c *testCompression) UnmarshalText(text []byte) error {
+	*c = testCompression(text)
+	return nil
+}
+
+// Conf stands in for confmap.Conf in tests
+type Conf struct{}
+
+// legacyProtocols accepts keys of a previous layout in its confmap.Unmarshaler
+type legacyProtocols struct {
+	GRPC string `mapstructure:"grpc"`
+}
+
+func (p *legacyProtocols) Unmarshal(_ *Conf) error { return nil }
+
+// unmarshalerConfig is a test configuration with fields of types with custom unmarshalers
+type unmarshalerConfig struct {
+	Verbosity   testVerbosity   `mapstructure:"verbosity"`
+	Endpoint    testEndpoint    `mapstructure:"endpoint"`
+	Endpoints   []testEndpoint  `mapstructure:"endpoints"`
+	Compression testCompression `mapstructure:"compression"`
+	Protocols   legacyProtocols `mapstructure:"protocols"`
+}
+
+// useTestConf matches Unmarshal methods taking the Conf of the test package
+func useTestConf(t *testing.T) {
+	previous := confmapPkgPath
+	confmapPkgPath = reflect.TypeOf(Conf{}).PkgPath()
+	t.Cleanup(func() { confmapPkgPath = previous })
+}
+
+func TestGenerateSchema_CustomUnmarshalers(t *testing.T) {
+	useTestConf(t)
+
+	schema, err := GenerateSchema(unmarshalerConfig{}, WithComments(false), WithStrict(true))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"oneOf":                  []interface{}{map[string]interface{}{"type": "string"}, map[string]interface{}{"type": "integer"}},
+		CustomUnmarshalerKeyword: UnmarshalerText,
+	}, properties["verbosity"])
+	endpoint := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"host": map[string]interface{}{"type": "string"},
+					"port": map[string]interface{}{"type": "integer", "minimum": float64(0), "maximum": float64(65535)},
+				},
+				"additionalProperties": false,
+			},
+		},
+		CustomUnmarshalerKeyword: UnmarshalerText,
+	}
+	assert.Equal(t, endpoint, properties["endpoint"])
+	assert.Equal(t, endpoint, properties["endpoints"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["compression"], "String types are decoded from strings anyway")
+	assert.Equal(t, map[string]interface{}{
+		"type":                   "object",
+		"properties":             map[string]interface{}{"grpc": map[string]interface{}{"type": "string"}},
+		CustomUnmarshalerKeyword: UnmarshalerConfmap,
+	}, properties["protocols"], "Objects with a confmap.Unmarshaler are not closed")
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		config map[string]interface{}
+		valid  bool
+	}{
+		{config: map[string]interface{}{"verbosity": "detailed", "endpoint": "localhost:4317"}, valid: true},
+		{config: map[string]interface{}{"verbosity": 1, "endpoint": map[string]interface{}{"host": "localhost", "port": 4317}}, valid: true},
+		{config: map[string]interface{}{"endpoints": []interface{}{"localhost:4317", map[string]interface{}{"host": "localhost"}}}, valid: true},
+		{config: map[string]interface{}{"protocols": map[string]interface{}{"grpc": "localhost:4317", "thrift": true}}, valid: true},
+		{config: map[string]interface{}{"endpoint": map[string]interface{}{"address": "localhost"}}, valid: false},
+		{config: map[string]interface{}{"verbosity": true}, valid: false},
+	}
+	for _, test := range tests {
+		result, err := compiled.Validate(gojsonschema.NewGoLoader(test.config))
+		require.NoError(t, err)
+		assert.Equal(t, test.valid, result.Valid(), "%v: %v", test.config, result.Errors())
+	}
+}
+
+func TestGenerateSchema_CustomUnmarshalerMapping(t *testing.T) {
+	schema, err := GenerateSchema(unmarshalerConfig{}, WithComments(false),
+		WithTypeMapping(TypeMapping{
+			PkgPath:  reflect.TypeOf(testEndpoint{}).PkgPath(),
+			TypeName: "testEndpoint",
+			Mapper:   StaticSchema(&Schema{Type: Types{"string"}, Pattern: `^[^:]+:[0-9]+$`}),
+		}))
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "pattern": `^[^:]+:[0-9]+$`}, properties["endpoint"],
+		"Mappings replace the schema of types with custom unmarshalers")
+}
+
+func TestIsConfmapUnmarshaler(t *testing.T) {
+	assert.False(t, isConfmapUnmarshaler(reflect.TypeOf(legacyProtocols{})), "Conf is not declared by confmap")
+
+	useTestConf(t)
+	assert.True(t, isConfmapUnmarshaler(reflect.TypeOf(legacyProtocols{})))
+	assert.False(t, isConfmapUnmarshaler(reflect.TypeOf(testEndpoint{})))
+}
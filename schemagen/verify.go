@@ -0,0 +1,52 @@
+package schemagen
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// verifyURL is the location serialized schemas are compiled at, references are resolved relative to it
+const verifyURL = "urn:schemagen:verify"
+
+// VerifySchema compiles a serialized schema with a JSON schema 2020-12 validator, or the draft of its $schema
+// keyword, e.g. for schemas converted with Draft07. It fails if the schema is not valid against the metaschema, e.g. keywords with wrong types or patterns that are not regular expressions,
+// or if a reference cannot be resolved.
+func VerifySchema(data []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(verifyURL, doc); err != nil {
+		return fmt.Errorf("failed to add schema: %w", err)
+	}
+	if _, err := compiler.Compile(verifyURL); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	return nil
+}
+
+// ValidateInstance validates a value against a serialized schema, e.g. an example configuration of a component
+// decoded with json.Decoder.UseNumber. Numbers must be json.Number or float64, maps map[string]interface{}. The error
+// lists every location of the value the schema rejects.
+func ValidateInstance(schema []byte, instance interface{}) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(verifyURL, doc); err != nil {
+		return fmt.Errorf("failed to add schema: %w", err)
+	}
+	compiled, err := compiler.Compile(verifyURL)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	return compiled.Validate(instance)
+}
@@ -0,0 +1,74 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySchema(t *testing.T) {
+	withTestDefinitions(t)
+	withTestPatterns(t)
+
+	for name, config := range map[string]interface{}{
+		"config":      testConfig{},
+		"definitions": exporterConfig{},
+		"recursive":   routingConfig{},
+		"patterns":    matchConfig{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			schema, err := Generate(config, WithComments(false), WithStrict(true))
+			require.NoError(t, err)
+			data, err := json.Marshal(schema)
+			require.NoError(t, err)
+			assert.NoError(t, VerifySchema(data))
+		})
+	}
+}
+
+func TestVerifySchema_Invalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		schema string
+		err    string
+	}{
+		"type":     {schema: `{"type":"objet"}`, err: "/type"},
+		"pattern":  {schema: `{"type":"string","pattern":"(["}`, err: "not valid regex"},
+		"required": {schema: `{"type":"object","required":"endpoint"}`, err: "/required"},
+		"ref":      {schema: `{"$ref":"#/$defs/missing"}`, err: "not found"},
+		"json":     {schema: `{"type":`, err: "failed to parse schema"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := VerifySchema([]byte(test.schema))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestValidateInstance(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"endpoint": {"type": "string"},
+			"port": {"type": "integer", "maximum": 65535},
+			"tls": {"$ref": "#/$defs/tls"}
+		},
+		"$defs": {"tls": {"type": "object", "properties": {"insecure": {"type": "boolean"}}}}
+	}`)
+
+	assert.NoError(t, ValidateInstance(schema, map[string]interface{}{
+		"endpoint": "localhost:4317",
+		"port":     json.Number("4317"),
+		"tls":      map[string]interface{}{"insecure": true},
+	}))
+
+	err := ValidateInstance(schema, map[string]interface{}{"port": json.Number("70000"), "tls": map[string]interface{}{"insecure": "yes"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/port")
+	assert.Contains(t, err.Error(), "/tls/insecure")
+
+	assert.ErrorContains(t, ValidateInstance([]byte(`{"type":`), nil), "failed to parse schema")
+	assert.ErrorContains(t, ValidateInstance([]byte(`{"$ref":"#/$defs/missing"}`), nil), "invalid schema")
+}
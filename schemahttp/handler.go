@@ -0,0 +1,173 @@
+// Package schemahttp serves the component schemas over HTTP in the layout of a schema registry, e.g. for editor
+// plugins that poll for schema updates or as the registry of WithRemoteRegistry. Responses carry an ETag derived
+// from the SHA-256 checksum of their content and requests with a matching If-None-Match are answered with 304 Not
+// Modified, so clients only download schemas that changed.
+package schemahttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+const (
+	// latestVersion is the version segment of the URLs of the latest embedded version, e.g. /latest/bundle.json
+	latestVersion = "latest"
+	bundleFile    = "bundle.json"
+	checksumsFile = "checksums.json"
+)
+
+// VersionHeader is the response header that holds the collector version of the served schema, it is set for
+// /latest/ URLs as well
+const VersionHeader = "X-Collector-Version"
+
+// Handler serves the schemas of a SchemaManager:
+//
+//	/<version>/bundle.json           the schema bundle of a version, see GetSchemaBundle
+//	/<version>/checksums.json        the schema manifest of the bundle, see SchemaManifest
+//	/<version>/<type>_<name>.json    the schema of a component, e.g. /0.139.0/receiver_otlp.json
+//
+// The version "latest" resolves to the latest embedded version.
+type Handler struct {
+	schemaManager *collectorschema.SchemaManager
+}
+
+// NewHandler creates a new handler serving the schemas of a SchemaManager
+func NewHandler(schemaManager *collectorschema.SchemaManager) *Handler {
+	return &Handler{schemaManager: schemaManager}
+}
+
+// document is a file served by the handler
+type document struct {
+	contentType string
+	// write writes the document, it is called once to compute the ETag and once more to send the body
+	write func(w io.Writer) error
+}
+
+// ServeHTTP serves GET and HEAD requests of schema files
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "only GET and HEAD are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	version, file, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok || version == "" || strings.Contains(file, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if version == latestVersion {
+		latest, err := h.schemaManager.GetLatestVersion()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		version = latest
+	}
+
+	doc, err := h.document(r, version, file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// The content is hashed without being held in memory, bundles are encoded again for the body
+	checksum := sha256.New()
+	if err := doc.write(checksum); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	etag := `"` + hex.EncodeToString(checksum.Sum(nil)) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set(VersionHeader, version)
+	// Clients may keep the schemas but revalidate them on every use
+	w.Header().Set("Cache-Control", "no-cache")
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", doc.contentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	// The status is sent with the first write, errors of the body cannot be reported anymore
+	_ = doc.write(w)
+}
+
+// document returns the schema file of a version
+func (h *Handler) document(r *http.Request, version string, file string) (*document, error) {
+	switch file {
+	case bundleFile:
+		return &document{
+			contentType: "application/schema+json",
+			write: func(w io.Writer) error {
+				return h.schemaManager.WriteBundleContext(r.Context(), w, version)
+			},
+		}, nil
+	case checksumsFile:
+		data, err := h.checksums(r, version)
+		if err != nil {
+			return nil, err
+		}
+		return staticDocument("application/json", data), nil
+	}
+
+	componentType, componentName, ok := strings.Cut(strings.TrimSuffix(file, ".json"), "_")
+	if !ok || !strings.HasSuffix(file, ".json") {
+		return nil, fmt.Errorf("unknown schema file %q", file)
+	}
+	data, err := h.schemaManager.GetComponentSchemaJSON(collectorschema.ComponentType(componentType), componentName, version)
+	if err != nil {
+		return nil, err
+	}
+	return staticDocument("application/schema+json", data), nil
+}
+
+// staticDocument returns a document of encoded content
+func staticDocument(contentType string, data []byte) *document {
+	return &document{
+		contentType: contentType,
+		write: func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		},
+	}
+}
+
+// checksums returns the schema manifest of the bundle of a version, the bundle is hashed as it is encoded
+func (h *Handler) checksums(r *http.Request, version string) ([]byte, error) {
+	checksum := sha256.New()
+	if err := h.schemaManager.WriteBundleContext(r.Context(), checksum, version); err != nil {
+		return nil, err
+	}
+	manifest := collectorschema.SchemaManifest{
+		Version: version,
+		Files:   map[string]string{bundleFile: hex.EncodeToString(checksum.Sum(nil))},
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema manifest: %w", err)
+	}
+	return data, nil
+}
+
+// matchesETag returns whether an If-None-Match header matches an ETag, weak validators match their strong
+// counterpart
+func matchesETag(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package schemahttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// get sends a request to the handler and returns the response
+func get(t *testing.T, handler http.Handler, method string, path string, ifNoneMatch string) *http.Response {
+	request := httptest.NewRequest(method, path, nil)
+	if ifNoneMatch != "" {
+		request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func readBody(t *testing.T, response *http.Response) string {
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandler_ComponentSchema(t *testing.T) {
+	schemaManager := collectorschema.NewSchemaManager()
+	handler := NewHandler(schemaManager)
+
+	response := get(t, handler, http.MethodGet, "/0.139.0/receiver_otlp.json", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	expected, err := schemaManager.GetComponentSchemaJSON(collectorschema.ComponentTypeReceiver, "otlp", "0.139.0")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), readBody(t, response))
+	assert.Equal(t, "application/schema+json", response.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+	assert.Equal(t, "0.139.0", response.Header.Get(VersionHeader))
+	etag := response.Header.Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{64}"$`, etag)
+
+	// Names of components may contain underscores
+	response = get(t, handler, http.MethodGet, "/0.139.0/processor_tail_sampling.json", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEqual(t, etag, response.Header.Get("ETag"), "Different content has a different ETag")
+
+	for _, path := range []string{"/0.139.0/receiver_doesnotexist.json", "/0.0.1/receiver_otlp.json", "/0.139.0/otlp.json", "/0.139.0/receiver_otlp.yaml", "/0.139.0", "/0.139.0/receiver/otlp.json"} {
+		assert.Equal(t, http.StatusNotFound, get(t, handler, http.MethodGet, path, "").StatusCode, path)
+	}
+}
+
+func TestHandler_ConditionalRequests(t *testing.T) {
+	handler := NewHandler(collectorschema.NewSchemaManager())
+
+	first := get(t, handler, http.MethodGet, "/0.139.0/bundle.json", "")
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	etag := first.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	second := get(t, handler, http.MethodGet, "/0.139.0/bundle.json", "")
+	assert.Equal(t, etag, second.Header.Get("ETag"), "The ETag is stable")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{name: "matching", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "weak", ifNoneMatch: "W/" + etag, status: http.StatusNotModified},
+		{name: "list", ifNoneMatch: `"other", ` + etag, status: http.StatusNotModified},
+		{name: "any", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "stale", ifNoneMatch: `"other"`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := get(t, handler, http.MethodGet, "/0.139.0/bundle.json", tt.ifNoneMatch)
+			assert.Equal(t, tt.status, response.StatusCode)
+			assert.Equal(t, etag, response.Header.Get("ETag"))
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, readBody(t, response))
+			}
+		})
+	}
+
+	// The ETag of another version differs
+	other := get(t, handler, http.MethodGet, "/0.138.0/bundle.json", etag)
+	assert.Equal(t, http.StatusOK, other.StatusCode)
+}
+
+func TestHandler_Bundle(t *testing.T) {
+	schemaManager := collectorschema.NewSchemaManager()
+	handler := NewHandler(schemaManager)
+	latest, err := schemaManager.GetLatestVersion()
+	require.NoError(t, err)
+
+	var expected bytes.Buffer
+	require.NoError(t, schemaManager.WriteBundle(&expected, latest))
+
+	response := get(t, handler, http.MethodGet, "/latest/bundle.json", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, latest, response.Header.Get(VersionHeader))
+	assert.Equal(t, expected.String(), readBody(t, response))
+
+	response = get(t, handler, http.MethodHead, "/latest/bundle.json", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("ETag"))
+	assert.Empty(t, readBody(t, response))
+
+	manifestResponse := get(t, handler, http.MethodGet, "/"+latest+"/checksums.json", "")
+	require.Equal(t, http.StatusOK, manifestResponse.StatusCode)
+	manifest, err := collectorschema.ParseSchemaManifest([]byte(readBody(t, manifestResponse)))
+	require.NoError(t, err)
+	assert.NoError(t, manifest.Verify("bundle.json", expected.Bytes()))
+
+	response = get(t, handler, http.MethodPost, "/latest/bundle.json", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	assert.Equal(t, "GET, HEAD", response.Header.Get("Allow"))
+}
+
+func TestHandler_RemoteRegistry(t *testing.T) {
+	served := collectorschema.NewSchemaManager()
+	require.NoError(t, served.RegisterCustomSchema(collectorschema.ComponentTypeReceiver, "inhouse", "9.9.9", []byte(`{"type": "object", "properties": {"endpoint": {"type": "string"}}}`)))
+	server := httptest.NewServer(NewHandler(served))
+	t.Cleanup(server.Close)
+
+	schemaManager := collectorschema.NewSchemaManager(collectorschema.WithRemoteRegistry(server.URL))
+	schema, err := schemaManager.GetComponentSchema(collectorschema.ComponentTypeReceiver, "inhouse", "9.9.9")
+	require.NoError(t, err)
+	assert.Contains(t, schema.Schema["properties"], "endpoint")
+}
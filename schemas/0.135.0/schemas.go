@@ -0,0 +1,18 @@
+// Package v0_135_0 embeds the component schemas of collector 0.135.0, importing it registers the version
+package v0_135_0
+
+import (
+	"embed"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemas"
+)
+
+// Schemas are embedded gzip compressed with the provenance manifest (manifest.json) and the reference
+// configurations (templates/*.yaml), bundles (bundle.json) are generated from them and not embedded
+//
+//go:embed *_*.json.gz *.md manifest.json templates/*.yaml
+var files embed.FS
+
+func init() {
+	schemas.Register("0.135.0", files)
+}
@@ -0,0 +1,10 @@
+// Package all imports the component schemas of every collector version
+package all
+
+import (
+	_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/0.135.0"
+	_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/0.136.0"
+	_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/0.137.0"
+	_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/0.138.0"
+	_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/0.139.0"
+)
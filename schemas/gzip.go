@@ -0,0 +1,134 @@
+package schemas
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// CompressedSuffix is the suffix of the gzip compressed schemas, <type>_<name>.json is embedded as
+// <type>_<name>.json.gz. Schemas compress about 10:1.
+const CompressedSuffix = ".gz"
+
+// gzipFS serves the gzip compressed files of a filesystem under their name without CompressedSuffix, files are
+// decompressed when they are read. Other files are served as they are.
+type gzipFS struct {
+	fsys fs.FS
+}
+
+var (
+	_ fs.ReadFileFS = gzipFS{}
+	_ fs.ReadDirFS  = gzipFS{}
+)
+
+// Open opens a file, the file of a compressed file holds the decompressed content
+func (g gzipFS) Open(name string) (fs.File, error) {
+	data, info, err := g.decompress(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return g.fsys.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decompressedFile{Reader: bytes.NewReader(data), info: info}, nil
+}
+
+// ReadFile reads a file, compressed files are decompressed
+func (g gzipFS) ReadFile(name string) ([]byte, error) {
+	data, _, err := g.decompress(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fs.ReadFile(g.fsys, name)
+	}
+	return data, err
+}
+
+// ReadDir lists a directory, compressed files are listed under their name without CompressedSuffix
+func (g gzipFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(g.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), CompressedSuffix) {
+			entries[i] = decompressedEntry{DirEntry: entry}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// decompress reads and decompresses the compressed file of name, it returns fs.ErrNotExist if name is not compressed
+func (g gzipFS) decompress(name string) ([]byte, fs.FileInfo, error) {
+	file, err := g.fsys.Open(name + CompressedSuffix)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decompress %s: %w", name, err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decompress %s: %w", name, err)
+	}
+	return data, decompressedInfo{FileInfo: info, size: int64(len(data))}, nil
+}
+
+// decompressedFile is an open compressed file
+type decompressedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *decompressedFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *decompressedFile) Close() error {
+	return nil
+}
+
+// decompressedEntry is the directory entry of a compressed file
+type decompressedEntry struct {
+	fs.DirEntry
+}
+
+func (e decompressedEntry) Name() string {
+	return strings.TrimSuffix(e.DirEntry.Name(), CompressedSuffix)
+}
+
+// Info returns the info of the compressed file under the decompressed name, the size is the compressed size
+func (e decompressedEntry) Info() (fs.FileInfo, error) {
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return decompressedInfo{FileInfo: info, size: info.Size()}, nil
+}
+
+// decompressedInfo is the info of a compressed file under the decompressed name
+type decompressedInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (i decompressedInfo) Name() string {
+	return strings.TrimSuffix(i.FileInfo.Name(), CompressedSuffix)
+}
+
+func (i decompressedInfo) Size() int64 {
+	return i.size
+}
@@ -0,0 +1,79 @@
+// Command compress writes the gzip compressed copies of the component schemas that are embedded by the version
+// packages of schemas, e.g. go run ./schemas/internal/compress schemas/0.139.0. It does not import the version
+// packages, their embed patterns only match once the compressed schemas exist.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemas"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: compress <schemas directory>...")
+		os.Exit(1)
+	}
+	for _, dir := range os.Args[1:] {
+		if err := compressDir(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// compressDir compresses the <type>_<name>.json schemas of a directory and removes compressed schemas whose
+// schema was removed
+func compressDir(dir string) error {
+	schemaFiles, err := filepath.Glob(filepath.Join(dir, "*_*.json"))
+	if err != nil {
+		return err
+	}
+	for _, schemaFile := range schemaFiles {
+		if err := compressFile(schemaFile); err != nil {
+			return err
+		}
+	}
+
+	compressedFiles, err := filepath.Glob(filepath.Join(dir, "*_*.json"+schemas.CompressedSuffix))
+	if err != nil {
+		return err
+	}
+	for _, compressedFile := range compressedFiles {
+		if _, err := os.Stat(strings.TrimSuffix(compressedFile, schemas.CompressedSuffix)); os.IsNotExist(err) {
+			if err := os.Remove(compressedFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("Compressed %d schemas in %s\n", len(schemaFiles), dir)
+	return nil
+}
+
+// compressFile writes the compressed copy of a schema, the gzip header has no name or modification time so
+// unchanged schemas produce identical files
+func compressFile(schemaFile string) error {
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	var compressed bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", schemaFile, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", schemaFile, err)
+	}
+	return os.WriteFile(schemaFile+schemas.CompressedSuffix, compressed.Bytes(), 0644)
+}
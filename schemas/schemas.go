@@ -0,0 +1,50 @@
+// Package schemas is the registry of the embedded component schemas. Every collector version is embedded by its
+// own sub-package, e.g. schemas/0.139.0, that registers the version when it is imported, binaries only contain
+// the versions they import. The schemas/all package imports every version.
+package schemas
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+var (
+	mu       sync.RWMutex
+	versions = make(map[string]fs.FS)
+)
+
+// Register makes the schemas of a collector version available, files holds the <type>_<name>.json schemas,
+// the <type>_<name>.md READMEs and the changelog.md of the version at its root. Schemas may be gzip compressed
+// (<type>_<name>.json.gz), they are decompressed when read. Register panics if the version is registered twice,
+// sub-packages register their version in init.
+func Register(version string, files fs.FS) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := versions[version]; exists {
+		panic(fmt.Sprintf("schemas: version %s is registered twice", version))
+	}
+	versions[version] = gzipFS{fsys: files}
+}
+
+// Lookup returns the files of a registered version, compressed schemas are served decompressed under their
+// name without CompressedSuffix
+func Lookup(version string) (fs.FS, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	files, ok := versions[version]
+	return files, ok
+}
+
+// Versions returns the registered versions sorted by name
+func Versions() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	registered := make([]string, 0, len(versions))
+	for version := range versions {
+		registered = append(registered, version)
+	}
+	sort.Strings(registered)
+	return registered
+}
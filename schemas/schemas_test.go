@@ -0,0 +1,98 @@
+package schemas
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	files := fstest.MapFS{"receiver_otlp.json": {Data: []byte(`{"type":"object"}`)}}
+	Register("0.199.0", files)
+	Register("0.198.0", fstest.MapFS{})
+
+	registered, ok := Lookup("0.199.0")
+	require.True(t, ok)
+	data, err := fs.ReadFile(registered, "receiver_otlp.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"object"}`, string(data))
+
+	_, ok = Lookup("0.197.0")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"0.198.0", "0.199.0"}, Versions())
+
+	assert.Panics(t, func() { Register("0.199.0", files) })
+}
+
+func TestRegister_Compressed(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(`{"type":"object"}`))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	Register("0.196.0", fstest.MapFS{
+		"receiver_otlp.json.gz": {Data: compressed.Bytes()},
+		"receiver_otlp.md":      {Data: []byte("# OTLP receiver")},
+	})
+	files, ok := Lookup("0.196.0")
+	require.True(t, ok)
+
+	data, err := fs.ReadFile(files, "receiver_otlp.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"object"}`, string(data))
+	data, err = fs.ReadFile(files, "receiver_otlp.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# OTLP receiver", string(data))
+
+	file, err := files.Open("receiver_otlp.json")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, "receiver_otlp.json", info.Name())
+	assert.Equal(t, int64(len(`{"type":"object"}`)), info.Size())
+	require.NoError(t, file.Close())
+
+	entries, err := fs.ReadDir(files, ".")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"receiver_otlp.json", "receiver_otlp.md"}, names)
+
+	_, err = fs.ReadFile(files, "receiver_kafka.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+// TestCompressedSchemas checks that the embedded compressed schemas match the schemas of every version,
+// run make compress-schemas after generating schemas
+func TestCompressedSchemas(t *testing.T) {
+	schemaFiles, err := filepath.Glob(filepath.Join("*.*", "*_*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, schemaFiles)
+	for _, schemaFile := range schemaFiles {
+		expected, err := os.ReadFile(schemaFile)
+		require.NoError(t, err)
+		file, err := os.Open(schemaFile + CompressedSuffix)
+		require.NoError(t, err, "Run make compress-schemas")
+		reader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		actual, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		assert.Equal(t, string(expected), string(actual), "%s is outdated, run make compress-schemas", schemaFile+CompressedSuffix)
+	}
+
+	compressedFiles, err := filepath.Glob(filepath.Join("*.*", "*_*.json"+CompressedSuffix))
+	require.NoError(t, err)
+	assert.Len(t, compressedFiles, len(schemaFiles), "Compressed schemas without a schema, run make compress-schemas")
+}
@@ -0,0 +1,7 @@
+//go:build !collectorconfigschema_selective
+
+package collectorconfigschema
+
+// Every version is embedded by default, build with the collectorconfigschema_selective tag and import the
+// sub-packages of schemas to embed only some versions
+import _ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/all"
@@ -0,0 +1,193 @@
+// Package schemaservice serves the component schemas over gRPC, e.g. for control planes that consume services over
+// a gRPC mesh. The service is defined in schema_service.proto, the Go code of the messages is generated with
+// protoc-gen-go and protoc-gen-go-grpc (see the generate-proto target of the Makefile).
+package schemaservice
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// Server implements SchemaService backed by a SchemaManager
+type Server struct {
+	UnimplementedSchemaServiceServer
+	schemaManager  *collectorschema.SchemaManager
+	defaultVersion string
+}
+
+// NewServer creates a new server. The default version is used for requests without a version; if empty, the latest
+// embedded version is used.
+func NewServer(schemaManager *collectorschema.SchemaManager, defaultVersion string) *Server {
+	return &Server{
+		schemaManager:  schemaManager,
+		defaultVersion: defaultVersion,
+	}
+}
+
+// Register mounts the service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	RegisterSchemaServiceServer(registrar, s)
+}
+
+// GetSchema returns the JSON schema of a component, NotFound is returned for unknown components
+func (s *Server) GetSchema(_ context.Context, request *GetSchemaRequest) (*GetSchemaResponse, error) {
+	version, err := s.resolveVersion(request.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	if request.GetComponentType() == "" || request.GetComponentName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "component_type and component_name are required")
+	}
+
+	schema, err := s.schemaManager.GetComponentSchemaJSON(collectorschema.ComponentType(request.GetComponentType()), request.GetComponentName(), version)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &GetSchemaResponse{Version: version, Schema: schema}, nil
+}
+
+// ListComponents returns the components of a version by type
+func (s *Server) ListComponents(_ context.Context, request *ListComponentsRequest) (*ListComponentsResponse, error) {
+	version, err := s.resolveVersion(request.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := s.schemaManager.ListAvailableComponents(version)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	response := &ListComponentsResponse{Version: version}
+	for componentType, names := range components {
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		response.Components = append(response.Components, &ComponentNames{ComponentType: string(componentType), Names: sorted})
+	}
+	sort.Slice(response.Components, func(i, j int) bool {
+		return response.Components[i].ComponentType < response.Components[j].ComponentType
+	})
+	return response, nil
+}
+
+// Validate validates a collector configuration, InvalidArgument is returned if it cannot be parsed
+func (s *Server) Validate(ctx context.Context, request *ValidateRequest) (*ValidateResponse, error) {
+	version, err := s.resolveVersion(request.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []collectorschema.ValidationOption
+	if request.GetStrict() {
+		opts = append(opts, collectorschema.Strict())
+	}
+	result, err := s.schemaManager.ValidateCollectorConfigContext(ctx, request.GetConfig(), version, opts...)
+	if err != nil {
+		return nil, contextError(err)
+	}
+
+	response := &ValidateResponse{Version: version, Valid: result.Valid()}
+	for _, validationError := range result.Errors {
+		response.Errors = append(response.Errors, &ValidationError{
+			Path:    validationError.Path,
+			Message: validationError.Message,
+			Code:    validationError.Code,
+		})
+	}
+	return response, nil
+}
+
+// Diff compares two collector configurations, InvalidArgument is returned if one cannot be parsed
+func (s *Server) Diff(_ context.Context, request *DiffRequest) (*DiffResponse, error) {
+	version, err := s.resolveVersion(request.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := s.schemaManager.DiffConfigs(request.GetOldConfig(), request.GetNewConfig(), version)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response := &DiffResponse{Version: version}
+	for _, component := range diff.Components {
+		fields, err := fieldChanges(component.Fields)
+		if err != nil {
+			return nil, err
+		}
+		response.Components = append(response.Components, &ComponentDiff{
+			ComponentType: string(component.Type),
+			Id:            component.ID,
+			Kind:          string(component.Kind),
+			Fields:        fields,
+		})
+	}
+	for _, pipeline := range diff.Pipelines {
+		fields, err := fieldChanges(pipeline.Fields)
+		if err != nil {
+			return nil, err
+		}
+		response.Pipelines = append(response.Pipelines, &PipelineDiff{Id: pipeline.ID, Kind: string(pipeline.Kind), Fields: fields})
+	}
+	if response.Service, err = fieldChanges(diff.Service); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// resolveVersion returns the requested version, the default version or the latest embedded version
+func (s *Server) resolveVersion(version string) (string, error) {
+	if version == "" {
+		version = s.defaultVersion
+	}
+	if version != "" {
+		return version, nil
+	}
+
+	latest, err := s.schemaManager.GetLatestVersion()
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	return latest, nil
+}
+
+// fieldChanges converts the changed fields of a diff, values are JSON compatible
+func fieldChanges(changes []collectorschema.FieldChange) ([]*FieldChange, error) {
+	var converted []*FieldChange
+	for _, change := range changes {
+		field := &FieldChange{Path: change.Path, Kind: string(change.Kind)}
+		var err error
+		if change.OldValue != nil {
+			if field.OldValue, err = structpb.NewValue(change.OldValue); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		if change.NewValue != nil {
+			if field.NewValue, err = structpb.NewValue(change.NewValue); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		converted = append(converted, field)
+	}
+	return converted, nil
+}
+
+// contextError converts the errors of canceled requests to their status, other errors are invalid arguments
+func contextError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
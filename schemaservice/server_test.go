@@ -0,0 +1,108 @@
+package schemaservice
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	collectorschema "github.com/pavolloffay/opentelemetry-collector-config-schema"
+)
+
+// newTestClient serves a server over an in-memory connection and returns a client of it
+func newTestClient(t *testing.T, defaultVersion string) SchemaServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	NewServer(collectorschema.NewSchemaManager(), defaultVersion).Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewSchemaServiceClient(conn)
+}
+
+func TestServer_GetSchema(t *testing.T) {
+	client := newTestClient(t, "0.139.0")
+
+	response, err := client.GetSchema(context.Background(), &GetSchemaRequest{ComponentType: "receiver", ComponentName: "otlp"})
+	require.NoError(t, err)
+	assert.Equal(t, "0.139.0", response.GetVersion())
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(response.GetSchema(), &schema))
+	assert.Contains(t, schema, "properties")
+
+	_, err = client.GetSchema(context.Background(), &GetSchemaRequest{ComponentType: "receiver", ComponentName: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetSchema(context.Background(), &GetSchemaRequest{ComponentType: "receiver"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_ListComponents(t *testing.T) {
+	client := newTestClient(t, "")
+
+	response, err := client.ListComponents(context.Background(), &ListComponentsRequest{Version: "0.139.0"})
+	require.NoError(t, err)
+	require.Len(t, response.GetComponents(), 5)
+	assert.Equal(t, "connector", response.GetComponents()[0].GetComponentType())
+	assert.Equal(t, "receiver", response.GetComponents()[4].GetComponentType())
+	assert.Contains(t, response.GetComponents()[4].GetNames(), "otlp")
+
+	latest, err := client.ListComponents(context.Background(), &ListComponentsRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, latest.GetVersion())
+
+	_, err = client.ListComponents(context.Background(), &ListComponentsRequest{Version: "0.0.1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_Validate(t *testing.T) {
+	client := newTestClient(t, "0.139.0")
+
+	response, err := client.Validate(context.Background(), &ValidateRequest{Config: []byte("receivers:\n  unknown:\n")})
+	require.NoError(t, err)
+	assert.False(t, response.GetValid())
+	require.Len(t, response.GetErrors(), 1)
+	assert.Equal(t, "receivers.unknown", response.GetErrors()[0].GetPath())
+	assert.Equal(t, collectorschema.ErrorCodeUnknownComponent, response.GetErrors()[0].GetCode())
+
+	response, err = client.Validate(context.Background(), &ValidateRequest{Config: []byte("exporters:\n  debug:\n    verbosity_level: high\n"), Strict: true})
+	require.NoError(t, err)
+	assert.False(t, response.GetValid())
+
+	_, err = client.Validate(context.Background(), &ValidateRequest{Config: []byte("receivers: [")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_Diff(t *testing.T) {
+	client := newTestClient(t, "0.139.0")
+
+	response, err := client.Diff(context.Background(), &DiffRequest{
+		OldConfig: []byte("exporters:\n  debug:\n    verbosity: basic\n"),
+		NewConfig: []byte("exporters:\n  debug:\n    verbosity: detailed\n  otlp:\n"),
+	})
+	require.NoError(t, err)
+	require.Len(t, response.GetComponents(), 2)
+	debug := response.GetComponents()[0]
+	assert.Equal(t, "debug", debug.GetId())
+	assert.Equal(t, "modified", debug.GetKind())
+	require.Len(t, debug.GetFields(), 1)
+	assert.Equal(t, "verbosity", debug.GetFields()[0].GetPath())
+	assert.Equal(t, "basic", debug.GetFields()[0].GetOldValue().GetStringValue())
+	assert.Equal(t, "detailed", debug.GetFields()[0].GetNewValue().GetStringValue())
+	assert.Equal(t, "added", response.GetComponents()[1].GetKind())
+
+	_, err = client.Diff(context.Background(), &DiffRequest{OldConfig: []byte("receivers: [")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,62 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaCatalogSchema is the schema of JSON Schema Store catalogs
+const schemaCatalogSchema = "https://json.schemastore.org/schema-catalog.json"
+
+// collectorSchemaFileName is the file name of the collector configuration schema of the latest version in a catalog
+const collectorSchemaFileName = "otel-collector.json"
+
+// catalogFileMatch are the config file names editors validate with the collector configuration schema
+var catalogFileMatch = []string{"otel-collector*.yaml", "otel-collector*.yml"}
+
+// SchemaCatalog is a JSON Schema Store (schemastore.org) catalog, editors that use the catalog validate files
+// matching the fileMatch patterns of an entry with its schema
+type SchemaCatalog struct {
+	Schema  string               `json:"$schema"`
+	Version float64              `json:"version"`
+	Schemas []SchemaCatalogEntry `json:"schemas"`
+}
+
+// SchemaCatalogEntry is the entry of a schema in a JSON Schema Store catalog
+type SchemaCatalogEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	FileMatch   []string `json:"fileMatch"`
+	// URL is the location of the schema of the latest version
+	URL string `json:"url"`
+	// Versions are the locations of the schemas of all versions
+	Versions map[string]string `json:"versions,omitempty"`
+}
+
+// GetSchemaCatalog returns a JSON Schema Store catalog for the collector configuration schemas published at
+// baseURL: the bundle of every version at <baseURL>/<version>/bundle.json, see GetSchemaBundle, and the
+// bundle of the latest version at <baseURL>/otel-collector.json
+func (sm *SchemaManager) GetSchemaCatalog(baseURL string) (*SchemaCatalog, error) {
+	versions, err := sm.GetAllVersions()
+	if err != nil {
+		return nil, err
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	entry := SchemaCatalogEntry{
+		Name:        "OpenTelemetry Collector",
+		Description: "OpenTelemetry Collector configuration file",
+		FileMatch:   catalogFileMatch,
+		URL:         fmt.Sprintf("%s/%s", baseURL, collectorSchemaFileName),
+		Versions:    make(map[string]string, len(versions)),
+	}
+	for _, version := range versions {
+		entry.Versions[version] = fmt.Sprintf("%s/%s/%s", baseURL, version, bundleFileName)
+	}
+
+	return &SchemaCatalog{
+		Schema:  schemaCatalogSchema,
+		Version: 1,
+		Schemas: []SchemaCatalogEntry{entry},
+	}, nil
+}
@@ -0,0 +1,26 @@
+package collectorconfigschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_GetSchemaCatalog(t *testing.T) {
+	manager := NewSchemaManager()
+
+	catalog, err := manager.GetSchemaCatalog("https://example.com/schemas/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://json.schemastore.org/schema-catalog.json", catalog.Schema)
+	require.Len(t, catalog.Schemas, 1)
+
+	entry := catalog.Schemas[0]
+	assert.Equal(t, []string{"otel-collector*.yaml", "otel-collector*.yml"}, entry.FileMatch)
+	assert.Equal(t, "https://example.com/schemas/otel-collector.json", entry.URL)
+
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+	assert.Len(t, entry.Versions, len(versions))
+	assert.Equal(t, "https://example.com/schemas/0.138.0/bundle.json", entry.Versions["0.138.0"])
+}
@@ -0,0 +1,198 @@
+#!/bin/bash
+
+# Generates the schemas of several collector versions in one run, e.g.
+#   ./scripts/generate_schema_matrix.sh 0.120.0 0.121.0 0.122.0
+#
+# Every version is built in its own temporary module directory, so the go.mod pins of
+# build/ are never changed. The schema generator sources of build/ are copied into it.
+# Versions without a manifest-<version>.yaml get one derived from the latest manifest,
+# review it if the builder fails, components are added and removed between releases.
+# After all versions are generated, schemas/versions.json lists the generated versions.
+
+set -e
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+SCHEMAS_DIR="$ROOT_DIR/schemas"
+BIN_DIR="$ROOT_DIR/.bin"
+SCHEMA_STRICT="${SCHEMA_STRICT:-false}"
+SCHEMA_FAIL_ON_ERROR="${SCHEMA_FAIL_ON_ERROR:-false}"
+# Draft-07 copies of the schemas are written to <SCHEMA_DRAFT07_DIR>/<version> if it is set
+SCHEMA_DRAFT07_DIR="${SCHEMA_DRAFT07_DIR:-}"
+# Only the components selected by SCHEMA_ONLY and not SCHEMA_EXCLUDE are regenerated, other schemas are kept
+SCHEMA_ONLY="${SCHEMA_ONLY:-}"
+SCHEMA_EXCLUDE="${SCHEMA_EXCLUDE:-}"
+# Schemas of components whose module version did not change since the last generation are kept
+SCHEMA_INCREMENTAL="${SCHEMA_INCREMENTAL:-false}"
+# The generation logs warnings only with SCHEMA_LOG=quiet and every generated schema with SCHEMA_LOG=verbose
+SCHEMA_LOG="${SCHEMA_LOG:-}"
+# Override files applied to the generated schemas of every version
+SCHEMA_OVERRIDES_DIR="${SCHEMA_OVERRIDES_DIR:-$ROOT_DIR/overrides}"
+# Example configurations of the component tests that the generated schemas reject fail the generation, see
+# make roundtrip-schemas
+SCHEMA_ROUNDTRIP="${SCHEMA_ROUNDTRIP:-true}"
+# Offset between contrib (0.x) and stable core (1.y) module versions, e.g. v0.139.0 and v1.45.0
+CORE_VERSION_OFFSET=94
+
+if [[ $# -eq 0 ]]; then
+    echo "Usage: $0 <version> [<version>...]" >&2
+    exit 1
+fi
+
+# Function to print the latest manifest file
+latest_manifest() {
+    ls "$ROOT_DIR"/manifest-*.yaml | sort -V | tail -n 1
+}
+
+# Function to derive the manifest of a version from the latest manifest
+derive_manifest() {
+    local version="$1"
+    local target="$2"
+    local template=$(latest_manifest)
+    local template_version=$(basename "$template" .yaml | sed 's/^manifest-//')
+
+    local minor=$(echo "$version" | cut -d. -f2)
+    local template_minor=$(echo "$template_version" | cut -d. -f2)
+    local core_version="1.$((minor - CORE_VERSION_OFFSET)).0"
+    local template_core_version="1.$((template_minor - CORE_VERSION_OFFSET)).0"
+
+    echo "Warning: $(basename "$target") not found, deriving it from $(basename "$template")"
+    sed -e "s/v${template_version}/v${version}/g" \
+        -e "s/version: ${template_version}/version: ${version}/" \
+        -e "s/v${template_core_version}/v${core_version}/g" \
+        "$template" > "$target"
+}
+
+# Function to generate the schemas of a single version in a temporary module directory
+generate_version() {
+    local version="$1"
+    local manifest="$ROOT_DIR/manifest-${version}.yaml"
+    local work_dir=$(mktemp -d)
+    trap "rm -rf '$work_dir'" RETURN
+
+    if [[ ! -f "$manifest" ]]; then
+        derive_manifest "$version" "$manifest"
+    fi
+
+    echo "Generating schemas for $version in $work_dir..."
+    mkdir -p "$BIN_DIR"
+    if [[ ! -x "$BIN_DIR/builder-${version}" ]]; then
+        GOBIN="$work_dir/bin" go install "go.opentelemetry.io/collector/cmd/builder@v${version}"
+        mv "$work_dir/bin/builder" "$BIN_DIR/builder-${version}"
+    fi
+
+    # The generated module lives in the temporary directory and uses the schemagen package of this checkout
+    sed -e "s|output_path: .*|output_path: ${work_dir}/build|" \
+        -e "s|=> \.\./|=> ${ROOT_DIR}/|" \
+        "$manifest" > "$work_dir/manifest.yaml"
+
+    mkdir -p "$work_dir/build"
+    cp "$ROOT_DIR"/build/schema_generator*.go "$work_dir/build/"
+    cp -r "$ROOT_DIR/build/testdata" "$work_dir/build/"
+
+    "$BIN_DIR/builder-${version}" --config "$work_dir/manifest.yaml" --skip-compilation
+
+    local draft07_dir=""
+    if [[ -n "$SCHEMA_DRAFT07_DIR" ]]; then
+        draft07_dir="$SCHEMA_DRAFT07_DIR/$version"
+    fi
+
+    (cd "$work_dir/build" && go mod vendor && \
+        SCHEMA_OUTPUT_DIR="$SCHEMAS_DIR/$version" SCHEMA_STRICT="$SCHEMA_STRICT" SCHEMA_FAIL_ON_ERROR="$SCHEMA_FAIL_ON_ERROR" \
+        SCHEMA_DRAFT07_OUTPUT_DIR="$draft07_dir" SCHEMA_ONLY="$SCHEMA_ONLY" SCHEMA_EXCLUDE="$SCHEMA_EXCLUDE" SCHEMA_INCREMENTAL="$SCHEMA_INCREMENTAL" \
+        SCHEMA_OVERRIDES_DIR="$SCHEMA_OVERRIDES_DIR" go test -run TestGenerateAllSchemas -v ${SCHEMA_LOG:+-$SCHEMA_LOG})
+
+    if [[ "$SCHEMA_ROUNDTRIP" == "true" ]]; then
+        (cd "$work_dir/build" && \
+            SCHEMA_OUTPUT_DIR="$SCHEMAS_DIR/$version" SCHEMA_ROUNDTRIP_KNOWN_FAILURES="$ROOT_DIR/build/roundtrip_known_failures.txt" \
+            go test -run TestRoundTripSchemas -v)
+    fi
+}
+
+# Function to print the generated versions, directories of the schemas package that are not versions are skipped
+list_versions() {
+    ls -d "$SCHEMAS_DIR"/*.*/ | xargs -n 1 basename | sort -V
+}
+
+# Function to write the Go package embedding the schemas of a version, importing it registers the version
+write_version_package() {
+    local version="$1"
+    local target="$SCHEMAS_DIR/$version/schemas.go"
+    # Reference configurations are curated by hand, versions without templates embed none
+    local patterns="*_*.json.gz *.md manifest.json"
+    if [ -d "$SCHEMAS_DIR/$version/templates" ]; then
+        patterns="$patterns templates/*.yaml"
+    fi
+
+    cat > "$target" <<EOF
+// Package v${version//./_} embeds the component schemas of collector $version, importing it registers the version
+package v${version//./_}
+
+import (
+	"embed"
+
+	"github.com/pavolloffay/opentelemetry-collector-config-schema/schemas"
+)
+
+// Schemas are embedded gzip compressed with the provenance manifest (manifest.json) and the reference
+// configurations (templates/*.yaml), bundles (bundle.json) are generated from them and not embedded
+//
+//go:embed $patterns
+var files embed.FS
+
+func init() {
+	schemas.Register("$version", files)
+}
+EOF
+}
+
+# Function to write the schemas/all package importing every generated version
+write_all_package() {
+    local target="$SCHEMAS_DIR/all/all.go"
+
+    mkdir -p "$SCHEMAS_DIR/all"
+    echo "// Package all imports the component schemas of every collector version" > "$target"
+    echo "package all" >> "$target"
+    echo "" >> "$target"
+    echo "import (" >> "$target"
+    for version in $(list_versions); do
+        printf '\t_ "github.com/pavolloffay/opentelemetry-collector-config-schema/schemas/%s"\n' "$version" >> "$target"
+    done
+    echo ")" >> "$target"
+    echo "Wrote $target"
+}
+
+# Function to write schemas/versions.json listing every generated version and its number of schemas
+write_versions_manifest() {
+    local target="$SCHEMAS_DIR/versions.json"
+    local first=true
+
+    echo "{" > "$target"
+    echo '  "versions": [' >> "$target"
+    for version in $(list_versions); do
+        local count=$(ls "$SCHEMAS_DIR/$version" | grep -c '_.*\.json$' || true)
+        if [[ "$first" == false ]]; then
+            echo "," >> "$target"
+        fi
+        first=false
+        printf '    {"version": "%s", "schemas": %d}' "$version" "$count" >> "$target"
+    done
+    echo "" >> "$target"
+    echo "  ]" >> "$target"
+    echo "}" >> "$target"
+    echo "Wrote $target"
+}
+
+for version in "$@"; do
+    generate_version "$version"
+    write_version_package "$version"
+    (cd "$ROOT_DIR" && go run ./schemas/internal/compress "schemas/$version")
+done
+
+write_all_package
+write_versions_manifest
+
+# Bundles are written once the schemas/all package registers the generated versions
+for version in "$@"; do
+    (cd "$ROOT_DIR" && go run ./cmd/otelschema bundle --version "$version" --output-file "schemas/$version/bundle.json")
+    (cd "$ROOT_DIR" && go run ./cmd/otelschema checksums --output-file "schemas/$version/checksums.json" "schemas/$version")
+done
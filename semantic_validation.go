@@ -0,0 +1,42 @@
+package collectorconfigschema
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// semanticError is a problem found by a validation pass that goes beyond the JSON schema, e.g. an OTTL syntax error
+type semanticError struct {
+	// path is the location of the value, e.g. ["trace_statements", "0", "statements", "1"]
+	path []string
+	// errorType is reported as the type of the schema validation error, e.g. "ottl_syntax"
+	errorType string
+	value     string
+	message   string
+	// details are added to the details of the schema validation error, e.g. the property of an unknown field
+	details gojsonschema.ErrorDetails
+}
+
+// addSemanticErrors records semantic errors in a schema validation result
+func addSemanticErrors(result *gojsonschema.Result, semanticErrors []semanticError) {
+	for _, semanticError := range semanticErrors {
+		context := gojsonschema.NewJsonContext(gojsonschema.STRING_CONTEXT_ROOT, nil)
+		for _, segment := range semanticError.path {
+			context = gojsonschema.NewJsonContext(segment, context)
+		}
+
+		resultError := &gojsonschema.ResultErrorFields{}
+		resultError.SetType(semanticError.errorType)
+		resultError.SetContext(context)
+		resultError.SetValue(semanticError.value)
+		// The message is passed as a detail, values like OTTL statements must not be interpreted as a template
+		resultError.SetDescriptionFormat("{{.message}}")
+		details := gojsonschema.ErrorDetails{"message": semanticError.message}
+		for key, value := range semanticError.details {
+			if key != "message" {
+				details[key] = value
+			}
+		}
+		resultError.SetDetails(details)
+		result.AddError(resultError, details)
+	}
+}
@@ -0,0 +1,105 @@
+package collectorconfigschema
+
+import "sort"
+
+// definitionReferencePrefix is the prefix of references to the shared definitions of a schema
+const definitionReferencePrefix = "#/$defs/"
+
+// DefinitionUsage is a field of a component that uses a shared definition, see GetSharedDefinitionUsages
+type DefinitionUsage struct {
+	ComponentType ComponentType `json:"componentType"`
+	Component     string        `json:"component"`
+	// Path is the dotted path of the field in the component configuration, e.g. retry_on_failure or
+	// protocol.otlp.retry_on_failure
+	Path string `json:"path"`
+	// Default is the default value of the field, defaults recorded next to the reference take precedence over the
+	// default of the definition. It is nil if the schema records no default.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// ListSharedDefinitions returns the names of the definitions in the schemas of a version, e.g. the shared
+// sending_queue, retry_on_failure and timeout definitions. Shared definitions are configuration blocks generated
+// once into the $defs of a schema so their constraints cannot drift between components.
+func (sm *SchemaManager) ListSharedDefinitions(version string) ([]string, error) {
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]interface{})
+	for componentType, names := range components {
+		for _, name := range names {
+			schema, err := sm.GetComponentSchema(componentType, name, version)
+			if err != nil {
+				return nil, err
+			}
+			definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+			for definition := range definitions {
+				found[definition] = true
+			}
+		}
+	}
+	return sortedKeys(found), nil
+}
+
+// GetSharedDefinitionUsages returns the fields of all components of a version that use a shared definition, e.g. every
+// exporter with a sending_queue. Together with the defaults of the fields it supports fleet wide audits like which
+// exporters have retries disabled by default. Usages are sorted by component type, component and path.
+func (sm *SchemaManager) GetSharedDefinitionUsages(version string, definition string) ([]DefinitionUsage, error) {
+	components, err := sm.ListAvailableComponents(version)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := definitionReferencePrefix + definition
+	var usages []DefinitionUsage
+	for componentType, names := range components {
+		for _, name := range names {
+			schema, err := sm.GetComponentSchema(componentType, name, version)
+			if err != nil {
+				return nil, err
+			}
+			definitions, _ := schema.Schema["$defs"].(map[string]interface{})
+			if _, ok := definitions[definition]; !ok {
+				continue
+			}
+			found := make(map[string]bool)
+			searchSchemaFields(schema.Schema, definitions, "", nil, func(path string, field map[string]interface{}) {
+				if field["$ref"] != reference || found[path] {
+					return
+				}
+				found[path] = true
+				usages = append(usages, DefinitionUsage{
+					ComponentType: componentType,
+					Component:     name,
+					Path:          path,
+					Default:       definitionUsageDefault(field, definitions[definition]),
+				})
+			})
+		}
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].ComponentType != usages[j].ComponentType {
+			return usages[i].ComponentType < usages[j].ComponentType
+		}
+		if usages[i].Component != usages[j].Component {
+			return usages[i].Component < usages[j].Component
+		}
+		return usages[i].Path < usages[j].Path
+	})
+	return usages, nil
+}
+
+// definitionUsageDefault returns the default of a field using a shared definition, the default recorded next to the
+// reference is merged over the default of the definition
+func definitionUsageDefault(field map[string]interface{}, definition interface{}) interface{} {
+	var defaults interface{}
+	if definitionSchema, ok := definition.(map[string]interface{}); ok {
+		defaults = deepCopyValue(definitionSchema["default"])
+	}
+	if fieldDefault, ok := field["default"]; ok {
+		defaults = mergeDefaults(defaults, deepCopyValue(fieldDefault))
+	}
+	return defaults
+}
@@ -0,0 +1,159 @@
+package collectorconfigschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSharedDefinitions_Consistent checks that the exporterhelper definitions are identical in all schemas of a version
+func TestSharedDefinitions_Consistent(t *testing.T) {
+	manager := NewSchemaManager()
+
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+
+	for _, version := range versions {
+		names, err := manager.GetComponentNames(ComponentTypeExporter, version)
+		require.NoError(t, err)
+
+		definitions := make(map[string]interface{})
+		for _, name := range names {
+			schema, err := manager.GetComponentSchema(ComponentTypeExporter, name, version)
+			require.NoError(t, err)
+
+			componentDefinitions, _ := schema.Schema["$defs"].(map[string]interface{})
+			for definitionName, definition := range componentDefinitions {
+				if previous, exists := definitions[definitionName]; exists {
+					assert.Equal(t, previous, definition, "%s definition of exporter %s v%s drifted", definitionName, name, version)
+				}
+				definitions[definitionName] = definition
+			}
+		}
+
+		assert.Contains(t, definitions, "sending_queue", version)
+		assert.Contains(t, definitions, "retry_on_failure", version)
+		assert.Contains(t, definitions, "timeout", version)
+	}
+}
+
+func TestSharedDefinitions_Validation(t *testing.T) {
+	manager := NewSchemaManager()
+
+	tests := []struct {
+		name     string
+		exporter string
+		config   string
+		errors   []string
+	}{
+		{
+			name:     "valid",
+			exporter: "otlp",
+			config:   `{"sending_queue": {"enabled": true, "queue_size": 1000, "num_consumers": 10, "sizer": "items"}, "retry_on_failure": {"enabled": true, "randomization_factor": 0.5, "multiplier": 1.5}}`,
+		},
+		{
+			name:     "negative queue size",
+			exporter: "otlp",
+			config:   `{"sending_queue": {"queue_size": -1}}`,
+			errors:   []string{"sending_queue.queue_size"},
+		},
+		{
+			name:     "no consumers",
+			exporter: "otlphttp",
+			config:   `{"sending_queue": {"num_consumers": 0}}`,
+			errors:   []string{"sending_queue.num_consumers"},
+		},
+		{
+			name:     "enabled is not a boolean",
+			exporter: "otlp",
+			config:   `{"sending_queue": {"enabled": "yes"}, "retry_on_failure": {"enabled": "no"}}`,
+			errors:   []string{"sending_queue.enabled", "retry_on_failure.enabled"},
+		},
+		{
+			name:     "unknown sizer",
+			exporter: "otlp",
+			config:   `{"sending_queue": {"sizer": "spans", "batch": {"sizer": "bytes", "min_size": -5}}}`,
+			errors:   []string{"sending_queue.sizer", "sending_queue.batch.min_size"},
+		},
+		{
+			name:     "randomization factor out of range",
+			exporter: "otlp",
+			config:   `{"retry_on_failure": {"randomization_factor": 1.5, "multiplier": 0}}`,
+			errors:   []string{"retry_on_failure.randomization_factor", "retry_on_failure.multiplier"},
+		},
+		{
+			name:     "invalid timeout",
+			exporter: "elasticsearch",
+			config:   `{"timeout": "5 seconds"}`,
+			errors:   []string{"timeout"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := manager.ValidateComponentJSON(ComponentTypeExporter, test.exporter, "0.138.0", []byte(test.config))
+			require.NoError(t, err)
+
+			var fields []string
+			for _, validationError := range result.Errors() {
+				fields = append(fields, validationError.Field())
+			}
+			assert.ElementsMatch(t, test.errors, fields, strings.Join(fields, ", "))
+		})
+	}
+}
+
+func TestSchemaManager_ListSharedDefinitions(t *testing.T) {
+	manager := NewSchemaManager()
+
+	definitions, err := manager.ListSharedDefinitions("0.139.0")
+	require.NoError(t, err)
+	assert.Subset(t, definitions, []string{"retry_on_failure", "sending_queue", "timeout"})
+	assert.IsIncreasing(t, definitions)
+}
+
+func TestSchemaManager_GetSharedDefinitionUsages(t *testing.T) {
+	manager := NewSchemaManager()
+
+	usages, err := manager.GetSharedDefinitionUsages("0.139.0", "retry_on_failure")
+	require.NoError(t, err)
+	assert.Contains(t, usages, DefinitionUsage{ComponentType: ComponentTypeExporter, Component: "otlp", Path: "retry_on_failure"})
+	assert.Contains(t, usages, DefinitionUsage{ComponentType: ComponentTypeReceiver, Component: "kafka", Path: "error_backoff"})
+	for _, usage := range usages {
+		assert.NotEqual(t, "elasticsearch", usage.Component, "elasticsearch declares its own retry settings")
+	}
+
+	// Defaults next to the reference are merged over the defaults of the definition
+	require.NoError(t, manager.RegisterCustomSchema(ComponentTypeExporter, "inhouse", "0.139.0", []byte(`{
+		"type": "object",
+		"properties": {
+			"retry_on_failure": {"$ref": "#/$defs/retry_on_failure", "default": {"enabled": false}},
+			"fallback": {"properties": {"retry_on_failure": {"$ref": "#/$defs/retry_on_failure"}}}
+		},
+		"$defs": {
+			"retry_on_failure": {
+				"type": "object",
+				"properties": {"enabled": {"type": "boolean"}, "multiplier": {"type": "number"}},
+				"default": {"enabled": true, "multiplier": 1.5}
+			}
+		}
+	}`)))
+	usages, err = manager.GetSharedDefinitionUsages("0.139.0", "retry_on_failure")
+	require.NoError(t, err)
+	var inhouse []DefinitionUsage
+	for _, usage := range usages {
+		if usage.Component == "inhouse" {
+			inhouse = append(inhouse, usage)
+		}
+	}
+	assert.Equal(t, []DefinitionUsage{
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Path: "fallback.retry_on_failure", Default: map[string]interface{}{"enabled": true, "multiplier": 1.5}},
+		{ComponentType: ComponentTypeExporter, Component: "inhouse", Path: "retry_on_failure", Default: map[string]interface{}{"enabled": false, "multiplier": 1.5}},
+	}, inhouse)
+
+	usages, err = manager.GetSharedDefinitionUsages("0.139.0", "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
@@ -0,0 +1,116 @@
+package collectorconfigschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ignoreDirective is the comment that suppresses diagnostics of a configuration entry, e.g.
+// "# otelschema:ignore OTELCFG013 -- debug exporter of the on-call pipeline"
+const ignoreDirective = "otelschema:ignore"
+
+// DiagnosticIgnore suppresses the diagnostics of a code at a path, see IgnoreDiagnostics
+type DiagnosticIgnore struct {
+	// Code is the diagnostic code to suppress, e.g. DiagnosticCodeDebugExporter. An empty code suppresses all codes.
+	Code string `json:"code,omitempty"`
+	// Path is the dot separated path whose diagnostics and the diagnostics of its children are suppressed, e.g.
+	// "exporters.debug". An empty path suppresses the code everywhere.
+	Path string `json:"path,omitempty"`
+}
+
+// IgnoreDiagnostics suppresses diagnostics by code and path, e.g. to acknowledge known warnings from a list kept
+// next to the configuration
+func IgnoreDiagnostics(ignores ...DiagnosticIgnore) DiagnosticOption {
+	return func(options *diagnosticOptions) {
+		options.ignores = append(options.ignores, ignores...)
+	}
+}
+
+// yamlSuppressions returns the diagnostics suppressed by ignore comments of a document. A comment above or on the
+// line of a map entry or list item suppresses the listed codes, or all codes if none are listed, for the entry and
+// its children. A comment at the start of the document, separated from the first entry by a blank line, applies
+// to the whole document.
+func yamlSuppressions(document *yaml.Node) []DiagnosticIgnore {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	ignores := parseIgnoreComments("", document.HeadComment)
+	return recordYAMLSuppressions("", document.Content[0], ignores)
+}
+
+// recordYAMLSuppressions appends the suppressions of the children of a node at path
+func recordYAMLSuppressions(path string, node *yaml.Node, ignores []DiagnosticIgnore) []DiagnosticIgnore {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			ignores = append(ignores, parseIgnoreComments(childPath, key.HeadComment, key.LineComment)...)
+			if value.Kind == yaml.ScalarNode || value.Kind == yaml.AliasNode {
+				ignores = append(ignores, parseIgnoreComments(childPath, value.LineComment)...)
+			}
+			ignores = recordYAMLSuppressions(childPath, value, ignores)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			ignores = append(ignores, parseIgnoreComments(itemPath, item.HeadComment, item.LineComment)...)
+			ignores = recordYAMLSuppressions(itemPath, item, ignores)
+		}
+	}
+	return ignores
+}
+
+// parseIgnoreComments returns the suppressions of the ignore directives in YAML comments, text after "--" is a
+// reason for the suppression and ignored
+func parseIgnoreComments(path string, comments ...string) []DiagnosticIgnore {
+	var ignores []DiagnosticIgnore
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			rest, found := strings.CutPrefix(line, ignoreDirective)
+			if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+				continue
+			}
+			rest, _, _ = strings.Cut(rest, "--")
+			codes := strings.FieldsFunc(rest, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})
+			if len(codes) == 0 {
+				ignores = append(ignores, DiagnosticIgnore{Path: path})
+			}
+			for _, code := range codes {
+				ignores = append(ignores, DiagnosticIgnore{Code: code, Path: path})
+			}
+		}
+	}
+	return ignores
+}
+
+// ignored returns whether a diagnostic is suppressed by one of the ignores
+func ignored(diagnostic Diagnostic, ignores []DiagnosticIgnore) bool {
+	for _, ignore := range ignores {
+		if ignore.Code != "" && ignore.Code != diagnostic.Code {
+			continue
+		}
+		if ignore.Path == "" || isPathOrChild(diagnostic.Path, ignore.Path) || isPathOrChild(bracketIndexes(diagnostic.Path), ignore.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathOrChild returns whether path is parent or one of its children, e.g. "exporters.debug.verbosity" or
+// "service.pipelines.traces.receivers[0]" for "exporters.debug" and "service.pipelines.traces.receivers"
+func isPathOrChild(path string, parent string) bool {
+	if !strings.HasPrefix(path, parent) {
+		return false
+	}
+	rest := path[len(parent):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
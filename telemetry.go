@@ -0,0 +1,207 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationScope is the name of the tracer and meter of a SchemaManager
+const instrumentationScope = "github.com/pavolloffay/opentelemetry-collector-config-schema"
+
+// Attributes of the spans and metrics of a SchemaManager
+const (
+	attributeComponentType = attribute.Key("otelschema.component.type")
+	attributeComponentName = attribute.Key("otelschema.component.name")
+	attributeVersion       = attribute.Key("otelschema.version")
+	attributeValid         = attribute.Key("otelschema.valid")
+	attributeCache         = attribute.Key("otelschema.cache")
+)
+
+// WithTracerProvider records spans of collector configuration validations, component validations and schema loads
+// with the tracer provider. Spans are not recorded by default.
+func WithTracerProvider(provider trace.TracerProvider) ManagerOption {
+	return func(options *managerOptions) {
+		options.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records metrics with the meter provider: schema load durations, validation counts, durations
+// and errors by component and the counters of the caches. Metrics are not recorded by default.
+func WithMeterProvider(provider metric.MeterProvider) ManagerOption {
+	return func(options *managerOptions) {
+		options.meterProvider = provider
+	}
+}
+
+// telemetry holds the tracer and the instruments of a SchemaManager
+type telemetry struct {
+	tracer             trace.Tracer
+	loadDuration       metric.Float64Histogram
+	validations        metric.Int64Counter
+	validationDuration metric.Float64Histogram
+	validationErrors   metric.Int64Counter
+
+	// registration is the callback observing the caches of the manager, it is unregistered by Close
+	mu           sync.Mutex
+	registration metric.Registration
+}
+
+// newTelemetry creates the tracer and the instruments of a SchemaManager, the cache metrics are observed from
+// the caches of the manager. Instruments that cannot be created are reported to the global error handler and
+// do not record.
+func newTelemetry(sm *SchemaManager, tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) *telemetry {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(instrumentationScope)
+
+	t := &telemetry{tracer: tracerProvider.Tracer(instrumentationScope)}
+	var err error
+	if t.loadDuration, err = meter.Float64Histogram("otelschema.schema.load.duration",
+		metric.WithDescription("Duration of loading and parsing a component schema"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if t.validations, err = meter.Int64Counter("otelschema.validations",
+		metric.WithDescription("Number of component configuration validations"), metric.WithUnit("{validation}")); err != nil {
+		otel.Handle(err)
+	}
+	if t.validationDuration, err = meter.Float64Histogram("otelschema.validation.duration",
+		metric.WithDescription("Duration of a component configuration validation"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if t.validationErrors, err = meter.Int64Counter("otelschema.validation.errors",
+		metric.WithDescription("Number of schema errors found in component configurations"), metric.WithUnit("{error}")); err != nil {
+		otel.Handle(err)
+	}
+
+	hits, err := meter.Int64ObservableCounter("otelschema.cache.hits",
+		metric.WithDescription("Number of schemas and validators served from the caches"), metric.WithUnit("{hit}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	misses, err := meter.Int64ObservableCounter("otelschema.cache.misses",
+		metric.WithDescription("Number of schemas and validators that were not cached"), metric.WithUnit("{miss}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	evictions, err := meter.Int64ObservableCounter("otelschema.cache.evictions",
+		metric.WithDescription("Number of schemas and validators evicted from the full caches"), metric.WithUnit("{eviction}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	size, err := meter.Int64ObservableUpDownCounter("otelschema.cache.size",
+		metric.WithDescription("Number of cached schemas and validators"), metric.WithUnit("{entry}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	if t.registration, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		for cache, stats := range map[string]CacheStats{"schema": sm.CacheStats(), "compiled": sm.CompiledCacheStats()} {
+			attributes := metric.WithAttributes(attributeCache.String(cache))
+			observer.ObserveInt64(hits, int64(stats.Hits), attributes)
+			observer.ObserveInt64(misses, int64(stats.Misses), attributes)
+			observer.ObserveInt64(evictions, int64(stats.Evictions), attributes)
+			observer.ObserveInt64(size, int64(stats.Size), attributes)
+		}
+		return nil
+	}, hits, misses, evictions, size); err != nil {
+		otel.Handle(err)
+	}
+
+	return t
+}
+
+// Close unregisters the callback observing the caches of the manager from the meter provider of WithMeterProvider,
+// so the provider no longer references the manager. The manager remains usable, its cache metrics are no longer
+// reported. Close can be called more than once.
+func (sm *SchemaManager) Close() error {
+	return sm.telemetry.shutdown()
+}
+
+// shutdown unregisters the callback of the cache metrics
+func (t *telemetry) shutdown() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.registration == nil {
+		return nil
+	}
+	err := t.registration.Unregister()
+	t.registration = nil
+	return err
+}
+
+// componentAttributes returns the attributes identifying a component schema
+func componentAttributes(componentType ComponentType, componentName string, version string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attributeComponentType.String(string(componentType)),
+		attributeComponentName.String(componentName),
+		attributeVersion.String(version),
+	}
+}
+
+// startCollectorConfigValidation starts the span of validating a collector configuration, the returned function
+// ends it
+func (t *telemetry) startCollectorConfigValidation(ctx context.Context, version string) (context.Context, func(result *ConfigValidationResult, err error)) {
+	ctx, span := t.tracer.Start(ctx, "ValidateCollectorConfig", trace.WithAttributes(attributeVersion.String(version)))
+
+	return ctx, func(result *ConfigValidationResult, err error) {
+		if result != nil {
+			span.SetAttributes(attributeValid.Bool(result.Valid()))
+		}
+		endSpan(span, err)
+	}
+}
+
+// startSchemaLoad starts the span of loading a component schema, the returned function ends it and records the
+// load duration
+func (t *telemetry) startSchemaLoad(ctx context.Context, componentType ComponentType, componentName string, version string) func(err error) {
+	attributes := componentAttributes(componentType, componentName, version)
+	_, span := t.tracer.Start(ctx, "LoadComponentSchema", trace.WithAttributes(attributes...))
+	start := time.Now()
+
+	return func(err error) {
+		t.loadDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attributes...))
+		endSpan(span, err)
+	}
+}
+
+// startValidation starts the span of validating a component configuration, the returned function ends it and
+// records the validation count, duration and the number of errors
+func (t *telemetry) startValidation(ctx context.Context, componentType ComponentType, componentName string, version string) (context.Context, func(errors int, err error)) {
+	attributes := componentAttributes(componentType, componentName, version)
+	ctx, span := t.tracer.Start(ctx, "ValidateComponent", trace.WithAttributes(attributes...))
+	start := time.Now()
+
+	return ctx, func(errors int, err error) {
+		recorded := attributes
+		if err == nil {
+			valid := attributeValid.Bool(errors == 0)
+			span.SetAttributes(valid)
+			recorded = append(recorded[:len(recorded):len(recorded)], valid)
+			t.validations.Add(ctx, 1, metric.WithAttributes(recorded...))
+			t.validationErrors.Add(ctx, int64(errors), metric.WithAttributes(attributes...))
+		}
+		t.validationDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(recorded...))
+		endSpan(span, err)
+	}
+}
+
+// endSpan ends a span and records an error on it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
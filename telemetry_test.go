@@ -0,0 +1,118 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSchemaManager_Telemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	manager := NewSchemaManager(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	config := []byte(`
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+    sampling_initial: invalid
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`)
+	result, err := manager.ValidateCollectorConfig(config, "0.139.0")
+	require.NoError(t, err)
+	require.False(t, result.Valid())
+	_, err = manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`{}`))
+	require.NoError(t, err)
+
+	names := map[string]int{}
+	var root sdktrace.ReadOnlySpan
+	for _, span := range spans.Ended() {
+		names[span.Name()]++
+		if span.Name() == "ValidateCollectorConfig" {
+			root = span
+		}
+	}
+	assert.Equal(t, map[string]int{"ValidateCollectorConfig": 1, "ValidateComponent": 3, "LoadComponentSchema": 2}, names)
+	require.NotNil(t, root)
+	for _, span := range spans.Ended() {
+		if span.Name() == "ValidateComponent" && span.Parent().IsValid() {
+			assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+		}
+	}
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	sums := map[string]int64{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					if cache, ok := point.Attributes.Value(attributeCache); ok && cache.AsString() != "schema" {
+						continue
+					}
+					sums[m.Name] += point.Value
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(3), sums["otelschema.validations"])
+	assert.Positive(t, sums["otelschema.validation.errors"])
+	assert.Equal(t, int64(1), sums["otelschema.cache.hits"])
+	assert.Equal(t, int64(2), sums["otelschema.cache.misses"])
+	assert.Equal(t, int64(2), sums["otelschema.cache.size"])
+}
+
+func TestSchemaManager_TelemetryDisabled(t *testing.T) {
+	manager := NewSchemaManager()
+	_, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`{}`))
+	require.NoError(t, err)
+}
+
+func TestSchemaManager_Close(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	manager := NewSchemaManager(WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	_, err := manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`{}`))
+	require.NoError(t, err)
+
+	// cacheMetrics returns the names of the cache metrics with data points
+	cacheMetrics := func() []string {
+		var metrics metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &metrics))
+		var names []string
+		for _, scope := range metrics.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if sum, ok := m.Data.(metricdata.Sum[int64]); ok && len(sum.DataPoints) > 0 && strings.HasPrefix(m.Name, "otelschema.cache.") {
+					names = append(names, m.Name)
+				}
+			}
+		}
+		return names
+	}
+	assert.Len(t, cacheMetrics(), 4)
+
+	require.NoError(t, manager.Close())
+	assert.Empty(t, cacheMetrics(), "The cache metrics are no longer observed")
+	assert.NoError(t, manager.Close())
+
+	_, err = manager.ValidateComponentJSON(ComponentTypeReceiver, "otlp", "0.139.0", []byte(`{}`))
+	assert.NoError(t, err, "The manager remains usable")
+	assert.NoError(t, NewSchemaManager().Close())
+}
@@ -0,0 +1,47 @@
+package collectorconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// versionManifestFileName is the file name of the provenance manifest of the schemas of a version
+const versionManifestFileName = "manifest.json"
+
+// VersionManifest records how the schemas of a collector version were generated, it is written by the schema
+// generator to schemas/<version>/manifest.json
+type VersionManifest struct {
+	// Version is the collector version of the schemas, e.g. "0.139.0"
+	Version string `json:"version"`
+	// Generator is the fingerprint of the generator sources, it is empty for snapshots generated before it was
+	// recorded
+	Generator string `json:"generator,omitempty"`
+	// GeneratedAt is the time the schemas were generated
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Modules maps the Go modules of the components to their versions, e.g. the collector contrib modules
+	Modules map[string]string `json:"modules"`
+	// Components is the number of component schemas by type
+	Components map[ComponentType]int `json:"components"`
+}
+
+// GetVersionManifest returns the provenance manifest of the embedded schemas of a version, e.g. to display which
+// module versions the schemas were generated from
+func (sm *SchemaManager) GetVersionManifest(version string) (*VersionManifest, error) {
+	data, err := readEmbeddedFile(version, versionManifestFileName)
+	if err != nil {
+		return nil, fmt.Errorf("manifest not found for version %s", version)
+	}
+
+	var manifest VersionManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of version %s: %w", version, err)
+	}
+	return &manifest, nil
+}
+
+// Stale returns whether the schemas were generated more than maxAge ago, e.g. to warn that a snapshot should be
+// regenerated
+func (m *VersionManifest) Stale(maxAge time.Duration) bool {
+	return time.Since(m.GeneratedAt) > maxAge
+}
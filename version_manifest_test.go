@@ -0,0 +1,39 @@
+package collectorconfigschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_GetVersionManifest(t *testing.T) {
+	manager := NewSchemaManager()
+
+	versions, err := manager.GetAllVersions()
+	require.NoError(t, err)
+	for _, version := range versions {
+		manifest, err := manager.GetVersionManifest(version)
+		require.NoError(t, err, version)
+		assert.Equal(t, version, manifest.Version)
+		assert.False(t, manifest.GeneratedAt.IsZero(), version)
+		assert.Equal(t, "v"+version, manifest.Modules["go.opentelemetry.io/collector/receiver/otlpreceiver"], version)
+
+		// The manifest counts the embedded schemas
+		components, err := manager.ListAvailableComponents(version)
+		require.NoError(t, err)
+		for componentType, names := range components {
+			assert.Equal(t, len(names), manifest.Components[componentType], "%s %s", version, componentType)
+		}
+	}
+
+	_, err = manager.GetVersionManifest("0.1.0")
+	assert.EqualError(t, err, "manifest not found for version 0.1.0")
+}
+
+func TestVersionManifest_Stale(t *testing.T) {
+	manifest := &VersionManifest{GeneratedAt: time.Now().Add(-48 * time.Hour)}
+	assert.True(t, manifest.Stale(24*time.Hour))
+	assert.False(t, manifest.Stale(72*time.Hour))
+}
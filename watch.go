@@ -0,0 +1,55 @@
+package collectorconfigschema
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultWatchInterval is the interval WatchConfigFile checks a file for changes at if no interval is given
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// WatchConfigFile validates a collector configuration file and validates it again whenever its content changes,
+// e.g. when it is saved in an editor, until the context is canceled. Every result is passed to onResult, including
+// a result with Err when the file cannot be read, e.g. while it is replaced. The file is polled at the interval, a
+// non-positive interval uses DefaultWatchInterval. The error of the context is returned when it is canceled.
+func (sm *SchemaManager) WatchConfigFile(ctx context.Context, path string, version string, interval time.Duration, onResult func(ConfigFileResult), opts ...ValidationOption) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []byte
+	var lastErr string
+	first := true
+	for {
+		config, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			// A missing file is reported once until it can be read again
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				last = nil
+				onResult(ConfigFileResult{Path: path, Err: fmt.Errorf("failed to read config file: %w", err)})
+			}
+		case first || lastErr != "" || !bytes.Equal(config, last):
+			lastErr = ""
+			last = config
+			result := sm.validateConfigData(ctx, path, config, version, opts...)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			onResult(result)
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package collectorconfigschema
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaManager_WatchConfigFile(t *testing.T) {
+	manager := NewSchemaManager()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("receivers:\n  otlp:\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan ConfigFileResult, 10)
+	done := make(chan error)
+	go func() {
+		done <- manager.WatchConfigFile(ctx, path, "0.139.0", 5*time.Millisecond, func(result ConfigFileResult) {
+			results <- result
+		})
+	}()
+
+	result := <-results
+	require.NoError(t, result.Err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, []string{"receivers.otlp"}, result.Components)
+
+	writeConfigFile(t, path, "receivers:\n  unknown:\n")
+	result = <-results
+	require.NoError(t, result.Err)
+	assert.False(t, result.Valid())
+
+	require.NoError(t, os.Remove(path))
+	result = <-results
+	assert.ErrorContains(t, result.Err, "failed to read config file")
+
+	writeConfigFile(t, path, "receivers:\n  otlp:\n")
+	result = <-results
+	assert.True(t, result.Valid())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Empty(t, results)
+}
+
+// writeConfigFile replaces a file at once so a watcher never reads it partially written
+func writeConfigFile(t *testing.T, path string, content string) {
+	temporary := path + ".tmp"
+	require.NoError(t, os.WriteFile(temporary, []byte(content), 0644))
+	require.NoError(t, os.Rename(temporary, path))
+}